@@ -9,7 +9,6 @@ import (
 	"io"
 	"os"
 	"os/exec"
-	"path/filepath"
 	"strings"
 
 	"charm.land/log/v2"
@@ -72,13 +71,11 @@ var (
 		stderr := cmd.ErrOrStderr()
 
 		cmdName := cmd.Name()
-		customHookPath := filepath.Join(cfg.DataPath, "hooks", cmdName)
 
 		var buf bytes.Buffer
 		opts := make([]hooks.HookArg, 0)
 
-		switch cmdName {
-		case hooks.PreReceiveHook, hooks.PostReceiveHook:
+		if cmdName == hooks.PreReceiveHook || cmdName == hooks.PostReceiveHook {
 			scanner := bufio.NewScanner(stdin)
 			for scanner.Scan() {
 				buf.Write(scanner.Bytes())
@@ -94,13 +91,36 @@ var (
 					RefName: fields[2],
 				})
 			}
+		}
+
+		// Custom hooks
+		runCustomHooks := func() error {
+			for _, path := range hooks.CustomHookPaths(cfg.DataPath, repoName, cmdName) {
+				if err := runCustomHook(ctx, path, bytes.NewReader(buf.Bytes()), stdout, stderr, args...); err != nil {
+					logger.Error("failed to run custom hook", "hook", path, "err", err)
+					return err
+				}
+			}
+
+			return nil
+		}
+
+		// Custom hooks can reject refs in pre-receive and update, so they
+		// run before the built-in handlers queue webhooks for the refs.
+		rejecting := cmdName == hooks.PreReceiveHook || cmdName == hooks.UpdateHook
+		if rejecting {
+			if err := runCustomHooks(); err != nil {
+				return err
+			}
+		}
 
-			switch cmdName {
-			case hooks.PreReceiveHook:
-				hks.PreReceive(ctx, stdout, stderr, repoName, opts)
-			case hooks.PostReceiveHook:
-				hks.PostReceive(ctx, stdout, stderr, repoName, opts)
+		switch cmdName {
+		case hooks.PreReceiveHook:
+			if err := hks.PreReceive(ctx, stdout, stderr, repoName, opts); err != nil {
+				return err
 			}
+		case hooks.PostReceiveHook:
+			hks.PostReceive(ctx, stdout, stderr, repoName, opts)
 		case hooks.UpdateHook:
 			if len(args) != 3 {
 				logger.Error("invalid update hook input", "input", args)
@@ -116,10 +136,8 @@ var (
 			hks.PostUpdate(ctx, stdout, stderr, repoName, args...)
 		}
 
-		// Custom hooks
-		if err := runCustomHook(ctx, customHookPath, &buf, stdout, stderr, args...); err != nil {
-			logger.Error("failed to run custom hook", "err", err)
-			return err
+		if !rejecting {
+			return runCustomHooks()
 		}
 
 		return nil
@@ -170,8 +188,7 @@ func runCommand(ctx context.Context, in io.Reader, out io.Writer, err io.Writer,
 }
 
 func runCustomHook(ctx context.Context, path string, in io.Reader, out io.Writer, err io.Writer, args ...string) error {
-	stat, statErr := os.Stat(path)
-	if statErr != nil || stat.IsDir() || stat.Mode()&0o111 == 0 {
+	if !hooks.IsCustomHook(path) {
 		return nil
 	}
 
@@ -0,0 +1,48 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Archive formats.
+const (
+	ArchiveTar   = "tar"
+	ArchiveTarGz = "tar.gz"
+	ArchiveZip   = "zip"
+)
+
+// ArchiveOptions are options for Archive.
+type ArchiveOptions struct {
+	// Format is the archive format, one of ArchiveTar, ArchiveTarGz, or
+	// ArchiveZip.
+	Format string
+	// Prefix is prepended to every path in the archive, e.g. "project/".
+	Prefix string
+}
+
+// Archive writes an archive of the tree of rev to w.
+func (r *Repository) Archive(w io.Writer, rev string, opts ArchiveOptions) error {
+	switch opts.Format {
+	case ArchiveTar, ArchiveTarGz, ArchiveZip:
+	default:
+		return fmt.Errorf("unsupported archive format %q", opts.Format)
+	}
+
+	args := []string{"archive", "--format=" + opts.Format}
+	if opts.Prefix != "" {
+		args = append(args, "--prefix="+opts.Prefix)
+	}
+
+	var stderr bytes.Buffer
+	if err := NewCommand(append(args, "--end-of-options", rev)...).RunInDirWithOptions(r.Path, RunInDirOptions{
+		Stdout: w,
+		Stderr: &stderr,
+	}); err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return nil
+}
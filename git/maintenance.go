@@ -0,0 +1,132 @@
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ObjectCount is the object store usage of a repository as reported by `git
+// count-objects`. Sizes are in bytes.
+type ObjectCount struct {
+	// LooseObjects is the number of loose objects.
+	LooseObjects int64
+	// LooseSize is the disk space taken by loose objects.
+	LooseSize int64
+	// PackedObjects is the number of objects in packs.
+	PackedObjects int64
+	// Packs is the number of packs.
+	Packs int64
+	// PackSize is the disk space taken by packs, including their indexes.
+	PackSize int64
+	// GarbageSize is the disk space taken by garbage files in the object
+	// store.
+	GarbageSize int64
+}
+
+// Size returns the total disk space taken by the object store.
+func (c ObjectCount) Size() int64 {
+	return c.LooseSize + c.PackSize + c.GarbageSize
+}
+
+// CountObjects returns the object store usage of the repository.
+func (r *Repository) CountObjects() (ObjectCount, error) {
+	out, err := NewCommand("count-objects", "-v").RunInDir(r.Path)
+	if err != nil {
+		return ObjectCount{}, err
+	}
+
+	var c ObjectCount
+	s := bufio.NewScanner(bytes.NewReader(out))
+	for s.Scan() {
+		k, v, ok := strings.Cut(s.Text(), ": ")
+		if !ok {
+			continue
+		}
+
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return ObjectCount{}, fmt.Errorf("count-objects: invalid %s: %w", k, err)
+		}
+
+		// Sizes are reported in KiB.
+		switch k {
+		case "count":
+			c.LooseObjects = n
+		case "size":
+			c.LooseSize = n * 1024
+		case "in-pack":
+			c.PackedObjects = n
+		case "packs":
+			c.Packs = n
+		case "size-pack":
+			c.PackSize = n * 1024
+		case "size-garbage":
+			c.GarbageSize = n * 1024
+		}
+	}
+
+	return c, s.Err()
+}
+
+// MaintenanceOptions are options for Maintenance.
+type MaintenanceOptions struct {
+	// Full runs `git gc`, repacking every object into a single pack and
+	// pruning unreachable ones, rather than `git gc --auto` which only does
+	// so once enough loose objects or packs piled up.
+	Full bool
+}
+
+// Maintenance runs housekeeping on the repository: it garbage collects,
+// packs loose objects geometrically into a multi-pack index with a bitmap,
+// and writes the commit-graph.
+func (r *Repository) Maintenance(ctx context.Context, opts MaintenanceOptions) error {
+	// Run gc in the foreground, it would detach with --auto otherwise.
+	gc := []string{"-c", "gc.autoDetach=false", "gc", "--quiet"}
+	if !opts.Full {
+		gc = append(gc, "--auto")
+	}
+
+	for _, step := range []struct {
+		name string
+		args []string
+	}{
+		{"gc", gc},
+		{"repack", []string{"repack", "-d", "--geometric=2", "--write-midx", "--write-bitmap-index", "--quiet"}},
+		{"commit-graph", []string{"commit-graph", "write", "--reachable", "--no-progress"}},
+	} {
+		if _, err := NewCommand(step.args...).WithContext(ctx).WithTimeout(-1).RunInDir(r.Path); err != nil {
+			return fmt.Errorf("%s: %w", step.name, err)
+		}
+	}
+
+	return nil
+}
+
+// IsReceiving returns whether a push to the repository is in progress. git
+// receives pushed objects into a quarantine directory in the object store,
+// and removes it once the push is accepted or rejected.
+func (r *Repository) IsReceiving() bool {
+	objects := filepath.Join(r.Path, "objects")
+	if !r.IsBare {
+		objects = filepath.Join(r.Path, ".git", "objects")
+	}
+
+	entries, err := os.ReadDir(objects)
+	if err != nil {
+		return false
+	}
+
+	for _, e := range entries {
+		if e.IsDir() && strings.HasPrefix(e.Name(), "tmp_objdir-incoming-") {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,54 @@
+package git
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMaintenance(t *testing.T) {
+	repo, _ := setupTestRepo(t)
+
+	before, err := repo.CountObjects()
+	if err != nil {
+		t.Fatalf("CountObjects failed: %v", err)
+	}
+	if before.LooseObjects == 0 || before.LooseSize == 0 || before.Packs != 0 {
+		t.Fatalf("unexpected object count before maintenance: %+v", before)
+	}
+
+	if err := repo.Maintenance(context.Background(), MaintenanceOptions{}); err != nil {
+		t.Fatalf("Maintenance failed: %v", err)
+	}
+
+	after, err := repo.CountObjects()
+	if err != nil {
+		t.Fatalf("CountObjects failed: %v", err)
+	}
+	if after.LooseObjects != 0 || after.Packs != 1 || after.PackedObjects != before.LooseObjects {
+		t.Errorf("loose objects were not packed: %+v", after)
+	}
+
+	objects := filepath.Join(repo.Path, ".git", "objects")
+	for _, pattern := range []string{"info/commit-graph", "pack/multi-pack-index", "pack/multi-pack-index-*.bitmap"} {
+		if m, _ := filepath.Glob(filepath.Join(objects, pattern)); len(m) == 0 {
+			t.Errorf("maintenance did not write %s", pattern)
+		}
+	}
+}
+
+func TestIsReceiving(t *testing.T) {
+	repo, _ := setupTestRepo(t)
+	if repo.IsReceiving() {
+		t.Fatal("IsReceiving() = true without a push")
+	}
+
+	quarantine := filepath.Join(repo.Path, ".git", "objects", "tmp_objdir-incoming-abc123")
+	if err := os.Mkdir(quarantine, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if !repo.IsReceiving() {
+		t.Error("IsReceiving() = false with a quarantine directory")
+	}
+}
@@ -0,0 +1,102 @@
+package git
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/aymanbagabas/git-module"
+)
+
+// ErrMergeConflict is returned when two commits cannot be merged cleanly.
+var ErrMergeConflict = errors.New("merge conflict")
+
+// Signature is the author or committer of a commit.
+type Signature = git.Signature
+
+// DiffRange returns the diff of the changes made on head since it diverged
+// from base, like "git diff base...head".
+func (r *Repository) DiffRange(base, head string) (*Diff, error) {
+	mergeBase, err := r.MergeBase(base, head)
+	if err != nil {
+		return nil, err
+	}
+
+	diff, err := r.Repository.Diff(head, DiffMaxFiles, DiffMaxFileLines, DiffMaxLineChars, git.DiffOptions{
+		Base: mergeBase,
+		CommandOptions: git.CommandOptions{
+			Envs: []string{"GIT_CONFIG_GLOBAL=/dev/null"},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toDiff(diff), nil
+}
+
+// MergeTree merges two commits without a work tree and returns the ID of the
+// resulting tree. It returns ErrMergeConflict if the commits conflict.
+func (r *Repository) MergeTree(ours, theirs string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := NewCommand("merge-tree", "--write-tree", "--no-messages", "--end-of-options", ours, theirs)
+	if err := cmd.RunInDirWithOptions(r.Path, RunInDirOptions{
+		Stdout: &stdout,
+		Stderr: &stderr,
+	}); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+			return "", ErrMergeConflict
+		}
+		return "", fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	// The tree ID is on the first line, conflicts would follow.
+	tree, _, _ := strings.Cut(stdout.String(), "\n")
+	return strings.TrimSpace(tree), nil
+}
+
+// CommitTree creates a commit of the given tree and returns its ID. The
+// signature is used both as the author and the committer.
+func (r *Repository) CommitTree(tree string, parents []string, message string, sig *Signature) (string, error) {
+	args := []string{"commit-tree", tree}
+	for _, p := range parents {
+		args = append(args, "-p", p)
+	}
+
+	cmd := NewCommand(args...).
+		AddEnvs(
+			"GIT_AUTHOR_NAME="+sig.Name,
+			"GIT_AUTHOR_EMAIL="+sig.Email,
+		).
+		AddCommitter(sig)
+
+	var stdout, stderr bytes.Buffer
+	if err := cmd.RunInDirWithOptions(r.Path, RunInDirOptions{
+		Stdin:  strings.NewReader(message),
+		Stdout: &stdout,
+		Stderr: &stderr,
+	}); err != nil {
+		return "", fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// UpdateRef points ref at newID. If oldID is not empty, the update only
+// happens if ref still points at oldID. An empty newID deletes ref.
+func (r *Repository) UpdateRef(ref, newID, oldID string) error {
+	args := []string{"update-ref"}
+	if newID == "" {
+		args = append(args, "-d", "--end-of-options", ref)
+	} else {
+		args = append(args, "--end-of-options", ref, newID)
+	}
+	if oldID != "" {
+		args = append(args, oldID)
+	}
+
+	_, err := NewCommand(args...).RunInDir(r.Path)
+	return err
+}
@@ -1,8 +1,13 @@
 package git
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
 	gopath "path"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/aymanbagabas/git-module"
@@ -190,3 +195,166 @@ func (r *Repository) SymbolicRef(name string, ref string, opts ...git.SymbolicRe
 	opt.Ref = ref
 	return r.Repository.SymbolicRef(opt)
 }
+
+// IsAncestor returns whether the ancestor commit is reachable from the
+// descendant commit, i.e. whether moving a ref from ancestor to descendant is
+// a fast-forward.
+func (r *Repository) IsAncestor(ancestor, descendant string) (bool, error) {
+	base, err := r.MergeBase(ancestor, descendant)
+	if err != nil {
+		if errors.Is(err, git.ErrNoMergeBase) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return base == ancestor, nil
+}
+
+// NewCommits returns the commits reachable from rev that are not reachable
+// from any reference of the repository, newest first. In a pre-receive hook,
+// these are the commits a push introduces.
+func (r *Repository) NewCommits(rev string) (Commits, error) {
+	// The second --not flips back, so only rev is included, and rev comes
+	// after --end-of-options so it is never parsed as an option.
+	out, err := NewCommand("rev-list", "--not", "--all", "--not", "--end-of-options", rev).RunInDir(r.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := strings.Fields(string(out))
+	commits := make(Commits, len(ids))
+	for i, id := range ids {
+		commits[i], err = r.CatFileCommit(id)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return commits, nil
+}
+
+// MergeCommits returns the IDs of the merge commits reachable from rev that
+// are not reachable from base, newest first. A zero base includes the whole
+// history of rev.
+func (r *Repository) MergeCommits(base, rev string) ([]string, error) {
+	args := []string{"rev-list", "--merges", "--end-of-options", rev}
+	if !IsZeroHash(base) {
+		args = append(args, "^"+base)
+	}
+
+	out, err := NewCommand(args...).RunInDir(r.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	return strings.Fields(string(out)), nil
+}
+
+// ChangedFile is a file added or modified by a commit.
+type ChangedFile struct {
+	// Path is the path of the file.
+	Path string
+	// ID is the ID of the file's blob.
+	ID string
+	// Size is the size of the file's blob in bytes.
+	Size int64
+}
+
+// ChangedFiles returns the files commit adds or modifies. For merge commits,
+// these are only the files that differ from every parent, i.e. the changes
+// made by the merge itself. Submodules are skipped.
+func (r *Repository) ChangedFiles(commit *Commit) ([]ChangedFile, error) {
+	var files []ChangedFile
+	if commit.ParentsCount() == 0 {
+		var err error
+		files, err = r.diffTree("--root", "--end-of-options", commit.ID.String())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for i := 0; i < commit.ParentsCount(); i++ {
+		parent, err := commit.ParentID(i)
+		if err != nil {
+			return nil, err
+		}
+
+		changed, err := r.diffTree("--end-of-options", parent.String(), commit.ID.String())
+		if err != nil {
+			return nil, err
+		}
+
+		if i == 0 {
+			files = changed
+			continue
+		}
+
+		ids := make(map[string]string, len(changed))
+		for _, f := range changed {
+			ids[f.Path] = f.ID
+		}
+
+		files = slices.DeleteFunc(files, func(f ChangedFile) bool {
+			return ids[f.Path] != f.ID
+		})
+	}
+
+	if len(files) == 0 {
+		return files, nil
+	}
+
+	var ids bytes.Buffer
+	for _, f := range files {
+		ids.WriteString(f.ID + "\n")
+	}
+
+	var stdout, stderr bytes.Buffer
+	if err := NewCommand("cat-file", "--batch-check=%(objectsize)").RunInDirWithOptions(r.Path, RunInDirOptions{
+		Stdin:  &ids,
+		Stdout: &stdout,
+		Stderr: &stderr,
+	}); err != nil {
+		return nil, fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	sizes := strings.Fields(stdout.String())
+	if len(sizes) != len(files) {
+		return nil, fmt.Errorf("unexpected cat-file output for %d files", len(files))
+	}
+
+	for i, s := range sizes {
+		size, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		files[i].Size = size
+	}
+
+	return files, nil
+}
+
+// diffTree runs "git diff-tree" with args and returns the files it reports as
+// added or modified, without their sizes.
+func (r *Repository) diffTree(args ...string) ([]ChangedFile, error) {
+	args = append([]string{"diff-tree", "-r", "-z", "--no-commit-id", "--no-renames", "--diff-filter=AMT"}, args...)
+	out, err := NewCommand(args...).RunInDir(r.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Each entry is ":<old mode> <new mode> <old id> <new id> <status>" and
+	// the path, separated by NUL.
+	var files []ChangedFile
+	fields := strings.Split(strings.TrimSuffix(string(out), "\x00"), "\x00")
+	for i := 0; i+1 < len(fields); i += 2 {
+		meta := strings.Fields(fields[i])
+		if len(meta) != 5 || meta[1] == "160000" {
+			continue
+		}
+
+		files = append(files, ChangedFile{Path: fields[i+1], ID: meta[3]})
+	}
+
+	return files, nil
+}
@@ -92,3 +92,63 @@ func TestTreeEntryPathForwardSlashes(t *testing.T) {
 		}
 	}
 }
+
+func TestNewCommitsAndChangedFiles(t *testing.T) {
+	repo, _ := setupTestRepo(t)
+
+	run := func(args ...string) string {
+		t.Helper()
+		cmd := exec.CommandContext(context.Background(), "git", args...)
+		cmd.Dir = repo.Path
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s failed: %v\n%s", args[0], err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+
+	if err := os.WriteFile(filepath.Join(repo.Path, "big.bin"), []byte(strings.Repeat("x", 100)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(repo.Path, "dot_config", "bat"), []byte("changed"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	run("add", ".")
+	run("-c", "user.email=test@example.com", "-c", "user.name=Test", "commit", "-m", "add big file")
+	id := run("rev-parse", "HEAD")
+
+	// Only commits not reachable from a reference are new.
+	commits, err := repo.NewCommits(id)
+	if err != nil {
+		t.Fatalf("NewCommits failed: %v", err)
+	}
+	if len(commits) != 0 {
+		t.Fatalf("NewCommits returned %d commits, want 0", len(commits))
+	}
+
+	run("reset", "--hard", "HEAD~1")
+	commits, err = repo.NewCommits(id)
+	if err != nil {
+		t.Fatalf("NewCommits failed: %v", err)
+	}
+	if len(commits) != 1 || commits[0].ID.String() != id {
+		t.Fatalf("NewCommits = %v, want [%s]", commits, id)
+	}
+
+	files, err := repo.ChangedFiles(commits[0])
+	if err != nil {
+		t.Fatalf("ChangedFiles failed: %v", err)
+	}
+	want := []ChangedFile{
+		{Path: "big.bin", Size: 100},
+		{Path: "dot_config/bat", Size: 7},
+	}
+	if len(files) != len(want) {
+		t.Fatalf("ChangedFiles = %v, want %v", files, want)
+	}
+	for i, f := range files {
+		if f.Path != want[i].Path || f.Size != want[i].Size || f.ID == "" {
+			t.Errorf("ChangedFiles[%d] = %+v, want path %q size %d", i, f, want[i].Path, want[i].Size)
+		}
+	}
+}
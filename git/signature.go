@@ -0,0 +1,168 @@
+package git
+
+import (
+	"bytes"
+
+	"github.com/aymanbagabas/git-module"
+)
+
+// ObjectSignature is the signature of a signed commit or tag.
+type ObjectSignature struct {
+	// Signature is the armored signature.
+	Signature []byte
+	// Payload is the signed content, the object without its signature.
+	Payload []byte
+	// Committer is the committer of a commit or the tagger of a tag, without
+	// a date.
+	Committer *Signature
+}
+
+// signatureHeaders are the first lines of the signatures git knows about.
+var signatureHeaders = [][]byte{
+	[]byte("-----BEGIN PGP SIGNATURE-----"),
+	[]byte("-----BEGIN PGP MESSAGE-----"),
+	[]byte("-----BEGIN SIGNED MESSAGE-----"),
+	[]byte("-----BEGIN SSH SIGNATURE-----"),
+}
+
+// CommitSignature returns the signature of the commit with the given ID, or
+// nil if the commit isn't signed.
+func (r *Repository) CommitSignature(id string) (*ObjectSignature, error) {
+	raw, err := NewCommand("cat-file", "commit", id).RunInDir(r.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseCommitSignature(raw), nil
+}
+
+// TagSignature returns the signature of the tag with the given name, or nil
+// if the tag isn't signed. Lightweight tags are never signed.
+func (r *Repository) TagSignature(name string) (*ObjectSignature, error) {
+	ref := RefsTags + name
+	typ, err := r.CatFileType(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	if typ != git.ObjectTag {
+		return nil, nil
+	}
+
+	raw, err := NewCommand("cat-file", "tag", ref).RunInDir(r.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseTagSignature(raw), nil
+}
+
+// Commits carry their signature in a gpgsig header, or gpgsig-sha256 for the
+// SHA-256 form of the commit. Both are left out of what is signed.
+var (
+	commitSignatureHeader       = []byte("gpgsig")
+	commitSignatureHeaderSHA256 = []byte("gpgsig-sha256")
+)
+
+// parseCommitSignature splits a raw commit object into its signature header
+// and the rest of the commit, which is what the signature signs. The header
+// matching the object format of the commit is preferred.
+func parseCommitSignature(raw []byte) *ObjectSignature {
+	headers, body, hasBody := bytes.Cut(raw, []byte("\n\n"))
+
+	sigs := make(map[string]*bytes.Buffer)
+	var payload bytes.Buffer
+	var cur *bytes.Buffer
+	for _, line := range bytes.Split(headers, []byte("\n")) {
+		if cur != nil && bytes.HasPrefix(line, []byte(" ")) {
+			cur.Write(line[1:])
+			cur.WriteByte('\n')
+			continue
+		}
+
+		cur = nil
+		name, v, _ := bytes.Cut(line, []byte(" "))
+		if bytes.Equal(name, commitSignatureHeader) || bytes.Equal(name, commitSignatureHeaderSHA256) {
+			cur = new(bytes.Buffer)
+			cur.Write(v)
+			cur.WriteByte('\n')
+			sigs[string(name)] = cur
+			continue
+		}
+
+		payload.Write(line)
+		payload.WriteByte('\n')
+	}
+
+	// Object IDs of SHA-256 repositories are 64 hex characters long.
+	preferred, other := commitSignatureHeader, commitSignatureHeaderSHA256
+	if tree, ok := bytes.CutPrefix(headers, []byte("tree ")); ok && bytes.IndexByte(tree, '\n') == 64 {
+		preferred, other = other, preferred
+	}
+
+	sig, ok := sigs[string(preferred)]
+	if !ok {
+		sig, ok = sigs[string(other)]
+	}
+	if !ok {
+		return nil
+	}
+
+	if hasBody {
+		payload.WriteByte('\n')
+		payload.Write(body)
+	}
+
+	return &ObjectSignature{
+		Signature: sig.Bytes(),
+		Payload:   payload.Bytes(),
+		Committer: parseIdentity(headers, "committer"),
+	}
+}
+
+// parseTagSignature splits a raw tag object into the signature appended to
+// its message and the rest of the tag, which is what the signature signs.
+func parseTagSignature(raw []byte) *ObjectSignature {
+	for i := 0; i < len(raw); {
+		line := raw[i:]
+		if j := bytes.IndexByte(line, '\n'); j >= 0 {
+			line = line[:j]
+		}
+
+		for _, h := range signatureHeaders {
+			if bytes.HasPrefix(line, h) {
+				return &ObjectSignature{
+					Signature: raw[i:],
+					Payload:   raw[:i],
+					Committer: parseIdentity(raw[:i], "tagger"),
+				}
+			}
+		}
+
+		i += len(line) + 1
+	}
+
+	return nil
+}
+
+// parseIdentity returns the name and email of the first header with the
+// given name in a raw object, e.g. "committer Name <email> 1700000000 +0000".
+func parseIdentity(raw []byte, header string) *Signature {
+	headers, _, _ := bytes.Cut(raw, []byte("\n\n"))
+	for _, line := range bytes.Split(headers, []byte("\n")) {
+		v, ok := bytes.CutPrefix(line, []byte(header+" "))
+		if !ok {
+			continue
+		}
+
+		name, rest, ok := bytes.Cut(v, []byte(" <"))
+		if !ok {
+			return nil
+		}
+
+		email, _, _ := bytes.Cut(rest, []byte(">"))
+		return &Signature{Name: string(name), Email: string(email)}
+	}
+
+	return nil
+}
@@ -0,0 +1,127 @@
+package git
+
+import "testing"
+
+func TestParseCommitSignature(t *testing.T) {
+	raw := "tree 4b825dc642cb6eb9a060e54bf8d69288fbee4904\n" +
+		"author Test <test@example.com> 1700000000 +0000\n" +
+		"committer Test <test@example.com> 1700000000 +0000\n" +
+		"gpgsig -----BEGIN SSH SIGNATURE-----\n" +
+		" U1NIU0lH\n" +
+		" \n" +
+		" -----END SSH SIGNATURE-----\n" +
+		"\n" +
+		"init\n"
+
+	sig := parseCommitSignature([]byte(raw))
+	if sig == nil {
+		t.Fatal("parseCommitSignature() = nil, want a signature")
+	}
+
+	wantSig := "-----BEGIN SSH SIGNATURE-----\nU1NIU0lH\n\n-----END SSH SIGNATURE-----\n"
+	if string(sig.Signature) != wantSig {
+		t.Errorf("signature = %q, want %q", sig.Signature, wantSig)
+	}
+
+	wantPayload := "tree 4b825dc642cb6eb9a060e54bf8d69288fbee4904\n" +
+		"author Test <test@example.com> 1700000000 +0000\n" +
+		"committer Test <test@example.com> 1700000000 +0000\n" +
+		"\n" +
+		"init\n"
+	if string(sig.Payload) != wantPayload {
+		t.Errorf("payload = %q, want %q", sig.Payload, wantPayload)
+	}
+
+	if c := sig.Committer; c == nil || c.Name != "Test" || c.Email != "test@example.com" {
+		t.Errorf("committer = %+v, want Test <test@example.com>", c)
+	}
+
+	if sig := parseCommitSignature([]byte(wantPayload)); sig != nil {
+		t.Errorf("parseCommitSignature() of an unsigned commit = %+v, want nil", sig)
+	}
+}
+
+func TestParseCommitSignatureSHA256(t *testing.T) {
+	sha1Tree := "tree 4b825dc642cb6eb9a060e54bf8d69288fbee4904\n"
+	sha256Tree := "tree 6ef19b41225c5369f1c104d45d8d85efa9b057b53b14b4b9b939dd74decc5321\n"
+	headers := "author Test <test@example.com> 1700000000 +0000\n" +
+		"committer Test <test@example.com> 1700000000 +0000\n"
+	sigs := "gpgsig -----BEGIN SSH SIGNATURE-----\n" +
+		" sha1\n" +
+		" -----END SSH SIGNATURE-----\n" +
+		"gpgsig-sha256 -----BEGIN SSH SIGNATURE-----\n" +
+		" sha256\n" +
+		" -----END SSH SIGNATURE-----\n"
+	body := "\ninit\n"
+
+	for _, c := range []struct {
+		name, raw, payload, sig string
+	}{
+		{
+			name:    "dual signed sha1",
+			raw:     sha1Tree + headers + sigs + body,
+			payload: sha1Tree + headers + body,
+			sig:     "sha1",
+		},
+		{
+			name:    "dual signed sha256",
+			raw:     sha256Tree + headers + sigs + body,
+			payload: sha256Tree + headers + body,
+			sig:     "sha256",
+		},
+		{
+			name: "sha256 only",
+			raw: sha256Tree + headers +
+				"gpgsig-sha256 -----BEGIN SSH SIGNATURE-----\n sha256\n -----END SSH SIGNATURE-----\n" + body,
+			payload: sha256Tree + headers + body,
+			sig:     "sha256",
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			sig := parseCommitSignature([]byte(c.raw))
+			if sig == nil {
+				t.Fatal("parseCommitSignature() = nil, want a signature")
+			}
+
+			want := "-----BEGIN SSH SIGNATURE-----\n" + c.sig + "\n-----END SSH SIGNATURE-----\n"
+			if string(sig.Signature) != want {
+				t.Errorf("signature = %q, want %q", sig.Signature, want)
+			}
+
+			if string(sig.Payload) != c.payload {
+				t.Errorf("payload = %q, want %q", sig.Payload, c.payload)
+			}
+		})
+	}
+}
+
+func TestParseTagSignature(t *testing.T) {
+	payload := "object 4b825dc642cb6eb9a060e54bf8d69288fbee4904\n" +
+		"type commit\n" +
+		"tag v1.0.0\n" +
+		"tagger Test <test@example.com> 1700000000 +0000\n" +
+		"\n" +
+		"release\n"
+	signature := "-----BEGIN SSH SIGNATURE-----\nU1NIU0lH\n-----END SSH SIGNATURE-----\n"
+
+	sig := parseTagSignature([]byte(payload + signature))
+	if sig == nil {
+		t.Fatal("parseTagSignature() = nil, want a signature")
+	}
+
+	if string(sig.Signature) != signature {
+		t.Errorf("signature = %q, want %q", sig.Signature, signature)
+	}
+
+	if string(sig.Payload) != payload {
+		t.Errorf("payload = %q, want %q", sig.Payload, payload)
+	}
+
+	if c := sig.Committer; c == nil || c.Name != "Test" || c.Email != "test@example.com" {
+		t.Errorf("tagger = %+v, want Test <test@example.com>", c)
+	}
+
+	if sig := parseTagSignature([]byte(payload)); sig != nil {
+		t.Errorf("parseTagSignature() of an unsigned tag = %+v, want nil", sig)
+	}
+}
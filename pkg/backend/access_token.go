@@ -3,30 +3,56 @@ package backend
 import (
 	"context"
 	"errors"
+	"strings"
 	"time"
 
+	"github.com/charmbracelet/soft-serve/pkg/access"
 	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/db/models"
 	"github.com/charmbracelet/soft-serve/pkg/proto"
 	"github.com/charmbracelet/soft-serve/pkg/utils"
 )
 
-// CreateAccessToken creates an access token for user.
-func (b *Backend) CreateAccessToken(ctx context.Context, user proto.User, name string, expiresAt time.Time) (string, error) {
+// CreateAccessToken creates an access token for user. The token has all the
+// scopes and works with any repository unless opts restrict it.
+func (b *Backend) CreateAccessToken(ctx context.Context, user proto.User, name string, expiresAt time.Time, opts proto.AccessTokenOptions) (string, error) {
 	token := GenerateToken()
 	tokenHash := HashToken(token)
 	name = utils.Sanitize(name)
 
+	scopes := make([]string, 0, len(opts.Scopes))
+	for _, s := range opts.Scopes {
+		if _, err := proto.ParseAccessTokenScope(string(s)); err != nil {
+			return "", err
+		}
+		scopes = append(scopes, string(s))
+	}
+
+	repos := make([]string, 0, len(opts.Repos))
+	for _, r := range opts.Repos {
+		r = utils.SanitizeRepo(r)
+		if err := utils.ValidateRepo(r); err != nil {
+			return "", err
+		}
+		repos = append(repos, r)
+	}
+
+	var id int64
 	if err := b.db.TransactionContext(ctx, func(tx *db.Tx) error {
-		_, err := b.store.CreateAccessToken(ctx, tx, name, user.ID(), tokenHash, expiresAt)
+		t, err := b.store.CreateAccessToken(ctx, tx, name, user.ID(), tokenHash, expiresAt,
+			strings.Join(scopes, "\n"), strings.Join(repos, "\n"))
 		if err != nil {
 			return db.WrapError(err)
 		}
 
+		id = t.ID
 		return nil
 	}); err != nil {
 		return "", err
 	}
 
+	b.audit(ctx, AuditTokenCreate, user.Username(), "id", id, "name", name,
+		"scopes", strings.Join(scopes, ","), "repos", strings.Join(repos, ","))
 	return token, nil
 }
 
@@ -50,6 +76,7 @@ func (b *Backend) DeleteAccessToken(ctx context.Context, user proto.User, id int
 		return err
 	}
 
+	b.audit(ctx, AuditTokenDelete, user.Username(), "id", id)
 	return nil
 }
 
@@ -62,19 +89,139 @@ func (b *Backend) ListAccessTokens(ctx context.Context, user proto.User) ([]prot
 
 	var tokens []proto.AccessToken
 	for _, t := range accessTokens {
-		token := proto.AccessToken{
-			ID:        t.ID,
-			Name:      t.Name,
-			TokenHash: t.Token,
-			UserID:    t.UserID,
-			CreatedAt: t.CreatedAt,
+		tokens = append(tokens, accessTokenFromModel(t))
+	}
+
+	return tokens, nil
+}
+
+// RecordAccessTokenUse records that an access token authenticated a request
+// from addr.
+func (b *Backend) RecordAccessTokenUse(ctx context.Context, id int64, addr string) error {
+	return db.WrapError(b.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		return b.store.TouchAccessToken(ctx, tx, id, addr)
+	}))
+}
+
+// OwnedAccessToken is an access token along with the username of its user.
+type OwnedAccessToken struct {
+	proto.AccessToken
+	Username string
+}
+
+// AllAccessTokens lists the access tokens of all users.
+func (b *Backend) AllAccessTokens(ctx context.Context) ([]OwnedAccessToken, error) {
+	var tokens []OwnedAccessToken
+	if err := b.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		users, err := b.store.GetAllUsers(ctx, tx)
+		if err != nil {
+			return err
+		}
+
+		usernames := make(map[int64]string, len(users))
+		for _, u := range users {
+			usernames[u.ID] = u.Username
 		}
-		if t.ExpiresAt.Valid {
-			token.ExpiresAt = t.ExpiresAt.Time
+
+		ms, err := b.store.GetAllAccessTokens(ctx, tx)
+		if err != nil {
+			return err
+		}
+
+		for _, m := range ms {
+			tokens = append(tokens, OwnedAccessToken{
+				AccessToken: accessTokenFromModel(m),
+				Username:    usernames[m.UserID],
+			})
 		}
 
-		tokens = append(tokens, token)
+		return nil
+	}); err != nil {
+		return nil, db.WrapError(err)
 	}
 
 	return tokens, nil
 }
+
+// RevokeAccessTokens deletes access tokens of any user.
+func (b *Backend) RevokeAccessTokens(ctx context.Context, tokens []OwnedAccessToken) error {
+	if err := b.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		for _, t := range tokens {
+			if err := b.store.DeleteAccessToken(ctx, tx, t.ID); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return db.WrapError(err)
+	}
+
+	for _, t := range tokens {
+		b.audit(ctx, AuditTokenDelete, t.Username, "id", t.ID, "name", t.Name)
+	}
+
+	return nil
+}
+
+// UserAccessToken returns the access token a user authenticated with, or nil
+// if they authenticated otherwise.
+func UserAccessToken(u proto.User) *proto.AccessToken {
+	if u, ok := u.(*user); ok {
+		return u.token
+	}
+
+	return nil
+}
+
+func accessTokenFromModel(t models.AccessToken) proto.AccessToken {
+	token := proto.AccessToken{
+		ID:        t.ID,
+		Name:      t.Name,
+		TokenHash: t.Token,
+		UserID:    t.UserID,
+		CreatedAt: t.CreatedAt,
+	}
+	if t.ExpiresAt.Valid {
+		token.ExpiresAt = t.ExpiresAt.Time
+	}
+	if t.Scopes != "" {
+		for _, s := range strings.Split(t.Scopes, "\n") {
+			token.Scopes = append(token.Scopes, proto.AccessTokenScope(s))
+		}
+	}
+	if t.Repos != "" {
+		token.Repos = strings.Split(t.Repos, "\n")
+	}
+	if t.LastUsedAt.Valid {
+		token.LastUsedAt = t.LastUsedAt.Time
+	}
+	token.LastUsedAddr = t.LastUsedAddr
+	token.UseCount = t.UseCount
+
+	return token
+}
+
+// restrictAccessLevel lowers an access level to what a token allows on a
+// repository.
+func restrictAccessLevel(t *proto.AccessToken, repo string, level access.AccessLevel) access.AccessLevel {
+	if !t.AllowsRepository(utils.SanitizeRepo(repo)) {
+		return access.NoAccess
+	}
+
+	limit := access.NoAccess
+	switch {
+	case t.HasScope(proto.AccessTokenScopeAdmin):
+		limit = access.AdminAccess
+	case t.HasScope(proto.AccessTokenScopeWrite):
+		limit = access.ReadWriteAccess
+	case t.HasScope(proto.AccessTokenScopeRead):
+		limit = access.ReadOnlyAccess
+	}
+
+	if level > limit {
+		return limit
+	}
+
+	return level
+}
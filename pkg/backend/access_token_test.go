@@ -0,0 +1,145 @@
+package backend
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/soft-serve/pkg/access"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/matryer/is"
+)
+
+func TestAccessTokenScopes(t *testing.T) {
+	is := is.New(t)
+	be, _ := newTestBackend(t)
+	ctx := context.Background()
+
+	alice, err := be.CreateUser(ctx, "alice", proto.UserOptions{})
+	is.NoErr(err)
+	_, err = be.CreateRepository(ctx, "r1", alice, proto.RepositoryOptions{})
+	is.NoErr(err)
+	_, err = be.CreateRepository(ctx, "r2", alice, proto.RepositoryOptions{})
+	is.NoErr(err)
+
+	levels := func(opts proto.AccessTokenOptions) (r1, r2, missing access.AccessLevel) {
+		token, err := be.CreateAccessToken(ctx, alice, "test", time.Time{}, opts)
+		is.NoErr(err)
+		user, err := be.UserByAccessToken(ctx, token)
+		is.NoErr(err)
+		return be.AccessLevelForUser(ctx, "r1", user),
+			be.AccessLevelForUser(ctx, "r2", user),
+			be.AccessLevelForUser(ctx, "missing", user)
+	}
+
+	// Tokens carry the full power of their user by default.
+	r1, r2, missing := levels(proto.AccessTokenOptions{})
+	is.Equal(r1, access.AdminAccess)
+	is.Equal(r2, access.AdminAccess)
+	is.Equal(missing, access.ReadWriteAccess)
+
+	// Scopes cap the access level.
+	r1, r2, missing = levels(proto.AccessTokenOptions{Scopes: []proto.AccessTokenScope{proto.AccessTokenScopeRead}})
+	is.Equal(r1, access.ReadOnlyAccess)
+	is.Equal(r2, access.ReadOnlyAccess)
+	is.Equal(missing, access.ReadOnlyAccess)
+
+	r1, _, _ = levels(proto.AccessTokenOptions{Scopes: []proto.AccessTokenScope{proto.AccessTokenScopeWrite, proto.AccessTokenScopeLFS}})
+	is.Equal(r1, access.ReadWriteAccess)
+
+	r1, _, _ = levels(proto.AccessTokenOptions{Scopes: []proto.AccessTokenScope{proto.AccessTokenScopeLFS}})
+	is.Equal(r1, access.NoAccess)
+
+	// Restricted tokens don't work with other repositories, not even to
+	// create them.
+	r1, r2, missing = levels(proto.AccessTokenOptions{
+		Scopes: []proto.AccessTokenScope{proto.AccessTokenScopeRead},
+		Repos:  []string{"/r1.git"},
+	})
+	is.Equal(r1, access.ReadOnlyAccess)
+	is.Equal(r2, access.NoAccess)
+	is.Equal(missing, access.NoAccess)
+
+	_, err = be.CreateAccessToken(ctx, alice, "test", time.Time{}, proto.AccessTokenOptions{Scopes: []proto.AccessTokenScope{"everything"}})
+	is.True(errors.Is(err, proto.ErrInvalidTokenScope))
+
+	tokens, err := be.ListAccessTokens(ctx, alice)
+	is.NoErr(err)
+	is.Equal(len(tokens), 5)
+	is.Equal(len(tokens[0].Scopes), 0)
+	is.Equal(tokens[4].Scopes, []proto.AccessTokenScope{proto.AccessTokenScopeRead})
+	is.Equal(tokens[4].Repos, []string{"r1"})
+}
+
+func TestAccessTokenAdminScope(t *testing.T) {
+	is := is.New(t)
+	be, _ := newTestBackend(t)
+	ctx := context.Background()
+
+	root, err := be.CreateUser(ctx, "root", proto.UserOptions{Admin: true})
+	is.NoErr(err)
+	bob, err := be.CreateUser(ctx, "bob", proto.UserOptions{})
+	is.NoErr(err)
+	_, err = be.CreateRepository(ctx, "secret", bob, proto.RepositoryOptions{Private: true})
+	is.NoErr(err)
+
+	user := func(opts proto.AccessTokenOptions) proto.User {
+		token, err := be.CreateAccessToken(ctx, root, "test", time.Time{}, opts)
+		is.NoErr(err)
+		u, err := be.UserByAccessToken(ctx, token)
+		is.NoErr(err)
+		return u
+	}
+
+	u := user(proto.AccessTokenOptions{})
+	is.True(u.IsAdmin())
+	is.Equal(be.AccessLevelForUser(ctx, "secret", u), access.AdminAccess)
+
+	// Admins keep their reach over all repositories with a narrower token,
+	// but the token isn't an admin one.
+	u = user(proto.AccessTokenOptions{Scopes: []proto.AccessTokenScope{proto.AccessTokenScopeRead}})
+	is.True(!u.IsAdmin())
+	is.Equal(be.AccessLevelForUser(ctx, "secret", u), access.ReadOnlyAccess)
+
+	u = user(proto.AccessTokenOptions{Scopes: []proto.AccessTokenScope{proto.AccessTokenScopeAdmin}, Repos: []string{"secret"}})
+	is.True(!u.IsAdmin())
+	is.Equal(be.AccessLevelForUser(ctx, "secret", u), access.AdminAccess)
+}
+
+func TestAccessTokenUsage(t *testing.T) {
+	is := is.New(t)
+	be, _ := newTestBackend(t)
+	ctx := context.Background()
+
+	alice, err := be.CreateUser(ctx, "alice", proto.UserOptions{})
+	is.NoErr(err)
+	bob, err := be.CreateUser(ctx, "bob", proto.UserOptions{})
+	is.NoErr(err)
+	_, err = be.CreateAccessToken(ctx, alice, "a", time.Time{}, proto.AccessTokenOptions{})
+	is.NoErr(err)
+	_, err = be.CreateAccessToken(ctx, bob, "b", time.Time{}, proto.AccessTokenOptions{})
+	is.NoErr(err)
+
+	tokens, err := be.AllAccessTokens(ctx)
+	is.NoErr(err)
+	is.Equal(len(tokens), 2)
+	is.Equal(tokens[0].Username, "alice")
+	is.Equal(tokens[1].Username, "bob")
+	is.True(tokens[0].LastUsedAt.IsZero())
+	is.Equal(tokens[0].LastActiveAt(), tokens[0].CreatedAt)
+
+	is.NoErr(be.RecordAccessTokenUse(ctx, tokens[0].ID, "192.0.2.1"))
+	is.NoErr(be.RecordAccessTokenUse(ctx, tokens[0].ID, "192.0.2.2"))
+	at, err := be.ListAccessTokens(ctx, alice)
+	is.NoErr(err)
+	is.Equal(at[0].UseCount, int64(2))
+	is.Equal(at[0].LastUsedAddr, "192.0.2.2")
+	is.True(!at[0].LastUsedAt.IsZero())
+
+	is.NoErr(be.RevokeAccessTokens(ctx, tokens[1:]))
+	tokens, err = be.AllAccessTokens(ctx)
+	is.NoErr(err)
+	is.Equal(len(tokens), 1)
+	is.Equal(tokens[0].Name, "a")
+}
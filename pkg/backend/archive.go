@@ -0,0 +1,235 @@
+package backend
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/config"
+	"github.com/charmbracelet/soft-serve/pkg/lfs"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/storage"
+	"github.com/charmbracelet/soft-serve/pkg/utils"
+)
+
+// lfsPointerMaxSize is the maximum size of a Git LFS pointer file.
+const lfsPointerMaxSize = 1024
+
+// ArchiveOptions are options for ArchiveRepository.
+type ArchiveOptions struct {
+	// Format is the archive format, git.ArchiveTarGz or git.ArchiveZip.
+	Format string
+	// Prefix is prepended to every path in the archive, e.g. "project/".
+	Prefix string
+	// LFS replaces Git LFS pointer files with their objects from the LFS
+	// store. Pointers to missing objects are kept as is.
+	LFS bool
+}
+
+// ArchiveRepository writes an archive of the tree of ref to w. An empty ref
+// archives HEAD.
+//
+// The ref is resolved before anything is written, so errors about the
+// repository, the ref, or the options leave w untouched.
+func (d *Backend) ArchiveRepository(ctx context.Context, w io.Writer, repo string, ref string, opts ArchiveOptions) error {
+	switch opts.Format {
+	case git.ArchiveTarGz, git.ArchiveZip:
+	default:
+		return fmt.Errorf("%w: %q", proto.ErrInvalidArchiveFormat, opts.Format)
+	}
+
+	repo = utils.SanitizeRepo(repo)
+	r, err := d.Repository(ctx, repo)
+	if err != nil {
+		return err
+	}
+
+	rr, err := r.Open()
+	if err != nil {
+		return err
+	}
+
+	if ref == "" {
+		ref = "HEAD"
+	}
+
+	if strings.HasPrefix(ref, "-") {
+		return git.ErrRevisionNotExist
+	}
+
+	c, err := rr.CatFileCommit(ref + "^{commit}")
+	if err != nil {
+		return git.ErrRevisionNotExist
+	}
+
+	if !opts.LFS || !d.cfg.LFS.Enabled {
+		return rr.Archive(w, c.ID.String(), git.ArchiveOptions{
+			Format: opts.Format,
+			Prefix: opts.Prefix,
+		})
+	}
+
+	strg, err := config.LFSStorage(d.cfg, r.ID())
+	if err != nil {
+		return err
+	}
+
+	// Re-encode a tar archive from git, swapping pointers for LFS objects
+	// on the way.
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(rr.Archive(pw, c.ID.String(), git.ArchiveOptions{
+			Format: git.ArchiveTar,
+			Prefix: opts.Prefix,
+		}))
+	}()
+	defer pr.Close() //nolint: errcheck
+
+	var aw archiveWriter
+	switch opts.Format {
+	case git.ArchiveTarGz:
+		aw = newTarGzArchiveWriter(w)
+	case git.ArchiveZip:
+		aw = newZipArchiveWriter(w)
+	}
+
+	tr := tar.NewReader(pr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := writeArchiveEntry(aw, strg, hdr, tr); err != nil {
+			return err
+		}
+	}
+
+	return aw.Close()
+}
+
+// writeArchiveEntry writes the tar entry hdr with the contents r to aw. If the
+// entry is an LFS pointer with its object in strg, the object is written
+// instead.
+func writeArchiveEntry(aw archiveWriter, strg storage.Storage, hdr *tar.Header, r io.Reader) error {
+	if hdr.Typeflag != tar.TypeReg || hdr.Size > lfsPointerMaxSize {
+		return aw.WriteEntry(hdr, r)
+	}
+
+	buf, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	p, err := lfs.ReadPointerFromBuffer(buf)
+	if err != nil || !p.IsValid() {
+		return aw.WriteEntry(hdr, bytes.NewReader(buf))
+	}
+
+	name := path.Join("objects", p.RelativePath())
+	fi, err := strg.Stat(name)
+	if err != nil || fi.Size() != p.Size {
+		return aw.WriteEntry(hdr, bytes.NewReader(buf))
+	}
+
+	obj, err := strg.Open(name)
+	if err != nil {
+		return err
+	}
+
+	defer obj.Close() //nolint: errcheck
+	hdr.Size = p.Size
+	return aw.WriteEntry(hdr, obj)
+}
+
+// archiveWriter writes the entries of a tar archive in another format.
+type archiveWriter interface {
+	WriteEntry(hdr *tar.Header, r io.Reader) error
+	Close() error
+}
+
+type tarGzArchiveWriter struct {
+	gz *gzip.Writer
+	tw *tar.Writer
+}
+
+func newTarGzArchiveWriter(w io.Writer) *tarGzArchiveWriter {
+	gz := gzip.NewWriter(w)
+	return &tarGzArchiveWriter{gz: gz, tw: tar.NewWriter(gz)}
+}
+
+// WriteEntry implements archiveWriter.
+func (a *tarGzArchiveWriter) WriteEntry(hdr *tar.Header, r io.Reader) error {
+	if err := a.tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+
+	_, err := io.Copy(a.tw, r)
+	return err
+}
+
+// Close implements archiveWriter.
+func (a *tarGzArchiveWriter) Close() error {
+	if err := a.tw.Close(); err != nil {
+		return err
+	}
+
+	return a.gz.Close()
+}
+
+type zipArchiveWriter struct {
+	zw *zip.Writer
+}
+
+func newZipArchiveWriter(w io.Writer) *zipArchiveWriter {
+	return &zipArchiveWriter{zw: zip.NewWriter(w)}
+}
+
+// WriteEntry implements archiveWriter.
+func (a *zipArchiveWriter) WriteEntry(hdr *tar.Header, r io.Reader) error {
+	switch hdr.Typeflag {
+	case tar.TypeXGlobalHeader:
+		// Git stores the commit ID in the global header, and in the comment
+		// of zip archives.
+		if id, ok := hdr.PAXRecords["comment"]; ok {
+			return a.zw.SetComment(id)
+		}
+		return nil
+	case tar.TypeSymlink:
+		r = strings.NewReader(hdr.Linkname)
+	}
+
+	fh, err := zip.FileInfoHeader(hdr.FileInfo())
+	if err != nil {
+		return err
+	}
+
+	fh.Name = hdr.Name
+	fh.Modified = hdr.ModTime
+	if hdr.Typeflag == tar.TypeReg {
+		fh.Method = zip.Deflate
+	}
+
+	fw, err := a.zw.CreateHeader(fh)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(fw, r)
+	return err
+}
+
+// Close implements archiveWriter.
+func (a *zipArchiveWriter) Close() error {
+	return a.zw.Close()
+}
@@ -0,0 +1,127 @@
+package backend
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/lfs"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/storage"
+	"github.com/matryer/is"
+)
+
+func TestArchiveRepository(t *testing.T) {
+	is := is.New(t)
+	be, cfg := newTestBackend(t)
+	cfg.LFS.Enabled = true
+	ctx := context.Background()
+
+	repo, err := be.CreateRepository(ctx, "repo", nil, proto.RepositoryOptions{})
+	is.NoErr(err)
+
+	// One pointer has its object in the LFS store, the other doesn't.
+	object := "large file contents"
+	sum := sha256.Sum256([]byte(object))
+	stored := lfs.Pointer{Oid: hex.EncodeToString(sum[:]), Size: int64(len(object))}
+	missing := lfs.Pointer{Oid: strings.Repeat("b", 64), Size: 42}
+	strg := storage.NewLocalStorage(filepath.Join(cfg.DataPath, "lfs", strconv.FormatInt(repo.ID(), 10)))
+	_, err = strg.Put(path.Join("objects", stored.RelativePath()), strings.NewReader(object))
+	is.NoErr(err)
+
+	work := t.TempDir()
+	gitOutput(t, work, "init", "-q", "-b", "main")
+	is.NoErr(os.MkdirAll(filepath.Join(work, "dir"), os.ModePerm))
+	is.NoErr(os.WriteFile(filepath.Join(work, "README.md"), []byte("hello\n"), 0o644))
+	is.NoErr(os.WriteFile(filepath.Join(work, "dir", "stored.bin"), []byte(stored.String()), 0o644))
+	is.NoErr(os.WriteFile(filepath.Join(work, "dir", "missing.bin"), []byte(missing.String()), 0o644))
+	gitOutput(t, work, "add", ".")
+	gitOutput(t, work, "commit", "-q", "-m", "first")
+	gitOutput(t, work, "tag", "v1")
+	gitOutput(t, be.repoPath("repo"), "fetch", "-q", work, "refs/heads/main:refs/heads/main", "refs/tags/v1:refs/tags/v1")
+	gitOutput(t, be.repoPath("repo"), "symbolic-ref", "HEAD", "refs/heads/main")
+
+	readTarGz := func(b []byte) map[string]string {
+		t.Helper()
+		gz, err := gzip.NewReader(bytes.NewReader(b))
+		is.NoErr(err)
+		files := map[string]string{}
+		tr := tar.NewReader(gz)
+		for {
+			hdr, err := tr.Next()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			is.NoErr(err)
+			if hdr.Typeflag == tar.TypeReg {
+				data, err := io.ReadAll(tr)
+				is.NoErr(err)
+				files[hdr.Name] = string(data)
+			}
+		}
+		return files
+	}
+	readZip := func(b []byte) map[string]string {
+		t.Helper()
+		zr, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+		is.NoErr(err)
+		files := map[string]string{}
+		for _, f := range zr.File {
+			if f.FileInfo().IsDir() {
+				continue
+			}
+			rc, err := f.Open()
+			is.NoErr(err)
+			data, err := io.ReadAll(rc)
+			is.NoErr(err)
+			rc.Close() //nolint: errcheck
+			files[f.Name] = string(data)
+		}
+		return files
+	}
+
+	var buf bytes.Buffer
+	is.NoErr(be.ArchiveRepository(ctx, &buf, "repo", "v1", ArchiveOptions{Format: git.ArchiveTarGz, Prefix: "repo-v1/"}))
+	is.Equal(readTarGz(buf.Bytes()), map[string]string{
+		"repo-v1/README.md":       "hello\n",
+		"repo-v1/dir/missing.bin": missing.String(),
+		"repo-v1/dir/stored.bin":  stored.String(),
+	})
+
+	// LFS pointers are replaced with the objects in the store.
+	for _, format := range []string{git.ArchiveTarGz, git.ArchiveZip} {
+		buf.Reset()
+		is.NoErr(be.ArchiveRepository(ctx, &buf, "repo", "", ArchiveOptions{Format: format, LFS: true}))
+		read := readTarGz
+		if format == git.ArchiveZip {
+			read = readZip
+		}
+		is.Equal(read(buf.Bytes()), map[string]string{
+			"README.md":       "hello\n",
+			"dir/missing.bin": missing.String(),
+			"dir/stored.bin":  object,
+		})
+	}
+
+	buf.Reset()
+	err = be.ArchiveRepository(ctx, &buf, "repo", "nope", ArchiveOptions{Format: git.ArchiveZip})
+	is.True(errors.Is(err, git.ErrRevisionNotExist))
+	err = be.ArchiveRepository(ctx, &buf, "repo", "--output=/tmp/x", ArchiveOptions{Format: git.ArchiveZip})
+	is.True(errors.Is(err, git.ErrRevisionNotExist))
+	err = be.ArchiveRepository(ctx, &buf, "repo", "main", ArchiveOptions{Format: "rar"})
+	is.True(errors.Is(err, proto.ErrInvalidArchiveFormat))
+	is.Equal(buf.Len(), 0)
+}
@@ -0,0 +1,242 @@
+package backend
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/db/models"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+)
+
+// AuditAction is the kind of an audited action. Actions are namespaced by
+// what they act on, e.g. "repo.delete".
+type AuditAction string
+
+// Audited actions.
+const (
+	AuditUserCreate       AuditAction = "user.create"
+	AuditUserDelete       AuditAction = "user.delete"
+	AuditUserSetAdmin     AuditAction = "user.set-admin"
+	AuditUserSetUsername  AuditAction = "user.set-username"
+	AuditUserSetPassword  AuditAction = "user.set-password"
+	AuditUserAddPubkey    AuditAction = "user.add-pubkey"
+	AuditUserRemovePubkey AuditAction = "user.remove-pubkey"
+	AuditUserSetQuota     AuditAction = "user.set-quota"
+
+	AuditTokenCreate AuditAction = "token.create"
+	AuditTokenDelete AuditAction = "token.delete"
+
+	AuditSettingsAnonAccess   AuditAction = "settings.anon-access"
+	AuditSettingsAllowKeyless AuditAction = "settings.allow-keyless"
+
+	AuditRepoCreate                  AuditAction = "repo.create"
+	AuditRepoImport                  AuditAction = "repo.import"
+	AuditRepoFork                    AuditAction = "repo.fork"
+	AuditRepoDelete                  AuditAction = "repo.delete"
+	AuditRepoRename                  AuditAction = "repo.rename"
+	AuditRepoSetPrivate              AuditAction = "repo.set-private"
+	AuditRepoSetHidden               AuditAction = "repo.set-hidden"
+	AuditRepoAddCollab               AuditAction = "repo.add-collab"
+	AuditRepoRemoveCollab            AuditAction = "repo.remove-collab"
+	AuditRepoAddDeployKey            AuditAction = "repo.add-deploy-key"
+	AuditRepoRemoveDeployKey         AuditAction = "repo.remove-deploy-key"
+	AuditRepoSetBranchProtection     AuditAction = "repo.set-branch-protection"
+	AuditRepoRemoveBranchProtection  AuditAction = "repo.remove-branch-protection"
+	AuditRepoSetPushPolicy           AuditAction = "repo.set-push-policy"
+	AuditRepoSetQuota                AuditAction = "repo.set-quota"
+	AuditRepoAddPushMirror           AuditAction = "repo.add-push-mirror"
+	AuditRepoRemovePushMirror        AuditAction = "repo.remove-push-mirror"
+	AuditRepoSetMirrorCredentials    AuditAction = "repo.set-mirror-credentials"
+	AuditRepoDeleteMirrorCredentials AuditAction = "repo.delete-mirror-credentials"
+	AuditRepoCreateWebhook           AuditAction = "repo.create-webhook"
+	AuditRepoUpdateWebhook           AuditAction = "repo.update-webhook"
+	AuditRepoDeleteWebhook           AuditAction = "repo.delete-webhook"
+
+	AuditTeamCreate       AuditAction = "team.create"
+	AuditTeamDelete       AuditAction = "team.delete"
+	AuditTeamAddMember    AuditAction = "team.add-member"
+	AuditTeamRemoveMember AuditAction = "team.remove-member"
+	AuditTeamSetAccess    AuditAction = "team.set-access"
+	AuditTeamRemoveAccess AuditAction = "team.remove-access"
+)
+
+// AuditSource is where audited actions come from.
+type AuditSource struct {
+	// Addr is the address of the client.
+	Addr string
+	// Transport is the protocol the client used, e.g. ssh or http.
+	Transport string
+}
+
+// ContextKeyAuditSource is the context key for the audit source.
+var ContextKeyAuditSource = &struct{ string }{"audit-source"}
+
+// AuditSourceFromContext returns the audit source from the context.
+func AuditSourceFromContext(ctx context.Context) AuditSource {
+	if s, ok := ctx.Value(ContextKeyAuditSource).(AuditSource); ok {
+		return s
+	}
+	return AuditSource{}
+}
+
+// WithAuditSourceContext returns a new context with the audit source. The
+// port is dropped from addr.
+func WithAuditSourceContext(ctx context.Context, addr string, transport string) context.Context {
+	return context.WithValue(ctx, ContextKeyAuditSource, NewAuditSource(addr, transport))
+}
+
+// NewAuditSource returns an audit source for a client address, dropping its
+// port.
+func NewAuditSource(addr string, transport string) AuditSource {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		addr = host
+	}
+
+	return AuditSource{Addr: addr, Transport: transport}
+}
+
+// AuditEvent is an entry of the audit log.
+type AuditEvent struct {
+	ID int64 `json:"-"`
+	// Actor is the username of the user who took the action, empty for
+	// anonymous users and the server itself.
+	Actor  string      `json:"actor"`
+	Action AuditAction `json:"action"`
+	Target string      `json:"target"`
+	// Details are space separated key=value pairs.
+	Details   string    `json:"details,omitempty"`
+	Addr      string    `json:"addr,omitempty"`
+	Transport string    `json:"transport,omitempty"`
+	CreatedAt time.Time `json:"time"`
+}
+
+// AuditFilter selects audit log events. Zero fields match any event.
+type AuditFilter struct {
+	Actor string
+	// Action matches the action and the actions it namespaces, "repo"
+	// matches "repo.delete".
+	Action string
+	Target string
+	Since  time.Time
+	// Limit is the maximum number of events, 0 for no limit.
+	Limit int
+}
+
+// AuditEvents returns the audit log events matching a filter, most recent
+// first.
+func (d *Backend) AuditEvents(ctx context.Context, f AuditFilter) ([]AuditEvent, error) {
+	var ms []models.AuditEvent
+	if err := d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		var err error
+		ms, err = d.store.GetAuditEvents(ctx, tx, f.Actor, f.Action, f.Target, f.Since, f.Limit)
+		return err
+	}); err != nil {
+		return nil, db.WrapError(err)
+	}
+
+	events := make([]AuditEvent, len(ms))
+	for i, m := range ms {
+		events[i] = AuditEvent{
+			ID:        m.ID,
+			Actor:     m.Actor,
+			Action:    AuditAction(m.Action),
+			Target:    m.Target,
+			Details:   m.Details,
+			Addr:      m.Addr,
+			Transport: m.Transport,
+			CreatedAt: m.CreatedAt,
+		}
+	}
+
+	return events, nil
+}
+
+// audit records an action taken by the user in ctx in the audit log. kv are
+// key value pairs of details, like the arguments of a logger.
+//
+// The action already happened, so a failure to record it is logged rather
+// than returned.
+func (d *Backend) audit(ctx context.Context, action AuditAction, target string, kv ...interface{}) {
+	src := AuditSourceFromContext(ctx)
+	event := AuditEvent{
+		Action:    action,
+		Target:    target,
+		Details:   auditDetails(kv...),
+		Addr:      src.Addr,
+		Transport: src.Transport,
+		CreatedAt: time.Now().UTC(),
+	}
+	if user := proto.UserFromContext(ctx); user != nil {
+		event.Actor = user.Username()
+	}
+
+	if err := d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		return d.store.CreateAuditEvent(ctx, tx, models.AuditEvent{
+			Actor:     event.Actor,
+			Action:    string(event.Action),
+			Target:    event.Target,
+			Details:   event.Details,
+			Addr:      event.Addr,
+			Transport: event.Transport,
+			CreatedAt: event.CreatedAt,
+		})
+	}); err != nil {
+		d.logger.Error("error recording audit event", "action", action, "target", target, "err", db.WrapError(err))
+	}
+
+	if d.cfg.Audit.LogPath == "" {
+		return
+	}
+
+	if err := appendAuditLog(d.cfg.Audit.LogPath, event); err != nil {
+		d.logger.Error("error writing audit log", "path", d.cfg.Audit.LogPath, "err", err)
+	}
+}
+
+// appendAuditLog appends an event to a JSON lines file. The file is opened
+// for every event, events are rare and may be written by several processes.
+func appendAuditLog(path string, event AuditEvent) error {
+	b, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Write(append(b, '\n')); err != nil {
+		f.Close() //nolint: errcheck
+		return err
+	}
+
+	return f.Close()
+}
+
+// auditDetails formats key value pairs as space separated key=value pairs,
+// quoting values as needed.
+func auditDetails(kv ...interface{}) string {
+	var sb strings.Builder
+	for i := 0; i+1 < len(kv); i += 2 {
+		if sb.Len() > 0 {
+			sb.WriteByte(' ')
+		}
+
+		v := fmt.Sprint(kv[i+1])
+		if v == "" || strings.ContainsAny(v, " \t\n\"=") {
+			v = strconv.Quote(v)
+		}
+
+		fmt.Fprintf(&sb, "%v=%s", kv[i], v)
+	}
+
+	return sb.String()
+}
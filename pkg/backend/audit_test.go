@@ -0,0 +1,111 @@
+package backend
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/soft-serve/pkg/access"
+	"github.com/charmbracelet/soft-serve/pkg/config"
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/store"
+	"github.com/matryer/is"
+)
+
+func TestAuditEvents(t *testing.T) {
+	is := is.New(t)
+	be, cfg := newTestBackend(t)
+	ctx := config.WithContext(context.Background(), cfg)
+	ctx = db.WithContext(ctx, be.db)
+	ctx = store.WithContext(ctx, be.store)
+
+	alice, err := be.CreateUser(ctx, "alice", proto.UserOptions{Admin: true})
+	is.NoErr(err)
+	_, err = be.CreateUser(ctx, "bob", proto.UserOptions{})
+	is.NoErr(err)
+
+	ctx = proto.WithUserContext(ctx, alice)
+	ctx = WithAuditSourceContext(ctx, "192.0.2.1:2222", "ssh")
+	_, err = be.CreateRepository(ctx, "repo", alice, proto.RepositoryOptions{Private: true})
+	is.NoErr(err)
+	is.NoErr(be.AddCollaborator(ctx, "repo", "bob", access.ReadWriteAccess))
+	is.NoErr(be.SetPrivate(ctx, "repo", false))
+	is.NoErr(be.SetAnonAccess(ctx, access.NoAccess))
+	is.NoErr(be.DeleteRepository(ctx, "repo"))
+
+	// Failed actions aren't recorded.
+	is.True(be.DeleteRepository(ctx, "nope") != nil)
+
+	events, err := be.AuditEvents(ctx, AuditFilter{Actor: "alice"})
+	is.NoErr(err)
+	is.Equal(len(events), 5)
+	is.Equal(events[0].Action, AuditRepoDelete)
+	is.Equal(events[0].Target, "repo")
+	is.Equal(events[0].Addr, "192.0.2.1")
+	is.Equal(events[0].Transport, "ssh")
+	is.Equal(events[1].Action, AuditSettingsAnonAccess)
+	is.Equal(events[1].Details, "level=no-access")
+	is.Equal(events[2].Action, AuditRepoSetPrivate)
+	is.Equal(events[2].Details, "private=false")
+	is.Equal(events[3].Details, "user=bob level=read-write")
+	is.Equal(events[4].Action, AuditRepoCreate)
+
+	// Actions match the actions they namespace.
+	events, err = be.AuditEvents(ctx, AuditFilter{Action: "repo", Target: "repo", Limit: 2})
+	is.NoErr(err)
+	is.Equal(len(events), 2)
+	is.Equal(events[0].Action, AuditRepoDelete)
+	is.Equal(events[1].Action, AuditRepoSetPrivate)
+
+	events, err = be.AuditEvents(ctx, AuditFilter{Action: "repo.set"})
+	is.NoErr(err)
+	is.Equal(len(events), 0)
+
+	events, err = be.AuditEvents(ctx, AuditFilter{Since: time.Now().Add(time.Hour)})
+	is.NoErr(err)
+	is.Equal(len(events), 0)
+
+	// Users created before anyone logged in have no actor.
+	events, err = be.AuditEvents(ctx, AuditFilter{Action: string(AuditUserCreate)})
+	is.NoErr(err)
+	is.Equal(len(events), 2)
+	is.Equal(events[0].Actor, "")
+	is.Equal(events[0].Target, "bob")
+}
+
+func TestAuditLogFile(t *testing.T) {
+	is := is.New(t)
+	be, cfg := newTestBackend(t)
+	cfg.Audit.LogPath = filepath.Join(t.TempDir(), "audit.log")
+	ctx := WithAuditSourceContext(context.Background(), "[2001:db8::1]:443", "http")
+
+	_, err := be.CreateUser(ctx, "alice", proto.UserOptions{})
+	is.NoErr(err)
+	is.NoErr(be.SetUsername(ctx, "alice", "alice2"))
+
+	b, err := os.ReadFile(cfg.Audit.LogPath)
+	is.NoErr(err)
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+	is.Equal(len(lines), 2)
+
+	var event AuditEvent
+	is.NoErr(json.Unmarshal([]byte(lines[1]), &event))
+	is.Equal(event.Action, AuditUserSetUsername)
+	is.Equal(event.Target, "alice")
+	is.Equal(event.Details, "username=alice2")
+	is.Equal(event.Addr, "2001:db8::1")
+	is.Equal(event.Transport, "http")
+}
+
+func TestAuditDetails(t *testing.T) {
+	is := is.New(t)
+	is.Equal(auditDetails(), "")
+	is.Equal(auditDetails("a", 1, "b", true), "a=1 b=true")
+	is.Equal(auditDetails("name", "my token", "empty", ""), `name="my token" empty=""`)
+	is.Equal(auditDetails("odd"), "")
+}
@@ -0,0 +1,259 @@
+package backend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/access"
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/db/models"
+	"github.com/charmbracelet/soft-serve/pkg/hooks"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/utils"
+	"github.com/gobwas/glob"
+)
+
+// BranchProtection is a protected branch rule of a repository.
+type BranchProtection struct {
+	// Pattern is the branch name or glob pattern the rule applies to, e.g.
+	// "main" or "release/*".
+	Pattern string
+	// BlockForcePush rejects non fast-forward updates.
+	BlockForcePush bool
+	// BlockDeletion rejects deleting matching branches.
+	BlockDeletion bool
+	// MinAccessLevel is the minimum access level required to push.
+	MinAccessLevel access.AccessLevel
+	// Users restricts pushing to the named users. An empty list means anyone
+	// with push access may push.
+	Users []string
+}
+
+// compileBranchPattern compiles a branch protection pattern. A single "*"
+// does not cross "/" boundaries, "**" does.
+func compileBranchPattern(pattern string) (glob.Glob, error) {
+	if pattern == "" {
+		return nil, proto.ErrInvalidBranchPattern
+	}
+
+	g, err := glob.Compile(pattern, '/')
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", proto.ErrInvalidBranchPattern, err)
+	}
+
+	return g, nil
+}
+
+// SetBranchProtection creates or replaces the protection rule with the given
+// pattern on a repository.
+func (d *Backend) SetBranchProtection(ctx context.Context, repo string, bp BranchProtection) error {
+	repo = utils.SanitizeRepo(repo)
+	if _, err := compileBranchPattern(bp.Pattern); err != nil {
+		return err
+	}
+
+	for i, u := range bp.Users {
+		bp.Users[i] = strings.ToLower(u)
+		if err := utils.ValidateUsername(bp.Users[i]); err != nil {
+			return err
+		}
+	}
+
+	if _, err := d.Repository(ctx, repo); err != nil {
+		return err
+	}
+
+	if err := db.WrapError(
+		d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+			m, err := d.store.GetBranchProtectionByRepoAndPattern(ctx, tx, repo, bp.Pattern)
+			if err != nil && !errors.Is(db.WrapError(err), db.ErrRecordNotFound) {
+				return err
+			}
+
+			id := m.ID
+			if id == 0 {
+				id, err = d.store.CreateBranchProtection(ctx, tx, repo, bp.Pattern, bp.BlockForcePush, bp.BlockDeletion, bp.MinAccessLevel)
+				if err != nil {
+					return err
+				}
+			} else {
+				if err := d.store.UpdateBranchProtectionByID(ctx, tx, id, bp.BlockForcePush, bp.BlockDeletion, bp.MinAccessLevel); err != nil {
+					return err
+				}
+				if err := d.store.RemoveBranchProtectionUsers(ctx, tx, id); err != nil {
+					return err
+				}
+			}
+
+			for _, u := range bp.Users {
+				if _, err := d.store.FindUserByUsername(ctx, tx, u); err != nil {
+					if errors.Is(db.WrapError(err), db.ErrRecordNotFound) {
+						return proto.ErrUserNotFound
+					}
+					return err
+				}
+				if err := d.store.AddBranchProtectionUserByUsername(ctx, tx, id, u); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		}),
+	); err != nil {
+		return err
+	}
+
+	d.audit(ctx, AuditRepoSetBranchProtection, repo, "pattern", bp.Pattern, "block-force-push", bp.BlockForcePush,
+		"block-deletion", bp.BlockDeletion, "min-access-level", bp.MinAccessLevel, "users", strings.Join(bp.Users, ","))
+	return nil
+}
+
+// BranchProtections returns the protection rules of a repository.
+func (d *Backend) BranchProtections(ctx context.Context, repo string) ([]BranchProtection, error) {
+	repo = utils.SanitizeRepo(repo)
+	var bps []BranchProtection
+	if err := d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		ms, err := d.store.ListBranchProtectionsByRepo(ctx, tx, repo)
+		if err != nil {
+			return err
+		}
+
+		bps = make([]BranchProtection, 0, len(ms))
+		for _, m := range ms {
+			bp, err := d.branchProtection(ctx, tx, m)
+			if err != nil {
+				return err
+			}
+			bps = append(bps, bp)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, db.WrapError(err)
+	}
+
+	return bps, nil
+}
+
+// RemoveBranchProtection removes the protection rule with the given pattern
+// from a repository.
+func (d *Backend) RemoveBranchProtection(ctx context.Context, repo string, pattern string) error {
+	repo = utils.SanitizeRepo(repo)
+	if err := db.WrapError(
+		d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+			if _, err := d.store.GetBranchProtectionByRepoAndPattern(ctx, tx, repo, pattern); err != nil {
+				if errors.Is(db.WrapError(err), db.ErrRecordNotFound) {
+					return proto.ErrBranchProtectionNotFound
+				}
+				return err
+			}
+
+			return d.store.DeleteBranchProtectionByRepoAndPattern(ctx, tx, repo, pattern)
+		}),
+	); err != nil {
+		return err
+	}
+
+	d.audit(ctx, AuditRepoRemoveBranchProtection, repo, "pattern", pattern)
+	return nil
+}
+
+func (d *Backend) branchProtection(ctx context.Context, tx *db.Tx, m models.BranchProtection) (BranchProtection, error) {
+	users, err := d.store.ListBranchProtectionUsers(ctx, tx, m.ID)
+	if err != nil {
+		return BranchProtection{}, err
+	}
+
+	bp := BranchProtection{
+		Pattern:        m.Pattern,
+		BlockForcePush: m.BlockForcePush,
+		BlockDeletion:  m.BlockDeletion,
+		MinAccessLevel: m.MinAccessLevel,
+		Users:          make([]string, len(users)),
+	}
+	for i, u := range users {
+		bp.Users[i] = u.Username
+	}
+
+	return bp, nil
+}
+
+// BranchProtectionViolations checks ref updates made by user against the
+// repository's protection rules and returns a message for every update that
+// violates one of them.
+func (d *Backend) BranchProtectionViolations(ctx context.Context, repo proto.Repository, user proto.User, args []hooks.HookArg) ([]string, error) {
+	bps, err := d.BranchProtections(ctx, repo.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	if len(bps) == 0 {
+		return nil, nil
+	}
+
+	var r *git.Repository
+	var username string
+	if user != nil {
+		username = user.Username()
+	}
+
+	level := d.AccessLevelForUser(ctx, repo.Name(), user)
+
+	var violations []string
+	for _, arg := range args {
+		if !strings.HasPrefix(arg.RefName, git.RefsHeads) {
+			continue
+		}
+
+		branch := strings.TrimPrefix(arg.RefName, git.RefsHeads)
+		for _, bp := range bps {
+			g, err := compileBranchPattern(bp.Pattern)
+			if err != nil || !g.Match(branch) {
+				continue
+			}
+
+			reject := func(reason string) {
+				violations = append(violations, fmt.Sprintf("branch %q is protected by rule %q: %s", branch, bp.Pattern, reason))
+			}
+
+			if len(bp.Users) > 0 && !slices.Contains(bp.Users, username) {
+				reject("you are not allowed to push to this branch")
+				continue
+			}
+
+			if level < bp.MinAccessLevel {
+				reject(fmt.Sprintf("pushing requires %s access", bp.MinAccessLevel))
+				continue
+			}
+
+			switch {
+			case git.IsZeroHash(arg.NewSha):
+				if bp.BlockDeletion {
+					reject("deleting this branch is not allowed")
+				}
+			case !git.IsZeroHash(arg.OldSha) && bp.BlockForcePush:
+				if r == nil {
+					r, err = repo.Open()
+					if err != nil {
+						return nil, err
+					}
+				}
+
+				ff, err := r.IsAncestor(arg.OldSha, arg.NewSha)
+				if err != nil {
+					return nil, err
+				}
+
+				if !ff {
+					reject("force pushing to this branch is not allowed")
+				}
+			}
+		}
+	}
+
+	return violations, nil
+}
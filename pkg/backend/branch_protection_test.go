@@ -0,0 +1,165 @@
+package backend
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/access"
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/hooks"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/matryer/is"
+)
+
+// gitOutput runs git in dir and returns its trimmed output.
+func gitOutput(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	cmd.Env = append(cmd.Environ(),
+		"GIT_CONFIG_GLOBAL=/dev/null",
+		"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com",
+	)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v: %v: %s", args, err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func TestBranchProtectionCRUD(t *testing.T) {
+	is := is.New(t)
+	be, _ := newTestBackend(t)
+	ctx := context.Background()
+
+	_, err := be.CreateRepository(ctx, "repo", nil, proto.RepositoryOptions{})
+	is.NoErr(err)
+	_, err = be.CreateUser(ctx, "alice", proto.UserOptions{})
+	is.NoErr(err)
+
+	is.NoErr(be.SetBranchProtection(ctx, "repo", BranchProtection{
+		Pattern:        "main",
+		BlockForcePush: true,
+		BlockDeletion:  true,
+		MinAccessLevel: access.ReadWriteAccess,
+		Users:          []string{"Alice"},
+	}))
+
+	bps, err := be.BranchProtections(ctx, "repo")
+	is.NoErr(err)
+	is.Equal(len(bps), 1)
+	is.Equal(bps[0].Users, []string{"alice"})
+
+	// Setting the same pattern again replaces the rule.
+	is.NoErr(be.SetBranchProtection(ctx, "repo", BranchProtection{
+		Pattern:        "main",
+		MinAccessLevel: access.AdminAccess,
+	}))
+
+	bps, err = be.BranchProtections(ctx, "repo")
+	is.NoErr(err)
+	is.Equal(len(bps), 1)
+	is.Equal(bps[0].MinAccessLevel, access.AdminAccess)
+	is.True(!bps[0].BlockForcePush)
+	is.Equal(len(bps[0].Users), 0)
+
+	err = be.SetBranchProtection(ctx, "repo", BranchProtection{Pattern: "dev", Users: []string{"bob"}})
+	is.True(errors.Is(err, proto.ErrUserNotFound))
+
+	err = be.SetBranchProtection(ctx, "repo", BranchProtection{Pattern: "[main"})
+	is.True(errors.Is(err, proto.ErrInvalidBranchPattern))
+
+	is.NoErr(be.RemoveBranchProtection(ctx, "repo", "main"))
+	err = be.RemoveBranchProtection(ctx, "repo", "main")
+	is.True(errors.Is(err, proto.ErrBranchProtectionNotFound))
+}
+
+func TestBranchProtectionViolations(t *testing.T) {
+	is := is.New(t)
+	be, _ := newTestBackend(t)
+	ctx := context.Background()
+
+	r, err := be.CreateRepository(ctx, "repo", nil, proto.RepositoryOptions{})
+	is.NoErr(err)
+	alice, err := be.CreateUser(ctx, "alice", proto.UserOptions{})
+	is.NoErr(err)
+	bob, err := be.CreateUser(ctx, "bob", proto.UserOptions{})
+	is.NoErr(err)
+	is.NoErr(be.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		for _, u := range []string{"alice", "bob"} {
+			if err := be.store.AddCollabByUsernameAndRepo(ctx, tx, u, "repo", access.ReadWriteAccess); err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+
+	// Build a history with a rewritten commit in a scratch repository and
+	// fetch it into the served one; fetching does not run any hooks.
+	work := t.TempDir()
+	gitOutput(t, work, "init", "-q")
+	gitOutput(t, work, "commit", "-q", "--allow-empty", "-m", "first")
+	first := gitOutput(t, work, "rev-parse", "HEAD")
+	gitOutput(t, work, "commit", "-q", "--allow-empty", "-m", "second")
+	second := gitOutput(t, work, "rev-parse", "HEAD")
+	gitOutput(t, work, "commit", "-q", "--amend", "--allow-empty", "-m", "rewritten")
+	rewritten := gitOutput(t, work, "rev-parse", "HEAD")
+	gitOutput(t, be.repoPath("repo"), "fetch", "-q", work, "+HEAD:refs/heads/scratch", second+":refs/heads/keep")
+
+	is.NoErr(be.SetBranchProtection(ctx, "repo", BranchProtection{
+		Pattern:        "main",
+		BlockForcePush: true,
+		BlockDeletion:  true,
+		MinAccessLevel: access.ReadWriteAccess,
+	}))
+	is.NoErr(be.SetBranchProtection(ctx, "repo", BranchProtection{
+		Pattern:        "release/*",
+		MinAccessLevel: access.ReadWriteAccess,
+		Users:          []string{"alice"},
+	}))
+
+	ref := func(name, oldSha, newSha string) []hooks.HookArg {
+		return []hooks.HookArg{{OldSha: oldSha, NewSha: newSha, RefName: git.RefsHeads + name}}
+	}
+
+	cases := []struct {
+		name   string
+		user   proto.User
+		args   []hooks.HookArg
+		reject bool
+	}{
+		{"fast-forward", alice, ref("main", first, second), false},
+		{"create", alice, ref("main", git.ZeroID, first), false},
+		{"force push", alice, ref("main", second, rewritten), true},
+		{"delete", alice, ref("main", second, git.ZeroID), true},
+		{"unprotected branch", alice, ref("feature", second, rewritten), false},
+		{"tag", alice, []hooks.HookArg{{OldSha: second, NewSha: git.ZeroID, RefName: "refs/tags/main"}}, false},
+		{"allowed user", alice, ref("release/1.0", second, rewritten), false},
+		{"other user", bob, ref("release/1.0", first, second), true},
+		{"anonymous", nil, ref("release/1.0", first, second), true},
+		{"pattern does not cross slash", bob, ref("release/1.0/hotfix", first, second), false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			is := is.New(t)
+			violations, err := be.BranchProtectionViolations(ctx, r, c.user, c.args)
+			is.NoErr(err)
+			is.Equal(len(violations) > 0, c.reject)
+		})
+	}
+
+	// A minimum access level above the pusher's rejects the push.
+	is.NoErr(be.SetBranchProtection(ctx, "repo", BranchProtection{
+		Pattern:        "main",
+		MinAccessLevel: access.AdminAccess,
+	}))
+	violations, err := be.BranchProtectionViolations(ctx, r, alice, ref("main", first, second))
+	is.NoErr(err)
+	is.Equal(len(violations), 1)
+	is.True(strings.Contains(violations[0], "admin-access"))
+}
@@ -0,0 +1,62 @@
+package backend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/sshutils"
+	"github.com/charmbracelet/soft-serve/pkg/utils"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+// IsTrustedCertificate reports whether cert is a user certificate signed by
+// one of the configured trusted user certificate authorities.
+func (d *Backend) IsTrustedCertificate(cert *gossh.Certificate) bool {
+	if cert.CertType != gossh.UserCert {
+		return false
+	}
+
+	for _, k := range d.cfg.TrustedUserCAKeys() {
+		if sshutils.KeysEqual(cert.SignatureKey, k) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// UserByCertificate returns the user a trusted user certificate was issued
+// for, the first of its principals that names a user.
+//
+// The certificate's signature, validity window and critical options are
+// checked, except for source-address which needs the client's address, see
+// sshutils.CheckSourceAddress.
+func (d *Backend) UserByCertificate(ctx context.Context, cert *gossh.Certificate) (proto.User, error) {
+	if !d.IsTrustedCertificate(cert) {
+		return nil, errors.New("certificate is not signed by a trusted authority")
+	}
+
+	checker := &gossh.CertChecker{}
+	for _, principal := range cert.ValidPrincipals {
+		username := strings.ToLower(principal)
+		if err := utils.ValidateUsername(username); err != nil {
+			continue
+		}
+
+		if err := checker.CheckCert(principal, cert); err != nil {
+			return nil, fmt.Errorf("invalid certificate: %w", err)
+		}
+
+		user, err := d.User(ctx, username)
+		if errors.Is(err, proto.ErrUserNotFound) {
+			continue
+		}
+
+		return user, err
+	}
+
+	return nil, proto.ErrUserNotFound
+}
@@ -0,0 +1,110 @@
+package backend
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/sshutils"
+	"github.com/matryer/is"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+func newTestSigner(t *testing.T) gossh.Signer {
+	t.Helper()
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := gossh.NewSignerFromKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signer
+}
+
+func newTestCertificate(t *testing.T, ca gossh.Signer, modify func(*gossh.Certificate)) *gossh.Certificate {
+	t.Helper()
+	cert := &gossh.Certificate{
+		Key:             newTestPublicKey(t),
+		KeyId:           "alice@laptop",
+		CertType:        gossh.UserCert,
+		ValidPrincipals: []string{"nobody", "Alice"},
+		ValidAfter:      uint64(time.Now().Add(-time.Minute).Unix()),
+		ValidBefore:     uint64(time.Now().Add(time.Hour).Unix()),
+	}
+	if modify != nil {
+		modify(cert)
+	}
+	if err := cert.SignCert(rand.Reader, ca); err != nil {
+		t.Fatal(err)
+	}
+	return cert
+}
+
+func TestUserByCertificate(t *testing.T) {
+	is := is.New(t)
+	be, cfg := newTestBackend(t)
+	ctx := context.Background()
+
+	ca, other := newTestSigner(t), newTestSigner(t)
+	cfg.SSH.TrustedUserCAKeys = []string{sshutils.MarshalAuthorizedKey(ca.PublicKey())}
+
+	_, err := be.CreateUser(ctx, "alice", proto.UserOptions{})
+	is.NoErr(err)
+
+	// The first principal that names a user wins.
+	cert := newTestCertificate(t, ca, nil)
+	is.True(be.IsTrustedCertificate(cert))
+	user, err := be.UserByCertificate(ctx, cert)
+	is.NoErr(err)
+	is.Equal(user.Username(), "alice")
+	user, err = be.UserByPublicKey(ctx, cert)
+	is.NoErr(err)
+	is.Equal(user.Username(), "alice")
+
+	// Certificates from other authorities are plain keys.
+	untrusted := newTestCertificate(t, other, nil)
+	is.True(!be.IsTrustedCertificate(untrusted))
+	_, err = be.UserByPublicKey(ctx, untrusted)
+	is.True(errors.Is(err, proto.ErrUserNotFound))
+
+	for name, modify := range map[string]func(*gossh.Certificate){
+		"expired": func(c *gossh.Certificate) {
+			c.ValidBefore = uint64(time.Now().Add(-time.Second).Unix())
+		},
+		"not yet valid": func(c *gossh.Certificate) {
+			c.ValidAfter = uint64(time.Now().Add(time.Hour).Unix())
+		},
+		"unsupported critical option": func(c *gossh.Certificate) {
+			c.CriticalOptions = map[string]string{"force-command": "true"}
+		},
+		"no user principal": func(c *gossh.Certificate) {
+			c.ValidPrincipals = []string{"bob"}
+		},
+		"no principals": func(c *gossh.Certificate) {
+			c.ValidPrincipals = nil
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := be.UserByCertificate(ctx, newTestCertificate(t, ca, modify))
+			if err == nil {
+				t.Error("UserByCertificate() => nil error, want non-nil error")
+			}
+		})
+	}
+
+	// Host certificates aren't user certificates.
+	host := newTestCertificate(t, ca, func(c *gossh.Certificate) { c.CertType = gossh.HostCert })
+	is.True(!be.IsTrustedCertificate(host))
+
+	// A tampered certificate fails the signature check.
+	tampered := newTestCertificate(t, ca, nil)
+	tampered.ValidPrincipals = []string{"alice"}
+	_, err = be.UserByCertificate(ctx, tampered)
+	is.True(err != nil)
+}
@@ -40,6 +40,8 @@ func (d *Backend) AddCollaborator(ctx context.Context, repo string, username str
 		return err
 	}
 
+	d.audit(ctx, AuditRepoAddCollab, repo, "user", username, "level", level)
+
 	wh, err := webhook.NewCollaboratorEvent(ctx, proto.UserFromContext(ctx), r, username, webhook.CollaboratorEventAdded)
 	if err != nil {
 		return err
@@ -118,5 +120,7 @@ func (d *Backend) RemoveCollaborator(ctx context.Context, repo string, username
 		return err
 	}
 
+	d.audit(ctx, AuditRepoRemoveCollab, repo, "user", username)
+
 	return webhook.SendEvent(ctx, wh)
 }
@@ -0,0 +1,211 @@
+package backend
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/soft-serve/pkg/access"
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/db/models"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/sshutils"
+	"github.com/charmbracelet/soft-serve/pkg/utils"
+	"golang.org/x/crypto/ssh"
+)
+
+// DeployKey is an SSH public key attached to a single repository. It grants
+// read-only or read-write access to that repository without a user account.
+type DeployKey struct {
+	ID          int64
+	RepoID      int64
+	Title       string
+	PublicKey   ssh.PublicKey
+	AccessLevel access.AccessLevel
+	CreatedAt   time.Time
+}
+
+// Fingerprint returns the SHA256 fingerprint of the deploy key.
+func (k DeployKey) Fingerprint() string {
+	return ssh.FingerprintSHA256(k.PublicKey)
+}
+
+// ContextKeyDeployKey is the context key for the deploy key a session
+// authenticated with.
+var ContextKeyDeployKey = &struct{ string }{"deploy-key"}
+
+// DeployKeyFromContext returns the deploy key from the context, or nil if
+// the session didn't authenticate with one.
+func DeployKeyFromContext(ctx context.Context) *DeployKey {
+	if k, ok := ctx.Value(ContextKeyDeployKey).(*DeployKey); ok {
+		return k
+	}
+
+	return nil
+}
+
+// WithDeployKeyContext returns a new context with the deploy key.
+func WithDeployKeyContext(ctx context.Context, k *DeployKey) context.Context {
+	return context.WithValue(ctx, ContextKeyDeployKey, k)
+}
+
+// AddDeployKey attaches a public key to a repository.
+func (d *Backend) AddDeployKey(ctx context.Context, repo string, title string, pk ssh.PublicKey, level access.AccessLevel) (DeployKey, error) {
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return DeployKey{}, errors.New("title is required")
+	}
+
+	if level != access.ReadOnlyAccess && level != access.ReadWriteAccess {
+		return DeployKey{}, proto.ErrInvalidDeployKeyAccess
+	}
+
+	for _, k := range d.cfg.AdminKeys() {
+		if sshutils.KeysEqual(pk, k) {
+			return DeployKey{}, proto.ErrPublicKeyInUse
+		}
+	}
+
+	repo = utils.SanitizeRepo(repo)
+	if _, err := d.Repository(ctx, repo); err != nil {
+		return DeployKey{}, err
+	}
+
+	var m models.DeployKey
+	if err := db.WrapError(
+		d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+			if _, err := d.store.FindUserByPublicKey(ctx, tx, pk); err == nil {
+				return proto.ErrPublicKeyInUse
+			}
+
+			var err error
+			m, err = d.store.CreateDeployKeyByRepo(ctx, tx, repo, title, pk, level)
+			return err
+		}),
+	); err != nil {
+		if errors.Is(err, db.ErrDuplicateKey) {
+			return DeployKey{}, proto.ErrDeployKeyExist
+		}
+
+		return DeployKey{}, err
+	}
+
+	d.audit(ctx, AuditRepoAddDeployKey, repo, "title", title, "fingerprint", ssh.FingerprintSHA256(pk), "level", level)
+	return deployKeyFromModel(m)
+}
+
+// DeployKeys returns the deploy keys of a repository.
+func (d *Backend) DeployKeys(ctx context.Context, repo string) ([]DeployKey, error) {
+	repo = utils.SanitizeRepo(repo)
+	if _, err := d.Repository(ctx, repo); err != nil {
+		return nil, err
+	}
+
+	var ms []models.DeployKey
+	if err := d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		var err error
+		ms, err = d.store.ListDeployKeysByRepo(ctx, tx, repo)
+		return err
+	}); err != nil {
+		return nil, db.WrapError(err)
+	}
+
+	keys := make([]DeployKey, 0, len(ms))
+	for _, m := range ms {
+		k, err := deployKeyFromModel(m)
+		if err != nil {
+			d.logger.Error("error parsing deploy key", "id", m.ID, "err", err)
+			continue
+		}
+
+		keys = append(keys, k)
+	}
+
+	return keys, nil
+}
+
+// RemoveDeployKey removes a deploy key from a repository.
+func (d *Backend) RemoveDeployKey(ctx context.Context, repo string, id int64) error {
+	keys, err := d.DeployKeys(ctx, repo)
+	if err != nil {
+		return err
+	}
+
+	var key *DeployKey
+	for i := range keys {
+		if keys[i].ID == id {
+			key = &keys[i]
+			break
+		}
+	}
+	if key == nil {
+		return proto.ErrDeployKeyNotFound
+	}
+
+	repo = utils.SanitizeRepo(repo)
+	if err := d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		return d.store.DeleteDeployKeyByRepo(ctx, tx, repo, id)
+	}); err != nil {
+		return db.WrapError(err)
+	}
+
+	d.audit(ctx, AuditRepoRemoveDeployKey, repo, "title", key.Title, "fingerprint", key.Fingerprint())
+	return nil
+}
+
+// DeployKeyByPublicKey finds the deploy key with the given public key.
+func (d *Backend) DeployKeyByPublicKey(ctx context.Context, pk ssh.PublicKey) (*DeployKey, error) {
+	var m models.DeployKey
+	if err := d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		var err error
+		m, err = d.store.FindDeployKeyByPublicKey(ctx, tx, pk)
+		return err
+	}); err != nil {
+		err = db.WrapError(err)
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, proto.ErrDeployKeyNotFound
+		}
+		return nil, err
+	}
+
+	k, err := deployKeyFromModel(m)
+	if err != nil {
+		return nil, err
+	}
+
+	return &k, nil
+}
+
+// deployKeyAccessLevel returns the access level of a deploy key for a
+// repository. Outside of its own repository, a deploy key is anonymous.
+func (d *Backend) deployKeyAccessLevel(ctx context.Context, repo string, k *DeployKey) access.AccessLevel {
+	anon := d.accessLevelForUser(ctx, repo, nil)
+
+	r := proto.RepositoryFromContext(ctx)
+	if r == nil || r.Name() != utils.SanitizeRepo(repo) {
+		r, _ = d.Repository(ctx, repo)
+	}
+
+	if r != nil && r.ID() == k.RepoID && k.AccessLevel > anon {
+		return k.AccessLevel
+	}
+
+	return anon
+}
+
+func deployKeyFromModel(m models.DeployKey) (DeployKey, error) {
+	pk, _, err := sshutils.ParseAuthorizedKey(m.PublicKey)
+	if err != nil {
+		return DeployKey{}, err
+	}
+
+	return DeployKey{
+		ID:          m.ID,
+		RepoID:      m.RepoID,
+		Title:       m.Title,
+		PublicKey:   pk,
+		AccessLevel: m.AccessLevel,
+		CreatedAt:   m.CreatedAt,
+	}, nil
+}
@@ -0,0 +1,127 @@
+package backend
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	"github.com/charmbracelet/soft-serve/pkg/access"
+	"github.com/charmbracelet/soft-serve/pkg/config"
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/store"
+	"github.com/matryer/is"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+func newTestPublicKey(t *testing.T) gossh.PublicKey {
+	t.Helper()
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk, err := gossh.NewPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pk
+}
+
+func TestDeployKeyCRUD(t *testing.T) {
+	is := is.New(t)
+	be, _ := newTestBackend(t)
+	ctx := context.Background()
+
+	_, err := be.CreateRepository(ctx, "repo", nil, proto.RepositoryOptions{})
+	is.NoErr(err)
+
+	pk := newTestPublicKey(t)
+	k, err := be.AddDeployKey(ctx, "repo", "ci", pk, access.ReadOnlyAccess)
+	is.NoErr(err)
+	is.Equal(k.Title, "ci")
+	is.Equal(k.AccessLevel, access.ReadOnlyAccess)
+
+	_, err = be.AddDeployKey(ctx, "repo", "again", pk, access.ReadWriteAccess)
+	is.True(errors.Is(err, proto.ErrDeployKeyExist))
+	_, err = be.AddDeployKey(ctx, "repo", "admin", newTestPublicKey(t), access.AdminAccess)
+	is.True(errors.Is(err, proto.ErrInvalidDeployKeyAccess))
+	_, err = be.AddDeployKey(ctx, "nope", "ci", newTestPublicKey(t), access.ReadOnlyAccess)
+	is.True(errors.Is(err, proto.ErrRepoNotFound))
+
+	keys, err := be.DeployKeys(ctx, "repo")
+	is.NoErr(err)
+	is.Equal(len(keys), 1)
+	is.Equal(keys[0].Fingerprint(), gossh.FingerprintSHA256(pk))
+
+	found, err := be.DeployKeyByPublicKey(ctx, pk)
+	is.NoErr(err)
+	is.Equal(found.ID, k.ID)
+
+	is.True(errors.Is(be.RemoveDeployKey(ctx, "repo", k.ID+1), proto.ErrDeployKeyNotFound))
+	is.NoErr(be.RemoveDeployKey(ctx, "repo", k.ID))
+	_, err = be.DeployKeyByPublicKey(ctx, pk)
+	is.True(errors.Is(err, proto.ErrDeployKeyNotFound))
+}
+
+func TestDeployKeyPublicKeyInUse(t *testing.T) {
+	is := is.New(t)
+	be, _ := newTestBackend(t)
+	ctx := context.Background()
+
+	_, err := be.CreateRepository(ctx, "repo", nil, proto.RepositoryOptions{})
+	is.NoErr(err)
+
+	userKey := newTestPublicKey(t)
+	_, err = be.CreateUser(ctx, "alice", proto.UserOptions{PublicKeys: []gossh.PublicKey{userKey}})
+	is.NoErr(err)
+	_, err = be.AddDeployKey(ctx, "repo", "ci", userKey, access.ReadOnlyAccess)
+	is.True(errors.Is(err, proto.ErrPublicKeyInUse))
+
+	deployKey := newTestPublicKey(t)
+	_, err = be.AddDeployKey(ctx, "repo", "ci", deployKey, access.ReadOnlyAccess)
+	is.NoErr(err)
+	is.True(errors.Is(be.AddPublicKey(ctx, "alice", deployKey), proto.ErrPublicKeyInUse))
+	_, err = be.CreateUser(ctx, "bob", proto.UserOptions{PublicKeys: []gossh.PublicKey{deployKey}})
+	is.True(errors.Is(err, proto.ErrPublicKeyInUse))
+}
+
+func TestDeployKeyAccessLevel(t *testing.T) {
+	is := is.New(t)
+	be, cfg := newTestBackend(t)
+	ctx := config.WithContext(context.Background(), cfg)
+	ctx = db.WithContext(ctx, be.db)
+	ctx = store.WithContext(ctx, be.store)
+	is.NoErr(be.SetAnonAccess(ctx, access.NoAccess))
+
+	alice, err := be.CreateUser(ctx, "alice", proto.UserOptions{Admin: true})
+	is.NoErr(err)
+	ctx = proto.WithUserContext(ctx, alice)
+	for _, name := range []string{"ro", "rw", "other"} {
+		_, err := be.CreateRepository(ctx, name, alice, proto.RepositoryOptions{Private: true})
+		is.NoErr(err)
+	}
+
+	ro, rw := newTestPublicKey(t), newTestPublicKey(t)
+	_, err = be.AddDeployKey(ctx, "ro", "ci", ro, access.ReadOnlyAccess)
+	is.NoErr(err)
+	rwKey, err := be.AddDeployKey(ctx, "rw", "deploy", rw, access.ReadWriteAccess)
+	is.NoErr(err)
+
+	is.Equal(be.AccessLevelByPublicKey(ctx, "ro", ro), access.ReadOnlyAccess)
+	is.Equal(be.AccessLevelByPublicKey(ctx, "rw", rw), access.ReadWriteAccess)
+	is.Equal(be.AccessLevelByPublicKey(ctx, "rw", ro), access.NoAccess)
+	is.Equal(be.AccessLevelByPublicKey(ctx, "other", rw), access.NoAccess)
+	is.Equal(be.AccessLevelByPublicKey(ctx, "new", rw), access.NoAccess)
+
+	// Anonymous sessions that authenticated with a deploy key get its access.
+	dctx := WithDeployKeyContext(ctx, &rwKey)
+	is.Equal(be.AccessLevelForUser(dctx, "rw", nil), access.ReadWriteAccess)
+	is.Equal(be.AccessLevelForUser(dctx, "other", nil), access.NoAccess)
+	is.Equal(be.AccessLevelForUser(ctx, "rw", nil), access.NoAccess)
+
+	// Deploy keys follow their repository.
+	is.NoErr(be.RenameRepository(ctx, "rw", "renamed"))
+	is.Equal(be.AccessLevelByPublicKey(ctx, "renamed", rw), access.ReadWriteAccess)
+}
@@ -0,0 +1,182 @@
+package backend
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+
+	"github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/config"
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/lfs"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/storage"
+	"github.com/charmbracelet/soft-serve/pkg/utils"
+)
+
+// ForkRepository creates the repository name as a copy of the branches and
+// tags of the repository source, owned by user.
+//
+// The fork is a local clone, so objects are hard-linked instead of copied
+// where the file system allows it. LFS objects of the source are copied to
+// the fork, skipping any missing from the source's storage. Forks of private
+// repositories are always private, and the description and project name
+// default to the source's.
+func (d *Backend) ForkRepository(ctx context.Context, source string, name string, user proto.User, opts proto.RepositoryOptions) (proto.Repository, error) {
+	source = utils.SanitizeRepo(source)
+	parent, err := d.Repository(ctx, source)
+	if err != nil {
+		return nil, err
+	}
+
+	name = utils.SanitizeRepo(name)
+	if err := utils.ValidateRepo(name); err != nil {
+		return nil, err
+	}
+
+	rp := d.repoPath(name)
+	if _, err := os.Stat(rp); err == nil || os.IsExist(err) {
+		return nil, proto.ErrRepoExist
+	}
+
+	opts.Mirror = false
+	opts.Private = opts.Private || parent.IsPrivate()
+	if opts.Description == "" {
+		opts.Description = parent.Description()
+	}
+	if opts.ProjectName == "" {
+		opts.ProjectName = parent.ProjectName()
+	}
+
+	d.logger.Info("forking repository", "source", source, "name", name)
+	if err := git.Clone(d.repoPath(source), rp, git.CloneOptions{
+		Bare:  true,
+		Quiet: true,
+		CommandOptions: git.CommandOptions{
+			Timeout: -1,
+			Context: ctx,
+		},
+	}); err != nil {
+		d.logger.Error("failed to clone repository", "err", err, "source", source, "path", rp)
+		if rerr := os.RemoveAll(rp); rerr != nil {
+			err = errors.Join(err, rerr)
+		}
+
+		return nil, err
+	}
+
+	r, err := d.createRepository(ctx, name, user, opts)
+	if err != nil {
+		if rerr := os.RemoveAll(rp); rerr != nil {
+			err = errors.Join(err, rerr)
+		}
+
+		return nil, err
+	}
+
+	if err := d.finishFork(ctx, parent, r); err != nil {
+		d.logger.Error("failed to fork repository", "err", err, "source", source, "name", name)
+		if rerr := d.DeleteRepository(ctx, name); rerr != nil {
+			err = errors.Join(err, rerr)
+		}
+
+		return nil, err
+	}
+
+	d.audit(ctx, AuditRepoFork, r.Name(), "source", source)
+	return r, nil
+}
+
+// finishFork drops the clone's remote, records the fork's parent, and copies
+// the parent's LFS objects.
+func (d *Backend) finishFork(ctx context.Context, parent proto.Repository, r proto.Repository) error {
+	rr, err := r.Open()
+	if err != nil {
+		return err
+	}
+
+	// The origin remote points at the parent's path on disk.
+	rcfg, err := rr.Config()
+	if err != nil {
+		return err
+	}
+
+	rcfg.RemoveSection("remote")
+	rcfg.RemoveSection("branch")
+	if err := rr.SetConfig(rcfg); err != nil {
+		return err
+	}
+
+	src, err := config.LFSStorage(d.cfg, parent.ID())
+	if err != nil {
+		return err
+	}
+
+	dst, err := config.LFSStorage(d.cfg, r.ID())
+	if err != nil {
+		return err
+	}
+
+	return db.WrapError(d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		if err := d.store.SetRepoParentIDByName(ctx, tx, r.Name(), parent.ID()); err != nil {
+			return err
+		}
+
+		objs, err := d.store.GetLFSObjects(ctx, tx, parent.ID())
+		if err != nil {
+			return err
+		}
+
+		for _, obj := range objs {
+			p := path.Join("objects", lfs.Pointer{Oid: obj.Oid, Size: obj.Size}.RelativePath())
+			if err := copyObject(src, dst, p); errors.Is(err, fs.ErrNotExist) {
+				// The parent lost the object, the fork just doesn't get it.
+				d.logger.Warn("skipping missing lfs object of fork parent", "repo", parent.Name(), "oid", obj.Oid)
+				continue
+			} else if err != nil {
+				return err
+			}
+
+			if err := d.store.CreateLFSObject(ctx, tx, r.ID(), obj.Oid, obj.Size); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}))
+}
+
+// copyObject copies the object p from src to dst.
+func copyObject(src, dst storage.Storage, p string) error {
+	obj, err := src.Open(p)
+	if err != nil {
+		return err
+	}
+
+	defer obj.Close() //nolint: errcheck
+	_, err = dst.Put(p, obj)
+	return err
+}
+
+// ForkParent returns the name of the repository repo was forked from. It
+// returns an empty string if repo is not a fork or its parent was deleted.
+func (d *Backend) ForkParent(ctx context.Context, repo string) (string, error) {
+	repo = utils.SanitizeRepo(repo)
+	var parent string
+	if err := d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		var err error
+		parent, err = d.store.GetRepoParentNameByName(ctx, tx, repo)
+		return err
+	}); err != nil {
+		err = db.WrapError(err)
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return "", nil
+		}
+
+		return "", err
+	}
+
+	return parent, nil
+}
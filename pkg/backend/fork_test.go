@@ -0,0 +1,84 @@
+package backend
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/soft-serve/pkg/config"
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/lfs"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/storage"
+	"github.com/charmbracelet/soft-serve/pkg/store"
+	"github.com/matryer/is"
+)
+
+func TestForkRepository(t *testing.T) {
+	is := is.New(t)
+	be, cfg := newTestBackend(t)
+	// Deleting a repository sends a webhook event built from the context.
+	ctx := config.WithContext(context.Background(), cfg)
+	ctx = db.WithContext(ctx, be.db)
+	ctx = store.WithContext(ctx, be.store)
+
+	parent, err := be.CreateRepository(ctx, "repo", nil, proto.RepositoryOptions{Private: true, Description: "desc"})
+	is.NoErr(err)
+	alice, err := be.CreateUser(ctx, "alice", proto.UserOptions{})
+	is.NoErr(err)
+
+	work := t.TempDir()
+	gitOutput(t, work, "init", "-q", "-b", "main")
+	gitOutput(t, work, "commit", "-q", "--allow-empty", "-m", "first")
+	gitOutput(t, work, "tag", "v1")
+	gitOutput(t, be.repoPath("repo"), "fetch", "-q", work, "refs/heads/main:refs/heads/main", "refs/tags/v1:refs/tags/v1")
+
+	// Store an LFS object in the parent.
+	p := lfs.Pointer{Oid: strings.Repeat("a", 64), Size: 3}
+	strg := storage.NewLocalStorage(filepath.Join(cfg.DataPath, "lfs", strconv.FormatInt(parent.ID(), 10)))
+	_, err = strg.Put(path.Join("objects", p.RelativePath()), strings.NewReader("foo"))
+	is.NoErr(err)
+	is.NoErr(be.store.CreateLFSObject(ctx, be.db, parent.ID(), p.Oid, p.Size))
+	// Objects missing from the parent's storage are skipped.
+	is.NoErr(be.store.CreateLFSObject(ctx, be.db, parent.ID(), strings.Repeat("b", 64), 3))
+
+	fork, err := be.ForkRepository(ctx, "repo", "alice/repo", alice, proto.RepositoryOptions{})
+	is.NoErr(err)
+	is.Equal(fork.UserID(), alice.ID())
+	is.True(fork.IsPrivate())
+	is.Equal(fork.Description(), "desc")
+
+	is.Equal(gitOutput(t, be.repoPath("alice/repo"), "for-each-ref", "--format=%(refname)"), "refs/heads/main\nrefs/tags/v1")
+	is.Equal(gitOutput(t, be.repoPath("alice/repo"), "remote"), "")
+
+	objs, err := be.store.GetLFSObjects(ctx, be.db, fork.ID())
+	is.NoErr(err)
+	is.Equal(len(objs), 1)
+	is.Equal(objs[0].Oid, p.Oid)
+	content, err := os.ReadFile(filepath.Join(cfg.DataPath, "lfs", strconv.FormatInt(fork.ID(), 10), "objects", p.RelativePath()))
+	is.NoErr(err)
+	is.Equal(string(content), "foo")
+
+	parentName, err := be.ForkParent(ctx, "alice/repo")
+	is.NoErr(err)
+	is.Equal(parentName, "repo")
+	parentName, err = be.ForkParent(ctx, "repo")
+	is.NoErr(err)
+	is.Equal(parentName, "")
+
+	_, err = be.ForkRepository(ctx, "repo", "alice/repo", alice, proto.RepositoryOptions{})
+	is.True(errors.Is(err, proto.ErrRepoExist))
+	_, err = be.ForkRepository(ctx, "nope", "alice/nope", alice, proto.RepositoryOptions{})
+	is.True(errors.Is(err, proto.ErrRepoNotFound))
+
+	// Deleting the parent leaves the fork intact.
+	is.NoErr(be.DeleteRepository(proto.WithUserContext(ctx, alice), "repo"))
+	parentName, err = be.ForkParent(ctx, "alice/repo")
+	is.NoErr(err)
+	is.Equal(parentName, "")
+}
@@ -2,8 +2,11 @@ package backend
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"io"
 	"os"
+	"strings"
 	"sync"
 
 	"github.com/charmbracelet/soft-serve/git"
@@ -15,18 +18,168 @@ import (
 
 var _ hooks.Hooks = (*Backend)(nil)
 
+// errHookUserUnknown is returned by hookUser when the hook environment does
+// not identify the pusher.
+var errHookUserUnknown = errors.New("unknown hook user")
+
+// hookUser returns the user that triggered a git hook, as passed down by the
+// server through the hook environment.
+func (d *Backend) hookUser(ctx context.Context) (proto.User, error) {
+	if pubkey := os.Getenv("SOFT_SERVE_PUBLIC_KEY"); pubkey != "" {
+		pk, _, err := sshutils.ParseAuthorizedKey(pubkey)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing public key: %w", err)
+		}
+
+		user, err := d.UserByPublicKey(ctx, pk)
+		if err != nil {
+			return nil, fmt.Errorf("error finding user from public key %q: %w", pubkey, err)
+		}
+
+		return user, nil
+	} else if username := os.Getenv("SOFT_SERVE_USERNAME"); username != "" {
+		user, err := d.User(ctx, username)
+		if err != nil {
+			return nil, fmt.Errorf("error finding user from username %q: %w", username, err)
+		}
+
+		return user, nil
+	}
+
+	return nil, errHookUserUnknown
+}
+
+// withHookDeployKey adds the deploy key that triggered a git hook, if any,
+// to the context, so access checks see the deploy key's access.
+func (d *Backend) withHookDeployKey(ctx context.Context) context.Context {
+	pubkey := os.Getenv("SOFT_SERVE_PUBLIC_KEY")
+	if pubkey == "" {
+		return ctx
+	}
+
+	pk, _, err := sshutils.ParseAuthorizedKey(pubkey)
+	if err != nil {
+		return ctx
+	}
+
+	if k, _ := d.DeployKeyByPublicKey(ctx, pk); k != nil {
+		return WithDeployKeyContext(ctx, k)
+	}
+
+	return ctx
+}
+
 // PostReceive is called by the git post-receive hook.
 //
 // It implements Hooks.
-func (d *Backend) PostReceive(_ context.Context, _ io.Writer, _ io.Writer, repo string, args []hooks.HookArg) {
+func (d *Backend) PostReceive(ctx context.Context, stdout io.Writer, _ io.Writer, repo string, args []hooks.HookArg) {
 	d.logger.Debug("post-receive hook called", "repo", repo, "args", args)
+
+	var r proto.Repository
+	var user proto.User
+	for _, arg := range args {
+		if !strings.HasPrefix(arg.RefName, RefsFor) || git.IsZeroHash(arg.NewSha) {
+			continue
+		}
+
+		if r == nil {
+			var err error
+			r, err = d.Repository(ctx, repo)
+			if err != nil {
+				d.logger.Error("error finding repository", "repo", repo, "err", err)
+				return
+			}
+
+			user, _ = d.hookUser(ctx)
+		}
+
+		pr, err := d.createPushedPullRequest(ctx, r, user, arg)
+		if err != nil {
+			d.logger.Error("error creating pull request", "repo", repo, "ref", arg.RefName, "err", err)
+			continue
+		}
+
+		fmt.Fprintf(stdout, "Created pull request #%d: %s\n", pr.Number, pr.Title) //nolint: errcheck
+	}
 }
 
 // PreReceive is called by the git pre-receive hook.
 //
 // It implements Hooks.
-func (d *Backend) PreReceive(_ context.Context, _ io.Writer, _ io.Writer, repo string, args []hooks.HookArg) {
+func (d *Backend) PreReceive(ctx context.Context, _ io.Writer, stderr io.Writer, repo string, args []hooks.HookArg) error {
 	d.logger.Debug("pre-receive hook called", "repo", repo, "args", args)
+
+	r, err := d.Repository(ctx, repo)
+	if err != nil {
+		d.logger.Error("error finding repository", "repo", repo, "err", err)
+		return err
+	}
+
+	// Pushers without an account are anonymous, which protection rules
+	// handle. Any other lookup failure must not let the push through.
+	user, err := d.hookUser(ctx)
+	if err != nil && !errors.Is(err, errHookUserUnknown) && !errors.Is(err, proto.ErrUserNotFound) {
+		d.logger.Error("error finding user", "err", err)
+		return err
+	}
+
+	if user == nil {
+		ctx = d.withHookDeployKey(ctx)
+	}
+
+	violations, err := d.BranchProtectionViolations(ctx, r, user, args)
+	if err != nil {
+		d.logger.Error("error checking branch protections", "repo", repo, "err", err)
+		return err
+	}
+
+	if len(violations) > 0 {
+		for _, v := range violations {
+			fmt.Fprintln(stderr, "error:", v) //nolint: errcheck
+		}
+		return proto.ErrProtectedBranch
+	}
+
+	violations, err = d.pullRequestRefViolations(ctx, r, user, args)
+	if err != nil {
+		d.logger.Error("error checking pull request refs", "repo", repo, "err", err)
+		return err
+	}
+
+	if len(violations) > 0 {
+		for _, v := range violations {
+			fmt.Fprintln(stderr, "error:", v) //nolint: errcheck
+		}
+		return proto.ErrInvalidPullRequestRef
+	}
+
+	violations, err = d.PushPolicyViolations(ctx, r, args)
+	if err != nil {
+		d.logger.Error("error checking push policy", "repo", repo, "err", err)
+		return err
+	}
+
+	if len(violations) > 0 {
+		for _, v := range violations {
+			fmt.Fprintln(stderr, "error:", v) //nolint: errcheck
+		}
+		return proto.ErrPushPolicy
+	}
+
+	violations, err = d.QuotaViolations(ctx, r, args)
+	if err != nil {
+		d.logger.Error("error checking quotas", "repo", repo, "err", err)
+		return err
+	}
+
+	if len(violations) > 0 {
+		for _, v := range violations {
+			fmt.Fprintln(stderr, "error:", v) //nolint: errcheck
+		}
+		return proto.ErrQuotaExceeded
+	}
+
+	return nil
 }
 
 // Update is called by the git update hook.
@@ -35,29 +188,17 @@ func (d *Backend) PreReceive(_ context.Context, _ io.Writer, _ io.Writer, repo s
 func (d *Backend) Update(ctx context.Context, _ io.Writer, _ io.Writer, repo string, arg hooks.HookArg) {
 	d.logger.Debug("update hook called", "repo", repo, "arg", arg)
 
-	// Find user
-	var user proto.User
-	if pubkey := os.Getenv("SOFT_SERVE_PUBLIC_KEY"); pubkey != "" {
-		pk, _, err := sshutils.ParseAuthorizedKey(pubkey)
-		if err != nil {
-			d.logger.Error("error parsing public key", "err", err)
-			return
-		}
+	// Pull request refs are not branches or tags, pull requests have their
+	// own events.
+	if strings.HasPrefix(arg.RefName, RefsFor) || strings.HasPrefix(arg.RefName, RefsPull) {
+		return
+	}
 
-		user, err = d.UserByPublicKey(ctx, pk)
-		if err != nil {
-			d.logger.Error("error finding user from public key", "key", pubkey, "err", err)
-			return
-		}
-	} else if username := os.Getenv("SOFT_SERVE_USERNAME"); username != "" {
-		var err error
-		user, err = d.User(ctx, username)
-		if err != nil {
-			d.logger.Error("error finding user from username", "username", username, "err", err)
-			return
-		}
-	} else {
-		d.logger.Error("error finding user")
+	// Find user, anonymous and deploy key pushes still send their events
+	// without a sender.
+	user, err := d.hookUser(ctx)
+	if err != nil && !errors.Is(err, errHookUserUnknown) && !errors.Is(err, proto.ErrUserNotFound) {
+		d.logger.Error("error finding user", "err", err)
 		return
 	}
 
@@ -68,21 +209,21 @@ func (d *Backend) Update(ctx context.Context, _ io.Writer, _ io.Writer, repo str
 		return
 	}
 
-	// TODO: run this async
-	// This would probably need something like an RPC server to communicate with the hook process.
+	// Webhooks are only queued here; the server delivers them in the
+	// background so a slow endpoint never holds up the push.
 	if git.IsZeroHash(arg.OldSha) || git.IsZeroHash(arg.NewSha) {
 		wh, err := webhook.NewBranchTagEvent(ctx, user, r, arg.RefName, arg.OldSha, arg.NewSha)
 		if err != nil {
 			d.logger.Error("error creating branch_tag webhook", "err", err)
 		} else if err := webhook.SendEvent(ctx, wh); err != nil {
-			d.logger.Error("error sending branch_tag webhook", "err", err)
+			d.logger.Error("error queuing branch_tag webhook", "err", err)
 		}
 	}
-	wh, err := webhook.NewPushEvent(ctx, user, r, arg.RefName, arg.OldSha, arg.NewSha)
+	wh, err := d.newPushEvent(ctx, user, r, arg.RefName, arg.OldSha, arg.NewSha)
 	if err != nil {
 		d.logger.Error("error creating push webhook", "err", err)
 	} else if err := webhook.SendEvent(ctx, wh); err != nil {
-		d.logger.Error("error sending push webhook", "err", err)
+		d.logger.Error("error queuing push webhook", "err", err)
 	}
 }
 
@@ -92,6 +233,12 @@ func (d *Backend) Update(ctx context.Context, _ io.Writer, _ io.Writer, repo str
 func (d *Backend) PostUpdate(ctx context.Context, _ io.Writer, _ io.Writer, repo string, args ...string) {
 	d.logger.Debug("post-update hook called", "repo", repo, "args", args)
 
+	// Push mirrors are only queued here; the server pushes to them in the
+	// background so a slow remote never holds up the push.
+	if err := d.QueuePushMirrorSync(ctx, repo); err != nil {
+		d.logger.Error("error queuing push mirror sync", "repo", repo, "err", err)
+	}
+
 	var wg sync.WaitGroup
 
 	// Populate last-modified file.
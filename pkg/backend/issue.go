@@ -0,0 +1,419 @@
+package backend
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/db/models"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/utils"
+	"github.com/charmbracelet/soft-serve/pkg/webhook"
+)
+
+// IssueState is the state of an issue.
+type IssueState string
+
+const (
+	// IssueStateOpen is an open issue.
+	IssueStateOpen IssueState = "open"
+	// IssueStateClosed is a closed issue.
+	IssueStateClosed IssueState = "closed"
+)
+
+// ParseIssueState parses an issue state. An empty string is returned for
+// unknown states.
+func ParseIssueState(s string) IssueState {
+	switch st := IssueState(strings.ToLower(s)); st {
+	case IssueStateOpen, IssueStateClosed:
+		return st
+	default:
+		return ""
+	}
+}
+
+// Issue is an issue of a repository.
+type Issue struct {
+	Number int64
+	Title  string
+	Body   string
+	// Author is the username of the user who opened the issue. It is empty
+	// if the user no longer exists.
+	Author    string
+	State     IssueState
+	Labels    []string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// IssueComment is a comment on an issue.
+type IssueComment struct {
+	// Author is the username of the commenter. It is empty if the user no
+	// longer exists.
+	Author    string
+	Body      string
+	CreatedAt time.Time
+}
+
+// CreateIssue opens an issue with the given labels.
+func (d *Backend) CreateIssue(ctx context.Context, repo string, user proto.User, title string, body string, labels []string) (Issue, error) {
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return Issue{}, proto.ErrEmptyTitle
+	}
+
+	labels, err := normalizeLabels(labels)
+	if err != nil {
+		return Issue{}, err
+	}
+
+	repo = utils.SanitizeRepo(repo)
+	r, err := d.Repository(ctx, repo)
+	if err != nil {
+		return Issue{}, err
+	}
+
+	var number int64
+	if err := d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		number, err = d.store.CreateIssue(ctx, tx, repo, userID(user), title, strings.TrimSpace(body))
+		if err != nil {
+			return err
+		}
+
+		m, err := d.store.GetIssueByRepoAndNumber(ctx, tx, repo, number)
+		if err != nil {
+			return err
+		}
+
+		for _, label := range labels {
+			if err := d.store.AddIssueLabel(ctx, tx, m.ID, label); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return Issue{}, db.WrapError(err)
+	}
+
+	d.sendIssueEvent(ctx, user, r, number, webhook.IssueEventActionOpened, "", "")
+
+	return d.Issue(ctx, repo, number)
+}
+
+// Issues returns the issues of a repository, newest first. An empty state
+// returns issues in any state, and an empty label returns issues regardless
+// of their labels.
+func (d *Backend) Issues(ctx context.Context, repo string, state IssueState, label string) ([]Issue, error) {
+	repo = utils.SanitizeRepo(repo)
+	var issues []Issue
+	if err := d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		ms, err := d.store.ListIssuesByRepo(ctx, tx, repo, string(state), strings.ToLower(label))
+		if err != nil {
+			return err
+		}
+
+		issues = make([]Issue, 0, len(ms))
+		for _, m := range ms {
+			issue, err := d.issue(ctx, tx, m)
+			if err != nil {
+				return err
+			}
+			issues = append(issues, issue)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, db.WrapError(err)
+	}
+
+	return issues, nil
+}
+
+// Issue returns an issue of a repository by number.
+func (d *Backend) Issue(ctx context.Context, repo string, number int64) (Issue, error) {
+	repo = utils.SanitizeRepo(repo)
+	var issue Issue
+	if err := d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		m, err := d.issueModel(ctx, tx, repo, number)
+		if err != nil {
+			return err
+		}
+
+		issue, err = d.issue(ctx, tx, m)
+		return err
+	}); err != nil {
+		return Issue{}, db.WrapError(err)
+	}
+
+	return issue, nil
+}
+
+// IssueComments returns the comments on an issue, oldest first.
+func (d *Backend) IssueComments(ctx context.Context, repo string, number int64) ([]IssueComment, error) {
+	repo = utils.SanitizeRepo(repo)
+	var comments []IssueComment
+	if err := d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		m, err := d.issueModel(ctx, tx, repo, number)
+		if err != nil {
+			return err
+		}
+
+		ms, err := d.store.ListIssueComments(ctx, tx, m.ID)
+		if err != nil {
+			return err
+		}
+
+		comments = make([]IssueComment, len(ms))
+		for i, m := range ms {
+			author, err := d.username(ctx, tx, m.UserID.Int64)
+			if err != nil {
+				return err
+			}
+
+			comments[i] = IssueComment{
+				Author:    author,
+				Body:      m.Body,
+				CreatedAt: m.CreatedAt,
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return nil, db.WrapError(err)
+	}
+
+	return comments, nil
+}
+
+// CommentIssue adds a comment to an issue.
+func (d *Backend) CommentIssue(ctx context.Context, repo string, number int64, user proto.User, body string) error {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return proto.ErrEmptyComment
+	}
+
+	repo = utils.SanitizeRepo(repo)
+	r, err := d.Repository(ctx, repo)
+	if err != nil {
+		return err
+	}
+
+	if err := db.WrapError(
+		d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+			m, err := d.issueModel(ctx, tx, repo, number)
+			if err != nil {
+				return err
+			}
+
+			return d.store.CreateIssueComment(ctx, tx, m.ID, userID(user), body)
+		}),
+	); err != nil {
+		return err
+	}
+
+	d.sendIssueEvent(ctx, user, r, number, webhook.IssueEventActionCommented, body, "")
+
+	return nil
+}
+
+// CloseIssue closes an open issue.
+func (d *Backend) CloseIssue(ctx context.Context, repo string, number int64, user proto.User) error {
+	return d.setIssueState(ctx, repo, number, user, IssueStateClosed)
+}
+
+// ReopenIssue reopens a closed issue.
+func (d *Backend) ReopenIssue(ctx context.Context, repo string, number int64, user proto.User) error {
+	return d.setIssueState(ctx, repo, number, user, IssueStateOpen)
+}
+
+func (d *Backend) setIssueState(ctx context.Context, repo string, number int64, user proto.User, state IssueState) error {
+	repo = utils.SanitizeRepo(repo)
+	r, err := d.Repository(ctx, repo)
+	if err != nil {
+		return err
+	}
+
+	if err := db.WrapError(
+		d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+			m, err := d.issueModel(ctx, tx, repo, number)
+			if err != nil {
+				return err
+			}
+
+			if IssueState(m.State) == state {
+				if state == IssueStateClosed {
+					return proto.ErrIssueNotOpen
+				}
+				return proto.ErrIssueNotClosed
+			}
+
+			return d.store.UpdateIssueStateByID(ctx, tx, m.ID, string(state))
+		}),
+	); err != nil {
+		return err
+	}
+
+	action := webhook.IssueEventActionClosed
+	if state == IssueStateOpen {
+		action = webhook.IssueEventActionReopened
+	}
+	d.sendIssueEvent(ctx, user, r, number, action, "", "")
+
+	return nil
+}
+
+// AddIssueLabels adds labels to an issue. Labels the issue already has are
+// ignored.
+func (d *Backend) AddIssueLabels(ctx context.Context, repo string, number int64, user proto.User, labels ...string) error {
+	return d.updateIssueLabels(ctx, repo, number, user, labels, true)
+}
+
+// RemoveIssueLabels removes labels from an issue. Labels the issue doesn't
+// have are ignored.
+func (d *Backend) RemoveIssueLabels(ctx context.Context, repo string, number int64, user proto.User, labels ...string) error {
+	return d.updateIssueLabels(ctx, repo, number, user, labels, false)
+}
+
+func (d *Backend) updateIssueLabels(ctx context.Context, repo string, number int64, user proto.User, labels []string, add bool) error {
+	labels, err := normalizeLabels(labels)
+	if err != nil {
+		return err
+	}
+
+	repo = utils.SanitizeRepo(repo)
+	r, err := d.Repository(ctx, repo)
+	if err != nil {
+		return err
+	}
+
+	// Only labels that were actually added or removed trigger events.
+	var changed []string
+	if err := db.WrapError(
+		d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+			m, err := d.issueModel(ctx, tx, repo, number)
+			if err != nil {
+				return err
+			}
+
+			current, err := d.store.ListIssueLabels(ctx, tx, m.ID)
+			if err != nil {
+				return err
+			}
+
+			has := make(map[string]bool, len(current))
+			for _, l := range current {
+				has[l] = true
+			}
+
+			for _, label := range labels {
+				if has[label] == add {
+					continue
+				}
+
+				if add {
+					err = d.store.AddIssueLabel(ctx, tx, m.ID, label)
+				} else {
+					err = d.store.RemoveIssueLabel(ctx, tx, m.ID, label)
+				}
+				if err != nil {
+					return err
+				}
+
+				changed = append(changed, label)
+			}
+
+			return nil
+		}),
+	); err != nil {
+		return err
+	}
+
+	action := webhook.IssueEventActionLabeled
+	if !add {
+		action = webhook.IssueEventActionUnlabeled
+	}
+	for _, label := range changed {
+		d.sendIssueEvent(ctx, user, r, number, action, "", label)
+	}
+
+	return nil
+}
+
+// normalizeLabels validates labels and returns them lowercased, without
+// duplicates.
+func normalizeLabels(labels []string) ([]string, error) {
+	seen := make(map[string]bool, len(labels))
+	normalized := make([]string, 0, len(labels))
+	for _, label := range labels {
+		label = strings.ToLower(strings.TrimSpace(label))
+		if err := utils.ValidateLabel(label); err != nil {
+			return nil, err
+		}
+
+		if !seen[label] {
+			seen[label] = true
+			normalized = append(normalized, label)
+		}
+	}
+
+	return normalized, nil
+}
+
+// sendIssueEvent queues an issue webhook event. Failing to do so does not
+// fail the action that triggered it.
+func (d *Backend) sendIssueEvent(ctx context.Context, user proto.User, r proto.Repository, number int64, action webhook.IssueEventAction, comment string, label string) {
+	// Events need a sender.
+	if user == nil {
+		return
+	}
+
+	wh, err := webhook.NewIssueEvent(ctx, user, r, number, action)
+	if err != nil {
+		d.logger.Error("error creating issue webhook", "err", err)
+		return
+	}
+
+	wh.Comment = comment
+	wh.Label = label
+	if err := webhook.SendEvent(ctx, wh); err != nil {
+		d.logger.Error("error queuing issue webhook", "err", err)
+	}
+}
+
+func (d *Backend) issueModel(ctx context.Context, tx *db.Tx, repo string, number int64) (models.Issue, error) {
+	m, err := d.store.GetIssueByRepoAndNumber(ctx, tx, repo, number)
+	if err != nil {
+		if errors.Is(db.WrapError(err), db.ErrRecordNotFound) {
+			return models.Issue{}, proto.ErrIssueNotFound
+		}
+		return models.Issue{}, err
+	}
+
+	return m, nil
+}
+
+func (d *Backend) issue(ctx context.Context, tx *db.Tx, m models.Issue) (Issue, error) {
+	author, err := d.username(ctx, tx, m.UserID.Int64)
+	if err != nil {
+		return Issue{}, err
+	}
+
+	labels, err := d.store.ListIssueLabels(ctx, tx, m.ID)
+	if err != nil {
+		return Issue{}, err
+	}
+
+	return Issue{
+		Number:    m.Number,
+		Title:     m.Title,
+		Body:      m.Body,
+		Author:    author,
+		State:     IssueState(m.State),
+		Labels:    labels,
+		CreatedAt: m.CreatedAt,
+		UpdatedAt: m.UpdatedAt,
+	}, nil
+}
@@ -0,0 +1,65 @@
+package backend
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/matryer/is"
+)
+
+func TestIssues(t *testing.T) {
+	is := is.New(t)
+	be, _ := newTestBackend(t)
+	ctx := context.Background()
+
+	_, err := be.CreateRepository(ctx, "repo", nil, proto.RepositoryOptions{})
+	is.NoErr(err)
+
+	_, err = be.CreateIssue(ctx, "repo", nil, " ", "", nil)
+	is.True(errors.Is(err, proto.ErrEmptyTitle))
+	_, err = be.CreateIssue(ctx, "repo", nil, "Bad label", "", []string{"not ok"})
+	is.True(err != nil)
+
+	bug, err := be.CreateIssue(ctx, "repo", nil, "Crash on start", "It crashes.", []string{"Bug", "bug", "p1"})
+	is.NoErr(err)
+	is.Equal(bug.Number, int64(1))
+	is.Equal(bug.State, IssueStateOpen)
+	is.Equal(bug.Labels, []string{"bug", "p1"})
+
+	feature, err := be.CreateIssue(ctx, "repo", nil, "Add dark mode", "", nil)
+	is.NoErr(err)
+	is.Equal(feature.Number, int64(2))
+
+	is.NoErr(be.AddIssueLabels(ctx, "repo", 2, nil, "enhancement", "p1"))
+	is.NoErr(be.RemoveIssueLabels(ctx, "repo", 1, nil, "p1", "missing"))
+
+	issues, err := be.Issues(ctx, "repo", IssueStateOpen, "p1")
+	is.NoErr(err)
+	is.Equal(len(issues), 1)
+	is.Equal(issues[0].Number, int64(2))
+	is.Equal(issues[0].Labels, []string{"enhancement", "p1"})
+
+	is.NoErr(be.CommentIssue(ctx, "repo", 1, nil, "Fixed in main."))
+	is.True(errors.Is(be.CommentIssue(ctx, "repo", 1, nil, "  "), proto.ErrEmptyComment))
+	comments, err := be.IssueComments(ctx, "repo", 1)
+	is.NoErr(err)
+	is.Equal(len(comments), 1)
+	is.Equal(comments[0].Body, "Fixed in main.")
+
+	is.True(errors.Is(be.ReopenIssue(ctx, "repo", 1, nil), proto.ErrIssueNotClosed))
+	is.NoErr(be.CloseIssue(ctx, "repo", 1, nil))
+	is.True(errors.Is(be.CloseIssue(ctx, "repo", 1, nil), proto.ErrIssueNotOpen))
+
+	issues, err = be.Issues(ctx, "repo", IssueStateOpen, "")
+	is.NoErr(err)
+	is.Equal(len(issues), 1)
+	issues, err = be.Issues(ctx, "repo", "", "")
+	is.NoErr(err)
+	is.Equal(len(issues), 2)
+
+	is.NoErr(be.ReopenIssue(ctx, "repo", 1, nil))
+	_, err = be.Issue(ctx, "repo", 3)
+	is.True(errors.Is(err, proto.ErrIssueNotFound))
+}
@@ -5,25 +5,21 @@ import (
 	"errors"
 	"io"
 	"path"
-	"path/filepath"
-	"strconv"
 
 	"github.com/charmbracelet/soft-serve/pkg/config"
 	"github.com/charmbracelet/soft-serve/pkg/db"
 	"github.com/charmbracelet/soft-serve/pkg/lfs"
 	"github.com/charmbracelet/soft-serve/pkg/proto"
-	"github.com/charmbracelet/soft-serve/pkg/storage"
 	"github.com/charmbracelet/soft-serve/pkg/store"
 )
 
 // StoreRepoMissingLFSObjects stores missing LFS objects for a repository.
 func StoreRepoMissingLFSObjects(ctx context.Context, repo proto.Repository, dbx *db.DB, store store.Store, lfsClient lfs.Client) error {
-	cfg := config.FromContext(ctx)
-	repoID := strconv.FormatInt(repo.ID(), 10)
-	lfsRoot := filepath.Join(cfg.DataPath, "lfs", repoID)
+	strg, err := config.LFSStorage(config.FromContext(ctx), repo.ID())
+	if err != nil {
+		return err
+	}
 
-	// TODO: support S3 storage
-	strg := storage.NewLocalStorage(lfsRoot)
 	pointerChan := make(chan lfs.PointerBlob)
 	errChan := make(chan error, 1)
 	r, err := repo.Open()
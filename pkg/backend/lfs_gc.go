@@ -0,0 +1,158 @@
+package backend
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"path"
+	"time"
+
+	"github.com/charmbracelet/soft-serve/pkg/config"
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/db/models"
+	"github.com/charmbracelet/soft-serve/pkg/lfs"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/task"
+	"github.com/charmbracelet/soft-serve/pkg/utils"
+)
+
+// LFSGCResult is the outcome of an LFS garbage collection run.
+type LFSGCResult struct {
+	// Objects are the unreferenced objects past the grace period. They are
+	// deleted unless the run was a dry run.
+	Objects []lfs.Pointer
+	// Size is the total size of Objects.
+	Size int64
+	// Kept is the number of unreferenced objects still within the grace
+	// period.
+	Kept int
+}
+
+// GarbageCollectLFS deletes the LFS objects of a repository that no ref
+// points to anymore, once they are older than the configured grace period.
+// With dryRun, objects are only reported.
+//
+// It returns proto.ErrRepoPushInProgress rather than run under a push, and
+// task.ErrAlreadyStarted if the repository is already being collected.
+func (d *Backend) GarbageCollectLFS(ctx context.Context, repo string, dryRun bool) (LFSGCResult, error) {
+	repo = utils.SanitizeRepo(repo)
+	r, err := d.Repository(ctx, repo)
+	if err != nil {
+		return LFSGCResult{}, err
+	}
+
+	rr, err := r.Open()
+	if err != nil {
+		return LFSGCResult{}, err
+	}
+
+	// A push may reference objects uploaded just before it.
+	if rr.IsReceiving() {
+		return LFSGCResult{}, proto.ErrRepoPushInProgress
+	}
+
+	tid := "lfs-gc:" + repo
+	if d.manager.Exists(tid) {
+		return LFSGCResult{}, task.ErrAlreadyStarted
+	}
+
+	var res LFSGCResult
+	done := make(chan error, 1)
+	d.manager.Add(tid, func(ctx context.Context) error {
+		// List the objects before scanning the refs, an object uploaded in
+		// between can't be mistaken for an unreferenced one.
+		var objs []models.LFSObject
+		if err := d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+			var err error
+			objs, err = d.store.GetLFSObjects(ctx, tx, r.ID())
+			return err
+		}); err != nil {
+			return db.WrapError(err)
+		}
+
+		if len(objs) == 0 {
+			return nil
+		}
+
+		referenced, err := lfsReferencedOids(ctx, r)
+		if err != nil {
+			return err
+		}
+
+		grace := time.Duration(d.cfg.LFS.GCGracePeriod) * time.Second
+		cutoff := time.Now().Add(-grace)
+		for _, obj := range objs {
+			if _, ok := referenced[obj.Oid]; ok {
+				continue
+			}
+
+			if obj.CreatedAt.After(cutoff) {
+				res.Kept++
+				continue
+			}
+
+			res.Objects = append(res.Objects, lfs.Pointer{Oid: obj.Oid, Size: obj.Size})
+			res.Size += obj.Size
+		}
+
+		if dryRun || len(res.Objects) == 0 {
+			return nil
+		}
+
+		strg, err := config.LFSStorage(d.cfg, r.ID())
+		if err != nil {
+			return err
+		}
+
+		for _, p := range res.Objects {
+			// Delete the row first: a file left behind only wastes space,
+			// while a row without its file is advertised as present.
+			if err := db.WrapError(
+				d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+					return d.store.DeleteLFSObjectByOid(ctx, tx, r.ID(), p.Oid)
+				}),
+			); err != nil {
+				return err
+			}
+
+			if err := strg.Delete(path.Join("objects", p.RelativePath())); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return err
+			}
+		}
+
+		d.logger.Info("deleted unreferenced lfs objects", "repo", repo, "count", len(res.Objects), "size", res.Size)
+		return nil
+	})
+
+	d.manager.Run(tid, done)
+
+	if err := <-done; err != nil {
+		return LFSGCResult{}, err
+	}
+
+	return res, nil
+}
+
+// lfsReferencedOids returns the oids of the LFS pointers reachable from any
+// ref of a repository.
+func lfsReferencedOids(ctx context.Context, repo proto.Repository) (map[string]struct{}, error) {
+	r, err := repo.Open()
+	if err != nil {
+		return nil, err
+	}
+
+	pointerChan := make(chan lfs.PointerBlob)
+	errChan := make(chan error, 1)
+	go lfs.SearchPointerBlobs(ctx, r, pointerChan, errChan)
+
+	oids := make(map[string]struct{})
+	for p := range pointerChan {
+		oids[p.Oid] = struct{}{}
+	}
+
+	if err, ok := <-errChan; ok && err != nil {
+		return nil, err
+	}
+
+	return oids, nil
+}
@@ -0,0 +1,100 @@
+package backend
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/lfs"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/storage"
+	"github.com/matryer/is"
+)
+
+func TestGarbageCollectLFS(t *testing.T) {
+	is := is.New(t)
+	be, cfg := newTestBackend(t)
+	cfg.LFS.Enabled = true
+	cfg.LFS.GCGracePeriod = 0
+	ctx := context.Background()
+
+	repo, err := be.CreateRepository(ctx, "repo", nil, proto.RepositoryOptions{})
+	is.NoErr(err)
+
+	// main points to one object, a feature branch to another, and nothing
+	// points to the last one.
+	onMain := lfs.Pointer{Oid: strings.Repeat("a", 64), Size: 10}
+	onBranch := lfs.Pointer{Oid: strings.Repeat("b", 64), Size: 20}
+	orphan := lfs.Pointer{Oid: strings.Repeat("c", 64), Size: 30}
+	strg := storage.NewLocalStorage(filepath.Join(cfg.DataPath, "lfs", strconv.FormatInt(repo.ID(), 10)))
+	for _, p := range []lfs.Pointer{onMain, onBranch, orphan} {
+		_, err := strg.Put(path.Join("objects", p.RelativePath()), strings.NewReader(strings.Repeat("x", int(p.Size))))
+		is.NoErr(err)
+		is.NoErr(be.store.CreateLFSObject(ctx, be.db, repo.ID(), p.Oid, p.Size))
+	}
+
+	work := t.TempDir()
+	gitOutput(t, work, "init", "-q", "-b", "main")
+	is.NoErr(os.WriteFile(filepath.Join(work, "main.bin"), []byte(onMain.String()), 0o644))
+	gitOutput(t, work, "add", ".")
+	gitOutput(t, work, "commit", "-q", "-m", "main")
+	gitOutput(t, work, "checkout", "-q", "-b", "feature")
+	is.NoErr(os.WriteFile(filepath.Join(work, "feature.bin"), []byte(onBranch.String()), 0o644))
+	gitOutput(t, work, "add", ".")
+	gitOutput(t, work, "commit", "-q", "-m", "feature")
+	gitOutput(t, be.repoPath("repo"), "fetch", "-q", work, "refs/heads/main:refs/heads/main", "refs/heads/feature:refs/heads/feature")
+
+	exists := func(p lfs.Pointer) bool {
+		t.Helper()
+		ok, err := strg.Exists(path.Join("objects", p.RelativePath()))
+		is.NoErr(err)
+		_, err = be.store.GetLFSObjectByOid(ctx, be.db, repo.ID(), p.Oid)
+		is.Equal(ok, err == nil) // file and row must go together
+		if err != nil {
+			is.True(errors.Is(err, db.ErrRecordNotFound))
+		}
+		return ok
+	}
+
+	// A dry run only reports.
+	res, err := be.GarbageCollectLFS(ctx, "repo", true)
+	is.NoErr(err)
+	is.Equal(res.Objects, []lfs.Pointer{orphan})
+	is.Equal(res.Size, int64(30))
+	is.True(exists(orphan))
+
+	res, err = be.GarbageCollectLFS(ctx, "repo", false)
+	is.NoErr(err)
+	is.Equal(res.Objects, []lfs.Pointer{orphan})
+	is.True(!exists(orphan))
+	is.True(exists(onMain))
+	is.True(exists(onBranch))
+
+	// Deleting the branch releases its object, unless it's still within the
+	// grace period.
+	gitOutput(t, be.repoPath("repo"), "update-ref", "-d", "refs/heads/feature")
+	cfg.LFS.GCGracePeriod = 3600
+	res, err = be.GarbageCollectLFS(ctx, "repo", false)
+	is.NoErr(err)
+	is.Equal(len(res.Objects), 0)
+	is.Equal(res.Kept, 1)
+	is.True(exists(onBranch))
+
+	cfg.LFS.GCGracePeriod = 0
+	res, err = be.GarbageCollectLFS(ctx, "repo", false)
+	is.NoErr(err)
+	is.Equal(res.Objects, []lfs.Pointer{onBranch})
+	is.True(!exists(onBranch))
+	is.True(exists(onMain))
+
+	// Nothing is collected under a push.
+	is.NoErr(os.Mkdir(filepath.Join(be.repoPath("repo"), "objects", "tmp_objdir-incoming-abc123"), 0o755))
+	_, err = be.GarbageCollectLFS(ctx, "repo", false)
+	is.True(errors.Is(err, proto.ErrRepoPushInProgress))
+}
@@ -0,0 +1,87 @@
+package backend
+
+import (
+	"context"
+	"time"
+
+	"github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/task"
+	"github.com/charmbracelet/soft-serve/pkg/utils"
+)
+
+// MaintenanceResult is the outcome of a repository maintenance run.
+type MaintenanceResult struct {
+	// Before is the object store usage before the run.
+	Before git.ObjectCount
+	// After is the object store usage after the run.
+	After git.ObjectCount
+	// Duration is how long the run took.
+	Duration time.Duration
+}
+
+// MaintainRepository runs housekeeping on a repository: garbage collection,
+// repacking into a multi-pack index with a bitmap, and writing the
+// commit-graph. Full forces a complete gc rather than one that only runs
+// when enough loose objects or packs piled up.
+//
+// It returns proto.ErrRepoPushInProgress rather than repack under a push, and
+// task.ErrAlreadyStarted if the repository is already being maintained.
+func (d *Backend) MaintainRepository(ctx context.Context, repo string, full bool) (MaintenanceResult, error) {
+	repo = utils.SanitizeRepo(repo)
+	r, err := d.Repository(ctx, repo)
+	if err != nil {
+		return MaintenanceResult{}, err
+	}
+
+	rr, err := r.Open()
+	if err != nil {
+		return MaintenanceResult{}, err
+	}
+
+	if rr.IsReceiving() {
+		return MaintenanceResult{}, proto.ErrRepoPushInProgress
+	}
+
+	tid := "maintenance:" + repo
+	if d.manager.Exists(tid) {
+		return MaintenanceResult{}, task.ErrAlreadyStarted
+	}
+
+	var res MaintenanceResult
+	done := make(chan error, 1)
+	d.manager.Add(tid, func(ctx context.Context) error {
+		start := time.Now()
+		before, err := rr.CountObjects()
+		if err != nil {
+			return err
+		}
+
+		if err := rr.Maintenance(ctx, git.MaintenanceOptions{Full: full}); err != nil {
+			d.logger.Error("failed to maintain repository", "repo", repo, "err", err)
+			return err
+		}
+
+		after, err := rr.CountObjects()
+		if err != nil {
+			return err
+		}
+
+		res = MaintenanceResult{
+			Before:   before,
+			After:    after,
+			Duration: time.Since(start),
+		}
+
+		d.logger.Debug("maintained repository", "repo", repo, "before", before.Size(), "after", after.Size(), "duration", res.Duration)
+		return nil
+	})
+
+	d.manager.Run(tid, done)
+
+	if err := <-done; err != nil {
+		return MaintenanceResult{}, err
+	}
+
+	return res, nil
+}
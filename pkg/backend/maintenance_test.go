@@ -0,0 +1,40 @@
+package backend
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/matryer/is"
+)
+
+func TestMaintainRepository(t *testing.T) {
+	is := is.New(t)
+	be, _ := newTestBackend(t)
+	ctx := context.Background()
+
+	_, err := be.CreateRepository(ctx, "repo", nil, proto.RepositoryOptions{})
+	is.NoErr(err)
+	rp := be.repoPath("repo")
+
+	// Write some loose objects.
+	tree := gitOutput(t, rp, "write-tree")
+	gitOutput(t, rp, "update-ref", "refs/heads/main", gitOutput(t, rp, "commit-tree", tree, "-m", "first"))
+
+	res, err := be.MaintainRepository(ctx, "repo", true)
+	is.NoErr(err)
+	is.True(res.Before.LooseObjects > 0)
+	is.Equal(res.After.LooseObjects, int64(0))
+	is.Equal(res.After.Packs, int64(1))
+
+	_, err = be.MaintainRepository(ctx, "nope", true)
+	is.True(errors.Is(err, proto.ErrRepoNotFound))
+
+	// Repositories are not repacked under a push.
+	is.NoErr(os.Mkdir(filepath.Join(rp, "objects", "tmp_objdir-incoming-abc123"), 0o755))
+	_, err = be.MaintainRepository(ctx, "repo", false)
+	is.True(errors.Is(err, proto.ErrRepoPushInProgress))
+}
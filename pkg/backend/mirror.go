@@ -0,0 +1,415 @@
+package backend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/db/models"
+	"github.com/charmbracelet/soft-serve/pkg/lfs"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/ssrf"
+	"github.com/charmbracelet/soft-serve/pkg/task"
+	"github.com/charmbracelet/soft-serve/pkg/utils"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// MinMirrorSyncInterval is the shortest sync interval a mirror can be set to.
+const MinMirrorSyncInterval = time.Minute
+
+var mirrorSyncCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "soft_serve",
+	Subsystem: "mirror",
+	Name:      "sync_total",
+	Help:      "The total number of mirror syncs",
+}, []string{"repo", "status"})
+
+// MirrorSync is the sync schedule and status of a mirror repository.
+type MirrorSync struct {
+	// Interval is the time between syncs, zero to sync on the global
+	// schedule.
+	Interval time.Duration
+	// LastAttemptAt is the time the last sync started, zero if it never ran.
+	LastAttemptAt time.Time
+	// LastSuccessAt is the time the last successful sync started, zero if
+	// no sync succeeded yet.
+	LastSuccessAt time.Time
+	// LastError is the error of the last sync, empty if it succeeded.
+	LastError string
+	// LastDuration is how long the last sync took.
+	LastDuration time.Duration
+}
+
+// Due returns whether the mirror should be synced at now.
+func (s MirrorSync) Due(now time.Time) bool {
+	if s.Interval <= 0 || s.LastAttemptAt.IsZero() {
+		return true
+	}
+
+	// Allow for the second granularity of the stored timestamps, a sync
+	// scheduled right on the interval must not slip to the next run.
+	return !now.Add(time.Second).Before(s.LastAttemptAt.Add(s.Interval))
+}
+
+func newMirrorSync(m models.MirrorSync) MirrorSync {
+	return MirrorSync{
+		Interval:      time.Duration(m.SyncInterval) * time.Second,
+		LastAttemptAt: m.LastAttemptAt.Time,
+		LastSuccessAt: m.LastSuccessAt.Time,
+		LastError:     m.LastError,
+		LastDuration:  time.Duration(m.LastDuration) * time.Millisecond,
+	}
+}
+
+// MirrorSync returns the sync schedule and status of a mirror repository.
+func (d *Backend) MirrorSync(ctx context.Context, repo string) (MirrorSync, error) {
+	repo = utils.SanitizeRepo(repo)
+	if _, err := d.mirrorRepository(ctx, repo); err != nil {
+		return MirrorSync{}, err
+	}
+
+	var m models.MirrorSync
+	if err := db.WrapError(
+		d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+			var err error
+			m, err = d.store.GetMirrorSyncByRepo(ctx, tx, repo)
+			return err
+		}),
+	); err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return MirrorSync{}, nil
+		}
+
+		return MirrorSync{}, err
+	}
+
+	return newMirrorSync(m), nil
+}
+
+// SetMirrorSyncInterval sets the time between syncs of a mirror repository.
+// Zero syncs the mirror on the global schedule.
+func (d *Backend) SetMirrorSyncInterval(ctx context.Context, repo string, interval time.Duration) error {
+	if interval != 0 && interval < MinMirrorSyncInterval {
+		return fmt.Errorf("%w: must be at least %s", proto.ErrInvalidMirrorSyncInterval, MinMirrorSyncInterval)
+	}
+
+	repo = utils.SanitizeRepo(repo)
+	if _, err := d.mirrorRepository(ctx, repo); err != nil {
+		return err
+	}
+
+	return db.WrapError(
+		d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+			return d.store.SetMirrorSyncIntervalByRepo(ctx, tx, repo, int64(interval/time.Second))
+		}),
+	)
+}
+
+// mirrorRepository returns the repository if it is a mirror.
+func (d *Backend) mirrorRepository(ctx context.Context, repo string) (proto.Repository, error) {
+	r, err := d.Repository(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	if !r.IsMirror() {
+		return nil, proto.ErrRepoNotMirror
+	}
+
+	return r, nil
+}
+
+// SyncMirror fetches a mirror repository from its remotes and records the
+// outcome. It returns task.ErrAlreadyStarted if the mirror is already being
+// synced.
+func (d *Backend) SyncMirror(ctx context.Context, repo string) error {
+	repo = utils.SanitizeRepo(repo)
+	r, err := d.mirrorRepository(ctx, repo)
+	if err != nil {
+		return err
+	}
+
+	tid := "mirror-sync:" + repo
+	if d.manager.Exists(tid) {
+		return task.ErrAlreadyStarted
+	}
+
+	done := make(chan error, 1)
+	d.manager.Add(tid, func(ctx context.Context) error {
+		start := time.Now()
+		serr := d.syncMirror(ctx, r)
+
+		status := "success"
+		var lastError string
+		if serr != nil {
+			status = "failed"
+			lastError = serr.Error()
+			d.logger.Error("failed to sync mirror", "repo", repo, "err", serr)
+		}
+
+		mirrorSyncCounter.WithLabelValues(repo, status).Inc()
+		if err := db.WrapError(
+			d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+				return d.store.SetMirrorSyncStatusByRepo(ctx, tx, repo, start, lastError, time.Since(start).Milliseconds())
+			}),
+		); err != nil {
+			d.logger.Error("failed to record mirror sync status", "repo", repo, "err", err)
+		}
+
+		return serr
+	})
+
+	d.manager.Run(tid, done)
+
+	return <-done
+}
+
+// syncMirror fetches every remote of a mirror repository, and its missing LFS
+// objects if LFS is enabled.
+func (d *Backend) syncMirror(ctx context.Context, repo proto.Repository) error {
+	r, err := repo.Open()
+	if err != nil {
+		return err
+	}
+
+	name := repo.Name()
+	creds, err := d.MirrorCredentials(ctx, name)
+	if err != nil {
+		return fmt.Errorf("reading credentials: %w", err)
+	}
+
+	// Re-validate every configured remote before syncing. `remote update`
+	// touches all of them, and a remote may predate the import-time guard
+	// or have been written out of band. Validation failure skips the repo
+	// entirely.
+	remoteEnv, err := validateMirrorRemotes(r, creds)
+	if err != nil {
+		return fmt.Errorf("%w: %w", proto.ErrInvalidRemote, err)
+	}
+
+	sshCommand, cleanup, err := MirrorSSHCommand(d.cfg, creds)
+	if err != nil {
+		return err
+	}
+
+	defer cleanup()
+
+	// remoteEnv carries the SSRF guard and must reach every command below,
+	// so build the full set once.
+	syncEnv := append(remoteEnv, sshCommand)
+
+	cmds := []string{
+		"fetch --prune",         // fetch prune before updating remote
+		"remote update --prune", // update remote and prune remote refs
+	}
+
+	for _, c := range cmds {
+		args := strings.Split(c, " ")
+		cmd := git.NewCommand(args...).WithContext(ctx).WithTimeout(-1)
+		cmd.AddEnvs(syncEnv...)
+
+		if _, err := cmd.RunInDir(r.Path); err != nil {
+			return errors.New(redactMirrorRemotes(err.Error(), r))
+		}
+	}
+
+	if !d.cfg.LFS.Enabled {
+		return nil
+	}
+
+	rcfg, err := r.Config()
+	if err != nil {
+		return err
+	}
+
+	lfsEndpoint := rcfg.Section("lfs").Option("url")
+	if lfsEndpoint == "" {
+		// If there is no LFS url defined, means the repo doesn't use LFS
+		// and we can skip it.
+		return nil
+	}
+
+	// The endpoint is stored in the repo config and may predate validation,
+	// so check it before dialing.
+	if _, err := ssrf.ValidateGitRemote(lfsEndpoint); err != nil {
+		return fmt.Errorf("lfs endpoint: %w: %w", proto.ErrInvalidRemote, err)
+	}
+
+	ep, err := lfs.NewEndpoint(lfsEndpoint)
+	if err != nil {
+		return err
+	}
+
+	client := lfs.NewClient(ep)
+	if client == nil {
+		// Imports set the endpoint to the remote, which may not speak LFS.
+		d.logger.Warn("failed to create lfs client: unsupported endpoint", "endpoint", lfsEndpoint)
+		return nil
+	}
+
+	if err := StoreRepoMissingLFSObjects(ctx, repo, d.db, d.store, client); err != nil {
+		return fmt.Errorf("storing missing lfs objects: %w", err)
+	}
+
+	return nil
+}
+
+// validateMirrorRemotes validates every remote configured on a mirror
+// repository and returns the git environment that must be applied to the sync
+// commands.
+//
+// `git remote update` fetches from all remotes, not just origin, so every one
+// of them has to pass. Any failure to read or validate skips the sync: a
+// remote that cannot be checked is not one to fetch from.
+//
+// HTTP remotes are authenticated with creds, if any.
+func validateMirrorRemotes(r *git.Repository, creds *proto.MirrorCredentials) ([]string, error) {
+	cfg, err := r.Config()
+	if err != nil {
+		return nil, fmt.Errorf("reading git config: %w", err)
+	}
+
+	var remotes []ssrf.ValidatedGitRemote
+	for _, sub := range cfg.Section("remote").Subsections {
+		url := sub.Option("url")
+		if url == "" {
+			continue
+		}
+
+		v, err := ssrf.ValidateGitRemote(url)
+		if err != nil {
+			return nil, fmt.Errorf("remote %q: %w", sub.Name, err)
+		}
+		if v.Transport == ssrf.GitTransportHTTP {
+			v.Config = append(v.Config, MirrorCredentialsGitConfig(creds, url)...)
+		}
+		remotes = append(remotes, v)
+	}
+
+	if len(remotes) == 0 {
+		return nil, fmt.Errorf("no remote url configured")
+	}
+
+	return ssrf.GitEnv(remotes...), nil
+}
+
+// redactMirrorRemotes redacts the passwords of the remote URLs of r in s.
+func redactMirrorRemotes(s string, r *git.Repository) string {
+	cfg, err := r.Config()
+	if err != nil {
+		return s
+	}
+
+	for _, sub := range cfg.Section("remote").Subsections {
+		if url := sub.Option("url"); url != "" {
+			s = redactURL(s, url)
+		}
+	}
+
+	return s
+}
+
+// MirrorCollector returns a Prometheus collector reporting the sync status of
+// every mirror repository. Values are read from the database on every scrape,
+// so the reported lag is current rather than as of the last sync.
+func (d *Backend) MirrorCollector() prometheus.Collector {
+	return &mirrorCollector{d: d}
+}
+
+var (
+	mirrorSyncLagDesc = prometheus.NewDesc(
+		"soft_serve_mirror_sync_lag_seconds",
+		"Seconds since the last successful sync of a mirror, or since its creation if it never synced",
+		[]string{"repo"}, nil,
+	)
+	mirrorLastSuccessDesc = prometheus.NewDesc(
+		"soft_serve_mirror_last_success_timestamp_seconds",
+		"Unix time of the last successful sync of a mirror",
+		[]string{"repo"}, nil,
+	)
+	mirrorLastDurationDesc = prometheus.NewDesc(
+		"soft_serve_mirror_last_sync_duration_seconds",
+		"Duration of the last sync of a mirror",
+		[]string{"repo"}, nil,
+	)
+	mirrorLastFailedDesc = prometheus.NewDesc(
+		"soft_serve_mirror_last_sync_failed",
+		"Whether the last sync of a mirror failed",
+		[]string{"repo"}, nil,
+	)
+)
+
+type mirrorCollector struct {
+	d *Backend
+}
+
+var _ prometheus.Collector = (*mirrorCollector)(nil)
+
+// Describe implements prometheus.Collector.
+func (c *mirrorCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- mirrorSyncLagDesc
+	ch <- mirrorLastSuccessDesc
+	ch <- mirrorLastDurationDesc
+	ch <- mirrorLastFailedDesc
+}
+
+// Collect implements prometheus.Collector.
+func (c *mirrorCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(c.d.ctx, 10*time.Second)
+	defer cancel()
+
+	repos, err := c.d.Repositories(ctx)
+	if err != nil {
+		c.d.logger.Error("failed to collect mirror metrics", "err", err)
+		return
+	}
+
+	var ms []models.MirrorSync
+	if err := c.d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		var err error
+		ms, err = c.d.store.GetMirrorSyncs(ctx, tx)
+		return err
+	}); err != nil {
+		c.d.logger.Error("failed to collect mirror metrics", "err", db.WrapError(err))
+		return
+	}
+
+	syncs := make(map[int64]MirrorSync, len(ms))
+	for _, m := range ms {
+		syncs[m.RepoID] = newMirrorSync(m)
+	}
+
+	now := time.Now()
+	for _, r := range repos {
+		if !r.IsMirror() {
+			continue
+		}
+
+		name := r.Name()
+		s := syncs[r.ID()]
+		since := r.CreatedAt()
+		if !s.LastSuccessAt.IsZero() {
+			since = s.LastSuccessAt
+			ch <- prometheus.MustNewConstMetric(mirrorLastSuccessDesc, prometheus.GaugeValue, float64(s.LastSuccessAt.Unix()), name)
+		}
+
+		ch <- prometheus.MustNewConstMetric(mirrorSyncLagDesc, prometheus.GaugeValue, now.Sub(since).Seconds(), name)
+
+		if s.LastAttemptAt.IsZero() {
+			continue
+		}
+
+		var failed float64
+		if s.LastError != "" {
+			failed = 1
+		}
+
+		ch <- prometheus.MustNewConstMetric(mirrorLastDurationDesc, prometheus.GaugeValue, s.LastDuration.Seconds(), name)
+		ch <- prometheus.MustNewConstMetric(mirrorLastFailedDesc, prometheus.GaugeValue, failed, name)
+	}
+}
@@ -0,0 +1,297 @@
+package backend
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ed25519"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/soft-serve/pkg/config"
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/db/models"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/ssrf"
+	"github.com/charmbracelet/soft-serve/pkg/utils"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+// mirrorCredentialsInfo is the HKDF info mirror credential keys are derived
+// with.
+const mirrorCredentialsInfo = "soft-serve mirror credentials"
+
+// validateMirrorCredentials checks that c is complete and safe to hand to git.
+func validateMirrorCredentials(c proto.MirrorCredentials) error {
+	if c.Secret == "" {
+		return fmt.Errorf("%w: missing secret", proto.ErrInvalidMirrorCredentials)
+	}
+
+	switch c.Type {
+	case proto.MirrorCredentialsBasic:
+		if c.Username == "" || strings.Contains(c.Username, ":") {
+			return fmt.Errorf("%w: invalid username", proto.ErrInvalidMirrorCredentials)
+		}
+		// The credentials end up in an HTTP header.
+		if strings.ContainsAny(c.Username+c.Secret, "\r\n") {
+			return fmt.Errorf("%w: credentials must be a single line", proto.ErrInvalidMirrorCredentials)
+		}
+	case proto.MirrorCredentialsToken:
+		if c.Username != "" {
+			return fmt.Errorf("%w: tokens don't take a username", proto.ErrInvalidMirrorCredentials)
+		}
+		if strings.ContainsAny(c.Secret, "\r\n") {
+			return fmt.Errorf("%w: credentials must be a single line", proto.ErrInvalidMirrorCredentials)
+		}
+	case proto.MirrorCredentialsSSHKey:
+		if c.Username != "" {
+			return fmt.Errorf("%w: SSH keys don't take a username", proto.ErrInvalidMirrorCredentials)
+		}
+		if _, err := gossh.ParsePrivateKey([]byte(c.Secret)); err != nil {
+			return fmt.Errorf("%w: %w", proto.ErrInvalidMirrorCredentials, err)
+		}
+	default:
+		return fmt.Errorf("%w: unknown type %q", proto.ErrInvalidMirrorCredentials, c.Type)
+	}
+
+	return nil
+}
+
+// SetMirrorCredentials sets the credentials a mirror repository fetches its
+// remotes with. The secret is encrypted before it is stored.
+func (d *Backend) SetMirrorCredentials(ctx context.Context, repo string, c proto.MirrorCredentials) error {
+	if err := validateMirrorCredentials(c); err != nil {
+		return err
+	}
+
+	repo = utils.SanitizeRepo(repo)
+	r, err := d.Repository(ctx, repo)
+	if err != nil {
+		return err
+	}
+
+	secret, err := d.sealMirrorSecret(r.ID(), c.Secret)
+	if err != nil {
+		return err
+	}
+
+	if err := db.WrapError(
+		d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+			return d.store.SetMirrorCredentialByRepo(ctx, tx, repo, models.MirrorCredential{
+				Type:     string(c.Type),
+				Username: c.Username,
+				Secret:   secret,
+			})
+		}),
+	); err != nil {
+		return err
+	}
+
+	d.audit(ctx, AuditRepoSetMirrorCredentials, repo, "type", c.Type, "username", c.Username)
+	return nil
+}
+
+// MirrorCredentials returns the decrypted credentials of a mirror repository,
+// or nil if it has none.
+func (d *Backend) MirrorCredentials(ctx context.Context, repo string) (*proto.MirrorCredentials, error) {
+	repo = utils.SanitizeRepo(repo)
+	r, err := d.Repository(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	var m models.MirrorCredential
+	if err := d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		var err error
+		m, err = d.store.GetMirrorCredentialByRepo(ctx, tx, repo)
+		return err
+	}); err != nil {
+		err = db.WrapError(err)
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	secret, err := d.openMirrorSecret(r.ID(), m.Secret)
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.MirrorCredentials{
+		Type:     proto.MirrorCredentialsType(m.Type),
+		Username: m.Username,
+		Secret:   secret,
+	}, nil
+}
+
+// DeleteMirrorCredentials removes the credentials of a mirror repository.
+func (d *Backend) DeleteMirrorCredentials(ctx context.Context, repo string) error {
+	repo = utils.SanitizeRepo(repo)
+	if _, err := d.Repository(ctx, repo); err != nil {
+		return err
+	}
+
+	if err := db.WrapError(
+		d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+			return d.store.DeleteMirrorCredentialByRepo(ctx, tx, repo)
+		}),
+	); err != nil {
+		return err
+	}
+
+	d.audit(ctx, AuditRepoDeleteMirrorCredentials, repo)
+	return nil
+}
+
+// mirrorCredentialsAEAD returns the cipher mirror secrets are encrypted with.
+// Its key is derived from the server's SSH host key, so replacing the host key
+// makes existing mirror credentials unreadable.
+func (d *Backend) mirrorCredentialsAEAD() (cipher.AEAD, error) {
+	// The SSH server creates the host key on start. Without it, a key
+	// generated on the fly would encrypt secrets nothing can decrypt later.
+	if _, err := os.Stat(d.cfg.SSH.KeyPath); err != nil {
+		return nil, fmt.Errorf("missing SSH host key: %w", err)
+	}
+
+	kp, err := config.KeyPair(d.cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	pk := kp.PrivateKey()
+	if k, ok := pk.(*ed25519.PrivateKey); ok {
+		pk = *k
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(pk)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := hkdf.Key(sha256.New, der, nil, mirrorCredentialsInfo, 32)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// sealMirrorSecret encrypts the secret of a repository's mirror credentials.
+// The ciphertext is bound to the repository ID.
+func (d *Backend) sealMirrorSecret(repoID int64, secret string) (string, error) {
+	aead, err := d.mirrorCredentialsAEAD()
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := aead.Seal(nonce, nonce, []byte(secret), []byte(strconv.FormatInt(repoID, 10)))
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// openMirrorSecret decrypts a secret sealed with sealMirrorSecret.
+func (d *Backend) openMirrorSecret(repoID int64, sealed string) (string, error) {
+	aead, err := d.mirrorCredentialsAEAD()
+	if err != nil {
+		return "", err
+	}
+
+	b, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil || len(b) < aead.NonceSize() {
+		return "", errors.New("malformed mirror credentials")
+	}
+
+	secret, err := aead.Open(nil, b[:aead.NonceSize()], b[aead.NonceSize():], []byte(strconv.FormatInt(repoID, 10)))
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt mirror credentials: %w", err)
+	}
+
+	return string(secret), nil
+}
+
+// MirrorCredentialsGitConfig returns the git configuration that authenticates
+// requests to an HTTP remote with c. The credentials are scoped to the remote
+// URL, and are only ever sent to it since redirects are not followed. It
+// returns nothing for SSH keys, see MirrorSSHCommand.
+func MirrorCredentialsGitConfig(c *proto.MirrorCredentials, remote string) []ssrf.GitConfigEntry {
+	if c == nil {
+		return nil
+	}
+
+	var header string
+	switch c.Type {
+	case proto.MirrorCredentialsBasic:
+		header = "Basic " + base64.StdEncoding.EncodeToString([]byte(c.Username+":"+c.Secret))
+	case proto.MirrorCredentialsToken:
+		header = "Bearer " + c.Secret
+	default:
+		return nil
+	}
+
+	return []ssrf.GitConfigEntry{
+		{Key: "http." + remote + ".extraHeader", Value: "Authorization: " + header},
+	}
+}
+
+// MirrorSSHCommand returns the GIT_SSH_COMMAND environment variable used to
+// fetch from SSH remotes. Mirrors with an SSH key use it instead of the
+// server's client key. The returned function removes the key from disk and
+// must be called once git is done.
+func MirrorSSHCommand(cfg *config.Config, c *proto.MirrorCredentials) (string, func(), error) {
+	knownHosts := filepath.Join(cfg.DataPath, "ssh", "known_hosts")
+	if c == nil || c.Type != proto.MirrorCredentialsSSHKey {
+		return fmt.Sprintf(`GIT_SSH_COMMAND=ssh -o UserKnownHostsFile="%s" -o StrictHostKeyChecking=no -i "%s"`,
+			knownHosts,
+			cfg.SSH.ClientKeyPath,
+		), func() {}, nil
+	}
+
+	// CreateTemp creates the file readable by the owner only, which ssh
+	// insists on for private keys.
+	f, err := os.CreateTemp("", "soft-serve-mirror-key-*")
+	if err != nil {
+		return "", nil, err
+	}
+
+	cleanup := func() { os.Remove(f.Name()) } //nolint: errcheck
+	key := c.Secret
+	if !strings.HasSuffix(key, "\n") {
+		key += "\n"
+	}
+
+	if _, err := f.WriteString(key); err != nil {
+		f.Close() //nolint: errcheck
+		cleanup()
+		return "", nil, err
+	}
+
+	if err := f.Close(); err != nil {
+		cleanup()
+		return "", nil, err
+	}
+
+	return fmt.Sprintf(`GIT_SSH_COMMAND=ssh -o UserKnownHostsFile="%s" -o StrictHostKeyChecking=no -o IdentitiesOnly=yes -i "%s"`,
+		knownHosts,
+		f.Name(),
+	), cleanup, nil
+}
@@ -0,0 +1,170 @@
+package backend
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/keygen"
+	"github.com/charmbracelet/soft-serve/pkg/config"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/ssrf"
+	"github.com/matryer/is"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+// newTestHostKey writes the SSH host key the server would create on start.
+func newTestHostKey(t *testing.T, cfg *config.Config) {
+	t.Helper()
+	if _, err := keygen.New(cfg.SSH.KeyPath, keygen.WithKeyType(keygen.Ed25519), keygen.WithWrite()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMirrorCredentials(t *testing.T) {
+	is := is.New(t)
+	be, cfg := newTestBackend(t)
+	ctx := context.Background()
+
+	_, err := be.CreateRepository(ctx, "repo1", nil, proto.RepositoryOptions{})
+	is.NoErr(err)
+	_, err = be.CreateRepository(ctx, "repo2", nil, proto.RepositoryOptions{})
+	is.NoErr(err)
+
+	c, err := be.MirrorCredentials(ctx, "repo1")
+	is.NoErr(err)
+	is.True(c == nil)
+
+	// Secrets are never encrypted with a key that isn't the host key.
+	basic := proto.MirrorCredentials{Type: proto.MirrorCredentialsBasic, Username: "bob", Secret: "hunter2"}
+	is.True(be.SetMirrorCredentials(ctx, "repo1", basic) != nil)
+	_, err = os.Stat(cfg.SSH.KeyPath)
+	is.True(errors.Is(err, os.ErrNotExist))
+
+	newTestHostKey(t, cfg)
+	is.NoErr(be.SetMirrorCredentials(ctx, "repo1", basic))
+	c, err = be.MirrorCredentials(ctx, "repo1")
+	is.NoErr(err)
+	is.Equal(*c, basic)
+
+	// The secret is encrypted at rest.
+	var stored string
+	is.NoErr(be.db.GetContext(ctx, &stored, `SELECT secret FROM mirror_credentials`))
+	is.True(!strings.Contains(stored, "hunter2"))
+
+	// Setting credentials replaces the current ones.
+	token := proto.MirrorCredentials{Type: proto.MirrorCredentialsToken, Secret: "ghp_token"}
+	is.NoErr(be.SetMirrorCredentials(ctx, "repo1", token))
+	c, err = be.MirrorCredentials(ctx, "repo1")
+	is.NoErr(err)
+	is.Equal(*c, token)
+
+	// Secrets are bound to their repository.
+	is.NoErr(be.SetMirrorCredentials(ctx, "repo2", basic))
+	_, err = be.db.ExecContext(ctx, `UPDATE mirror_credentials SET secret = (SELECT secret FROM mirror_credentials WHERE repo_id = 1) WHERE repo_id = 2`)
+	is.NoErr(err)
+	_, err = be.MirrorCredentials(ctx, "repo2")
+	is.True(err != nil)
+
+	is.NoErr(be.DeleteMirrorCredentials(ctx, "repo1"))
+	c, err = be.MirrorCredentials(ctx, "repo1")
+	is.NoErr(err)
+	is.True(c == nil)
+
+	err = be.SetMirrorCredentials(ctx, "nope", basic)
+	is.True(errors.Is(err, proto.ErrRepoNotFound))
+}
+
+func TestValidateMirrorCredentials(t *testing.T) {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, err := gossh.MarshalPrivateKey(priv, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := string(pem.EncodeToMemory(block))
+
+	tests := []struct {
+		name  string
+		creds proto.MirrorCredentials
+		valid bool
+	}{
+		{"basic", proto.MirrorCredentials{Type: proto.MirrorCredentialsBasic, Username: "bob", Secret: "pass"}, true},
+		{"basic without username", proto.MirrorCredentials{Type: proto.MirrorCredentialsBasic, Secret: "pass"}, false},
+		{"basic username with colon", proto.MirrorCredentials{Type: proto.MirrorCredentialsBasic, Username: "bob:x", Secret: "pass"}, false},
+		{"basic header injection", proto.MirrorCredentials{Type: proto.MirrorCredentialsBasic, Username: "bob", Secret: "pass\r\nX-Evil: 1"}, false},
+		{"token", proto.MirrorCredentials{Type: proto.MirrorCredentialsToken, Secret: "token"}, true},
+		{"token with username", proto.MirrorCredentials{Type: proto.MirrorCredentialsToken, Username: "bob", Secret: "token"}, false},
+		{"token header injection", proto.MirrorCredentials{Type: proto.MirrorCredentialsToken, Secret: "token\nX-Evil: 1"}, false},
+		{"ssh key", proto.MirrorCredentials{Type: proto.MirrorCredentialsSSHKey, Secret: key}, true},
+		{"invalid ssh key", proto.MirrorCredentials{Type: proto.MirrorCredentialsSSHKey, Secret: "not a key"}, false},
+		{"missing secret", proto.MirrorCredentials{Type: proto.MirrorCredentialsToken}, false},
+		{"unknown type", proto.MirrorCredentials{Type: "oauth", Secret: "x"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateMirrorCredentials(tt.creds)
+			if tt.valid && err != nil {
+				t.Fatalf("validateMirrorCredentials() unexpected error: %v", err)
+			}
+			if !tt.valid && !errors.Is(err, proto.ErrInvalidMirrorCredentials) {
+				t.Fatalf("validateMirrorCredentials() error = %v, want %v", err, proto.ErrInvalidMirrorCredentials)
+			}
+		})
+	}
+}
+
+func TestMirrorCredentialsGitConfig(t *testing.T) {
+	is := is.New(t)
+	remote := "https://example.com/repo.git"
+
+	is.Equal(len(MirrorCredentialsGitConfig(nil, remote)), 0)
+	is.Equal(MirrorCredentialsGitConfig(&proto.MirrorCredentials{
+		Type: proto.MirrorCredentialsBasic, Username: "bob", Secret: "pass",
+	}, remote), []ssrf.GitConfigEntry{
+		{Key: "http.https://example.com/repo.git.extraHeader", Value: "Authorization: Basic Ym9iOnBhc3M="},
+	})
+	is.Equal(MirrorCredentialsGitConfig(&proto.MirrorCredentials{
+		Type: proto.MirrorCredentialsToken, Secret: "token",
+	}, remote), []ssrf.GitConfigEntry{
+		{Key: "http.https://example.com/repo.git.extraHeader", Value: "Authorization: Bearer token"},
+	})
+	is.Equal(len(MirrorCredentialsGitConfig(&proto.MirrorCredentials{
+		Type: proto.MirrorCredentialsSSHKey, Secret: "key",
+	}, remote)), 0)
+}
+
+func TestMirrorSSHCommand(t *testing.T) {
+	is := is.New(t)
+	_, cfg := newTestBackend(t)
+
+	cmd, cleanup, err := MirrorSSHCommand(cfg, nil)
+	is.NoErr(err)
+	cleanup()
+	is.True(strings.Contains(cmd, `-i "`+cfg.SSH.ClientKeyPath+`"`))
+
+	cmd, cleanup, err = MirrorSSHCommand(cfg, &proto.MirrorCredentials{Type: proto.MirrorCredentialsSSHKey, Secret: "key"})
+	is.NoErr(err)
+	is.True(strings.Contains(cmd, "IdentitiesOnly=yes"))
+	is.True(!strings.Contains(cmd, cfg.SSH.ClientKeyPath))
+
+	keyPath := cmd[strings.LastIndex(cmd[:len(cmd)-1], `"`)+1 : len(cmd)-1]
+	fi, err := os.Stat(keyPath)
+	is.NoErr(err)
+	is.Equal(fi.Mode().Perm(), os.FileMode(0o600))
+	b, err := os.ReadFile(keyPath)
+	is.NoErr(err)
+	is.Equal(string(b), "key\n")
+
+	cleanup()
+	_, err = os.Stat(keyPath)
+	is.True(errors.Is(err, os.ErrNotExist))
+}
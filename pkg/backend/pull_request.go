@@ -0,0 +1,692 @@
+package backend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/access"
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/db/models"
+	"github.com/charmbracelet/soft-serve/pkg/hooks"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/utils"
+	"github.com/charmbracelet/soft-serve/pkg/webhook"
+)
+
+const (
+	// RefsFor is the prefix of the refs that open a pull request when pushed
+	// to, e.g. "refs/for/main".
+	RefsFor = "refs/for/"
+	// RefsPull is the prefix of the refs that hold the commits of pull
+	// requests opened by pushing to RefsFor.
+	RefsPull = "refs/pull/"
+)
+
+// PullRequestState is the state of a pull request.
+type PullRequestState string
+
+const (
+	// PullRequestStateOpen is an open pull request.
+	PullRequestStateOpen PullRequestState = "open"
+	// PullRequestStateMerged is a merged pull request.
+	PullRequestStateMerged PullRequestState = "merged"
+	// PullRequestStateClosed is a pull request closed without merging.
+	PullRequestStateClosed PullRequestState = "closed"
+)
+
+// ParsePullRequestState parses a pull request state. An empty string is
+// returned for unknown states.
+func ParsePullRequestState(s string) PullRequestState {
+	switch st := PullRequestState(strings.ToLower(s)); st {
+	case PullRequestStateOpen, PullRequestStateMerged, PullRequestStateClosed:
+		return st
+	default:
+		return ""
+	}
+}
+
+// PullRequest is a request to merge a ref into a branch of a repository.
+type PullRequest struct {
+	Number      int64
+	Title       string
+	Description string
+	// Author is the username of the user who opened the pull request. It is
+	// empty if the user no longer exists.
+	Author string
+	// SourceRef is the full name of the ref to merge, e.g.
+	// "refs/heads/feature" or "refs/pull/1/head".
+	SourceRef string
+	// TargetBranch is the name of the branch to merge into.
+	TargetBranch string
+	State        PullRequestState
+	// MergeCommit is the commit the target branch pointed at after merging.
+	MergeCommit string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// Source returns the short name of the source ref.
+func (pr PullRequest) Source() string {
+	return strings.TrimPrefix(pr.SourceRef, git.RefsHeads)
+}
+
+// PullRequestComment is a comment on a pull request.
+type PullRequestComment struct {
+	// Author is the username of the commenter. It is empty if the user no
+	// longer exists.
+	Author    string
+	Body      string
+	CreatedAt time.Time
+}
+
+// CreatePullRequest opens a pull request to merge source into the target
+// branch. Source is a branch name or a full ref name. An empty title
+// defaults to the summary of the source's latest commit.
+func (d *Backend) CreatePullRequest(ctx context.Context, repo string, user proto.User, source string, target string, title string, description string) (PullRequest, error) {
+	repo = utils.SanitizeRepo(repo)
+	r, err := d.Repository(ctx, repo)
+	if err != nil {
+		return PullRequest{}, err
+	}
+
+	rr, err := r.Open()
+	if err != nil {
+		return PullRequest{}, err
+	}
+
+	sourceRef := source
+	if !strings.HasPrefix(sourceRef, "refs/") {
+		sourceRef = git.RefsHeads + source
+	}
+	if sourceRef == git.RefsHeads+target {
+		return PullRequest{}, proto.ErrPullRequestSameRef
+	}
+	if !rr.HasBranch(target) {
+		return PullRequest{}, fmt.Errorf("%w: %s", git.ErrReferenceNotExist, target)
+	}
+
+	sourceID, err := rr.ShowRefVerify(sourceRef)
+	if err != nil {
+		return PullRequest{}, fmt.Errorf("%w: %s", git.ErrReferenceNotExist, source)
+	}
+
+	if title == "" {
+		c, err := rr.CatFileCommit(sourceID)
+		if err != nil {
+			return PullRequest{}, err
+		}
+		title = c.Summary()
+	}
+
+	var number int64
+	if err := d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		number, err = d.store.CreatePullRequest(ctx, tx, repo, userID(user), title, description, sourceRef, target)
+		return err
+	}); err != nil {
+		return PullRequest{}, db.WrapError(err)
+	}
+
+	d.sendPullRequestEvent(ctx, user, r, number, webhook.PullRequestEventActionOpened, "")
+
+	return d.PullRequest(ctx, repo, number)
+}
+
+// createPushedPullRequest opens a pull request for a commit pushed to a
+// RefsFor ref. The commit is moved to a RefsPull ref named after the pull
+// request, leaving the RefsFor ref free for the next push.
+func (d *Backend) createPushedPullRequest(ctx context.Context, r proto.Repository, user proto.User, arg hooks.HookArg) (PullRequest, error) {
+	rr, err := r.Open()
+	if err != nil {
+		return PullRequest{}, err
+	}
+
+	c, err := rr.CatFileCommit(arg.NewSha)
+	if err != nil {
+		return PullRequest{}, err
+	}
+
+	// The commit message body describes the change, like an email patch.
+	_, description, _ := strings.Cut(strings.TrimSpace(c.Message), "\n")
+	target := strings.TrimPrefix(arg.RefName, RefsFor)
+
+	var number int64
+	if err := d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		number, err = d.store.CreatePullRequest(ctx, tx, r.Name(), userID(user), c.Summary(), strings.TrimSpace(description), "", target)
+		if err != nil {
+			return err
+		}
+
+		m, err := d.store.GetPullRequestByRepoAndNumber(ctx, tx, r.Name(), number)
+		if err != nil {
+			return err
+		}
+
+		sourceRef := pullRequestRef(number)
+		if err := d.store.UpdatePullRequestSourceRefByID(ctx, tx, m.ID, sourceRef); err != nil {
+			return err
+		}
+
+		return rr.UpdateRef(sourceRef, arg.NewSha, "")
+	}); err != nil {
+		return PullRequest{}, db.WrapError(err)
+	}
+
+	if err := rr.UpdateRef(arg.RefName, "", arg.NewSha); err != nil {
+		d.logger.Error("error deleting pushed ref", "repo", r.Name(), "ref", arg.RefName, "err", err)
+	}
+
+	d.sendPullRequestEvent(ctx, user, r, number, webhook.PullRequestEventActionOpened, "")
+
+	return d.PullRequest(ctx, r.Name(), number)
+}
+
+// pullRequestRef returns the RefsPull ref of a pull request.
+func pullRequestRef(number int64) string {
+	return RefsPull + strconv.FormatInt(number, 10) + "/head"
+}
+
+// parsePullRequestRef returns the pull request number of a RefsPull ref.
+func parsePullRequestRef(ref string) (int64, bool) {
+	s, ok := strings.CutPrefix(ref, RefsPull)
+	if !ok {
+		return 0, false
+	}
+
+	s, ok = strings.CutSuffix(s, "/head")
+	if !ok {
+		return 0, false
+	}
+
+	number, err := strconv.ParseInt(s, 10, 64)
+	return number, err == nil && number > 0
+}
+
+// PullRequests returns the pull requests of a repository, newest first. An
+// empty state returns pull requests in any state.
+func (d *Backend) PullRequests(ctx context.Context, repo string, state PullRequestState) ([]PullRequest, error) {
+	repo = utils.SanitizeRepo(repo)
+	var prs []PullRequest
+	if err := d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		ms, err := d.store.ListPullRequestsByRepo(ctx, tx, repo, string(state))
+		if err != nil {
+			return err
+		}
+
+		prs = make([]PullRequest, 0, len(ms))
+		for _, m := range ms {
+			pr, err := d.pullRequest(ctx, tx, m)
+			if err != nil {
+				return err
+			}
+			prs = append(prs, pr)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, db.WrapError(err)
+	}
+
+	return prs, nil
+}
+
+// PullRequest returns a pull request of a repository by number.
+func (d *Backend) PullRequest(ctx context.Context, repo string, number int64) (PullRequest, error) {
+	repo = utils.SanitizeRepo(repo)
+	var pr PullRequest
+	if err := d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		m, err := d.pullRequestModel(ctx, tx, repo, number)
+		if err != nil {
+			return err
+		}
+
+		pr, err = d.pullRequest(ctx, tx, m)
+		return err
+	}); err != nil {
+		return PullRequest{}, db.WrapError(err)
+	}
+
+	return pr, nil
+}
+
+// PullRequestDiff returns the changes a pull request makes to its target
+// branch. Changes that already landed on the target branch are left out.
+func (d *Backend) PullRequestDiff(ctx context.Context, repo string, number int64) (*git.Diff, error) {
+	r, err := d.Repository(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	pr, err := d.PullRequest(ctx, repo, number)
+	if err != nil {
+		return nil, err
+	}
+
+	rr, err := r.Open()
+	if err != nil {
+		return nil, err
+	}
+
+	sourceID, err := rr.ShowRefVerify(pr.SourceRef)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", git.ErrReferenceNotExist, pr.Source())
+	}
+
+	targetID, err := rr.BranchCommitID(pr.TargetBranch)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", git.ErrReferenceNotExist, pr.TargetBranch)
+	}
+
+	return rr.DiffRange(targetID, sourceID)
+}
+
+// PullRequestComments returns the comments on a pull request, oldest first.
+func (d *Backend) PullRequestComments(ctx context.Context, repo string, number int64) ([]PullRequestComment, error) {
+	repo = utils.SanitizeRepo(repo)
+	var comments []PullRequestComment
+	if err := d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		m, err := d.pullRequestModel(ctx, tx, repo, number)
+		if err != nil {
+			return err
+		}
+
+		ms, err := d.store.ListPullRequestComments(ctx, tx, m.ID)
+		if err != nil {
+			return err
+		}
+
+		comments = make([]PullRequestComment, len(ms))
+		for i, m := range ms {
+			author, err := d.username(ctx, tx, m.UserID.Int64)
+			if err != nil {
+				return err
+			}
+
+			comments[i] = PullRequestComment{
+				Author:    author,
+				Body:      m.Body,
+				CreatedAt: m.CreatedAt,
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return nil, db.WrapError(err)
+	}
+
+	return comments, nil
+}
+
+// CommentPullRequest adds a comment to a pull request.
+func (d *Backend) CommentPullRequest(ctx context.Context, repo string, number int64, user proto.User, body string) error {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return proto.ErrEmptyComment
+	}
+
+	repo = utils.SanitizeRepo(repo)
+	r, err := d.Repository(ctx, repo)
+	if err != nil {
+		return err
+	}
+
+	if err := db.WrapError(
+		d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+			m, err := d.pullRequestModel(ctx, tx, repo, number)
+			if err != nil {
+				return err
+			}
+
+			return d.store.CreatePullRequestComment(ctx, tx, m.ID, userID(user), body)
+		}),
+	); err != nil {
+		return err
+	}
+
+	d.sendPullRequestEvent(ctx, user, r, number, webhook.PullRequestEventActionCommented, body)
+
+	return nil
+}
+
+// ClosePullRequest closes an open pull request without merging it.
+func (d *Backend) ClosePullRequest(ctx context.Context, repo string, number int64, user proto.User) error {
+	repo = utils.SanitizeRepo(repo)
+	r, err := d.Repository(ctx, repo)
+	if err != nil {
+		return err
+	}
+
+	if err := db.WrapError(
+		d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+			m, err := d.pullRequestModel(ctx, tx, repo, number)
+			if err != nil {
+				return err
+			}
+
+			if PullRequestState(m.State) != PullRequestStateOpen {
+				return proto.ErrPullRequestNotOpen
+			}
+
+			return d.store.UpdatePullRequestStateByID(ctx, tx, m.ID, string(PullRequestStateClosed), "")
+		}),
+	); err != nil {
+		return err
+	}
+
+	d.sendPullRequestEvent(ctx, user, r, number, webhook.PullRequestEventActionClosed, "")
+
+	return nil
+}
+
+// MergePullRequest merges an open pull request into its target branch and
+// returns the commit the target branch points at afterwards.
+//
+// The target branch is fast-forwarded when possible, otherwise a merge
+// commit authored by user is created. The update is subject to the target
+// branch's protection rules, the push policies and the storage quotas, like a
+// push by user would be. Branches that reject merge commits are only fast-forwarded.
+//
+// Custom hook scripts are run by git on push only, so merges are refused when
+// custom pre-receive or update hooks could reject the update.
+func (d *Backend) MergePullRequest(ctx context.Context, repo string, number int64, user proto.User) (string, error) {
+	repo = utils.SanitizeRepo(repo)
+	r, err := d.Repository(ctx, repo)
+	if err != nil {
+		return "", err
+	}
+
+	pr, err := d.PullRequest(ctx, repo, number)
+	if err != nil {
+		return "", err
+	}
+
+	if pr.State != PullRequestStateOpen {
+		return "", proto.ErrPullRequestNotOpen
+	}
+
+	if d.hasRejectingCustomHooks(repo) {
+		return "", proto.ErrMergeCustomHooks
+	}
+
+	rr, err := r.Open()
+	if err != nil {
+		return "", err
+	}
+
+	targetRef := git.RefsHeads + pr.TargetBranch
+	oldID, err := rr.ShowRefVerify(targetRef)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", git.ErrReferenceNotExist, pr.TargetBranch)
+	}
+
+	sourceID, err := rr.ShowRefVerify(pr.SourceRef)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", git.ErrReferenceNotExist, pr.Source())
+	}
+
+	newID := oldID
+	if ff, err := rr.IsAncestor(oldID, sourceID); err != nil {
+		return "", err
+	} else if ff {
+		newID = sourceID
+	} else if merged, err := rr.IsAncestor(sourceID, oldID); err != nil {
+		return "", err
+	} else if !merged {
+		if violations, err := d.mergeCommitViolations(ctx, r, pr.TargetBranch); err != nil {
+			return "", err
+		} else if len(violations) > 0 {
+			return "", fmt.Errorf("%w: %s", proto.ErrPushPolicy, strings.Join(violations, "; "))
+		}
+
+		tree, err := rr.MergeTree(oldID, sourceID)
+		if errors.Is(err, git.ErrMergeConflict) {
+			return "", proto.ErrPullRequestConflict
+		} else if err != nil {
+			return "", err
+		}
+
+		msg := fmt.Sprintf("Merge pull request #%d from %s\n\n%s\n", pr.Number, pr.Source(), pr.Title)
+		newID, err = rr.CommitTree(tree, []string{oldID, sourceID}, msg, d.signature(user))
+		if err != nil {
+			return "", err
+		}
+	}
+
+	arg := hooks.HookArg{OldSha: oldID, NewSha: newID, RefName: targetRef}
+	violations, err := d.BranchProtectionViolations(ctx, r, user, []hooks.HookArg{arg})
+	if err != nil {
+		return "", err
+	}
+	if len(violations) > 0 {
+		return "", fmt.Errorf("%w: %s", proto.ErrProtectedBranch, strings.Join(violations, "; "))
+	}
+
+	violations, err = d.PushPolicyViolations(ctx, r, []hooks.HookArg{arg})
+	if err != nil {
+		return "", err
+	}
+	if len(violations) > 0 {
+		return "", fmt.Errorf("%w: %s", proto.ErrPushPolicy, strings.Join(violations, "; "))
+	}
+
+	violations, err = d.QuotaViolations(ctx, r, []hooks.HookArg{arg})
+	if err != nil {
+		return "", err
+	}
+	if len(violations) > 0 {
+		return "", fmt.Errorf("%w: %s", proto.ErrQuotaExceeded, strings.Join(violations, "; "))
+	}
+
+	if newID != oldID {
+		if err := rr.UpdateRef(targetRef, newID, oldID); err != nil {
+			return "", err
+		}
+	}
+
+	if err := db.WrapError(
+		d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+			m, err := d.pullRequestModel(ctx, tx, repo, number)
+			if err != nil {
+				return err
+			}
+
+			return d.store.UpdatePullRequestStateByID(ctx, tx, m.ID, string(PullRequestStateMerged), newID)
+		}),
+	); err != nil {
+		return "", err
+	}
+
+	if newID != oldID {
+		if err := populateLastModified(ctx, d, repo); err != nil {
+			d.logger.Error("error populating last-modified", "repo", repo, "err", err)
+		}
+
+		if user != nil {
+			wh, err := d.newPushEvent(ctx, user, r, targetRef, oldID, newID)
+			if err != nil {
+				d.logger.Error("error creating push webhook", "err", err)
+			} else if err := webhook.SendEvent(ctx, wh); err != nil {
+				d.logger.Error("error queuing push webhook", "err", err)
+			}
+		}
+	}
+
+	d.sendPullRequestEvent(ctx, user, r, number, webhook.PullRequestEventActionMerged, "")
+
+	return newID, nil
+}
+
+// hasRejectingCustomHooks returns whether custom pre-receive or update hooks,
+// which can reject a push, are configured for the repository.
+func (d *Backend) hasRejectingCustomHooks(repo string) bool {
+	for _, name := range []string{hooks.PreReceiveHook, hooks.UpdateHook} {
+		for _, path := range hooks.CustomHookPaths(d.cfg.DataPath, repo, name) {
+			if hooks.IsCustomHook(path) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// signature returns the signature of merge commits made by user.
+func (d *Backend) signature(user proto.User) *git.Signature {
+	name := d.cfg.Name
+	if user != nil {
+		name = user.Username()
+	}
+
+	host := "localhost"
+	if u, err := url.Parse(d.cfg.SSH.PublicURL); err == nil && u.Hostname() != "" {
+		host = u.Hostname()
+	}
+
+	return &git.Signature{
+		Name:  name,
+		Email: strings.ToLower(strings.ReplaceAll(name, " ", "-")) + "@" + host,
+		When:  time.Now(),
+	}
+}
+
+// sendPullRequestEvent queues a pull request webhook event. Failing to do so
+// does not fail the action that triggered it.
+func (d *Backend) sendPullRequestEvent(ctx context.Context, user proto.User, r proto.Repository, number int64, action webhook.PullRequestEventAction, comment string) {
+	// Events need a sender.
+	if user == nil {
+		return
+	}
+
+	wh, err := webhook.NewPullRequestEvent(ctx, user, r, number, action)
+	if err != nil {
+		d.logger.Error("error creating pull_request webhook", "err", err)
+		return
+	}
+
+	wh.Comment = comment
+	if err := webhook.SendEvent(ctx, wh); err != nil {
+		d.logger.Error("error queuing pull_request webhook", "err", err)
+	}
+}
+
+func (d *Backend) pullRequestModel(ctx context.Context, tx *db.Tx, repo string, number int64) (models.PullRequest, error) {
+	m, err := d.store.GetPullRequestByRepoAndNumber(ctx, tx, repo, number)
+	if err != nil {
+		if errors.Is(db.WrapError(err), db.ErrRecordNotFound) {
+			return models.PullRequest{}, proto.ErrPullRequestNotFound
+		}
+		return models.PullRequest{}, err
+	}
+
+	return m, nil
+}
+
+func (d *Backend) pullRequest(ctx context.Context, tx *db.Tx, m models.PullRequest) (PullRequest, error) {
+	author, err := d.username(ctx, tx, m.UserID.Int64)
+	if err != nil {
+		return PullRequest{}, err
+	}
+
+	return PullRequest{
+		Number:       m.Number,
+		Title:        m.Title,
+		Description:  m.Description,
+		Author:       author,
+		SourceRef:    m.SourceRef,
+		TargetBranch: m.TargetBranch,
+		State:        PullRequestState(m.State),
+		MergeCommit:  m.MergeCommit.String,
+		CreatedAt:    m.CreatedAt,
+		UpdatedAt:    m.UpdatedAt,
+	}, nil
+}
+
+// username returns the username of the user with the given ID, or an empty
+// string if there is no such user.
+func (d *Backend) username(ctx context.Context, tx *db.Tx, id int64) (string, error) {
+	if id <= 0 {
+		return "", nil
+	}
+
+	u, err := d.store.GetUserByID(ctx, tx, id)
+	if err != nil {
+		if errors.Is(db.WrapError(err), db.ErrRecordNotFound) {
+			return "", nil
+		}
+		return "", err
+	}
+
+	return u.Username, nil
+}
+
+// userID returns the ID of user, or 0 for anonymous users.
+func userID(user proto.User) int64 {
+	if user == nil {
+		return 0
+	}
+
+	return user.ID()
+}
+
+// pullRequestRefViolations checks pushes to pull request refs and returns a
+// message for every rejected update.
+//
+// Pushing to RefsFor opens a pull request against an existing branch.
+// RefsPull refs may only be updated by the author of the open pull request
+// they belong to, or by repository admins.
+func (d *Backend) pullRequestRefViolations(ctx context.Context, repo proto.Repository, user proto.User, args []hooks.HookArg) ([]string, error) {
+	var rr *git.Repository
+	var violations []string
+	for _, arg := range args {
+		switch {
+		case strings.HasPrefix(arg.RefName, RefsFor):
+			target := strings.TrimPrefix(arg.RefName, RefsFor)
+			if git.IsZeroHash(arg.NewSha) {
+				violations = append(violations, fmt.Sprintf("%q cannot be deleted", arg.RefName))
+				continue
+			}
+
+			if rr == nil {
+				var err error
+				rr, err = repo.Open()
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			if !rr.HasBranch(target) {
+				violations = append(violations, fmt.Sprintf("cannot open a pull request against %q: branch does not exist", target))
+			}
+		case strings.HasPrefix(arg.RefName, RefsPull):
+			number, ok := parsePullRequestRef(arg.RefName)
+			if !ok {
+				violations = append(violations, fmt.Sprintf("%q is reserved for pull requests", arg.RefName))
+				continue
+			}
+
+			pr, err := d.PullRequest(ctx, repo.Name(), number)
+			if errors.Is(err, proto.ErrPullRequestNotFound) {
+				violations = append(violations, fmt.Sprintf("%q is reserved for pull requests", arg.RefName))
+				continue
+			} else if err != nil {
+				return nil, err
+			}
+
+			switch {
+			case pr.State != PullRequestStateOpen:
+				violations = append(violations, fmt.Sprintf("pull request #%d is %s", number, pr.State))
+			case git.IsZeroHash(arg.NewSha):
+				violations = append(violations, fmt.Sprintf("%q cannot be deleted", arg.RefName))
+			case user == nil || (user.Username() != pr.Author && d.AccessLevelForUser(ctx, repo.Name(), user) < access.AdminAccess):
+				violations = append(violations, fmt.Sprintf("only the author can update pull request #%d", number))
+			}
+		}
+	}
+
+	return violations, nil
+}
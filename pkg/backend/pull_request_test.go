@@ -0,0 +1,232 @@
+package backend
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/soft-serve/pkg/config"
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/store"
+	"github.com/matryer/is"
+)
+
+func TestParsePullRequestRef(t *testing.T) {
+	is := is.New(t)
+
+	n, ok := parsePullRequestRef(pullRequestRef(42))
+	is.True(ok)
+	is.Equal(n, int64(42))
+
+	for _, ref := range []string{"refs/heads/main", "refs/pull/42", "refs/pull/x/head", "refs/pull/0/head", "refs/pull/1/merge"} {
+		_, ok := parsePullRequestRef(ref)
+		is.True(!ok)
+	}
+}
+
+func TestMergePullRequest(t *testing.T) {
+	is := is.New(t)
+	be, cfg := newTestBackend(t)
+	// Pull request events are built from the context.
+	ctx := config.WithContext(context.Background(), cfg)
+	ctx = db.WithContext(ctx, be.db)
+	ctx = store.WithContext(ctx, be.store)
+
+	_, err := be.CreateRepository(ctx, "repo", nil, proto.RepositoryOptions{})
+	is.NoErr(err)
+	alice, err := be.CreateUser(ctx, "alice", proto.UserOptions{})
+	is.NoErr(err)
+
+	// main and feature diverge on different files, conflict changes the
+	// file feature adds.
+	work := t.TempDir()
+	write := func(name, content string) {
+		is.NoErr(os.WriteFile(filepath.Join(work, name), []byte(content), 0o644))
+		gitOutput(t, work, "add", name)
+		gitOutput(t, work, "commit", "-q", "-m", "add "+name)
+	}
+	gitOutput(t, work, "init", "-q", "-b", "main")
+	write("README.md", "readme")
+	gitOutput(t, work, "checkout", "-q", "-b", "feature")
+	write("feature.txt", "feature")
+	gitOutput(t, work, "checkout", "-q", "-b", "conflict", "main")
+	write("feature.txt", "conflict")
+	gitOutput(t, work, "checkout", "-q", "main")
+	write("main.txt", "main")
+	gitOutput(t, be.repoPath("repo"), "fetch", "-q", work, "refs/heads/*:refs/heads/*")
+
+	_, err = be.CreatePullRequest(ctx, "repo", alice, "main", "main", "", "")
+	is.True(errors.Is(err, proto.ErrPullRequestSameRef))
+
+	pr, err := be.CreatePullRequest(ctx, "repo", alice, "feature", "main", "", "")
+	is.NoErr(err)
+	is.Equal(pr.Number, int64(1))
+	is.Equal(pr.Title, "add feature.txt")
+	is.Equal(pr.Author, "alice")
+	is.Equal(pr.State, PullRequestStateOpen)
+
+	conflict, err := be.CreatePullRequest(ctx, "repo", alice, "conflict", "feature", "conflicting", "")
+	is.NoErr(err)
+	is.Equal(conflict.Number, int64(2))
+
+	diff, err := be.PullRequestDiff(ctx, "repo", pr.Number)
+	is.NoErr(err)
+	is.Equal(len(diff.Files), 1)
+	_, to := diff.Files[0].Files()
+	is.Equal(to.Name(), "feature.txt")
+
+	id, err := be.MergePullRequest(ctx, "repo", pr.Number, alice)
+	is.NoErr(err)
+	is.Equal(gitOutput(t, be.repoPath("repo"), "rev-parse", "main"), id)
+	is.Equal(gitOutput(t, be.repoPath("repo"), "rev-list", "--parents", "-n1", "main"),
+		id+" "+gitOutput(t, work, "rev-parse", "main")+" "+gitOutput(t, work, "rev-parse", "feature"))
+
+	pr, err = be.PullRequest(ctx, "repo", pr.Number)
+	is.NoErr(err)
+	is.Equal(pr.State, PullRequestStateMerged)
+	is.Equal(pr.MergeCommit, id)
+
+	_, err = be.MergePullRequest(ctx, "repo", pr.Number, alice)
+	is.True(errors.Is(err, proto.ErrPullRequestNotOpen))
+
+	_, err = be.MergePullRequest(ctx, "repo", conflict.Number, alice)
+	is.True(errors.Is(err, proto.ErrPullRequestConflict))
+
+	is.NoErr(be.ClosePullRequest(ctx, "repo", conflict.Number, alice))
+	prs, err := be.PullRequests(ctx, "repo", PullRequestStateOpen)
+	is.NoErr(err)
+	is.Equal(len(prs), 0)
+	prs, err = be.PullRequests(ctx, "repo", "")
+	is.NoErr(err)
+	is.Equal(len(prs), 2)
+}
+
+func TestMergePullRequestPushPolicy(t *testing.T) {
+	is := is.New(t)
+	be, cfg := newTestBackend(t)
+	ctx := config.WithContext(context.Background(), cfg)
+	ctx = db.WithContext(ctx, be.db)
+	ctx = store.WithContext(ctx, be.store)
+
+	_, err := be.CreateRepository(ctx, "repo", nil, proto.RepositoryOptions{})
+	is.NoErr(err)
+	alice, err := be.CreateUser(ctx, "alice", proto.UserOptions{})
+	is.NoErr(err)
+
+	// feature diverges from main, ahead only adds to it.
+	work := t.TempDir()
+	gitOutput(t, work, "init", "-q", "-b", "main")
+	gitOutput(t, work, "commit", "-q", "--allow-empty", "-m", "feat: first")
+	gitOutput(t, work, "checkout", "-q", "-b", "feature")
+	gitOutput(t, work, "commit", "-q", "--allow-empty", "-m", "feat: feature")
+	gitOutput(t, work, "checkout", "-q", "main")
+	gitOutput(t, work, "commit", "-q", "--allow-empty", "-m", "feat: main")
+	gitOutput(t, work, "checkout", "-q", "-b", "ahead")
+	gitOutput(t, work, "commit", "-q", "--allow-empty", "-m", "feat: ahead")
+	gitOutput(t, be.repoPath("repo"), "fetch", "-q", work, "refs/heads/*:refs/heads/*")
+	main := gitOutput(t, be.repoPath("repo"), "rev-parse", "main")
+
+	feature, err := be.CreatePullRequest(ctx, "repo", alice, "feature", "main", "", "")
+	is.NoErr(err)
+
+	// Merge commits must follow the rules pushed commits follow.
+	is.NoErr(be.SetPushPolicy(ctx, "repo", PushPolicy{CommitMessagePattern: "^feat:"}))
+	_, err = be.MergePullRequest(ctx, "repo", feature.Number, alice)
+	is.True(errors.Is(err, proto.ErrPushPolicy))
+	is.True(strings.Contains(err.Error(), `commit message does not match "^feat:"`))
+	is.Equal(gitOutput(t, be.repoPath("repo"), "rev-parse", "main"), main)
+
+	// Branches that reject merge commits are only fast-forwarded.
+	is.NoErr(be.SetPushPolicy(ctx, "repo", PushPolicy{NoMergeBranches: []string{"main"}}))
+	_, err = be.MergePullRequest(ctx, "repo", feature.Number, alice)
+	is.True(errors.Is(err, proto.ErrPushPolicy))
+	is.True(strings.Contains(err.Error(), `merge commits are not allowed on branch "main"`))
+	is.Equal(gitOutput(t, be.repoPath("repo"), "rev-parse", "main"), main)
+
+	ahead, err := be.CreatePullRequest(ctx, "repo", alice, "ahead", "main", "", "")
+	is.NoErr(err)
+	id, err := be.MergePullRequest(ctx, "repo", ahead.Number, alice)
+	is.NoErr(err)
+	is.Equal(id, gitOutput(t, be.repoPath("repo"), "rev-parse", "ahead"))
+}
+
+func TestMergePullRequestQuota(t *testing.T) {
+	is := is.New(t)
+	be, cfg := newTestBackend(t)
+	ctx := config.WithContext(context.Background(), cfg)
+	ctx = db.WithContext(ctx, be.db)
+	ctx = store.WithContext(ctx, be.store)
+
+	_, err := be.CreateRepository(ctx, "repo", nil, proto.RepositoryOptions{})
+	is.NoErr(err)
+	alice, err := be.CreateUser(ctx, "alice", proto.UserOptions{})
+	is.NoErr(err)
+
+	work := t.TempDir()
+	gitOutput(t, work, "init", "-q", "-b", "main")
+	gitOutput(t, work, "commit", "-q", "--allow-empty", "-m", "first")
+	gitOutput(t, work, "checkout", "-q", "-b", "feature")
+	is.NoErr(os.WriteFile(filepath.Join(work, "big.bin"), []byte(strings.Repeat("x", 4096)), 0o644))
+	gitOutput(t, work, "add", ".")
+	gitOutput(t, work, "commit", "-q", "-m", "add big.bin")
+	gitOutput(t, be.repoPath("repo"), "fetch", "-q", work, "refs/heads/*:refs/heads/*")
+	main := gitOutput(t, be.repoPath("repo"), "rev-parse", "main")
+
+	pr, err := be.CreatePullRequest(ctx, "repo", alice, "feature", "main", "", "")
+	is.NoErr(err)
+
+	cfg.Quota.RepoSize = 1
+	_, err = be.MergePullRequest(ctx, "repo", pr.Number, alice)
+	is.True(errors.Is(err, proto.ErrQuotaExceeded))
+	is.Equal(gitOutput(t, be.repoPath("repo"), "rev-parse", "main"), main)
+
+	cfg.Quota.RepoSize = 0
+	_, err = be.MergePullRequest(ctx, "repo", pr.Number, alice)
+	is.NoErr(err)
+}
+
+func TestMergePullRequestCustomHooks(t *testing.T) {
+	is := is.New(t)
+	be, cfg := newTestBackend(t)
+	ctx := config.WithContext(context.Background(), cfg)
+	ctx = db.WithContext(ctx, be.db)
+	ctx = store.WithContext(ctx, be.store)
+
+	_, err := be.CreateRepository(ctx, "repo", nil, proto.RepositoryOptions{})
+	is.NoErr(err)
+	alice, err := be.CreateUser(ctx, "alice", proto.UserOptions{})
+	is.NoErr(err)
+
+	work := t.TempDir()
+	gitOutput(t, work, "init", "-q", "-b", "main")
+	gitOutput(t, work, "commit", "-q", "--allow-empty", "-m", "first")
+	gitOutput(t, work, "checkout", "-q", "-b", "feature")
+	gitOutput(t, work, "commit", "-q", "--allow-empty", "-m", "second")
+	gitOutput(t, be.repoPath("repo"), "fetch", "-q", work, "refs/heads/*:refs/heads/*")
+	main := gitOutput(t, be.repoPath("repo"), "rev-parse", "main")
+
+	pr, err := be.CreatePullRequest(ctx, "repo", alice, "feature", "main", "", "")
+	is.NoErr(err)
+
+	// Custom hooks that can't reject anything, or aren't executable, don't
+	// block merges.
+	hooksPath := filepath.Join(cfg.DataPath, "hooks")
+	hook := filepath.Join(hooksPath, "repos", "repo", "update.d", "10-check")
+	is.NoErr(os.MkdirAll(filepath.Dir(hook), 0o755))
+	is.NoErr(os.WriteFile(filepath.Join(hooksPath, "post-receive"), []byte("#!/bin/sh\n"), 0o755))
+	is.NoErr(os.WriteFile(hook, []byte("#!/bin/sh\nexit 1\n"), 0o644))
+	is.True(!be.hasRejectingCustomHooks("repo"))
+
+	is.NoErr(os.Chmod(hook, 0o755))
+	_, err = be.MergePullRequest(ctx, "repo", pr.Number, alice)
+	is.True(errors.Is(err, proto.ErrMergeCustomHooks))
+	is.Equal(gitOutput(t, be.repoPath("repo"), "rev-parse", "main"), main)
+
+	is.NoErr(os.Remove(hook))
+	_, err = be.MergePullRequest(ctx, "repo", pr.Number, alice)
+	is.NoErr(err)
+}
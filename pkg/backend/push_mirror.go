@@ -0,0 +1,389 @@
+package backend
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/db/models"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/ssrf"
+	"github.com/charmbracelet/soft-serve/pkg/task"
+	"github.com/charmbracelet/soft-serve/pkg/utils"
+)
+
+// pushMirrorTimeout bounds a single push to a push mirror, so a hanging
+// remote doesn't hold up the others.
+const pushMirrorTimeout = 10 * time.Minute
+
+// pushMirrorNameRe matches valid push mirror names.
+var pushMirrorNameRe = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// PushMirror is a downstream remote a repository is pushed to after every
+// update and on a schedule.
+type PushMirror struct {
+	// Name identifies the mirror within its repository.
+	Name string
+	// URL is the remote URL of the mirror.
+	URL string
+	// LastSyncAt is the time of the last push, zero if it never ran.
+	LastSyncAt time.Time
+	// LastError is the error of the last push, empty if it succeeded.
+	LastError string
+}
+
+// RedactedURL returns the URL of the mirror without its user info. Tokens
+// are often passed as the user name, so the whole user info is removed.
+func (m PushMirror) RedactedURL() string {
+	u, err := url.Parse(m.URL)
+	if err != nil || u.User == nil {
+		return m.URL
+	}
+
+	u.User = nil
+	return u.String()
+}
+
+// validatePushMirrorRemote validates the URL of a push mirror.
+func validatePushMirrorRemote(remote string) (ssrf.ValidatedGitRemote, error) {
+	// git would read a leading dash as an option rather than a remote.
+	if strings.HasPrefix(remote, "-") {
+		return ssrf.ValidatedGitRemote{}, proto.ErrInvalidRemote
+	}
+
+	v, err := ssrf.ValidateGitRemote(remote)
+	if err != nil {
+		return ssrf.ValidatedGitRemote{}, fmt.Errorf("%w: %w", proto.ErrInvalidRemote, err)
+	}
+
+	return v, nil
+}
+
+// splitPushMirrorCredentials removes the user info from an HTTP remote URL
+// and returns it as basic auth credentials, so they are neither stored in
+// the clear nor passed to git on the command line.
+func splitPushMirrorCredentials(remote string) (string, *proto.MirrorCredentials) {
+	u, err := url.Parse(remote)
+	if err != nil || u.User == nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return remote, nil
+	}
+
+	password, _ := u.User.Password()
+	c := &proto.MirrorCredentials{
+		Type:     proto.MirrorCredentialsBasic,
+		Username: u.User.Username(),
+		Secret:   password,
+	}
+
+	u.User = nil
+	return u.String(), c
+}
+
+// sealPushMirrorCredentials encrypts the credentials of a push mirror, an
+// empty string stands for no credentials.
+func (d *Backend) sealPushMirrorCredentials(repoID int64, c *proto.MirrorCredentials) (string, error) {
+	if c == nil {
+		return "", nil
+	}
+
+	return d.sealMirrorSecret(repoID, c.Username+":"+c.Secret)
+}
+
+// pushMirrorCredentials returns the remote URL and decrypted credentials of
+// a push mirror. Mirrors added with credentials in their URL are updated to
+// store them encrypted.
+func (d *Backend) pushMirrorCredentials(ctx context.Context, repoID int64, m models.PushMirror) (string, *proto.MirrorCredentials, error) {
+	remote, c := splitPushMirrorCredentials(m.URL)
+	if c != nil {
+		sealed, err := d.sealPushMirrorCredentials(repoID, c)
+		if err != nil {
+			return "", nil, err
+		}
+
+		if err := db.WrapError(
+			d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+				return d.store.SetPushMirrorURLByID(ctx, tx, m.ID, remote, sealed)
+			}),
+		); err != nil {
+			return "", nil, err
+		}
+
+		return remote, c, nil
+	}
+
+	if m.Credentials == "" {
+		return remote, nil, nil
+	}
+
+	secret, err := d.openMirrorSecret(repoID, m.Credentials)
+	if err != nil {
+		return "", nil, err
+	}
+
+	username, password, _ := strings.Cut(secret, ":")
+	return remote, &proto.MirrorCredentials{
+		Type:     proto.MirrorCredentialsBasic,
+		Username: username,
+		Secret:   password,
+	}, nil
+}
+
+// AddPushMirror adds a push mirror to a repository. Credentials in the user
+// info of an HTTP remote URL are encrypted and stored apart from it.
+func (d *Backend) AddPushMirror(ctx context.Context, repo string, name string, remote string) error {
+	if !pushMirrorNameRe.MatchString(name) {
+		return fmt.Errorf("%w: invalid push mirror name %q", proto.ErrInvalidRemote, name)
+	}
+
+	remote, creds := splitPushMirrorCredentials(strings.TrimSpace(remote))
+	if _, err := validatePushMirrorRemote(remote); err != nil {
+		return err
+	}
+
+	repo = utils.SanitizeRepo(repo)
+	r, err := d.Repository(ctx, repo)
+	if err != nil {
+		return err
+	}
+
+	sealed, err := d.sealPushMirrorCredentials(r.ID(), creds)
+	if err != nil {
+		return err
+	}
+
+	if err := db.WrapError(
+		d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+			return d.store.CreatePushMirror(ctx, tx, repo, name, remote, sealed)
+		}),
+	); err != nil {
+		if errors.Is(err, db.ErrDuplicateKey) {
+			return proto.ErrPushMirrorExist
+		}
+
+		return err
+	}
+
+	d.audit(ctx, AuditRepoAddPushMirror, repo, "name", name, "remote", remote)
+	return nil
+}
+
+// RemovePushMirror removes a push mirror from a repository.
+func (d *Backend) RemovePushMirror(ctx context.Context, repo string, name string) error {
+	mirrors, err := d.PushMirrors(ctx, repo)
+	if err != nil {
+		return err
+	}
+
+	found := false
+	for _, m := range mirrors {
+		if m.Name == name {
+			found = true
+			break
+		}
+	}
+
+	if !found {
+		return proto.ErrPushMirrorNotFound
+	}
+
+	if err := db.WrapError(
+		d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+			return d.store.DeletePushMirrorByRepoAndName(ctx, tx, utils.SanitizeRepo(repo), name)
+		}),
+	); err != nil {
+		return err
+	}
+
+	d.audit(ctx, AuditRepoRemovePushMirror, utils.SanitizeRepo(repo), "name", name)
+	return nil
+}
+
+// PushMirrors returns the push mirrors of a repository.
+func (d *Backend) PushMirrors(ctx context.Context, repo string) ([]PushMirror, error) {
+	repo = utils.SanitizeRepo(repo)
+	if _, err := d.Repository(ctx, repo); err != nil {
+		return nil, err
+	}
+
+	ms, err := d.pushMirrors(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	mirrors := make([]PushMirror, 0, len(ms))
+	for _, m := range ms {
+		mirrors = append(mirrors, PushMirror{
+			Name:       m.Name,
+			URL:        m.URL,
+			LastSyncAt: m.LastSyncAt.Time,
+			LastError:  m.LastError,
+		})
+	}
+
+	return mirrors, nil
+}
+
+func (d *Backend) pushMirrors(ctx context.Context, repo string) ([]models.PushMirror, error) {
+	var ms []models.PushMirror
+	if err := d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		var err error
+		ms, err = d.store.GetPushMirrorsByRepo(ctx, tx, repo)
+		return err
+	}); err != nil {
+		return nil, db.WrapError(err)
+	}
+
+	return ms, nil
+}
+
+// QueuePushMirrorSync queues a push of a repository to all of its push
+// mirrors. The server runs queued pushes in the background, see
+// QueuedPushMirrorSyncs.
+func (d *Backend) QueuePushMirrorSync(ctx context.Context, repo string) error {
+	return db.WrapError(
+		d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+			return d.store.SetPushMirrorSyncRequestedByRepo(ctx, tx, utils.SanitizeRepo(repo), true)
+		}),
+	)
+}
+
+// QueuedPushMirrorSyncs returns the names of the repositories queued to be
+// pushed to their push mirrors.
+func (d *Backend) QueuedPushMirrorSyncs(ctx context.Context) ([]string, error) {
+	var repos []string
+	if err := d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		var err error
+		repos, err = d.store.GetReposWithRequestedPushMirrorSync(ctx, tx)
+		return err
+	}); err != nil {
+		return nil, db.WrapError(err)
+	}
+
+	return repos, nil
+}
+
+// SyncPushMirrors pushes a repository to all of its push mirrors with `git
+// push --mirror` and records the outcome of every push. It returns the joined
+// errors of the pushes that failed. It dequeues the repository if it was
+// queued with QueuePushMirrorSync.
+//
+// It returns task.ErrAlreadyStarted if the repository is already being
+// pushed to its mirrors.
+func (d *Backend) SyncPushMirrors(ctx context.Context, repo string) error {
+	repo = utils.SanitizeRepo(repo)
+	r, err := d.Repository(ctx, repo)
+	if err != nil {
+		return err
+	}
+
+	ms, err := d.pushMirrors(ctx, repo)
+	if err != nil || len(ms) == 0 {
+		return err
+	}
+
+	rr, err := r.Open()
+	if err != nil {
+		return err
+	}
+
+	tid := "push-mirror:" + repo
+	if d.manager.Exists(tid) {
+		return task.ErrAlreadyStarted
+	}
+
+	done := make(chan error, 1)
+	d.manager.Add(tid, func(ctx context.Context) error {
+		// Dequeue before pushing, an update during the push queues it again.
+		if err := db.WrapError(
+			d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+				return d.store.SetPushMirrorSyncRequestedByRepo(ctx, tx, repo, false)
+			}),
+		); err != nil {
+			return err
+		}
+
+		var errs []error
+		for _, m := range ms {
+			remote, creds, perr := d.pushMirrorCredentials(ctx, r.ID(), m)
+			if perr == nil {
+				perr = d.pushMirror(ctx, rr, remote, creds)
+			}
+
+			var lastError string
+			if perr != nil {
+				lastError = perr.Error()
+				errs = append(errs, fmt.Errorf("push mirror %s: %w", m.Name, perr))
+				d.logger.Error("failed to push to mirror", "repo", repo, "mirror", m.Name, "err", perr)
+			}
+
+			if err := db.WrapError(
+				d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+					return d.store.SetPushMirrorStatusByID(ctx, tx, m.ID, lastError)
+				}),
+			); err != nil {
+				d.logger.Error("failed to record push mirror status", "repo", repo, "mirror", m.Name, "err", err)
+			}
+		}
+
+		return errors.Join(errs...)
+	})
+
+	d.manager.Run(tid, done)
+
+	return <-done
+}
+
+// pushMirror pushes every ref of r to remote. HTTP remotes are
+// authenticated with creds through the environment.
+func (d *Backend) pushMirror(ctx context.Context, r *git.Repository, remote string, creds *proto.MirrorCredentials) error {
+	// Re-validate before every push: the address a host name resolves to
+	// can change after the mirror was added.
+	v, err := validatePushMirrorRemote(remote)
+	if err != nil {
+		return err
+	}
+
+	if v.Transport == ssrf.GitTransportHTTP {
+		v.Config = append(v.Config, MirrorCredentialsGitConfig(creds, remote)...)
+	}
+
+	sshCommand, cleanup, err := MirrorSSHCommand(d.cfg, nil)
+	if err != nil {
+		return err
+	}
+
+	defer cleanup()
+
+	var stderr bytes.Buffer
+	cmd := git.NewCommand("push", "--mirror", "--quiet", remote).WithContext(ctx).WithTimeout(pushMirrorTimeout)
+	cmd.AddEnvs(append(ssrf.GitEnv(v), sshCommand, "GIT_TERMINAL_PROMPT=0")...)
+	if err := cmd.RunInDirWithOptions(r.Path, git.RunInDirOptions{
+		Stderr: &stderr,
+	}); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return errors.New(redactURL(msg, remote))
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// redactURL replaces remote in s with its redacted form, git includes the
+// remote URL in its error messages.
+func redactURL(s string, remote string) string {
+	redacted := PushMirror{URL: remote}.RedactedURL()
+	if redacted == remote {
+		return s
+	}
+
+	return strings.ReplaceAll(s, remote, redacted)
+}
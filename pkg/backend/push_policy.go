@@ -0,0 +1,398 @@
+package backend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/config"
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/db/models"
+	"github.com/charmbracelet/soft-serve/pkg/hooks"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/utils"
+	"github.com/dustin/go-humanize"
+	"github.com/gobwas/glob"
+)
+
+// PushPolicy is a set of rules pushed commits must follow.
+type PushPolicy struct {
+	// MaxFileSize is the maximum size in bytes of a file added by a push. A
+	// value of 0 means no limit.
+	MaxFileSize int64
+	// ForbiddenPaths are glob patterns of paths a push may not add or
+	// modify. A single "*" does not cross "/" boundaries, "**" does.
+	ForbiddenPaths []string
+	// CommitMessagePattern is a regular expression every pushed commit
+	// message must match.
+	CommitMessagePattern string
+	// AuthorEmailDomains restricts the email domains of pushed commit
+	// authors. An empty list allows any domain.
+	AuthorEmailDomains []string
+	// NoMergeBranches are branch names or glob patterns of branches that
+	// reject pushed merge commits.
+	NoMergeBranches []string
+	// RequireSignedCommits rejects pushed commits that aren't signed with
+	// an SSH key registered by a user.
+	RequireSignedCommits bool
+}
+
+// IsEmpty returns whether the policy has no rules.
+func (p PushPolicy) IsEmpty() bool {
+	return p.MaxFileSize == 0 &&
+		len(p.ForbiddenPaths) == 0 &&
+		p.CommitMessagePattern == "" &&
+		len(p.AuthorEmailDomains) == 0 &&
+		len(p.NoMergeBranches) == 0 &&
+		!p.RequireSignedCommits
+}
+
+// pushPolicyFromConfig returns the server wide push policy.
+func pushPolicyFromConfig(cfg config.PushPolicyConfig) PushPolicy {
+	return PushPolicy{
+		MaxFileSize:          cfg.MaxFileSize,
+		ForbiddenPaths:       cfg.ForbiddenPaths,
+		CommitMessagePattern: cfg.CommitMessagePattern,
+		AuthorEmailDomains:   cfg.AuthorEmailDomains,
+		NoMergeBranches:      cfg.NoMergeBranches,
+	}
+}
+
+// compiledPushPolicy is a PushPolicy ready to be evaluated.
+type compiledPushPolicy struct {
+	PushPolicy
+	forbiddenPaths  []glob.Glob
+	message         *regexp.Regexp
+	noMergeBranches []glob.Glob
+}
+
+// compile validates the rules of the policy and compiles its patterns.
+func (p PushPolicy) compile() (compiledPushPolicy, error) {
+	c := compiledPushPolicy{PushPolicy: p}
+	if p.MaxFileSize < 0 {
+		return c, fmt.Errorf("%w: max file size must not be negative", proto.ErrInvalidPushPolicy)
+	}
+
+	for _, pattern := range p.ForbiddenPaths {
+		g, err := glob.Compile(pattern, '/')
+		if err != nil {
+			return c, fmt.Errorf("%w: path pattern %q: %w", proto.ErrInvalidPushPolicy, pattern, err)
+		}
+		c.forbiddenPaths = append(c.forbiddenPaths, g)
+	}
+
+	if p.CommitMessagePattern != "" {
+		re, err := regexp.Compile(p.CommitMessagePattern)
+		if err != nil {
+			return c, fmt.Errorf("%w: commit message pattern: %w", proto.ErrInvalidPushPolicy, err)
+		}
+		c.message = re
+	}
+
+	for _, domain := range p.AuthorEmailDomains {
+		if domain == "" || strings.ContainsAny(domain, "@ \t\n") {
+			return c, fmt.Errorf("%w: invalid email domain %q", proto.ErrInvalidPushPolicy, domain)
+		}
+	}
+
+	for _, pattern := range p.NoMergeBranches {
+		g, err := compileBranchPattern(pattern)
+		if err != nil {
+			return c, err
+		}
+		c.noMergeBranches = append(c.noMergeBranches, g)
+	}
+
+	return c, nil
+}
+
+// SetPushPolicy replaces the push policy of a repository. An empty policy
+// removes it.
+func (d *Backend) SetPushPolicy(ctx context.Context, repo string, p PushPolicy) error {
+	repo = utils.SanitizeRepo(repo)
+	for i, domain := range p.AuthorEmailDomains {
+		p.AuthorEmailDomains[i] = strings.ToLower(strings.TrimPrefix(domain, "@"))
+	}
+
+	if _, err := p.compile(); err != nil {
+		return err
+	}
+
+	if _, err := d.Repository(ctx, repo); err != nil {
+		return err
+	}
+
+	if err := db.WrapError(
+		d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+			if p.IsEmpty() {
+				return d.store.DeletePushPolicyByRepo(ctx, tx, repo)
+			}
+
+			return d.store.SetPushPolicyByRepo(ctx, tx, repo, models.PushPolicy{
+				MaxFileSize:          p.MaxFileSize,
+				ForbiddenPaths:       strings.Join(p.ForbiddenPaths, "\n"),
+				CommitMessagePattern: p.CommitMessagePattern,
+				AuthorEmailDomains:   strings.Join(p.AuthorEmailDomains, "\n"),
+				NoMergeBranches:      strings.Join(p.NoMergeBranches, "\n"),
+				RequireSignedCommits: p.RequireSignedCommits,
+			})
+		}),
+	); err != nil {
+		return err
+	}
+
+	d.audit(ctx, AuditRepoSetPushPolicy, repo, "removed", p.IsEmpty())
+	return nil
+}
+
+// PushPolicy returns the push policy of a repository. It does not include
+// the server wide policy, see ServerPushPolicy.
+func (d *Backend) PushPolicy(ctx context.Context, repo string) (PushPolicy, error) {
+	repo = utils.SanitizeRepo(repo)
+	var m models.PushPolicy
+	if err := d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		var err error
+		m, err = d.store.GetPushPolicyByRepo(ctx, tx, repo)
+		return err
+	}); err != nil {
+		err = db.WrapError(err)
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return PushPolicy{}, nil
+		}
+
+		return PushPolicy{}, err
+	}
+
+	return PushPolicy{
+		MaxFileSize:          m.MaxFileSize,
+		ForbiddenPaths:       splitLines(m.ForbiddenPaths),
+		CommitMessagePattern: m.CommitMessagePattern,
+		AuthorEmailDomains:   splitLines(m.AuthorEmailDomains),
+		NoMergeBranches:      splitLines(m.NoMergeBranches),
+		RequireSignedCommits: m.RequireSignedCommits,
+	}, nil
+}
+
+// ServerPushPolicy returns the push policy enforced on every repository.
+func (d *Backend) ServerPushPolicy() PushPolicy {
+	return pushPolicyFromConfig(d.cfg.PushPolicy)
+}
+
+// splitLines splits s into its non-empty lines.
+func splitLines(s string) []string {
+	var lines []string
+	for _, l := range strings.Split(s, "\n") {
+		if l != "" {
+			lines = append(lines, l)
+		}
+	}
+
+	return lines
+}
+
+// PushPolicyViolations checks the commits introduced by ref updates against
+// the server and repository push policies and returns a message for every
+// rule a commit breaks.
+func (d *Backend) PushPolicyViolations(ctx context.Context, repo proto.Repository, args []hooks.HookArg) ([]string, error) {
+	rp, err := d.PushPolicy(ctx, repo.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	var policies []compiledPushPolicy
+	for _, p := range []PushPolicy{d.ServerPushPolicy(), rp} {
+		if p.IsEmpty() {
+			continue
+		}
+
+		c, err := p.compile()
+		if err != nil {
+			return nil, err
+		}
+		policies = append(policies, c)
+	}
+
+	if len(policies) == 0 {
+		return nil, nil
+	}
+
+	r, err := repo.Open()
+	if err != nil {
+		return nil, err
+	}
+
+	var violations []string
+	seen := make(map[string]struct{})
+	for _, arg := range args {
+		if git.IsZeroHash(arg.NewSha) {
+			continue
+		}
+
+		commits, err := r.NewCommits(arg.NewSha)
+		if err != nil {
+			return nil, err
+		}
+
+		// Merge commits are checked against everything the ref update adds
+		// to the branch, including commits the repository already has on
+		// other refs.
+		if branch, ok := strings.CutPrefix(arg.RefName, git.RefsHeads); ok {
+			for _, p := range policies {
+				if !matchAny(p.noMergeBranches, branch) {
+					continue
+				}
+
+				merges, err := r.MergeCommits(arg.OldSha, arg.NewSha)
+				if err != nil {
+					return nil, err
+				}
+
+				for _, id := range merges {
+					violations = append(violations, fmt.Sprintf("commit %s: merge commits are not allowed on branch %q", id[:7], branch))
+				}
+				break
+			}
+		}
+
+		for _, c := range commits {
+			id := c.ID.String()
+			reject := func(reason string) {
+				violations = append(violations, fmt.Sprintf("commit %s: %s", id[:7], reason))
+			}
+
+			// The other rules only depend on the commit, check it once.
+			if _, ok := seen[id]; ok {
+				continue
+			}
+			seen[id] = struct{}{}
+
+			for _, p := range policies {
+				if p.message != nil && !p.message.MatchString(c.Message) {
+					reject(fmt.Sprintf("commit message does not match %q", p.CommitMessagePattern))
+				}
+
+				if len(p.AuthorEmailDomains) > 0 && c.Author != nil {
+					_, domain, _ := strings.Cut(c.Author.Email, "@")
+					if !containsFold(p.AuthorEmailDomains, domain) {
+						reject(fmt.Sprintf("author email %q is not in an allowed domain (%s)",
+							c.Author.Email, strings.Join(p.AuthorEmailDomains, ", ")))
+					}
+				}
+			}
+
+			if policiesRequireSignedCommits(policies) {
+				v, err := d.VerifyCommit(ctx, r, id)
+				if err != nil {
+					return nil, err
+				}
+
+				if v.Status != SignatureVerified {
+					reject(fmt.Sprintf("commit must be signed with a registered SSH key (%s)", strings.ToLower(v.Status.String())))
+				}
+			}
+
+			if !policiesCheckFiles(policies) {
+				continue
+			}
+
+			files, err := r.ChangedFiles(c)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, f := range files {
+				for _, p := range policies {
+					if matchAny(p.forbiddenPaths, f.Path) {
+						reject(fmt.Sprintf("path %q is not allowed", f.Path))
+					}
+
+					if p.MaxFileSize > 0 && f.Size > p.MaxFileSize {
+						reject(fmt.Sprintf("file %q is %s, larger than the %s limit; consider tracking it with Git LFS",
+							f.Path, humanize.IBytes(uint64(f.Size)), humanize.IBytes(uint64(p.MaxFileSize)))) //nolint: gosec
+					}
+				}
+			}
+		}
+	}
+
+	return violations, nil
+}
+
+// mergeCommitViolations returns why the server and repository push policies
+// don't allow the server to create a merge commit on a branch. Merge commits
+// made by the server are never signed, so they are refused where signed
+// commits are required.
+func (d *Backend) mergeCommitViolations(ctx context.Context, repo proto.Repository, branch string) ([]string, error) {
+	rp, err := d.PushPolicy(ctx, repo.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	var noMerge, requireSigned bool
+	for _, p := range []PushPolicy{d.ServerPushPolicy(), rp} {
+		c, err := p.compile()
+		if err != nil {
+			return nil, err
+		}
+
+		noMerge = noMerge || matchAny(c.noMergeBranches, branch)
+		requireSigned = requireSigned || c.RequireSignedCommits
+	}
+
+	var violations []string
+	if noMerge {
+		violations = append(violations, fmt.Sprintf("merge commits are not allowed on branch %q", branch))
+	}
+	if requireSigned {
+		violations = append(violations, "commits must be signed, merge the pull request locally and push a signed merge commit")
+	}
+
+	return violations, nil
+}
+
+// policiesCheckFiles returns whether any of the policies has rules on the
+// files changed by a commit.
+func policiesCheckFiles(policies []compiledPushPolicy) bool {
+	for _, p := range policies {
+		if p.MaxFileSize > 0 || len(p.forbiddenPaths) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// policiesRequireSignedCommits returns whether any of the policies requires
+// signed commits.
+func policiesRequireSignedCommits(policies []compiledPushPolicy) bool {
+	for _, p := range policies {
+		if p.RequireSignedCommits {
+			return true
+		}
+	}
+
+	return false
+}
+
+func matchAny(globs []glob.Glob, s string) bool {
+	for _, g := range globs {
+		if g.Match(s) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+
+	return false
+}
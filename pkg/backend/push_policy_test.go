@@ -0,0 +1,150 @@
+package backend
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/soft-serve/pkg/hooks"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/matryer/is"
+)
+
+func TestPushPolicyCRUD(t *testing.T) {
+	is := is.New(t)
+	be, _ := newTestBackend(t)
+	ctx := context.Background()
+
+	_, err := be.CreateRepository(ctx, "repo", nil, proto.RepositoryOptions{})
+	is.NoErr(err)
+
+	p, err := be.PushPolicy(ctx, "repo")
+	is.NoErr(err)
+	is.True(p.IsEmpty())
+
+	is.NoErr(be.SetPushPolicy(ctx, "repo", PushPolicy{
+		MaxFileSize:        1024,
+		ForbiddenPaths:     []string{"**.exe", "{a,b}/*"},
+		AuthorEmailDomains: []string{"@Example.com"},
+		NoMergeBranches:    []string{"main"},
+	}))
+
+	p, err = be.PushPolicy(ctx, "repo")
+	is.NoErr(err)
+	is.Equal(p.MaxFileSize, int64(1024))
+	is.Equal(p.ForbiddenPaths, []string{"**.exe", "{a,b}/*"})
+	is.Equal(p.AuthorEmailDomains, []string{"example.com"})
+	is.Equal(p.NoMergeBranches, []string{"main"})
+
+	// Setting a policy replaces it.
+	is.NoErr(be.SetPushPolicy(ctx, "repo", PushPolicy{CommitMessagePattern: "^fix"}))
+	p, err = be.PushPolicy(ctx, "repo")
+	is.NoErr(err)
+	is.Equal(p, PushPolicy{CommitMessagePattern: "^fix"})
+
+	for _, invalid := range []PushPolicy{
+		{MaxFileSize: -1},
+		{ForbiddenPaths: []string{"[a"}},
+		{CommitMessagePattern: "(fix"},
+		{AuthorEmailDomains: []string{"a@b.com"}},
+	} {
+		err = be.SetPushPolicy(ctx, "repo", invalid)
+		is.True(errors.Is(err, proto.ErrInvalidPushPolicy))
+	}
+
+	err = be.SetPushPolicy(ctx, "missing", PushPolicy{MaxFileSize: 1})
+	is.True(errors.Is(err, proto.ErrRepoNotFound))
+
+	// An empty policy removes it.
+	is.NoErr(be.SetPushPolicy(ctx, "repo", PushPolicy{}))
+	p, err = be.PushPolicy(ctx, "repo")
+	is.NoErr(err)
+	is.True(p.IsEmpty())
+}
+
+func TestPushPolicyViolations(t *testing.T) {
+	is := is.New(t)
+	be, cfg := newTestBackend(t)
+	ctx := context.Background()
+
+	r, err := be.CreateRepository(ctx, "repo", nil, proto.RepositoryOptions{})
+	is.NoErr(err)
+
+	// Build a history in a scratch repository and copy its objects into the
+	// served one without referencing them, like a push in progress.
+	work := t.TempDir()
+	gitOutput(t, work, "init", "-q")
+	gitOutput(t, work, "commit", "-q", "--allow-empty", "-m", "fix: first")
+	first := gitOutput(t, work, "rev-parse", "HEAD")
+	gitOutput(t, work, "checkout", "-q", "-b", "feature")
+	is.NoErr(os.WriteFile(filepath.Join(work, "big.bin"), []byte(strings.Repeat("x", 100)), 0o644))
+	is.NoErr(os.WriteFile(filepath.Join(work, "tool.exe"), []byte("x"), 0o644))
+	gitOutput(t, work, "add", ".")
+	gitOutput(t, work, "commit", "-q", "-m", "add files")
+	gitOutput(t, work, "checkout", "-q", "-")
+	gitOutput(t, work, "merge", "-q", "--no-ff", "-m", "fix: merge", "feature")
+	merge := gitOutput(t, work, "rev-parse", "HEAD")
+	gitOutput(t, be.repoPath("repo"), "fetch", "-q", work, first+":refs/heads/main", merge+":refs/heads/scratch")
+	gitOutput(t, be.repoPath("repo"), "update-ref", "-d", "refs/heads/scratch")
+
+	args := []hooks.HookArg{{OldSha: first, NewSha: merge, RefName: "refs/heads/main"}}
+
+	// Without a policy, anything goes.
+	violations, err := be.PushPolicyViolations(ctx, r, args)
+	is.NoErr(err)
+	is.Equal(len(violations), 0)
+
+	// The server policy applies to every repository.
+	cfg.PushPolicy.ForbiddenPaths = []string{"*.exe"}
+	violations, err = be.PushPolicyViolations(ctx, r, args)
+	is.NoErr(err)
+	is.Equal(len(violations), 1)
+	is.True(strings.Contains(violations[0], `path "tool.exe" is not allowed`))
+	cfg.PushPolicy.ForbiddenPaths = nil
+
+	is.NoErr(be.SetPushPolicy(ctx, "repo", PushPolicy{
+		MaxFileSize:          10,
+		CommitMessagePattern: "^fix:",
+		AuthorEmailDomains:   []string{"example.org"},
+		NoMergeBranches:      []string{"main"},
+	}))
+	violations, err = be.PushPolicyViolations(ctx, r, args)
+	is.NoErr(err)
+	joined := strings.Join(violations, "\n")
+	is.True(strings.Contains(joined, `merge commits are not allowed on branch "main"`))
+	is.True(strings.Contains(joined, `commit message does not match "^fix:"`))
+	is.True(strings.Contains(joined, `file "big.bin" is 100 B, larger than the 10 B limit`))
+	is.True(strings.Contains(joined, `author email "test@example.com" is not in an allowed domain`))
+	// Both new commits break the domain rule, only one its message rule,
+	// and the merge itself changes no files.
+	is.Equal(strings.Count(joined, "author email"), 2)
+	is.Equal(strings.Count(joined, "commit message"), 1)
+	is.Equal(strings.Count(joined, "larger than"), 1)
+
+	// Merge commits are allowed on other branches, and deleting refs or
+	// pushing already known commits is never rejected.
+	is.NoErr(be.SetPushPolicy(ctx, "repo", PushPolicy{NoMergeBranches: []string{"main"}}))
+	violations, err = be.PushPolicyViolations(ctx, r, []hooks.HookArg{
+		{OldSha: first, NewSha: merge, RefName: "refs/heads/feature"},
+		{OldSha: first, NewSha: strings.Repeat("0", 40), RefName: "refs/heads/main"},
+		{OldSha: strings.Repeat("0", 40), NewSha: first, RefName: "refs/heads/other"},
+	})
+	is.NoErr(err)
+	is.Equal(len(violations), 0)
+
+	// A merge the repository already has on another ref can't be
+	// fast-forwarded into a no merge branch, nor can a new no merge branch
+	// start at it.
+	gitOutput(t, be.repoPath("repo"), "update-ref", "refs/heads/feature", merge)
+	for _, old := range []string{first, strings.Repeat("0", 40)} {
+		violations, err = be.PushPolicyViolations(ctx, r, []hooks.HookArg{
+			{OldSha: old, NewSha: merge, RefName: "refs/heads/main"},
+		})
+		is.NoErr(err)
+		is.Equal(len(violations), 1)
+		is.True(strings.Contains(violations[0], `merge commits are not allowed on branch "main"`))
+	}
+}
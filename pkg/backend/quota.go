@@ -0,0 +1,435 @@
+package backend
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/db/models"
+	"github.com/charmbracelet/soft-serve/pkg/hooks"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/utils"
+	"github.com/dustin/go-humanize"
+)
+
+// Quota is a storage quota of a repository or user. Sizes are in bytes and a
+// value of 0 means no limit.
+type Quota struct {
+	// Size is the maximum size of the Git objects.
+	Size int64
+	// LFSSize is the maximum size of the LFS objects.
+	LFSSize int64
+}
+
+// IsEmpty returns whether the quota has no limits.
+func (q Quota) IsEmpty() bool {
+	return q.Size == 0 && q.LFSSize == 0
+}
+
+// QuotaUsage is the storage used by a repository or user.
+type QuotaUsage struct {
+	// Size is the disk space taken by the Git objects.
+	Size int64
+	// LFSSize is the total size of the LFS objects.
+	LFSSize int64
+}
+
+// QuotaOptions are the limits to change in a quota. A nil limit is left
+// unchanged and a negative one is reset to the server default.
+type QuotaOptions struct {
+	Size    *int64
+	LFSSize *int64
+}
+
+// quotaFromModel returns the quota with the overrides of m applied on top of
+// the server default def.
+func quotaFromModel(m models.Quota, def Quota) Quota {
+	if m.Size.Valid {
+		def.Size = m.Size.Int64
+	}
+	if m.LFSSize.Valid {
+		def.LFSSize = m.LFSSize.Int64
+	}
+
+	return def
+}
+
+// applyQuotaOptions returns the overrides of m changed by opts.
+func applyQuotaOptions(m models.Quota, opts QuotaOptions) (sql.NullInt64, sql.NullInt64) {
+	apply := func(v sql.NullInt64, opt *int64) sql.NullInt64 {
+		switch {
+		case opt == nil:
+			return v
+		case *opt < 0:
+			return sql.NullInt64{}
+		default:
+			return sql.NullInt64{Int64: *opt, Valid: true}
+		}
+	}
+
+	return apply(m.Size, opts.Size), apply(m.LFSSize, opts.LFSSize)
+}
+
+// auditDetails returns the changed limits as audit log details, "default"
+// for limits reset to the server default.
+func (o QuotaOptions) auditDetails() []interface{} {
+	var kv []interface{}
+	for _, l := range []struct {
+		key string
+		v   *int64
+	}{{"size", o.Size}, {"lfs-size", o.LFSSize}} {
+		switch {
+		case l.v == nil:
+		case *l.v < 0:
+			kv = append(kv, l.key, "default")
+		default:
+			kv = append(kv, l.key, *l.v)
+		}
+	}
+
+	return kv
+}
+
+// RepositoryQuota returns the quota of a repository, the server default
+// unless an admin overrode it.
+func (d *Backend) RepositoryQuota(ctx context.Context, repo string) (Quota, error) {
+	repo = utils.SanitizeRepo(repo)
+	if _, err := d.Repository(ctx, repo); err != nil {
+		return Quota{}, err
+	}
+
+	var m models.Quota
+	if err := d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		var err error
+		m, err = d.store.GetRepoQuotaByName(ctx, tx, repo)
+		return err
+	}); err != nil && !errors.Is(db.WrapError(err), db.ErrRecordNotFound) {
+		return Quota{}, db.WrapError(err)
+	}
+
+	return quotaFromModel(m, Quota{
+		Size:    d.cfg.Quota.RepoSize,
+		LFSSize: d.cfg.Quota.RepoLFSSize,
+	}), nil
+}
+
+// SetRepositoryQuota overrides the quota of a repository.
+func (d *Backend) SetRepositoryQuota(ctx context.Context, repo string, opts QuotaOptions) error {
+	repo = utils.SanitizeRepo(repo)
+	if _, err := d.Repository(ctx, repo); err != nil {
+		return err
+	}
+
+	if err := db.WrapError(
+		d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+			m, err := d.store.GetRepoQuotaByName(ctx, tx, repo)
+			if err != nil && !errors.Is(db.WrapError(err), db.ErrRecordNotFound) {
+				return err
+			}
+
+			size, lfsSize := applyQuotaOptions(m, opts)
+			return d.store.SetRepoQuotaByName(ctx, tx, repo, size, lfsSize)
+		}),
+	); err != nil {
+		return err
+	}
+
+	d.audit(ctx, AuditRepoSetQuota, repo, opts.auditDetails()...)
+	return nil
+}
+
+// RepositoryUsage returns the storage used by a repository.
+func (d *Backend) RepositoryUsage(ctx context.Context, repo string) (QuotaUsage, error) {
+	r, err := d.Repository(ctx, repo)
+	if err != nil {
+		return QuotaUsage{}, err
+	}
+
+	size, err := dirSize(filepath.Join(d.repoPath(r.Name()), "objects"))
+	if err != nil {
+		return QuotaUsage{}, err
+	}
+
+	var lfsSize int64
+	if err := d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		var err error
+		lfsSize, err = d.store.GetLFSObjectsSizeByRepoID(ctx, tx, r.ID())
+		return err
+	}); err != nil {
+		return QuotaUsage{}, db.WrapError(err)
+	}
+
+	return QuotaUsage{Size: size, LFSSize: lfsSize}, nil
+}
+
+// UserQuota returns the quota of a user, the server default unless an admin
+// overrode it. It applies to all the repositories the user owns.
+func (d *Backend) UserQuota(ctx context.Context, username string) (Quota, error) {
+	user, err := d.User(ctx, username)
+	if err != nil {
+		return Quota{}, err
+	}
+
+	return d.userQuota(ctx, user.ID())
+}
+
+func (d *Backend) userQuota(ctx context.Context, userID int64) (Quota, error) {
+	var m models.Quota
+	if err := d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		var err error
+		m, err = d.store.GetUserQuotaByID(ctx, tx, userID)
+		return err
+	}); err != nil && !errors.Is(db.WrapError(err), db.ErrRecordNotFound) {
+		return Quota{}, db.WrapError(err)
+	}
+
+	return quotaFromModel(m, Quota{
+		Size:    d.cfg.Quota.UserSize,
+		LFSSize: d.cfg.Quota.UserLFSSize,
+	}), nil
+}
+
+// SetUserQuota overrides the quota of a user.
+func (d *Backend) SetUserQuota(ctx context.Context, username string, opts QuotaOptions) error {
+	user, err := d.User(ctx, username)
+	if err != nil {
+		return err
+	}
+
+	if err := db.WrapError(
+		d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+			m, err := d.store.GetUserQuotaByID(ctx, tx, user.ID())
+			if err != nil && !errors.Is(db.WrapError(err), db.ErrRecordNotFound) {
+				return err
+			}
+
+			size, lfsSize := applyQuotaOptions(m, opts)
+			return d.store.SetUserQuotaByID(ctx, tx, user.ID(), size, lfsSize)
+		}),
+	); err != nil {
+		return err
+	}
+
+	d.audit(ctx, AuditUserSetQuota, user.Username(), opts.auditDetails()...)
+	return nil
+}
+
+// UserUsage returns the storage used by all the repositories a user owns.
+func (d *Backend) UserUsage(ctx context.Context, username string) (QuotaUsage, error) {
+	user, err := d.User(ctx, username)
+	if err != nil {
+		return QuotaUsage{}, err
+	}
+
+	size, err := d.userSize(ctx, user.ID())
+	if err != nil {
+		return QuotaUsage{}, err
+	}
+
+	var lfsSize int64
+	if err := d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		var err error
+		lfsSize, err = d.store.GetLFSObjectsSizeByUserID(ctx, tx, user.ID())
+		return err
+	}); err != nil {
+		return QuotaUsage{}, db.WrapError(err)
+	}
+
+	return QuotaUsage{Size: size, LFSSize: lfsSize}, nil
+}
+
+// userSize returns the disk space taken by the Git objects of all the
+// repositories a user owns.
+func (d *Backend) userSize(ctx context.Context, userID int64) (int64, error) {
+	var repos []models.Repo
+	if err := d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		var err error
+		repos, err = d.store.GetUserRepos(ctx, tx, userID)
+		return err
+	}); err != nil {
+		return 0, db.WrapError(err)
+	}
+
+	var size int64
+	for _, r := range repos {
+		n, err := dirSize(filepath.Join(d.repoPath(r.Name), "objects"))
+		if err != nil {
+			return 0, err
+		}
+		size += n
+	}
+
+	return size, nil
+}
+
+// CheckLFSQuota returns an error wrapping proto.ErrQuotaExceeded if storing
+// size more bytes of LFS objects in a repository would exceed its quota or
+// the quota of its owner.
+func (d *Backend) CheckLFSQuota(ctx context.Context, repo proto.Repository, size int64) error {
+	if size <= 0 {
+		return nil
+	}
+
+	q, err := d.RepositoryQuota(ctx, repo.Name())
+	if err != nil {
+		return err
+	}
+
+	if q.LFSSize > 0 {
+		var used int64
+		if err := d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+			var err error
+			used, err = d.store.GetLFSObjectsSizeByRepoID(ctx, tx, repo.ID())
+			return err
+		}); err != nil {
+			return db.WrapError(err)
+		}
+
+		if used+size > q.LFSSize {
+			return fmt.Errorf("%w: repository LFS storage would be %s, quota is %s",
+				proto.ErrQuotaExceeded, humanize.IBytes(uint64(used+size)), humanize.IBytes(uint64(q.LFSSize))) //nolint: gosec
+		}
+	}
+
+	if repo.UserID() == 0 {
+		return nil
+	}
+
+	uq, err := d.userQuota(ctx, repo.UserID())
+	if err != nil {
+		return err
+	}
+
+	if uq.LFSSize > 0 {
+		var used int64
+		if err := d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+			var err error
+			used, err = d.store.GetLFSObjectsSizeByUserID(ctx, tx, repo.UserID())
+			return err
+		}); err != nil {
+			return db.WrapError(err)
+		}
+
+		if used+size > uq.LFSSize {
+			return fmt.Errorf("%w: owner LFS storage would be %s, quota is %s",
+				proto.ErrQuotaExceeded, humanize.IBytes(uint64(used+size)), humanize.IBytes(uint64(uq.LFSSize))) //nolint: gosec
+		}
+	}
+
+	return nil
+}
+
+// QuotaViolations checks the size of a repository receiving a push against
+// its quota and the quota of its owner, and returns a message for every quota
+// the push would exceed.
+//
+// It runs in the pre-receive hook, where the pushed objects are still in the
+// quarantine directory inside the repository's object store. Pushes that
+// bring no new objects, like deletions, are let through so a repository over
+// its quota can still be cleaned up.
+func (d *Backend) QuotaViolations(ctx context.Context, repo proto.Repository, args []hooks.HookArg) ([]string, error) {
+	deleteOnly := true
+	for _, arg := range args {
+		if !git.IsZeroHash(arg.NewSha) {
+			deleteOnly = false
+			break
+		}
+	}
+
+	if deleteOnly {
+		return nil, nil
+	}
+
+	q, err := d.RepositoryQuota(ctx, repo.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	var uq Quota
+	if repo.UserID() > 0 {
+		uq, err = d.userQuota(ctx, repo.UserID())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if q.Size == 0 && uq.Size == 0 {
+		return nil, nil
+	}
+
+	if qp := os.Getenv("GIT_QUARANTINE_PATH"); qp != "" {
+		incoming, err := dirSize(qp)
+		if err != nil {
+			return nil, err
+		}
+
+		if incoming == 0 {
+			return nil, nil
+		}
+	}
+
+	var violations []string
+	if q.Size > 0 {
+		size, err := dirSize(filepath.Join(d.repoPath(repo.Name()), "objects"))
+		if err != nil {
+			return nil, err
+		}
+
+		if size > q.Size {
+			violations = append(violations, fmt.Sprintf("repository size would be %s, quota is %s",
+				humanize.IBytes(uint64(size)), humanize.IBytes(uint64(q.Size)))) //nolint: gosec
+		}
+	}
+
+	if uq.Size > 0 {
+		size, err := d.userSize(ctx, repo.UserID())
+		if err != nil {
+			return nil, err
+		}
+
+		if size > uq.Size {
+			violations = append(violations, fmt.Sprintf("size of the owner's repositories would be %s, quota is %s",
+				humanize.IBytes(uint64(size)), humanize.IBytes(uint64(uq.Size)))) //nolint: gosec
+		}
+	}
+
+	return violations, nil
+}
+
+// dirSize returns the total size of the regular files under a directory. A
+// missing directory is empty.
+func dirSize(dir string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// Files come and go while objects are repacked.
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		fi, err := d.Info()
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+
+		size += fi.Size()
+		return nil
+	})
+
+	return size, err
+}
@@ -0,0 +1,146 @@
+package backend
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/config"
+	"github.com/charmbracelet/soft-serve/pkg/hooks"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/matryer/is"
+)
+
+func TestQuotaOverrides(t *testing.T) {
+	is := is.New(t)
+	be, cfg := newTestBackend(t)
+	ctx := context.Background()
+
+	_, err := be.CreateRepository(ctx, "repo", nil, proto.RepositoryOptions{})
+	is.NoErr(err)
+	_, err = be.CreateUser(ctx, "alice", proto.UserOptions{})
+	is.NoErr(err)
+
+	// Without overrides, the server defaults apply.
+	cfg.Quota = config.QuotaConfig{
+		RepoSize:    1000,
+		RepoLFSSize: 2000,
+		UserSize:    3000,
+		UserLFSSize: 4000,
+	}
+	q, err := be.RepositoryQuota(ctx, "repo")
+	is.NoErr(err)
+	is.Equal(q, Quota{Size: 1000, LFSSize: 2000})
+	q, err = be.UserQuota(ctx, "alice")
+	is.NoErr(err)
+	is.Equal(q, Quota{Size: 3000, LFSSize: 4000})
+
+	// Limits are changed one at a time, 0 lifts a limit and a negative
+	// value resets it to the default.
+	zero, ten, reset := int64(0), int64(10), int64(-1)
+	is.NoErr(be.SetRepositoryQuota(ctx, "repo", QuotaOptions{Size: &ten}))
+	is.NoErr(be.SetRepositoryQuota(ctx, "repo", QuotaOptions{LFSSize: &zero}))
+	q, err = be.RepositoryQuota(ctx, "repo")
+	is.NoErr(err)
+	is.Equal(q, Quota{Size: 10, LFSSize: 0})
+
+	is.NoErr(be.SetRepositoryQuota(ctx, "repo", QuotaOptions{Size: &reset}))
+	q, err = be.RepositoryQuota(ctx, "repo")
+	is.NoErr(err)
+	is.Equal(q, Quota{Size: 1000, LFSSize: 0})
+
+	is.NoErr(be.SetUserQuota(ctx, "alice", QuotaOptions{Size: &ten, LFSSize: &ten}))
+	q, err = be.UserQuota(ctx, "alice")
+	is.NoErr(err)
+	is.Equal(q, Quota{Size: 10, LFSSize: 10})
+
+	err = be.SetRepositoryQuota(ctx, "nope", QuotaOptions{Size: &ten})
+	is.True(errors.Is(err, proto.ErrRepoNotFound))
+	err = be.SetUserQuota(ctx, "bob", QuotaOptions{Size: &ten})
+	is.True(errors.Is(err, proto.ErrUserNotFound))
+}
+
+func TestCheckLFSQuota(t *testing.T) {
+	is := is.New(t)
+	be, cfg := newTestBackend(t)
+	ctx := context.Background()
+
+	alice, err := be.CreateUser(ctx, "alice", proto.UserOptions{})
+	is.NoErr(err)
+	r1, err := be.CreateRepository(ctx, "r1", alice, proto.RepositoryOptions{})
+	is.NoErr(err)
+	r2, err := be.CreateRepository(ctx, "r2", alice, proto.RepositoryOptions{})
+	is.NoErr(err)
+	is.NoErr(be.store.CreateLFSObject(ctx, be.db, r1.ID(), strings.Repeat("a", 64), 600))
+
+	// No limits by default.
+	is.NoErr(be.CheckLFSQuota(ctx, r1, 1<<30))
+
+	cfg.Quota.RepoLFSSize = 1000
+	is.NoErr(be.CheckLFSQuota(ctx, r1, 400))
+	err = be.CheckLFSQuota(ctx, r1, 401)
+	is.True(errors.Is(err, proto.ErrQuotaExceeded))
+	is.True(strings.Contains(err.Error(), "repository LFS storage would be 1001 B, quota is 1000 B"))
+
+	// The owner's quota counts the objects of all their repositories.
+	cfg.Quota.UserLFSSize = 1000
+	is.NoErr(be.CheckLFSQuota(ctx, r2, 400))
+	err = be.CheckLFSQuota(ctx, r2, 401)
+	is.True(errors.Is(err, proto.ErrQuotaExceeded))
+	is.True(strings.Contains(err.Error(), "owner LFS storage"))
+
+	u, err := be.UserUsage(ctx, "alice")
+	is.NoErr(err)
+	is.Equal(u.LFSSize, int64(600))
+}
+
+func TestQuotaViolations(t *testing.T) {
+	is := is.New(t)
+	be, cfg := newTestBackend(t)
+	ctx := context.Background()
+
+	alice, err := be.CreateUser(ctx, "alice", proto.UserOptions{})
+	is.NoErr(err)
+	r, err := be.CreateRepository(ctx, "repo", alice, proto.RepositoryOptions{})
+	is.NoErr(err)
+
+	// Pushed objects wait in a quarantine directory inside the object store
+	// until the hooks accept them.
+	quarantine := filepath.Join(be.repoPath("repo"), "objects", "tmp_objdir-incoming-abc123")
+	is.NoErr(os.MkdirAll(quarantine, 0o755))
+	t.Setenv("GIT_QUARANTINE_PATH", quarantine)
+
+	u, err := be.RepositoryUsage(ctx, "repo")
+	is.NoErr(err)
+	cfg.Quota.RepoSize = u.Size + 100
+	cfg.Quota.UserSize = u.Size + 50
+
+	push := []hooks.HookArg{{OldSha: git.ZeroID, NewSha: strings.Repeat("1", 40), RefName: "refs/heads/main"}}
+
+	// Nothing new received.
+	violations, err := be.QuotaViolations(ctx, r, push)
+	is.NoErr(err)
+	is.Equal(len(violations), 0)
+
+	is.NoErr(os.WriteFile(filepath.Join(quarantine, "pack-1.pack"), []byte(strings.Repeat("x", 75)), 0o644))
+	violations, err = be.QuotaViolations(ctx, r, push)
+	is.NoErr(err)
+	is.Equal(len(violations), 1)
+	is.True(strings.Contains(violations[0], "size of the owner's repositories would be"))
+
+	is.NoErr(os.WriteFile(filepath.Join(quarantine, "pack-2.pack"), []byte(strings.Repeat("x", 50)), 0o644))
+	violations, err = be.QuotaViolations(ctx, r, push)
+	is.NoErr(err)
+	is.Equal(len(violations), 2)
+	is.True(strings.Contains(violations[0], "repository size would be"))
+
+	// Deletions are never rejected, a repository over quota can be cleaned
+	// up.
+	violations, err = be.QuotaViolations(ctx, r, []hooks.HookArg{{OldSha: strings.Repeat("1", 40), NewSha: git.ZeroID, RefName: "refs/heads/main"}})
+	is.NoErr(err)
+	is.Equal(len(violations), 0)
+}
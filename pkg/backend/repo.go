@@ -9,18 +9,17 @@ import (
 	"os"
 	"path"
 	"path/filepath"
-	"strconv"
 	"strings"
 	"time"
 
 	"github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/config"
 	"github.com/charmbracelet/soft-serve/pkg/db"
 	"github.com/charmbracelet/soft-serve/pkg/db/models"
 	"github.com/charmbracelet/soft-serve/pkg/hooks"
 	"github.com/charmbracelet/soft-serve/pkg/lfs"
 	"github.com/charmbracelet/soft-serve/pkg/proto"
 	"github.com/charmbracelet/soft-serve/pkg/ssrf"
-	"github.com/charmbracelet/soft-serve/pkg/storage"
 	"github.com/charmbracelet/soft-serve/pkg/task"
 	"github.com/charmbracelet/soft-serve/pkg/utils"
 	"github.com/charmbracelet/soft-serve/pkg/webhook"
@@ -28,8 +27,9 @@ import (
 
 // validateImportRemote validates an import remote against private, internal,
 // and loopback ranges, and returns the git environment that must be applied to
-// the clone subprocess for that validation to hold.
-func validateImportRemote(remote string) ([]string, error) {
+// the clone subprocess for that validation to hold. The environment
+// authenticates to the remote with creds, if any.
+func validateImportRemote(remote string, creds *proto.MirrorCredentials) ([]string, error) {
 	endpoint, err := lfs.NewEndpoint(remote)
 	if err != nil || endpoint.Host == "" {
 		return nil, proto.ErrInvalidRemote
@@ -42,6 +42,9 @@ func validateImportRemote(remote string) ([]string, error) {
 		return nil, fmt.Errorf("%w: %w", proto.ErrInvalidRemote, err)
 	}
 
+	if v.Transport == ssrf.GitTransportHTTP {
+		v.Config = append(v.Config, MirrorCredentialsGitConfig(creds, remote)...)
+	}
 	return ssrf.GitEnv(v), nil
 }
 
@@ -49,6 +52,18 @@ func validateImportRemote(remote string) ([]string, error) {
 //
 // It implements backend.Backend.
 func (d *Backend) CreateRepository(ctx context.Context, name string, user proto.User, opts proto.RepositoryOptions) (proto.Repository, error) {
+	r, err := d.createRepository(ctx, name, user, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	d.audit(ctx, AuditRepoCreate, r.Name(), "private", opts.Private, "hidden", opts.Hidden)
+	return r, nil
+}
+
+// createRepository creates a new repository without recording it in the
+// audit log, for callers that record their own action.
+func (d *Backend) createRepository(ctx context.Context, name string, user proto.User, opts proto.RepositoryOptions) (proto.Repository, error) {
 	name = utils.SanitizeRepo(name)
 	if err := utils.ValidateRepo(name); err != nil {
 		return nil, err
@@ -149,14 +164,20 @@ func (d *Backend) defaultAdminUserID(ctx context.Context, tx *db.Tx) (int64, err
 
 // ImportRepository imports a repository from remote.
 // XXX: This a expensive operation and should be run in a goroutine.
-func (d *Backend) ImportRepository(_ context.Context, name string, user proto.User, remote string, opts proto.RepositoryOptions) (proto.Repository, error) {
+func (d *Backend) ImportRepository(ctx context.Context, name string, user proto.User, remote string, opts proto.RepositoryOptions) (proto.Repository, error) {
 	name = utils.SanitizeRepo(name)
 	if err := utils.ValidateRepo(name); err != nil {
 		return nil, err
 	}
 
+	if opts.MirrorCredentials != nil {
+		if err := validateMirrorCredentials(*opts.MirrorCredentials); err != nil {
+			return nil, err
+		}
+	}
+
 	remote = utils.Sanitize(remote)
-	remoteEnv, err := validateImportRemote(remote)
+	remoteEnv, err := validateImportRemote(remote, opts.MirrorCredentials)
 	if err != nil {
 		return nil, err
 	}
@@ -187,15 +208,17 @@ func (d *Backend) ImportRepository(_ context.Context, name string, user proto.Us
 	d.manager.Add(tid, func(ctx context.Context) (err error) {
 		ctx = proto.WithUserContext(ctx, user)
 
+		sshCommand, cleanup, err := MirrorSSHCommand(d.cfg, opts.MirrorCredentials)
+		if err != nil {
+			return err
+		}
+
+		defer cleanup()
+
 		// remoteEnv carries the SSRF guard: it disables redirect following
 		// and pins the validated address. Without it the clone can reach a
 		// different host than the one validated above.
-		cloneEnv := append(remoteEnv,
-			fmt.Sprintf(`GIT_SSH_COMMAND=ssh -o UserKnownHostsFile="%s" -o StrictHostKeyChecking=no -i "%s"`,
-				filepath.Join(d.cfg.DataPath, "ssh", "known_hosts"),
-				d.cfg.SSH.ClientKeyPath,
-			),
-		)
+		cloneEnv := append(remoteEnv, sshCommand)
 
 		copts := git.CloneOptions{
 			Bare:   true,
@@ -218,7 +241,7 @@ func (d *Backend) ImportRepository(_ context.Context, name string, user proto.Us
 			return err
 		}
 
-		r, err := d.CreateRepository(ctx, name, user, opts)
+		r, err := d.createRepository(ctx, name, user, opts)
 		if err != nil {
 			d.logger.Error("failed to create repository", "err", err, "name", name)
 			return err
@@ -232,6 +255,13 @@ func (d *Backend) ImportRepository(_ context.Context, name string, user proto.Us
 			}
 		}()
 
+		if opts.MirrorCredentials != nil {
+			if err := d.SetMirrorCredentials(ctx, name, *opts.MirrorCredentials); err != nil {
+				d.logger.Error("failed to set mirror credentials", "err", err, "name", name)
+				return err
+			}
+		}
+
 		rr, err := r.Open()
 		if err != nil {
 			d.logger.Error("failed to open repository", "err", err, "path", rp)
@@ -283,7 +313,13 @@ func (d *Backend) ImportRepository(_ context.Context, name string, user proto.Us
 		d.manager.Run(tid, done)
 	}()
 
-	return <-repoc, <-done
+	r := <-repoc
+	if err := <-done; err != nil {
+		return r, err
+	}
+
+	d.audit(ctx, AuditRepoImport, name, "remote", PushMirror{URL: remote}.RedactedURL(), "mirror", opts.Mirror)
+	return r, nil
 }
 
 // DeleteRepository deletes a repository.
@@ -323,8 +359,11 @@ func (d *Backend) DeleteRepository(ctx context.Context, name string) error {
 			return db.WrapError(dberr)
 		}
 
-		repoID := strconv.FormatInt(repom.ID, 10)
-		strg := storage.NewLocalStorage(filepath.Join(d.cfg.DataPath, "lfs", repoID))
+		strg, err := config.LFSStorage(d.cfg, repom.ID)
+		if err != nil {
+			return err
+		}
+
 		objs, err := d.store.GetLFSObjectsByName(ctx, tx, name)
 		if err != nil {
 			return db.WrapError(err)
@@ -355,6 +394,7 @@ func (d *Backend) DeleteRepository(ctx context.Context, name string) error {
 		return db.WrapError(err)
 	}
 
+	d.audit(ctx, AuditRepoDelete, name)
 	return webhook.SendEvent(ctx, wh)
 }
 
@@ -431,6 +471,8 @@ func (d *Backend) RenameRepository(ctx context.Context, oldName string, newName
 		return db.WrapError(err)
 	}
 
+	d.audit(ctx, AuditRepoRename, oldName, "name", newName)
+
 	user := proto.UserFromContext(ctx)
 	repo, err := d.Repository(ctx, newName)
 	if err != nil {
@@ -613,9 +655,14 @@ func (d *Backend) SetHidden(ctx context.Context, name string, hidden bool) error
 	// Delete cache
 	d.cache.Delete(name)
 
-	return db.WrapError(d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+	if err := d.db.TransactionContext(ctx, func(tx *db.Tx) error {
 		return d.store.SetRepoIsHiddenByName(ctx, tx, name, hidden)
-	}))
+	}); err != nil {
+		return db.WrapError(err)
+	}
+
+	d.audit(ctx, AuditRepoSetHidden, name, "hidden", hidden)
+	return nil
 }
 
 // SetMirror sets the mirror flag of a repository.
@@ -735,6 +782,8 @@ func (d *Backend) SetPrivate(ctx context.Context, name string, private bool) err
 		return err
 	}
 
+	d.audit(ctx, AuditRepoSetPrivate, name, "private", private)
+
 	user := proto.UserFromContext(ctx)
 	repo, err := d.Repository(ctx, name)
 	if err != nil {
@@ -73,7 +73,7 @@ func TestValidateImportRemote(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			is := is.New(t)
-			env, err := validateImportRemote(tt.remote)
+			env, err := validateImportRemote(tt.remote, nil)
 
 			if tt.wantErr {
 				is.True(err != nil)
@@ -37,9 +37,14 @@ func (b *Backend) AllowKeyless(ctx context.Context) bool {
 //
 // It implements backend.Backend.
 func (b *Backend) SetAllowKeyless(ctx context.Context, allow bool) error {
-	return b.db.TransactionContext(ctx, func(tx *db.Tx) error {
+	if err := b.db.TransactionContext(ctx, func(tx *db.Tx) error {
 		return b.store.SetAllowKeylessAccess(ctx, tx, allow)
-	})
+	}); err != nil {
+		return err
+	}
+
+	b.audit(ctx, AuditSettingsAllowKeyless, "", "allow", allow)
+	return nil
 }
 
 // AnonAccess returns the level of anonymous access.
@@ -72,7 +77,12 @@ func (b *Backend) AnonAccess(ctx context.Context) access.AccessLevel {
 //
 // It implements backend.Backend.
 func (b *Backend) SetAnonAccess(ctx context.Context, level access.AccessLevel) error {
-	return b.db.TransactionContext(ctx, func(tx *db.Tx) error {
+	if err := b.db.TransactionContext(ctx, func(tx *db.Tx) error {
 		return b.store.SetAnonAccess(ctx, tx, level)
-	})
+	}); err != nil {
+		return err
+	}
+
+	b.audit(ctx, AuditSettingsAnonAccess, "", "level", level)
+	return nil
 }
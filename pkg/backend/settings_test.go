@@ -2,6 +2,7 @@ package backend
 
 import (
 	"context"
+	"path/filepath"
 	"testing"
 
 	"github.com/charmbracelet/soft-serve/pkg/access"
@@ -25,6 +26,8 @@ func newTestBackend(t *testing.T) (*Backend, *config.Config) {
 	dp := t.TempDir()
 	cfg := config.DefaultConfig()
 	cfg.DataPath = dp
+	cfg.SSH.KeyPath = filepath.Join(dp, "ssh", "soft_serve_host_ed25519")
+	cfg.SSH.ClientKeyPath = filepath.Join(dp, "ssh", "soft_serve_client_ed25519")
 	cfg.DB.Driver = "sqlite"
 	cfg.DB.DataSource = dp + "/test.db"
 
@@ -0,0 +1,182 @@
+package backend
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/sshutils"
+	"github.com/charmbracelet/soft-serve/pkg/webhook"
+	"golang.org/x/crypto/ssh"
+)
+
+// signatureNamespace is the namespace git signs commits and tags in.
+const signatureNamespace = "git"
+
+// SignatureStatus is the result of verifying the signature of a commit or
+// tag.
+type SignatureStatus string
+
+const (
+	// SignatureUnsigned means the object has no signature.
+	SignatureUnsigned SignatureStatus = "unsigned"
+	// SignatureVerified means the signature is valid and made with a key
+	// registered by the user who committed or tagged the object.
+	SignatureVerified SignatureStatus = "verified"
+	// SignatureUnverified means the signature is malformed, doesn't match
+	// the object, or is made with the key of a user other than the
+	// committer or tagger.
+	SignatureUnverified SignatureStatus = "unverified"
+	// SignatureUnknownKey means the signature is valid but made with a key
+	// that no user registered, or isn't an SSH signature.
+	SignatureUnknownKey SignatureStatus = "unknown_key"
+)
+
+// String returns a human readable representation of the status.
+func (s SignatureStatus) String() string {
+	switch s {
+	case SignatureVerified:
+		return "Verified"
+	case SignatureUnverified:
+		return "Unverified"
+	case SignatureUnknownKey:
+		return "Unknown key"
+	default:
+		return "Unsigned"
+	}
+}
+
+// SignatureVerification is the verification of a commit or tag signature.
+type SignatureVerification struct {
+	Status SignatureStatus
+	// Signer is the username of the user who owns the signing key, set
+	// when the signature is valid and the key registered.
+	Signer string
+	// Fingerprint is the SHA256 fingerprint of the signing key, set for
+	// SSH signatures.
+	Fingerprint string
+}
+
+// IsSigned returns whether the object has a signature.
+func (v SignatureVerification) IsSigned() bool {
+	return v.Status != "" && v.Status != SignatureUnsigned
+}
+
+// String returns the status, followed by the signer if known.
+func (v SignatureVerification) String() string {
+	switch {
+	case v.Signer == "":
+		return v.Status.String()
+	case v.Status == SignatureVerified:
+		return v.Status.String() + " (" + v.Signer + ")"
+	default:
+		return v.Status.String() + " (signed by " + v.Signer + ")"
+	}
+}
+
+// VerifyCommit verifies the signature of the commit with the given ID
+// against the public keys registered by its committer.
+func (d *Backend) VerifyCommit(ctx context.Context, r *git.Repository, id string) (SignatureVerification, error) {
+	sig, err := r.CommitSignature(id)
+	if err != nil {
+		return SignatureVerification{}, err
+	}
+
+	return d.verifySignature(ctx, sig), nil
+}
+
+// VerifyTag verifies the signature of the tag with the given name against
+// the public keys registered by its tagger. Lightweight tags are unsigned.
+func (d *Backend) VerifyTag(ctx context.Context, r *git.Repository, name string) (SignatureVerification, error) {
+	sig, err := r.TagSignature(name)
+	if err != nil {
+		return SignatureVerification{}, err
+	}
+
+	return d.verifySignature(ctx, sig), nil
+}
+
+func (d *Backend) verifySignature(ctx context.Context, sig *git.ObjectSignature) SignatureVerification {
+	if sig == nil {
+		return SignatureVerification{Status: SignatureUnsigned}
+	}
+
+	s, err := sshutils.ParseSignature(sig.Signature)
+	if err != nil {
+		// GPG and X.509 signatures can't be tied to a user.
+		if !bytes.HasPrefix(sig.Signature, []byte("-----BEGIN SSH SIGNATURE-----")) {
+			return SignatureVerification{Status: SignatureUnknownKey}
+		}
+
+		return SignatureVerification{Status: SignatureUnverified}
+	}
+
+	v := SignatureVerification{Fingerprint: ssh.FingerprintSHA256(s.PublicKey)}
+	if err := s.Verify(sig.Payload, signatureNamespace); err != nil {
+		v.Status = SignatureUnverified
+		return v
+	}
+
+	u, err := d.UserByPublicKey(ctx, s.PublicKey)
+	if err != nil {
+		if !errors.Is(err, proto.ErrUserNotFound) {
+			d.logger.Error("error finding signer", "fingerprint", v.Fingerprint, "err", err)
+		}
+
+		v.Status = SignatureUnknownKey
+		return v
+	}
+
+	v.Signer = u.Username()
+	v.Status = SignatureVerified
+	if !isCommitter(v.Signer, sig.Committer) {
+		v.Status = SignatureUnverified
+	}
+
+	return v
+}
+
+// isCommitter returns whether the user with the given username committed or
+// tagged an object: users have no email addresses, so their username has to
+// be the committer's name or the local part of the committer's email.
+func isCommitter(username string, committer *git.Signature) bool {
+	if committer == nil {
+		return false
+	}
+
+	local, _, _ := strings.Cut(committer.Email, "@")
+	return strings.EqualFold(committer.Name, username) || strings.EqualFold(local, username)
+}
+
+// newPushEvent creates a push webhook event with the signature verification
+// of the pushed commits.
+func (d *Backend) newPushEvent(ctx context.Context, user proto.User, repo proto.Repository, ref, before, after string) (webhook.PushEvent, error) {
+	wh, err := webhook.NewPushEvent(ctx, user, repo, ref, before, after)
+	if err != nil {
+		return wh, err
+	}
+
+	r, err := repo.Open()
+	if err != nil {
+		return wh, err
+	}
+
+	for i, c := range wh.Commits {
+		v, err := d.VerifyCommit(ctx, r, c.ID)
+		if err != nil {
+			d.logger.Error("error verifying commit signature", "repo", repo.Name(), "commit", c.ID, "err", err)
+			continue
+		}
+
+		wh.Commits[i].Verification = webhook.Verification{
+			Status:      string(v.Status),
+			Signer:      v.Signer,
+			Fingerprint: v.Fingerprint,
+		}
+	}
+
+	return wh, nil
+}
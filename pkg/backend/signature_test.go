@@ -0,0 +1,212 @@
+package backend
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/config"
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/hooks"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/store"
+	"github.com/charmbracelet/soft-serve/pkg/webhook"
+	"github.com/matryer/is"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+// newTestSigningKey writes a new SSH private key to a file git can sign
+// with, and returns the path and public key.
+func newTestSigningKey(t *testing.T) (string, gossh.PublicKey) {
+	t.Helper()
+	if _, err := exec.LookPath("ssh-keygen"); err != nil {
+		t.Skip("ssh-keygen not found")
+	}
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	block, err := gossh.MarshalPrivateKey(priv, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "id_ed25519")
+	if err := os.WriteFile(path, pem.EncodeToMemory(block), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	pk, err := gossh.NewPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return path, pk
+}
+
+func TestVerifySignatures(t *testing.T) {
+	is := is.New(t)
+	be, cfg := newTestBackend(t)
+	// Push events are built from the context.
+	ctx := config.WithContext(context.Background(), cfg)
+	ctx = db.WithContext(ctx, be.db)
+	ctx = store.WithContext(ctx, be.store)
+	keyPath, pk := newTestSigningKey(t)
+
+	rr, err := be.CreateRepository(ctx, "repo", nil, proto.RepositoryOptions{})
+	is.NoErr(err)
+
+	sign := []string{"-c", "gpg.format=ssh", "-c", "user.signingkey=" + keyPath}
+	work := t.TempDir()
+	gitOutput(t, work, "init", "-q")
+	gitOutput(t, work, "commit", "-q", "--allow-empty", "-m", "unsigned")
+	unsigned := gitOutput(t, work, "rev-parse", "HEAD")
+	gitOutput(t, work, append(sign, "commit", "-q", "-S", "--allow-empty", "-m", "signed")...)
+	signed := gitOutput(t, work, "rev-parse", "HEAD")
+	gitOutput(t, work, append(sign, "tag", "-s", "-m", "signed", "v1")...)
+	gitOutput(t, work, "tag", "-a", "-m", "annotated", "v2")
+	gitOutput(t, work, "tag", "v3")
+	// Like a push in progress, the signed commit isn't referenced yet.
+	gitOutput(t, be.repoPath("repo"), "fetch", "-q", "--no-tags", work, unsigned+":refs/heads/main", signed+":refs/heads/scratch")
+	gitOutput(t, be.repoPath("repo"), "update-ref", "-d", "refs/heads/scratch")
+
+	r, err := rr.Open()
+	is.NoErr(err)
+
+	v, err := be.VerifyCommit(ctx, r, unsigned)
+	is.NoErr(err)
+	is.Equal(v, SignatureVerification{Status: SignatureUnsigned})
+	is.True(!v.IsSigned())
+
+	// Until the key is registered, the signature can't be tied to a user.
+	v, err = be.VerifyCommit(ctx, r, signed)
+	is.NoErr(err)
+	is.Equal(v.Status, SignatureUnknownKey)
+	is.Equal(v.Fingerprint, gossh.FingerprintSHA256(pk))
+
+	args := []hooks.HookArg{{OldSha: unsigned, NewSha: signed, RefName: "refs/heads/main"}}
+	is.NoErr(be.SetPushPolicy(ctx, "repo", PushPolicy{RequireSignedCommits: true}))
+	p, err := be.PushPolicy(ctx, "repo")
+	is.NoErr(err)
+	is.True(p.RequireSignedCommits)
+	violations, err := be.PushPolicyViolations(ctx, rr, args)
+	is.NoErr(err)
+	is.Equal(len(violations), 1)
+	is.True(strings.Contains(violations[0], "must be signed with a registered SSH key (unknown key)"))
+
+	// The signer has to be the committer.
+	_, err = be.CreateUser(ctx, "other", proto.UserOptions{PublicKeys: []gossh.PublicKey{pk}})
+	is.NoErr(err)
+
+	v, err = be.VerifyCommit(ctx, r, signed)
+	is.NoErr(err)
+	is.Equal(v.Status, SignatureUnverified)
+	is.Equal(v.String(), "Unverified (signed by other)")
+
+	// Commits are made by "test <test@example.com>".
+	is.NoErr(be.SetUsername(ctx, "other", "test"))
+	signer, err := be.User(ctx, "test")
+	is.NoErr(err)
+
+	v, err = be.VerifyCommit(ctx, r, signed)
+	is.NoErr(err)
+	is.Equal(v.Status, SignatureVerified)
+	is.Equal(v.Signer, "test")
+	is.Equal(v.String(), "Verified (test)")
+
+	violations, err = be.PushPolicyViolations(ctx, rr, args)
+	is.NoErr(err)
+	is.Equal(len(violations), 0)
+
+	// Push webhooks carry the verification of the pushed commits.
+	wh, err := be.newPushEvent(ctx, signer, rr, "refs/heads/main", unsigned, signed)
+	is.NoErr(err)
+	is.Equal(len(wh.Commits), 1)
+	is.Equal(wh.Commits[0].Verification, webhook.Verification{
+		Status:      "verified",
+		Signer:      "test",
+		Fingerprint: gossh.FingerprintSHA256(pk),
+	})
+
+	gitOutput(t, be.repoPath("repo"), "fetch", "-q", work, "refs/tags/*:refs/tags/*")
+
+	for tag, want := range map[string]SignatureStatus{
+		"v1": SignatureVerified,
+		"v2": SignatureUnsigned,
+		"v3": SignatureUnsigned,
+	} {
+		v, err := be.VerifyTag(ctx, r, tag)
+		is.NoErr(err)
+		is.Equal(v.Status, want)
+	}
+
+	// A signature that doesn't match its object doesn't verify.
+	sig, err := r.CommitSignature(signed)
+	is.NoErr(err)
+	v = be.verifySignature(ctx, &git.ObjectSignature{Signature: sig.Signature, Payload: []byte("tampered")})
+	is.Equal(v.Status, SignatureUnverified)
+
+	// GPG signatures are never tied to a user.
+	v = be.verifySignature(ctx, &git.ObjectSignature{
+		Signature: []byte("-----BEGIN PGP SIGNATURE-----\n\n-----END PGP SIGNATURE-----\n"),
+		Payload:   sig.Payload,
+	})
+	is.Equal(v.Status, SignatureUnknownKey)
+}
+
+func TestMergePullRequestSignedCommits(t *testing.T) {
+	is := is.New(t)
+	be, cfg := newTestBackend(t)
+	ctx := config.WithContext(context.Background(), cfg)
+	ctx = db.WithContext(ctx, be.db)
+	ctx = store.WithContext(ctx, be.store)
+	keyPath, pk := newTestSigningKey(t)
+
+	_, err := be.CreateRepository(ctx, "repo", nil, proto.RepositoryOptions{})
+	is.NoErr(err)
+	alice, err := be.CreateUser(ctx, "alice", proto.UserOptions{PublicKeys: []gossh.PublicKey{pk}})
+	is.NoErr(err)
+
+	// feature diverges from main, ahead only adds to it. All commits are
+	// signed.
+	commit := append([]string{"-c", "gpg.format=ssh", "-c", "user.signingkey=" + keyPath},
+		"commit", "-q", "-S", "--allow-empty", "-m")
+	work := t.TempDir()
+	gitOutput(t, work, "init", "-q", "-b", "main")
+	gitOutput(t, work, append(commit, "first")...)
+	gitOutput(t, work, "checkout", "-q", "-b", "feature")
+	gitOutput(t, work, append(commit, "feature")...)
+	gitOutput(t, work, "checkout", "-q", "main")
+	gitOutput(t, work, append(commit, "main")...)
+	gitOutput(t, work, "checkout", "-q", "-b", "ahead")
+	gitOutput(t, work, append(commit, "ahead")...)
+	gitOutput(t, be.repoPath("repo"), "fetch", "-q", work, "refs/heads/*:refs/heads/*")
+	main := gitOutput(t, be.repoPath("repo"), "rev-parse", "main")
+
+	is.NoErr(be.SetPushPolicy(ctx, "repo", PushPolicy{RequireSignedCommits: true}))
+
+	// The server can't sign merge commits.
+	feature, err := be.CreatePullRequest(ctx, "repo", alice, "feature", "main", "", "")
+	is.NoErr(err)
+	_, err = be.MergePullRequest(ctx, "repo", feature.Number, alice)
+	is.True(errors.Is(err, proto.ErrPushPolicy))
+	is.True(strings.Contains(err.Error(), "commits must be signed"))
+	is.Equal(gitOutput(t, be.repoPath("repo"), "rev-parse", "main"), main)
+
+	// Fast-forwards keep the signed commits.
+	ahead, err := be.CreatePullRequest(ctx, "repo", alice, "ahead", "main", "", "")
+	is.NoErr(err)
+	id, err := be.MergePullRequest(ctx, "repo", ahead.Number, alice)
+	is.NoErr(err)
+	is.Equal(id, gitOutput(t, be.repoPath("repo"), "rev-parse", "ahead"))
+}
@@ -0,0 +1,329 @@
+package backend
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/charmbracelet/soft-serve/pkg/access"
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/db/models"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/utils"
+)
+
+// TeamRepository is a repository a team has been granted access to.
+type TeamRepository struct {
+	Repo        string
+	AccessLevel access.AccessLevel
+}
+
+// CreateTeam creates a new team.
+func (d *Backend) CreateTeam(ctx context.Context, name string) error {
+	name = strings.ToLower(name)
+	if err := utils.ValidateTeam(name); err != nil {
+		return err
+	}
+
+	if err := db.WrapError(
+		d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+			return d.store.CreateTeam(ctx, tx, name)
+		}),
+	); err != nil {
+		if errors.Is(err, db.ErrDuplicateKey) {
+			return proto.ErrTeamExist
+		}
+
+		return err
+	}
+
+	d.audit(ctx, AuditTeamCreate, name)
+	return nil
+}
+
+// DeleteTeam deletes a team along with its memberships and grants.
+func (d *Backend) DeleteTeam(ctx context.Context, name string) error {
+	if err := db.WrapError(
+		d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+			if _, err := d.team(ctx, tx, name); err != nil {
+				return err
+			}
+
+			return d.store.DeleteTeamByName(ctx, tx, name)
+		}),
+	); err != nil {
+		return err
+	}
+
+	d.audit(ctx, AuditTeamDelete, name)
+	return nil
+}
+
+// Teams returns the names of all teams.
+func (d *Backend) Teams(ctx context.Context) ([]string, error) {
+	var ms []models.Team
+	if err := d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		var err error
+		ms, err = d.store.GetAllTeams(ctx, tx)
+		return err
+	}); err != nil {
+		return nil, db.WrapError(err)
+	}
+
+	teams := make([]string, len(ms))
+	for i, m := range ms {
+		teams[i] = m.Name
+	}
+
+	return teams, nil
+}
+
+// UserTeams returns the names of the teams a user is a member of.
+func (d *Backend) UserTeams(ctx context.Context, username string) ([]string, error) {
+	var ms []models.Team
+	if err := d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		var err error
+		ms, err = d.store.ListTeamsByUsername(ctx, tx, username)
+		return err
+	}); err != nil {
+		return nil, db.WrapError(err)
+	}
+
+	teams := make([]string, len(ms))
+	for i, m := range ms {
+		teams[i] = m.Name
+	}
+
+	return teams, nil
+}
+
+// AddTeamMember adds a user to a team.
+func (d *Backend) AddTeamMember(ctx context.Context, team string, username string) error {
+	if err := db.WrapError(
+		d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+			if _, err := d.team(ctx, tx, team); err != nil {
+				return err
+			}
+
+			if _, err := d.store.FindUserByUsername(ctx, tx, strings.ToLower(username)); err != nil {
+				if errors.Is(db.WrapError(err), db.ErrRecordNotFound) {
+					return proto.ErrUserNotFound
+				}
+				return err
+			}
+
+			return d.store.AddTeamMemberByUsername(ctx, tx, team, username)
+		}),
+	); err != nil {
+		if errors.Is(err, db.ErrDuplicateKey) {
+			return proto.ErrTeamMemberExist
+		}
+
+		return err
+	}
+
+	d.audit(ctx, AuditTeamAddMember, team, "user", username)
+	return nil
+}
+
+// RemoveTeamMember removes a user from a team.
+func (d *Backend) RemoveTeamMember(ctx context.Context, team string, username string) error {
+	username = strings.ToLower(username)
+	if err := db.WrapError(
+		d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+			members, err := d.teamMembers(ctx, tx, team)
+			if err != nil {
+				return err
+			}
+
+			var found bool
+			for _, m := range members {
+				if m.Username == username {
+					found = true
+					break
+				}
+			}
+
+			if !found {
+				return proto.ErrTeamMemberNotFound
+			}
+
+			return d.store.RemoveTeamMemberByUsername(ctx, tx, team, username)
+		}),
+	); err != nil {
+		return err
+	}
+
+	d.audit(ctx, AuditTeamRemoveMember, team, "user", username)
+	return nil
+}
+
+// TeamMembers returns the usernames of the members of a team.
+func (d *Backend) TeamMembers(ctx context.Context, team string) ([]string, error) {
+	var users []models.User
+	if err := d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		var err error
+		users, err = d.teamMembers(ctx, tx, team)
+		return err
+	}); err != nil {
+		return nil, db.WrapError(err)
+	}
+
+	usernames := make([]string, len(users))
+	for i, u := range users {
+		usernames[i] = u.Username
+	}
+
+	return usernames, nil
+}
+
+// SetTeamAccess grants a team an access level on a repository, replacing any
+// previous grant.
+func (d *Backend) SetTeamAccess(ctx context.Context, team string, repo string, level access.AccessLevel) error {
+	repo = utils.SanitizeRepo(repo)
+	if _, err := d.Repository(ctx, repo); err != nil {
+		return err
+	}
+
+	if err := db.WrapError(
+		d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+			if _, err := d.team(ctx, tx, team); err != nil {
+				return err
+			}
+
+			if err := d.store.RemoveTeamCollabByTeamAndRepo(ctx, tx, team, repo); err != nil {
+				return err
+			}
+
+			return d.store.AddTeamCollabByTeamAndRepo(ctx, tx, team, repo, level)
+		}),
+	); err != nil {
+		return err
+	}
+
+	d.audit(ctx, AuditTeamSetAccess, team, "repo", repo, "level", level)
+	return nil
+}
+
+// RemoveTeamAccess revokes a team's grant on a repository.
+func (d *Backend) RemoveTeamAccess(ctx context.Context, team string, repo string) error {
+	repo = utils.SanitizeRepo(repo)
+	if err := db.WrapError(
+		d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+			repos, err := d.teamRepositories(ctx, tx, team)
+			if err != nil {
+				return err
+			}
+
+			var found bool
+			for _, r := range repos {
+				if r.Repo == repo {
+					found = true
+					break
+				}
+			}
+
+			if !found {
+				return proto.ErrTeamAccessNotFound
+			}
+
+			return d.store.RemoveTeamCollabByTeamAndRepo(ctx, tx, team, repo)
+		}),
+	); err != nil {
+		return err
+	}
+
+	d.audit(ctx, AuditTeamRemoveAccess, team, "repo", repo)
+	return nil
+}
+
+// TeamRepositories returns the repositories a team has been granted access to.
+func (d *Backend) TeamRepositories(ctx context.Context, team string) ([]TeamRepository, error) {
+	var repos []TeamRepository
+	if err := d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		var err error
+		repos, err = d.teamRepositories(ctx, tx, team)
+		return err
+	}); err != nil {
+		return nil, db.WrapError(err)
+	}
+
+	return repos, nil
+}
+
+// teamAccessLevel returns the highest access level granted to the user on the
+// repository through their teams, and whether any team grants access at all.
+func (d *Backend) teamAccessLevel(ctx context.Context, repo string, username string) (access.AccessLevel, bool, error) {
+	if username == "" {
+		return -1, false, nil
+	}
+
+	var ms []models.TeamCollab
+	if err := d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		var err error
+		ms, err = d.store.ListTeamCollabsByUsernameAndRepo(ctx, tx, username, repo)
+		return err
+	}); err != nil {
+		return -1, false, db.WrapError(err)
+	}
+
+	level := access.AccessLevel(-1)
+	for _, m := range ms {
+		if m.AccessLevel > level {
+			level = m.AccessLevel
+		}
+	}
+
+	return level, len(ms) > 0, nil
+}
+
+func (d *Backend) team(ctx context.Context, tx *db.Tx, name string) (models.Team, error) {
+	m, err := d.store.GetTeamByName(ctx, tx, name)
+	if err != nil {
+		if errors.Is(db.WrapError(err), db.ErrRecordNotFound) {
+			return models.Team{}, proto.ErrTeamNotFound
+		}
+		return models.Team{}, err
+	}
+
+	return m, nil
+}
+
+func (d *Backend) teamMembers(ctx context.Context, tx *db.Tx, team string) ([]models.User, error) {
+	if _, err := d.team(ctx, tx, team); err != nil {
+		return nil, err
+	}
+
+	return d.store.ListTeamMembers(ctx, tx, team)
+}
+
+func (d *Backend) teamRepositories(ctx context.Context, tx *db.Tx, team string) ([]TeamRepository, error) {
+	if _, err := d.team(ctx, tx, team); err != nil {
+		return nil, err
+	}
+
+	collabs, err := d.store.ListTeamCollabsByTeam(ctx, tx, team)
+	if err != nil {
+		return nil, err
+	}
+
+	levels := make(map[int64]access.AccessLevel, len(collabs))
+	for _, c := range collabs {
+		levels[c.RepoID] = c.AccessLevel
+	}
+
+	rs, err := d.store.ListTeamReposByTeam(ctx, tx, team)
+	if err != nil {
+		return nil, err
+	}
+
+	repos := make([]TeamRepository, len(rs))
+	for i, r := range rs {
+		repos[i] = TeamRepository{
+			Repo:        r.Name,
+			AccessLevel: levels[r.ID],
+		}
+	}
+
+	return repos, nil
+}
@@ -0,0 +1,95 @@
+package backend
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/charmbracelet/soft-serve/pkg/access"
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/matryer/is"
+)
+
+func TestTeamMembership(t *testing.T) {
+	is := is.New(t)
+	be, _ := newTestBackend(t)
+	ctx := context.Background()
+
+	_, err := be.CreateUser(ctx, "alice", proto.UserOptions{})
+	is.NoErr(err)
+
+	is.NoErr(be.CreateTeam(ctx, "Devs"))
+	is.True(errors.Is(be.CreateTeam(ctx, "devs"), proto.ErrTeamExist))
+	is.True(be.CreateTeam(ctx, "-bad") != nil)
+
+	is.NoErr(be.AddTeamMember(ctx, "devs", "alice"))
+	is.True(errors.Is(be.AddTeamMember(ctx, "devs", "alice"), proto.ErrTeamMemberExist))
+	is.True(errors.Is(be.AddTeamMember(ctx, "devs", "bob"), proto.ErrUserNotFound))
+	is.True(errors.Is(be.AddTeamMember(ctx, "ops", "alice"), proto.ErrTeamNotFound))
+
+	members, err := be.TeamMembers(ctx, "devs")
+	is.NoErr(err)
+	is.Equal(members, []string{"alice"})
+
+	teams, err := be.UserTeams(ctx, "alice")
+	is.NoErr(err)
+	is.Equal(teams, []string{"devs"})
+
+	is.NoErr(be.RemoveTeamMember(ctx, "devs", "alice"))
+	is.True(errors.Is(be.RemoveTeamMember(ctx, "devs", "alice"), proto.ErrTeamMemberNotFound))
+
+	is.NoErr(be.DeleteTeam(ctx, "devs"))
+	is.True(errors.Is(be.DeleteTeam(ctx, "devs"), proto.ErrTeamNotFound))
+}
+
+func TestTeamAccessLevel(t *testing.T) {
+	is := is.New(t)
+	be, _ := newTestBackend(t)
+	ctx := context.Background()
+
+	_, err := be.CreateRepository(ctx, "private", nil, proto.RepositoryOptions{Private: true})
+	is.NoErr(err)
+	alice, err := be.CreateUser(ctx, "alice", proto.UserOptions{})
+	is.NoErr(err)
+
+	is.Equal(be.AccessLevelForUser(ctx, "private", alice), access.NoAccess)
+
+	is.NoErr(be.CreateTeam(ctx, "readers"))
+	is.NoErr(be.CreateTeam(ctx, "writers"))
+	is.NoErr(be.AddTeamMember(ctx, "readers", "alice"))
+	is.NoErr(be.SetTeamAccess(ctx, "readers", "private", access.ReadOnlyAccess))
+	is.Equal(be.AccessLevelForUser(ctx, "private", alice), access.ReadOnlyAccess)
+
+	// The highest team grant wins.
+	is.NoErr(be.AddTeamMember(ctx, "writers", "alice"))
+	is.NoErr(be.SetTeamAccess(ctx, "writers", "private", access.ReadWriteAccess))
+	is.Equal(be.AccessLevelForUser(ctx, "private", alice), access.ReadWriteAccess)
+
+	// An individual grant above the team grants wins too.
+	is.NoErr(be.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		return be.store.AddCollabByUsernameAndRepo(ctx, tx, "alice", "private", access.AdminAccess)
+	}))
+	is.Equal(be.AccessLevelForUser(ctx, "private", alice), access.AdminAccess)
+	is.NoErr(be.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		return be.store.RemoveCollabByUsernameAndRepo(ctx, tx, "alice", "private")
+	}))
+
+	// But a lower individual grant does not cap the team grant.
+	is.NoErr(be.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		return be.store.AddCollabByUsernameAndRepo(ctx, tx, "alice", "private", access.ReadOnlyAccess)
+	}))
+	is.Equal(be.AccessLevelForUser(ctx, "private", alice), access.ReadWriteAccess)
+
+	repos, err := be.TeamRepositories(ctx, "writers")
+	is.NoErr(err)
+	is.Equal(repos, []TeamRepository{{Repo: "private", AccessLevel: access.ReadWriteAccess}})
+
+	// Granting again replaces the previous grant.
+	is.NoErr(be.SetTeamAccess(ctx, "writers", "private", access.ReadOnlyAccess))
+	is.Equal(be.AccessLevelForUser(ctx, "private", alice), access.ReadOnlyAccess)
+
+	is.NoErr(be.RemoveTeamAccess(ctx, "writers", "private"))
+	is.True(errors.Is(be.RemoveTeamAccess(ctx, "writers", "private"), proto.ErrTeamAccessNotFound))
+	is.True(errors.Is(be.SetTeamAccess(ctx, "writers", "missing", access.ReadOnlyAccess), proto.ErrRepoNotFound))
+}
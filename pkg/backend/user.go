@@ -24,6 +24,7 @@ func (d *Backend) AccessLevel(ctx context.Context, repo string, username string)
 }
 
 // AccessLevelByPublicKey returns the access level of a user's public key for a repository.
+// Deploy keys are resolved before users.
 //
 // It implements backend.Backend.
 func (d *Backend) AccessLevelByPublicKey(ctx context.Context, repo string, pk ssh.PublicKey) access.AccessLevel {
@@ -33,6 +34,12 @@ func (d *Backend) AccessLevelByPublicKey(ctx context.Context, repo string, pk ss
 		}
 	}
 
+	if pk != nil {
+		if k, _ := d.DeployKeyByPublicKey(ctx, pk); k != nil {
+			return d.deployKeyAccessLevel(ctx, repo, k)
+		}
+	}
+
 	user, _ := d.UserByPublicKey(ctx, pk)
 	if user != nil {
 		return d.AccessLevel(ctx, repo, user.Username())
@@ -42,8 +49,26 @@ func (d *Backend) AccessLevelByPublicKey(ctx context.Context, repo string, pk ss
 }
 
 // AccessLevelForUser returns the access level of a user for a repository.
+// Users who authenticated with an access token get at most what the token's
+// scopes and repositories allow. Anonymous sessions that authenticated with
+// a deploy key get the deploy key's access.
+func (d *Backend) AccessLevelForUser(ctx context.Context, repo string, u proto.User) access.AccessLevel {
+	if k := DeployKeyFromContext(ctx); k != nil && u == nil {
+		return d.deployKeyAccessLevel(ctx, repo, k)
+	}
+
+	if u, ok := u.(*user); ok && u.token != nil {
+		unscoped := &user{user: u.user, publicKeys: u.publicKeys}
+		return restrictAccessLevel(u.token, repo, d.accessLevelForUser(ctx, repo, unscoped))
+	}
+
+	return d.accessLevelForUser(ctx, repo, u)
+}
+
+// accessLevelForUser returns the access level of a user for a repository,
+// regardless of how they authenticated.
 // TODO: user repository ownership
-func (d *Backend) AccessLevelForUser(ctx context.Context, repo string, user proto.User) access.AccessLevel {
+func (d *Backend) accessLevelForUser(ctx context.Context, repo string, user proto.User) access.AccessLevel {
 	var username string
 	anon := d.AnonAccess(ctx)
 	if user != nil {
@@ -70,7 +95,15 @@ func (d *Backend) AccessLevelForUser(ctx context.Context, repo string, user prot
 		}
 
 		// If the user is a collaborator, they have return their access level.
+		// Grants through the user's teams count as collaboration too, and
+		// the highest of the individual and team grants wins.
 		collabAccess, isCollab, _ := d.IsCollaborator(ctx, repo, username)
+		if teamAccess, inTeam, _ := d.teamAccessLevel(ctx, repo, username); inTeam {
+			if !isCollab || teamAccess > collabAccess {
+				collabAccess = teamAccess
+			}
+			isCollab = true
+		}
 		if isCollab {
 			if anon > collabAccess {
 				return anon
@@ -167,10 +200,15 @@ func (d *Backend) UserByID(ctx context.Context, id int64) (proto.User, error) {
 	}, nil
 }
 
-// UserByPublicKey finds a user by public key.
+// UserByPublicKey finds a user by public key. Certificates signed by a
+// trusted user certificate authority are mapped to a user by principal.
 //
 // It implements backend.Backend.
 func (d *Backend) UserByPublicKey(ctx context.Context, pk ssh.PublicKey) (proto.User, error) {
+	if cert, ok := pk.(*ssh.Certificate); ok && d.IsTrustedCertificate(cert) {
+		return d.UserByCertificate(ctx, cert)
+	}
+
 	var m models.User
 	var pks []ssh.PublicKey
 	if err := d.db.TransactionContext(ctx, func(tx *db.Tx) error {
@@ -201,11 +239,13 @@ func (d *Backend) UserByPublicKey(ctx context.Context, pk ssh.PublicKey) (proto.
 // This also validates the token for expiration and returns proto.ErrTokenExpired.
 func (d *Backend) UserByAccessToken(ctx context.Context, token string) (proto.User, error) {
 	var m models.User
+	var t models.AccessToken
 	var pks []ssh.PublicKey
 	token = HashToken(token)
 
 	if err := d.db.TransactionContext(ctx, func(tx *db.Tx) error {
-		t, err := d.store.GetAccessTokenByToken(ctx, tx, token)
+		var err error
+		t, err = d.store.GetAccessTokenByToken(ctx, tx, token)
 		if err != nil {
 			return db.WrapError(err)
 		}
@@ -230,9 +270,11 @@ func (d *Backend) UserByAccessToken(ctx context.Context, token string) (proto.Us
 		return nil, err
 	}
 
+	at := accessTokenFromModel(t)
 	return &user{
 		user:       m,
 		publicKeys: pks,
+		token:      &at,
 	}, nil
 }
 
@@ -268,11 +310,18 @@ func (d *Backend) AddPublicKey(ctx context.Context, username string, pk ssh.Publ
 		return err
 	}
 
-	return db.WrapError(
-		d.db.TransactionContext(ctx, func(tx *db.Tx) error {
-			return d.store.AddPublicKeyByUsername(ctx, tx, username, pk)
-		}),
-	)
+	if err := d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		if _, err := d.store.FindDeployKeyByPublicKey(ctx, tx, pk); err == nil {
+			return proto.ErrPublicKeyInUse
+		}
+
+		return d.store.AddPublicKeyByUsername(ctx, tx, username, pk)
+	}); err != nil {
+		return db.WrapError(err)
+	}
+
+	d.audit(ctx, AuditUserAddPubkey, username, "fingerprint", ssh.FingerprintSHA256(pk))
+	return nil
 }
 
 // CreateUser creates a new user.
@@ -286,11 +335,18 @@ func (d *Backend) CreateUser(ctx context.Context, username string, opts proto.Us
 	}
 
 	if err := d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		for _, pk := range opts.PublicKeys {
+			if _, err := d.store.FindDeployKeyByPublicKey(ctx, tx, pk); err == nil {
+				return proto.ErrPublicKeyInUse
+			}
+		}
+
 		return d.store.CreateUser(ctx, tx, username, opts.Admin, opts.PublicKeys)
 	}); err != nil {
 		return nil, db.WrapError(err)
 	}
 
+	d.audit(ctx, AuditUserCreate, username, "admin", opts.Admin)
 	return d.User(ctx, username)
 }
 
@@ -303,24 +359,32 @@ func (d *Backend) DeleteUser(ctx context.Context, username string) error {
 		return err
 	}
 
-	return d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+	if err := d.db.TransactionContext(ctx, func(tx *db.Tx) error {
 		if err := d.store.DeleteUserByUsername(ctx, tx, username); err != nil {
 			return db.WrapError(err)
 		}
 
 		return d.DeleteUserRepositories(ctx, username)
-	})
+	}); err != nil {
+		return err
+	}
+
+	d.audit(ctx, AuditUserDelete, username)
+	return nil
 }
 
 // RemovePublicKey removes a public key from a user.
 //
 // It implements backend.Backend.
 func (d *Backend) RemovePublicKey(ctx context.Context, username string, pk ssh.PublicKey) error {
-	return db.WrapError(
-		d.db.TransactionContext(ctx, func(tx *db.Tx) error {
-			return d.store.RemovePublicKeyByUsername(ctx, tx, username, pk)
-		}),
-	)
+	if err := d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		return d.store.RemovePublicKeyByUsername(ctx, tx, username, pk)
+	}); err != nil {
+		return db.WrapError(err)
+	}
+
+	d.audit(ctx, AuditUserRemovePubkey, username, "fingerprint", ssh.FingerprintSHA256(pk))
+	return nil
 }
 
 // ListPublicKeys lists the public keys of a user.
@@ -351,11 +415,14 @@ func (d *Backend) SetUsername(ctx context.Context, username string, newUsername
 		return err
 	}
 
-	return db.WrapError(
-		d.db.TransactionContext(ctx, func(tx *db.Tx) error {
-			return d.store.SetUsernameByUsername(ctx, tx, username, newUsername)
-		}),
-	)
+	if err := d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		return d.store.SetUsernameByUsername(ctx, tx, username, newUsername)
+	}); err != nil {
+		return db.WrapError(err)
+	}
+
+	d.audit(ctx, AuditUserSetUsername, username, "username", newUsername)
+	return nil
 }
 
 // SetAdmin sets the admin flag of a user.
@@ -367,11 +434,14 @@ func (d *Backend) SetAdmin(ctx context.Context, username string, admin bool) err
 		return err
 	}
 
-	return db.WrapError(
-		d.db.TransactionContext(ctx, func(tx *db.Tx) error {
-			return d.store.SetAdminByUsername(ctx, tx, username, admin)
-		}),
-	)
+	if err := d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		return d.store.SetAdminByUsername(ctx, tx, username, admin)
+	}); err != nil {
+		return db.WrapError(err)
+	}
+
+	d.audit(ctx, AuditUserSetAdmin, username, "admin", admin)
+	return nil
 }
 
 // SetPassword sets the password of a user.
@@ -386,22 +456,33 @@ func (d *Backend) SetPassword(ctx context.Context, username string, rawPassword
 		return err
 	}
 
-	return db.WrapError(
-		d.db.TransactionContext(ctx, func(tx *db.Tx) error {
-			return d.store.SetUserPasswordByUsername(ctx, tx, username, password)
-		}),
-	)
+	if err := d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		return d.store.SetUserPasswordByUsername(ctx, tx, username, password)
+	}); err != nil {
+		return db.WrapError(err)
+	}
+
+	d.audit(ctx, AuditUserSetPassword, username)
+	return nil
 }
 
 type user struct {
 	user       models.User
 	publicKeys []ssh.PublicKey
+	// token is the access token the user authenticated with, if any.
+	token *proto.AccessToken
 }
 
 var _ proto.User = (*user)(nil)
 
-// IsAdmin implements proto.User
+// IsAdmin implements proto.User. Admins who authenticated with an access
+// token are only admins if the token has the admin scope and isn't
+// restricted to some repositories.
 func (u *user) IsAdmin() bool {
+	if u.token != nil && (!u.token.HasScope(proto.AccessTokenScopeAdmin) || len(u.token.Repos) > 0) {
+		return false
+	}
+
 	return u.user.Admin
 }
 
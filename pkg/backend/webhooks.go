@@ -2,7 +2,6 @@ package backend
 
 import (
 	"context"
-	"encoding/json"
 
 	"charm.land/log/v2"
 	"github.com/charmbracelet/soft-serve/pkg/db"
@@ -25,11 +24,13 @@ func (b *Backend) CreateWebhook(ctx context.Context, repo proto.Repository, url
 		return err //nolint:wrapcheck
 	}
 
-	return dbx.TransactionContext(ctx, func(tx *db.Tx) error {
+	var id int64
+	if err := dbx.TransactionContext(ctx, func(tx *db.Tx) error {
 		lastID, err := datastore.CreateWebhook(ctx, tx, repo.ID(), url, secret, int(contentType), active)
 		if err != nil {
 			return db.WrapError(err)
 		}
+		id = lastID
 
 		evs := make([]int, len(events))
 		for i, e := range events {
@@ -40,7 +41,12 @@ func (b *Backend) CreateWebhook(ctx context.Context, repo proto.Repository, url
 		}
 
 		return nil
-	})
+	}); err != nil {
+		return err
+	}
+
+	b.audit(ctx, AuditRepoCreateWebhook, repo.Name(), "id", id, "url", url)
+	return nil
 }
 
 // Webhook returns a webhook for a repository.
@@ -130,7 +136,7 @@ func (b *Backend) UpdateWebhook(ctx context.Context, repo proto.Repository, id i
 		return err
 	}
 
-	return dbx.TransactionContext(ctx, func(tx *db.Tx) error {
+	if err := dbx.TransactionContext(ctx, func(tx *db.Tx) error {
 		if err := datastore.UpdateWebhookByID(ctx, tx, repo.ID(), id, url, secret, int(contentType), active); err != nil {
 			return db.WrapError(err)
 		}
@@ -155,8 +161,10 @@ func (b *Backend) UpdateWebhook(ctx context.Context, repo proto.Repository, id i
 			}
 		}
 
-		if err := datastore.DeleteWebhookEventsByID(ctx, tx, toBeDeleted); err != nil {
-			return db.WrapError(err)
+		if len(toBeDeleted) > 0 {
+			if err := datastore.DeleteWebhookEventsByID(ctx, tx, toBeDeleted); err != nil {
+				return db.WrapError(err)
+			}
 		}
 
 		// Prune events that are already in the list.
@@ -174,12 +182,19 @@ func (b *Backend) UpdateWebhook(ctx context.Context, repo proto.Repository, id i
 			}
 		}
 
-		if err := datastore.CreateWebhookEvents(ctx, tx, id, newEvents); err != nil {
-			return db.WrapError(err)
+		if len(newEvents) > 0 {
+			if err := datastore.CreateWebhookEvents(ctx, tx, id, newEvents); err != nil {
+				return db.WrapError(err)
+			}
 		}
 
 		return nil
-	})
+	}); err != nil {
+		return err
+	}
+
+	b.audit(ctx, AuditRepoUpdateWebhook, repo.Name(), "id", id, "url", url, "active", active)
+	return nil
 }
 
 // DeleteWebhook deletes a webhook for a repository.
@@ -187,7 +202,7 @@ func (b *Backend) DeleteWebhook(ctx context.Context, repo proto.Repository, id i
 	dbx := db.FromContext(ctx)
 	datastore := store.FromContext(ctx)
 
-	return dbx.TransactionContext(ctx, func(tx *db.Tx) error {
+	if err := dbx.TransactionContext(ctx, func(tx *db.Tx) error {
 		_, err := datastore.GetWebhookByID(ctx, tx, repo.ID(), id)
 		if err != nil {
 			return db.WrapError(err)
@@ -197,7 +212,12 @@ func (b *Backend) DeleteWebhook(ctx context.Context, repo proto.Repository, id i
 		}
 
 		return nil
-	})
+	}); err != nil {
+		return err
+	}
+
+	b.audit(ctx, AuditRepoDeleteWebhook, repo.Name(), "id", id)
+	return nil
 }
 
 // ListWebhookDeliveries lists webhook deliveries for a webhook belonging to
@@ -228,16 +248,14 @@ func (b *Backend) ListWebhookDeliveries(ctx context.Context, repo proto.Reposito
 
 	ds := make([]webhook.Delivery, len(deliveries))
 	for i, d := range deliveries {
-		ds[i] = webhook.Delivery{
-			WebhookDelivery: d,
-			Event:           webhook.Event(d.Event),
-		}
+		ds[i] = webhook.NewDelivery(d)
 	}
 
 	return ds, nil
 }
 
-// RedeliverWebhookDelivery redelivers a webhook delivery.
+// RedeliverWebhookDelivery queues a new delivery of a webhook delivery's
+// request body.
 func (b *Backend) RedeliverWebhookDelivery(ctx context.Context, repo proto.Repository, id int64, delID uuid.UUID) error {
 	dbx := db.FromContext(ctx)
 	datastore := store.FromContext(ctx)
@@ -262,15 +280,9 @@ func (b *Backend) RedeliverWebhookDelivery(ctx context.Context, repo proto.Repos
 		return db.WrapError(err)
 	}
 
-	log.Infof("redelivering webhook delivery %s for webhook %d\n\n%s\n\n", delID, id, delivery.RequestBody)
-
-	var payload json.RawMessage
-	if err := json.Unmarshal([]byte(delivery.RequestBody), &payload); err != nil {
-		log.Errorf("error unmarshaling webhook payload: %v", err)
-		return err
-	}
+	log.Infof("redelivering webhook delivery %s for webhook %d", delID, id)
 
-	return webhook.SendWebhook(ctx, wh, webhook.Event(delivery.Event), payload)
+	return webhook.Redeliver(ctx, wh, delivery)
 }
 
 // WebhookDelivery returns a webhook delivery for a webhook belonging to the
@@ -295,10 +307,7 @@ func (b *Backend) WebhookDelivery(ctx context.Context, repo proto.Repository, we
 			return db.WrapError(err)
 		}
 
-		delivery = webhook.Delivery{
-			WebhookDelivery: d,
-			Event:           webhook.Event(d.Event),
-		}
+		delivery = webhook.NewDelivery(d)
 
 		return nil
 	}); err != nil {
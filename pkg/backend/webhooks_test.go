@@ -0,0 +1,115 @@
+package backend
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/access"
+	"github.com/charmbracelet/soft-serve/pkg/config"
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/hooks"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/sshutils"
+	"github.com/charmbracelet/soft-serve/pkg/store"
+	"github.com/charmbracelet/soft-serve/pkg/webhook"
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func TestPendingWebhookDeliveries(t *testing.T) {
+	is := is.New(t)
+	be, cfg := newTestBackend(t)
+	ctx := config.WithContext(context.Background(), cfg)
+	ctx = db.WithContext(ctx, be.db)
+	ctx = store.WithContext(ctx, be.store)
+
+	r, err := be.CreateRepository(ctx, "repo", nil, proto.RepositoryOptions{})
+	is.NoErr(err)
+	for _, url := range []string{"https://1.1.1.1/a", "https://1.1.1.1/b", "https://1.1.1.1/c"} {
+		is.NoErr(be.CreateWebhook(ctx, r, url, webhook.ContentTypeJSON, "", []webhook.Event{webhook.EventPush}, true))
+	}
+	hooks, err := be.ListWebhooks(ctx, r)
+	is.NoErr(err)
+	is.Equal(len(hooks), 3)
+	a, b, c := hooks[0], hooks[1], hooks[2]
+
+	now := time.Now().UTC()
+	deliver := func(h webhook.Hook, next time.Time) uuid.UUID {
+		did := uuid.New()
+		is.NoErr(be.store.CreateWebhookDelivery(ctx, be.db, did, h.ID, int(webhook.EventPush), h.URL, "POST", "", "{}"))
+		if !next.IsZero() {
+			is.NoErr(be.store.UpdateWebhookDeliveryByID(ctx, be.db, did, int(webhook.DeliveryStatusPending), 1, next, nil, 500, "", ""))
+		}
+		return did
+	}
+	due := deliver(a, time.Time{})
+	retry := deliver(a, now.Add(-time.Minute))
+	deliver(a, now.Add(time.Minute))
+	inactive := deliver(b, time.Time{})
+	moved := deliver(c, time.Time{})
+
+	// Deactivating or re-pointing a webhook stops its pending deliveries.
+	is.NoErr(be.UpdateWebhook(ctx, r, b.ID, b.URL, b.ContentType, "", b.Events, false))
+	is.NoErr(be.UpdateWebhook(ctx, r, c.ID, "https://1.1.1.1/moved", c.ContentType, "", c.Events, true))
+
+	pending, err := be.store.GetPendingWebhookDeliveries(ctx, be.db, now)
+	is.NoErr(err)
+	is.Equal(len(pending), 2)
+	ids := map[uuid.UUID]bool{pending[0].ID: true, pending[1].ID: true}
+	is.True(ids[due] && ids[retry])
+
+	is.NoErr(be.store.FailStaleWebhookDeliveries(ctx, be.db))
+	for _, tc := range []struct {
+		hook webhook.Hook
+		id   uuid.UUID
+		want webhook.DeliveryStatus
+	}{
+		{a, due, webhook.DeliveryStatusPending},
+		{b, inactive, webhook.DeliveryStatusFailed},
+		{c, moved, webhook.DeliveryStatusFailed},
+	} {
+		d, err := be.WebhookDelivery(ctx, r, tc.hook.ID, tc.id)
+		is.NoErr(err)
+		is.Equal(d.Status, tc.want)
+	}
+}
+
+func TestUpdateHookQueuesDeployKeyPush(t *testing.T) {
+	is := is.New(t)
+	be, cfg := newTestBackend(t)
+	ctx := config.WithContext(context.Background(), cfg)
+	ctx = db.WithContext(ctx, be.db)
+	ctx = store.WithContext(ctx, be.store)
+
+	r, err := be.CreateRepository(ctx, "repo", nil, proto.RepositoryOptions{})
+	is.NoErr(err)
+	is.NoErr(be.CreateWebhook(ctx, r, "https://1.1.1.1/hook", webhook.ContentTypeJSON, "", webhook.Events(), true))
+	whs, err := be.ListWebhooks(ctx, r)
+	is.NoErr(err)
+	is.Equal(len(whs), 1)
+
+	work := t.TempDir()
+	gitOutput(t, work, "init", "-q")
+	gitOutput(t, work, "commit", "-q", "--allow-empty", "-m", "first")
+	head := gitOutput(t, work, "rev-parse", "HEAD")
+	gitOutput(t, be.repoPath("repo"), "fetch", "-q", work, "HEAD:refs/heads/main")
+
+	// The pusher is a deploy key, which has no user account.
+	pk := newTestPublicKey(t)
+	_, err = be.AddDeployKey(ctx, "repo", "ci", pk, access.ReadWriteAccess)
+	is.NoErr(err)
+	t.Setenv("SOFT_SERVE_PUBLIC_KEY", sshutils.MarshalAuthorizedKey(pk))
+
+	be.Update(ctx, nil, nil, "repo", hooks.HookArg{OldSha: git.ZeroID, NewSha: head, RefName: git.RefsHeads + "main"})
+
+	deliveries, err := be.ListWebhookDeliveries(ctx, r, whs[0].ID)
+	is.NoErr(err)
+	events := map[webhook.Event]bool{}
+	for _, d := range deliveries {
+		events[d.Event] = true
+	}
+	is.True(events[webhook.EventPush])
+	is.True(events[webhook.EventBranchTagCreate])
+}
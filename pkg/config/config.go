@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -11,6 +12,7 @@ import (
 	"github.com/caarlos0/env/v11"
 	"github.com/charmbracelet/soft-serve/pkg/access"
 	"github.com/charmbracelet/soft-serve/pkg/sshutils"
+	"github.com/gobwas/glob"
 	"golang.org/x/crypto/ssh"
 	"gopkg.in/yaml.v3"
 )
@@ -39,6 +41,10 @@ type SSHConfig struct {
 
 	// IdleTimeout is the number of seconds a connection can be idle before it is closed.
 	IdleTimeout int `env:"IDLE_TIMEOUT" yaml:"idle_timeout"`
+
+	// TrustedUserCAKeys is a list of certificate authority public keys, or
+	// paths to files containing them, trusted to sign user certificates.
+	TrustedUserCAKeys []string `env:"TRUSTED_USER_CA_KEYS" envSeparator:"\n" yaml:"trusted_user_ca_keys"`
 }
 
 // GitConfig is the Git daemon configuration for the server.
@@ -133,11 +139,126 @@ type LFSConfig struct {
 	// SSHEnabled is whether or not Git LFS over SSH is enabled.
 	// This is only used if LFS is enabled.
 	SSHEnabled bool `env:"SSH_ENABLED" yaml:"ssh_enabled"`
+
+	// GCGracePeriod is the number of seconds an unreferenced LFS object is
+	// kept before garbage collection deletes it. It covers objects uploaded
+	// ahead of the push that references them.
+	GCGracePeriod int `env:"GC_GRACE_PERIOD" yaml:"gc_grace_period"`
+
+	// Storage is where LFS objects are stored.
+	// Valid values are "local", under the data path, and "s3".
+	Storage string `env:"STORAGE" yaml:"storage"`
+
+	// S3 is the S3-compatible object storage used when Storage is "s3".
+	S3 S3Config `envPrefix:"S3_" yaml:"s3"`
+}
+
+// S3Config is the configuration for an S3-compatible object storage.
+type S3Config struct {
+	// Endpoint is the URL of the S3 server. It defaults to the Amazon S3
+	// endpoint of Region.
+	Endpoint string `env:"ENDPOINT" yaml:"endpoint"`
+
+	// Region is the region of the bucket.
+	Region string `env:"REGION" yaml:"region"`
+
+	// Bucket is the bucket objects are stored in.
+	Bucket string `env:"BUCKET" yaml:"bucket"`
+
+	// Prefix is the key prefix objects are stored under.
+	Prefix string `env:"PREFIX" yaml:"prefix"`
+
+	// AccessKeyID is the access key ID used to sign requests.
+	AccessKeyID string `env:"ACCESS_KEY_ID" yaml:"access_key_id"`
+
+	// SecretAccessKey is the secret access key used to sign requests.
+	SecretAccessKey string `env:"SECRET_ACCESS_KEY" yaml:"secret_access_key"`
+
+	// PathStyle is whether the bucket is addressed in the URL path rather
+	// than in the host name, as MinIO and most self-hosted servers need.
+	PathStyle bool `env:"PATH_STYLE" yaml:"path_style"`
+
+	// Presign is whether LFS clients transfer objects straight to and from
+	// the bucket using pre-signed URLs, rather than through the server.
+	Presign bool `env:"PRESIGN" yaml:"presign"`
+
+	// PresignExpiry is the number of seconds pre-signed URLs are valid for.
+	PresignExpiry int `env:"PRESIGN_EXPIRY" yaml:"presign_expiry"`
 }
 
 // JobsConfig is the configuration for cron jobs.
 type JobsConfig struct {
 	MirrorPull string `env:"MIRROR_PULL" yaml:"mirror_pull"`
+
+	// MirrorPush is the schedule on which repositories are pushed to their
+	// push mirrors, on top of the push after every update.
+	MirrorPush string `env:"MIRROR_PUSH" yaml:"mirror_push"`
+
+	// MirrorPushQueue is the schedule on which repositories updated by a
+	// push are pushed to their push mirrors.
+	MirrorPushQueue string `env:"MIRROR_PUSH_QUEUE" yaml:"mirror_push_queue"`
+
+	// LFSGC is the schedule on which unreferenced LFS objects are deleted.
+	LFSGC string `env:"LFS_GC" yaml:"lfs_gc"`
+
+	// RepoMaintenance is the schedule on which repositories are repacked and
+	// their commit-graphs and bitmaps written.
+	RepoMaintenance string `env:"REPO_MAINTENANCE" yaml:"repo_maintenance"`
+
+	// WebhookDeliveries is the schedule on which queued webhook deliveries
+	// are sent and failed ones retried.
+	WebhookDeliveries string `env:"WEBHOOK_DELIVERIES" yaml:"webhook_deliveries"`
+}
+
+// PushPolicyConfig is the configuration for the push policy enforced on
+// every repository. Repositories can add their own rules on top of it.
+type PushPolicyConfig struct {
+	// MaxFileSize is the maximum size in bytes of a file added by a push.
+	// A value of 0 means no limit.
+	MaxFileSize int64 `env:"MAX_FILE_SIZE" yaml:"max_file_size"`
+
+	// ForbiddenPaths is a list of glob patterns of paths a push may not add
+	// or modify, e.g. "**.exe" or "secrets/*".
+	ForbiddenPaths []string `env:"FORBIDDEN_PATHS" envSeparator:"\n" yaml:"forbidden_paths"`
+
+	// CommitMessagePattern is a regular expression every pushed commit
+	// message must match.
+	CommitMessagePattern string `env:"COMMIT_MESSAGE_PATTERN" yaml:"commit_message_pattern"`
+
+	// AuthorEmailDomains restricts the email domains of pushed commit
+	// authors. An empty list allows any domain.
+	AuthorEmailDomains []string `env:"AUTHOR_EMAIL_DOMAINS" envSeparator:"\n" yaml:"author_email_domains"`
+
+	// NoMergeBranches is a list of branch names or glob patterns that reject
+	// pushed merge commits.
+	NoMergeBranches []string `env:"NO_MERGE_BRANCHES" envSeparator:"\n" yaml:"no_merge_branches"`
+}
+
+// QuotaConfig is the configuration for the default storage quotas. Admins can
+// override them for a single repository or user. Sizes are in bytes and a
+// value of 0 means no limit.
+type QuotaConfig struct {
+	// RepoSize is the maximum size of the Git objects of a repository.
+	RepoSize int64 `env:"REPO_SIZE" yaml:"repo_size"`
+
+	// RepoLFSSize is the maximum size of the LFS objects of a repository.
+	RepoLFSSize int64 `env:"REPO_LFS_SIZE" yaml:"repo_lfs_size"`
+
+	// UserSize is the maximum size of the Git objects of all repositories a
+	// user owns.
+	UserSize int64 `env:"USER_SIZE" yaml:"user_size"`
+
+	// UserLFSSize is the maximum size of the LFS objects of all repositories
+	// a user owns.
+	UserLFSSize int64 `env:"USER_LFS_SIZE" yaml:"user_lfs_size"`
+}
+
+// AuditConfig is the configuration for the audit log.
+type AuditConfig struct {
+	// LogPath is the path to a file audit events are appended to as JSON
+	// lines, in addition to the database. If not set, events are only
+	// stored in the database.
+	LogPath string `env:"LOG_PATH" yaml:"log_path"`
 }
 
 // Config is the configuration for Soft Serve.
@@ -169,6 +290,15 @@ type Config struct {
 	// Jobs is the configuration for cron jobs
 	Jobs JobsConfig `envPrefix:"JOBS_" yaml:"jobs"`
 
+	// PushPolicy is the push policy enforced on every repository.
+	PushPolicy PushPolicyConfig `envPrefix:"PUSH_POLICY_" yaml:"push_policy"`
+
+	// Quota is the default storage quotas.
+	Quota QuotaConfig `envPrefix:"QUOTA_" yaml:"quota"`
+
+	// Audit is the configuration for the audit log.
+	Audit AuditConfig `envPrefix:"AUDIT_" yaml:"audit"`
+
 	// InitialAdminKeys is a list of public keys that will be added to the list of admins.
 	InitialAdminKeys []string `env:"INITIAL_ADMIN_KEYS" envSeparator:"\n" yaml:"initial_admin_keys"`
 
@@ -222,6 +352,7 @@ func (c *Config) Environ() []string {
 		fmt.Sprintf("SOFT_SERVE_SSH_CLIENT_KEY_PATH=%s", c.SSH.ClientKeyPath),
 		fmt.Sprintf("SOFT_SERVE_SSH_MAX_TIMEOUT=%d", c.SSH.MaxTimeout),
 		fmt.Sprintf("SOFT_SERVE_SSH_IDLE_TIMEOUT=%d", c.SSH.IdleTimeout),
+		fmt.Sprintf("SOFT_SERVE_SSH_TRUSTED_USER_CA_KEYS=%s", strings.Join(c.SSH.TrustedUserCAKeys, "\n")),
 		fmt.Sprintf("SOFT_SERVE_GIT_ENABLED=%t", c.Git.Enabled),
 		fmt.Sprintf("SOFT_SERVE_GIT_LISTEN_ADDR=%s", c.Git.ListenAddr),
 		fmt.Sprintf("SOFT_SERVE_GIT_PUBLIC_URL=%s", c.Git.PublicURL),
@@ -244,7 +375,33 @@ func (c *Config) Environ() []string {
 		fmt.Sprintf("SOFT_SERVE_DB_DATA_SOURCE=%s", c.DB.DataSource),
 		fmt.Sprintf("SOFT_SERVE_LFS_ENABLED=%t", c.LFS.Enabled),
 		fmt.Sprintf("SOFT_SERVE_LFS_SSH_ENABLED=%t", c.LFS.SSHEnabled),
+		fmt.Sprintf("SOFT_SERVE_LFS_GC_GRACE_PERIOD=%d", c.LFS.GCGracePeriod),
+		fmt.Sprintf("SOFT_SERVE_LFS_STORAGE=%s", c.LFS.Storage),
+		fmt.Sprintf("SOFT_SERVE_LFS_S3_ENDPOINT=%s", c.LFS.S3.Endpoint),
+		fmt.Sprintf("SOFT_SERVE_LFS_S3_REGION=%s", c.LFS.S3.Region),
+		fmt.Sprintf("SOFT_SERVE_LFS_S3_BUCKET=%s", c.LFS.S3.Bucket),
+		fmt.Sprintf("SOFT_SERVE_LFS_S3_PREFIX=%s", c.LFS.S3.Prefix),
+		fmt.Sprintf("SOFT_SERVE_LFS_S3_ACCESS_KEY_ID=%s", c.LFS.S3.AccessKeyID),
+		fmt.Sprintf("SOFT_SERVE_LFS_S3_SECRET_ACCESS_KEY=%s", c.LFS.S3.SecretAccessKey),
+		fmt.Sprintf("SOFT_SERVE_LFS_S3_PATH_STYLE=%t", c.LFS.S3.PathStyle),
+		fmt.Sprintf("SOFT_SERVE_LFS_S3_PRESIGN=%t", c.LFS.S3.Presign),
+		fmt.Sprintf("SOFT_SERVE_LFS_S3_PRESIGN_EXPIRY=%d", c.LFS.S3.PresignExpiry),
 		fmt.Sprintf("SOFT_SERVE_JOBS_MIRROR_PULL=%s", c.Jobs.MirrorPull),
+		fmt.Sprintf("SOFT_SERVE_JOBS_MIRROR_PUSH=%s", c.Jobs.MirrorPush),
+		fmt.Sprintf("SOFT_SERVE_JOBS_MIRROR_PUSH_QUEUE=%s", c.Jobs.MirrorPushQueue),
+		fmt.Sprintf("SOFT_SERVE_JOBS_LFS_GC=%s", c.Jobs.LFSGC),
+		fmt.Sprintf("SOFT_SERVE_JOBS_REPO_MAINTENANCE=%s", c.Jobs.RepoMaintenance),
+		fmt.Sprintf("SOFT_SERVE_JOBS_WEBHOOK_DELIVERIES=%s", c.Jobs.WebhookDeliveries),
+		fmt.Sprintf("SOFT_SERVE_PUSH_POLICY_MAX_FILE_SIZE=%d", c.PushPolicy.MaxFileSize),
+		fmt.Sprintf("SOFT_SERVE_PUSH_POLICY_FORBIDDEN_PATHS=%s", strings.Join(c.PushPolicy.ForbiddenPaths, "\n")),
+		fmt.Sprintf("SOFT_SERVE_PUSH_POLICY_COMMIT_MESSAGE_PATTERN=%s", c.PushPolicy.CommitMessagePattern),
+		fmt.Sprintf("SOFT_SERVE_PUSH_POLICY_AUTHOR_EMAIL_DOMAINS=%s", strings.Join(c.PushPolicy.AuthorEmailDomains, "\n")),
+		fmt.Sprintf("SOFT_SERVE_PUSH_POLICY_NO_MERGE_BRANCHES=%s", strings.Join(c.PushPolicy.NoMergeBranches, "\n")),
+		fmt.Sprintf("SOFT_SERVE_QUOTA_REPO_SIZE=%d", c.Quota.RepoSize),
+		fmt.Sprintf("SOFT_SERVE_QUOTA_REPO_LFS_SIZE=%d", c.Quota.RepoLFSSize),
+		fmt.Sprintf("SOFT_SERVE_QUOTA_USER_SIZE=%d", c.Quota.UserSize),
+		fmt.Sprintf("SOFT_SERVE_QUOTA_USER_LFS_SIZE=%d", c.Quota.UserLFSSize),
+		fmt.Sprintf("SOFT_SERVE_AUDIT_LOG_PATH=%s", c.Audit.LogPath),
 	}...)
 
 	// AnonAccess and AllowKeyless are tri-state overrides: only emit them
@@ -427,11 +584,23 @@ func DefaultConfig() *Config {
 				"?_pragma=busy_timeout(5000)&_pragma=foreign_keys(1)",
 		},
 		LFS: LFSConfig{
-			Enabled:    true,
-			SSHEnabled: false,
+			Enabled:       true,
+			SSHEnabled:    false,
+			GCGracePeriod: 7 * 24 * 60 * 60,
+			Storage:       "local",
+			S3: S3Config{
+				Region:        "us-east-1",
+				Presign:       true,
+				PresignExpiry: 60 * 60,
+			},
 		},
 		Jobs: JobsConfig{
-			MirrorPull: "@every 10m",
+			MirrorPull:        "@every 10m",
+			MirrorPush:        "@every 1h",
+			MirrorPushQueue:   "@every 10s",
+			LFSGC:             "@every 24h",
+			RepoMaintenance:   "@every 24h",
+			WebhookDeliveries: "@every 10s",
 		},
 	}
 }
@@ -471,6 +640,10 @@ func (c *Config) Validate() error {
 		c.DB.DataSource = filepath.Join(c.DataPath, c.DB.DataSource)
 	}
 
+	if c.Audit.LogPath != "" && !filepath.IsAbs(c.Audit.LogPath) {
+		c.Audit.LogPath = filepath.Join(c.DataPath, c.Audit.LogPath)
+	}
+
 	// Validate keys
 	pks := make([]string, 0)
 	for _, key := range parseAuthKeys(c.InitialAdminKeys) {
@@ -480,6 +653,52 @@ func (c *Config) Validate() error {
 
 	c.InitialAdminKeys = pks
 
+	cas := make([]string, 0)
+	for _, key := range c.SSH.TrustedUserCAKeys {
+		keys, err := parseCAKeys(key)
+		if err != nil {
+			return fmt.Errorf("ssh.trusted_user_ca_keys: %w", err)
+		}
+
+		for _, k := range keys {
+			cas = append(cas, sshutils.MarshalAuthorizedKey(k))
+		}
+	}
+
+	c.SSH.TrustedUserCAKeys = cas
+
+	if c.PushPolicy.MaxFileSize < 0 {
+		return fmt.Errorf("push_policy.max_file_size must not be negative")
+	}
+
+	if _, err := regexp.Compile(c.PushPolicy.CommitMessagePattern); err != nil {
+		return fmt.Errorf("push_policy.commit_message_pattern: %w", err)
+	}
+
+	for _, p := range append(c.PushPolicy.ForbiddenPaths, c.PushPolicy.NoMergeBranches...) {
+		if _, err := glob.Compile(p, '/'); err != nil {
+			return fmt.Errorf("push policy pattern %q: %w", p, err)
+		}
+	}
+
+	if c.Quota.RepoSize < 0 || c.Quota.RepoLFSSize < 0 || c.Quota.UserSize < 0 || c.Quota.UserLFSSize < 0 {
+		return fmt.Errorf("quota sizes must not be negative")
+	}
+
+	switch c.LFS.Storage {
+	case "", "local":
+	case "s3":
+		if c.LFS.S3.Bucket == "" {
+			return fmt.Errorf("lfs.s3.bucket is required with s3 storage")
+		}
+		// Pre-signed URLs can't be valid for longer than a week.
+		if c.LFS.S3.PresignExpiry <= 0 || c.LFS.S3.PresignExpiry > 7*24*60*60 {
+			return fmt.Errorf("lfs.s3.presign_expiry must be between 1 second and 7 days")
+		}
+	default:
+		return fmt.Errorf("invalid lfs storage %q, must be local or s3", c.LFS.Storage)
+	}
+
 	c.HTTP.CORS.AllowedOrigins = append([]string{c.HTTP.PublicURL}, c.HTTP.CORS.AllowedOrigins...)
 
 	return nil
@@ -510,6 +729,38 @@ func (c *Config) AdminKeys() []ssh.PublicKey {
 	return parseAuthKeys(c.InitialAdminKeys)
 }
 
+// parseCAKeys parses certificate authority keys from either a file path, with
+// one key per line, or a string authorized key.
+func parseCAKeys(key string) ([]ssh.PublicKey, error) {
+	lines := []string{key}
+	if bts, err := os.ReadFile(key); err == nil {
+		lines = strings.Split(string(bts), "\n")
+	}
+
+	pks := make([]ssh.PublicKey, 0, len(lines))
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		pk, _, err := sshutils.ParseAuthorizedKey(line)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key %q: %w", line, err)
+		}
+
+		pks = append(pks, pk)
+	}
+
+	return pks, nil
+}
+
+// TrustedUserCAKeys returns the certificate authority keys trusted to sign
+// user certificates.
+func (c *Config) TrustedUserCAKeys() []ssh.PublicKey {
+	return parseAuthKeys(c.SSH.TrustedUserCAKeys)
+}
+
 func init() {
 	if ex, err := os.Executable(); err == nil {
 		binPath = filepath.ToSlash(ex)
@@ -2,9 +2,11 @@ package config
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/charmbracelet/soft-serve/pkg/access"
+	"github.com/charmbracelet/soft-serve/pkg/storage"
 	"github.com/matryer/is"
 )
 
@@ -58,6 +60,29 @@ func TestValidateInitAdminKeys(t *testing.T) {
 	})
 }
 
+func TestValidateTrustedUserCAKeys(t *testing.T) {
+	is := is.New(t)
+	ca := "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIFxIobhwtfdwN7m1TFt9wx3PsfvcAkISGPxmbmbauST8"
+	path := filepath.Join(t.TempDir(), "ca.pub")
+	is.NoErr(os.WriteFile(path, []byte("# CAs\n"+ca+" ca@example\n\n"), 0o600))
+
+	cfg := &Config{
+		DataPath: t.TempDir(),
+		SSH: SSHConfig{
+			TrustedUserCAKeys: []string{path, "testdata/k1.pub"},
+		},
+	}
+	is.NoErr(cfg.Validate())
+	is.Equal(cfg.SSH.TrustedUserCAKeys, []string{
+		ca,
+		"ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAINMwLvyV3ouVrTysUYGoJdl5Vgn5BACKov+n9PlzfPwH",
+	})
+	is.Equal(len(cfg.TrustedUserCAKeys()), 2)
+
+	cfg.SSH.TrustedUserCAKeys = []string{"abc"}
+	is.True(cfg.Validate() != nil)
+}
+
 func TestCustomConfigLocation(t *testing.T) {
 	is := is.New(t)
 	td := t.TempDir()
@@ -215,3 +240,79 @@ func TestParseAllowKeylessEnvFalse(t *testing.T) {
 	is.True(cfg.AllowKeyless != nil)
 	is.Equal(*cfg.AllowKeyless, false)
 }
+
+func TestParseLFSS3Env(t *testing.T) {
+	is := is.New(t)
+	for k, v := range map[string]string{
+		"SOFT_SERVE_LFS_STORAGE":          "s3",
+		"SOFT_SERVE_LFS_S3_ENDPOINT":      "http://localhost:9000",
+		"SOFT_SERVE_LFS_S3_BUCKET":        "lfs",
+		"SOFT_SERVE_LFS_S3_PATH_STYLE":    "true",
+		"SOFT_SERVE_LFS_S3_PRESIGN":       "false",
+		"SOFT_SERVE_LFS_S3_ACCESS_KEY_ID": "minio",
+	} {
+		is.NoErr(os.Setenv(k, v))
+		t.Cleanup(func() { is.NoErr(os.Unsetenv(k)) })
+	}
+	cfg := DefaultConfig()
+	is.NoErr(cfg.ParseEnv())
+	is.NoErr(cfg.Validate())
+	is.Equal(cfg.LFS.Storage, "s3")
+	is.Equal(cfg.LFS.S3.Endpoint, "http://localhost:9000")
+	is.Equal(cfg.LFS.S3.Region, "us-east-1")
+	is.Equal(cfg.LFS.S3.Bucket, "lfs")
+	is.Equal(cfg.LFS.S3.AccessKeyID, "minio")
+	is.True(cfg.LFS.S3.PathStyle)
+	is.True(!cfg.LFS.S3.Presign)
+}
+
+func TestValidateLFSStorage(t *testing.T) {
+	is := is.New(t)
+	cfg := DefaultConfig()
+	cfg.LFS.Storage = "nfs"
+	is.True(cfg.Validate() != nil)
+
+	// A bucket is required.
+	cfg = DefaultConfig()
+	cfg.LFS.Storage = "s3"
+	is.True(cfg.Validate() != nil)
+
+	// Pre-signed URLs can't outlive a week.
+	cfg.LFS.S3.Bucket = "lfs"
+	cfg.LFS.S3.PresignExpiry = 8 * 24 * 60 * 60
+	is.True(cfg.Validate() != nil)
+
+	cfg.LFS.S3.PresignExpiry = 60
+	is.NoErr(cfg.Validate())
+}
+
+func TestValidateQuota(t *testing.T) {
+	is := is.New(t)
+	cfg := DefaultConfig()
+	cfg.Quota.UserLFSSize = -1
+	is.True(cfg.Validate() != nil)
+
+	cfg.Quota.UserLFSSize = 1 << 30
+	is.NoErr(cfg.Validate())
+}
+
+func TestLFSStorage(t *testing.T) {
+	is := is.New(t)
+	cfg := DefaultConfig()
+	cfg.DataPath = t.TempDir()
+	strg, err := LFSStorage(cfg, 1)
+	is.NoErr(err)
+	_, ok := strg.(*storage.LocalStorage)
+	is.True(ok)
+
+	cfg.LFS.Storage = "s3"
+	cfg.LFS.S3.Bucket = "lfs"
+	strg, err = LFSStorage(cfg, 1)
+	is.NoErr(err)
+	_, ok = strg.(*storage.S3Storage)
+	is.True(ok)
+
+	cfg.LFS.S3.Endpoint = "localhost:9000"
+	_, err = LFSStorage(cfg, 1)
+	is.True(err != nil) // the endpoint must be a URL
+}
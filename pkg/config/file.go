@@ -48,6 +48,12 @@ ssh:
   # A value of 0 means no timeout.
   idle_timeout: {{ .SSH.IdleTimeout }}
 
+  # Certificate authorities trusted to sign user certificates, as public keys
+  # or paths to files with one key per line. Certificates signed by them are
+  # accepted for the user named by their first principal that is a user.
+  #trusted_user_ca_keys:
+  #  - "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5..."
+
 # The Git daemon configuration.
 git:
   # Enable the Git daemon.
@@ -139,10 +145,84 @@ lfs:
   enabled: {{ .LFS.Enabled }}
   # Enable Git SSH transfer.
   ssh_enabled: {{ .LFS.SSHEnabled }}
+  # The number of seconds an unreferenced LFS object is kept before it's
+  # garbage collected. This covers objects uploaded ahead of their push.
+  gc_grace_period: {{ .LFS.GCGracePeriod }}
+  # Where LFS objects are stored: "local", under the data path, or "s3".
+  storage: "{{ .LFS.Storage }}"
+  # The S3-compatible object storage used with the "s3" storage.
+  s3:
+    # The URL of the S3 server. Leave empty for Amazon S3.
+    endpoint: "{{ .LFS.S3.Endpoint }}"
+    region: "{{ .LFS.S3.Region }}"
+    bucket: "{{ .LFS.S3.Bucket }}"
+    # The key prefix objects are stored under.
+    prefix: "{{ .LFS.S3.Prefix }}"
+    access_key_id: "{{ .LFS.S3.AccessKeyID }}"
+    secret_access_key: "{{ .LFS.S3.SecretAccessKey }}"
+    # Address the bucket in the URL path, as MinIO needs.
+    path_style: {{ .LFS.S3.PathStyle }}
+    # Let LFS clients transfer objects straight to and from the bucket using
+    # pre-signed URLs.
+    presign: {{ .LFS.S3.Presign }}
+    # The number of seconds pre-signed URLs are valid for.
+    presign_expiry: {{ .LFS.S3.PresignExpiry }}
 
 # Cron job configuration
 jobs:
+  # How often mirrors are synced. Mirrors with their own interval are synced
+  # once it elapsed instead, which is checked every minute.
   mirror_pull: "{{ .Jobs.MirrorPull }}"
+  # How often repositories are pushed to their push mirrors, on top of the
+  # push after every update.
+  mirror_push: "{{ .Jobs.MirrorPush }}"
+  # How often repositories updated by a push are pushed to their push mirrors.
+  mirror_push_queue: "{{ .Jobs.MirrorPushQueue }}"
+  # How often LFS objects no longer referenced by any ref are deleted.
+  lfs_gc: "{{ .Jobs.LFSGC }}"
+  # How often repositories are repacked and their commit-graphs and bitmaps
+  # written.
+  repo_maintenance: "{{ .Jobs.RepoMaintenance }}"
+  # How often queued webhook deliveries are sent and failed ones retried.
+  webhook_deliveries: "{{ .Jobs.WebhookDeliveries }}"
+
+# Push policy enforced on every repository. Repositories can add their own
+# rules using the "repo policy" command.
+push_policy:
+  # Maximum size in bytes of a file added by a push. Use Git LFS for larger
+  # files. A value of 0 means no limit.
+  max_file_size: {{ .PushPolicy.MaxFileSize }}
+  # Glob patterns of paths a push may not add or modify.
+  #forbidden_paths:
+  #  - "**.exe"
+  # Regular expression every pushed commit message must match.
+  #commit_message_pattern: "^(feat|fix|docs|chore)(\\(.+\\))?: "
+  # Email domains pushed commit authors must use.
+  #author_email_domains:
+  #  - "example.com"
+  # Branches, or glob patterns of branches, that reject merge commits.
+  #no_merge_branches:
+  #  - "main"
+
+# Default storage quotas, in bytes. A value of 0 means no limit. Admins can
+# override them for a single repository or user using the "repo quota" and
+# "user quota" commands.
+quota:
+  # Maximum size of the Git objects of a repository.
+  repo_size: {{ .Quota.RepoSize }}
+  # Maximum size of the LFS objects of a repository.
+  repo_lfs_size: {{ .Quota.RepoLFSSize }}
+  # Maximum size of the Git objects of all repositories a user owns.
+  user_size: {{ .Quota.UserSize }}
+  # Maximum size of the LFS objects of all repositories a user owns.
+  user_lfs_size: {{ .Quota.UserLFSSize }}
+
+# The audit log of administrative and security events. Events are stored in
+# the database and can be queried by admins using the "audit" command.
+audit:
+  # Path to a file events are also appended to as JSON lines. Relative paths
+  # are relative to the data directory.
+  #log_path: "{{ .Audit.LogPath }}"
 
 # Additional admin keys.
 #initial_admin_keys:
@@ -0,0 +1,33 @@
+package config
+
+import (
+	"path"
+	"path/filepath"
+	"strconv"
+
+	"github.com/charmbracelet/soft-serve/pkg/storage"
+)
+
+// LFSStorage returns the storage of the LFS objects of a repository, as
+// configured by LFS.Storage.
+func LFSStorage(cfg *Config, repoID int64) (storage.Storage, error) {
+	if cfg == nil {
+		return nil, ErrNilConfig
+	}
+
+	id := strconv.FormatInt(repoID, 10)
+	if cfg.LFS.Storage != "s3" {
+		return storage.NewLocalStorage(filepath.Join(cfg.DataPath, "lfs", id)), nil
+	}
+
+	s3 := cfg.LFS.S3
+	return storage.NewS3Storage(storage.S3Options{
+		Endpoint:        s3.Endpoint,
+		Region:          s3.Region,
+		Bucket:          s3.Bucket,
+		Prefix:          path.Join(s3.Prefix, id),
+		AccessKeyID:     s3.AccessKeyID,
+		SecretAccessKey: s3.SecretAccessKey,
+		PathStyle:       s3.PathStyle,
+	})
+}
@@ -0,0 +1,23 @@
+package migrate
+
+import (
+	"context"
+
+	"github.com/charmbracelet/soft-serve/pkg/db"
+)
+
+const (
+	webhookDeliveryQueueName    = "webhook_delivery_queue"
+	webhookDeliveryQueueVersion = 4
+)
+
+var webhookDeliveryQueue = Migration{
+	Name:    webhookDeliveryQueueName,
+	Version: webhookDeliveryQueueVersion,
+	Migrate: func(ctx context.Context, tx *db.Tx) error {
+		return migrateUp(ctx, tx, webhookDeliveryQueueVersion, webhookDeliveryQueueName)
+	},
+	Rollback: func(ctx context.Context, tx *db.Tx) error {
+		return migrateDown(ctx, tx, webhookDeliveryQueueVersion, webhookDeliveryQueueName)
+	},
+}
@@ -0,0 +1,11 @@
+ALTER TABLE webhook_deliveries ADD COLUMN status INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE webhook_deliveries ADD COLUMN attempts INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE webhook_deliveries ADD COLUMN next_attempt_at TIMESTAMP;
+
+-- Deliveries recorded before the queue existed were attempted exactly once.
+UPDATE webhook_deliveries SET attempts = 1, status = CASE
+  WHEN response_status >= 200 AND response_status < 300 THEN 2
+  ELSE 1
+END;
+
+CREATE INDEX IF NOT EXISTS webhook_deliveries_status_idx ON webhook_deliveries (status);
@@ -0,0 +1,23 @@
+package migrate
+
+import (
+	"context"
+
+	"github.com/charmbracelet/soft-serve/pkg/db"
+)
+
+const (
+	pullRequestsName    = "pull_requests"
+	pullRequestsVersion = 7
+)
+
+var pullRequests = Migration{
+	Name:    pullRequestsName,
+	Version: pullRequestsVersion,
+	Migrate: func(ctx context.Context, tx *db.Tx) error {
+		return migrateUp(ctx, tx, pullRequestsVersion, pullRequestsName)
+	},
+	Rollback: func(ctx context.Context, tx *db.Tx) error {
+		return migrateDown(ctx, tx, pullRequestsVersion, pullRequestsName)
+	},
+}
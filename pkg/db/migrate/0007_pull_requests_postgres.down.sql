@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS pull_request_comments;
+DROP TABLE IF EXISTS pull_requests;
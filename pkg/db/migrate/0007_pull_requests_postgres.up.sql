@@ -0,0 +1,40 @@
+CREATE TABLE IF NOT EXISTS pull_requests (
+  id SERIAL PRIMARY KEY,
+  repo_id INTEGER NOT NULL,
+  number INTEGER NOT NULL,
+  user_id INTEGER,
+  title TEXT NOT NULL,
+  description TEXT NOT NULL,
+  source_ref TEXT NOT NULL,
+  target_branch TEXT NOT NULL,
+  state TEXT NOT NULL,
+  merge_commit TEXT,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL,
+  UNIQUE (repo_id, number),
+  CONSTRAINT repo_id_fk
+  FOREIGN KEY(repo_id) REFERENCES repos(id)
+  ON DELETE CASCADE
+  ON UPDATE CASCADE,
+  CONSTRAINT user_id_fk
+  FOREIGN KEY(user_id) REFERENCES users(id)
+  ON DELETE SET NULL
+  ON UPDATE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS pull_request_comments (
+  id SERIAL PRIMARY KEY,
+  pull_request_id INTEGER NOT NULL,
+  user_id INTEGER,
+  body TEXT NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL,
+  CONSTRAINT pull_request_id_fk
+  FOREIGN KEY(pull_request_id) REFERENCES pull_requests(id)
+  ON DELETE CASCADE
+  ON UPDATE CASCADE,
+  CONSTRAINT user_id_fk
+  FOREIGN KEY(user_id) REFERENCES users(id)
+  ON DELETE SET NULL
+  ON UPDATE CASCADE
+);
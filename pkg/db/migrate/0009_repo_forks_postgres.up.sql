@@ -0,0 +1 @@
+ALTER TABLE repos ADD COLUMN parent_id INTEGER;
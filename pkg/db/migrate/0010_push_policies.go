@@ -0,0 +1,23 @@
+package migrate
+
+import (
+	"context"
+
+	"github.com/charmbracelet/soft-serve/pkg/db"
+)
+
+const (
+	pushPoliciesName    = "push_policies"
+	pushPoliciesVersion = 10
+)
+
+var pushPolicies = Migration{
+	Name:    pushPoliciesName,
+	Version: pushPoliciesVersion,
+	Migrate: func(ctx context.Context, tx *db.Tx) error {
+		return migrateUp(ctx, tx, pushPoliciesVersion, pushPoliciesName)
+	},
+	Rollback: func(ctx context.Context, tx *db.Tx) error {
+		return migrateDown(ctx, tx, pushPoliciesVersion, pushPoliciesName)
+	},
+}
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS push_policies (
+  id SERIAL PRIMARY KEY,
+  repo_id INTEGER NOT NULL UNIQUE,
+  max_file_size BIGINT NOT NULL DEFAULT 0,
+  forbidden_paths TEXT NOT NULL DEFAULT '',
+  commit_message_pattern TEXT NOT NULL DEFAULT '',
+  author_email_domains TEXT NOT NULL DEFAULT '',
+  no_merge_branches TEXT NOT NULL DEFAULT '',
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL,
+  CONSTRAINT repo_id_fk
+  FOREIGN KEY(repo_id) REFERENCES repos(id)
+  ON DELETE CASCADE
+  ON UPDATE CASCADE
+);
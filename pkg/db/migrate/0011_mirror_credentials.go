@@ -0,0 +1,23 @@
+package migrate
+
+import (
+	"context"
+
+	"github.com/charmbracelet/soft-serve/pkg/db"
+)
+
+const (
+	mirrorCredentialsName    = "mirror_credentials"
+	mirrorCredentialsVersion = 11
+)
+
+var mirrorCredentials = Migration{
+	Name:    mirrorCredentialsName,
+	Version: mirrorCredentialsVersion,
+	Migrate: func(ctx context.Context, tx *db.Tx) error {
+		return migrateUp(ctx, tx, mirrorCredentialsVersion, mirrorCredentialsName)
+	},
+	Rollback: func(ctx context.Context, tx *db.Tx) error {
+		return migrateDown(ctx, tx, mirrorCredentialsVersion, mirrorCredentialsName)
+	},
+}
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS mirror_credentials;
@@ -0,0 +1,23 @@
+package migrate
+
+import (
+	"context"
+
+	"github.com/charmbracelet/soft-serve/pkg/db"
+)
+
+const (
+	mirrorSyncsName    = "mirror_syncs"
+	mirrorSyncsVersion = 13
+)
+
+var mirrorSyncs = Migration{
+	Name:    mirrorSyncsName,
+	Version: mirrorSyncsVersion,
+	Migrate: func(ctx context.Context, tx *db.Tx) error {
+		return migrateUp(ctx, tx, mirrorSyncsVersion, mirrorSyncsName)
+	},
+	Rollback: func(ctx context.Context, tx *db.Tx) error {
+		return migrateDown(ctx, tx, mirrorSyncsVersion, mirrorSyncsName)
+	},
+}
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS mirror_syncs (
+  id SERIAL PRIMARY KEY,
+  repo_id INTEGER NOT NULL UNIQUE,
+  sync_interval INTEGER NOT NULL DEFAULT 0,
+  last_attempt_at TIMESTAMP,
+  last_success_at TIMESTAMP,
+  last_error TEXT NOT NULL DEFAULT '',
+  last_duration INTEGER NOT NULL DEFAULT 0,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL,
+  CONSTRAINT repo_id_fk
+  FOREIGN KEY(repo_id) REFERENCES repos(id)
+  ON DELETE CASCADE
+  ON UPDATE CASCADE
+);
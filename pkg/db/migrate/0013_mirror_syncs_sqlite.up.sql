@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS mirror_syncs (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  repo_id INTEGER NOT NULL UNIQUE,
+  sync_interval INTEGER NOT NULL DEFAULT 0,
+  last_attempt_at DATETIME,
+  last_success_at DATETIME,
+  last_error TEXT NOT NULL DEFAULT '',
+  last_duration INTEGER NOT NULL DEFAULT 0,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at DATETIME NOT NULL,
+  CONSTRAINT repo_id_fk
+  FOREIGN KEY(repo_id) REFERENCES repos(id)
+  ON DELETE CASCADE
+  ON UPDATE CASCADE
+);
@@ -0,0 +1,23 @@
+package migrate
+
+import (
+	"context"
+
+	"github.com/charmbracelet/soft-serve/pkg/db"
+)
+
+const (
+	quotasName    = "quotas"
+	quotasVersion = 14
+)
+
+var quotas = Migration{
+	Name:    quotasName,
+	Version: quotasVersion,
+	Migrate: func(ctx context.Context, tx *db.Tx) error {
+		return migrateUp(ctx, tx, quotasVersion, quotasName)
+	},
+	Rollback: func(ctx context.Context, tx *db.Tx) error {
+		return migrateDown(ctx, tx, quotasVersion, quotasName)
+	},
+}
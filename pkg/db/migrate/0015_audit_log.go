@@ -0,0 +1,23 @@
+package migrate
+
+import (
+	"context"
+
+	"github.com/charmbracelet/soft-serve/pkg/db"
+)
+
+const (
+	auditLogName    = "audit_log"
+	auditLogVersion = 15
+)
+
+var auditLog = Migration{
+	Name:    auditLogName,
+	Version: auditLogVersion,
+	Migrate: func(ctx context.Context, tx *db.Tx) error {
+		return migrateUp(ctx, tx, auditLogVersion, auditLogName)
+	},
+	Rollback: func(ctx context.Context, tx *db.Tx) error {
+		return migrateDown(ctx, tx, auditLogVersion, auditLogName)
+	},
+}
@@ -0,0 +1,2 @@
+ALTER TABLE access_tokens DROP COLUMN repos;
+ALTER TABLE access_tokens DROP COLUMN scopes;
@@ -0,0 +1,2 @@
+ALTER TABLE access_tokens ADD COLUMN scopes TEXT NOT NULL DEFAULT '';
+ALTER TABLE access_tokens ADD COLUMN repos TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,3 @@
+ALTER TABLE access_tokens ADD COLUMN last_used_at TIMESTAMP;
+ALTER TABLE access_tokens ADD COLUMN last_used_addr TEXT NOT NULL DEFAULT '';
+ALTER TABLE access_tokens ADD COLUMN use_count BIGINT NOT NULL DEFAULT 0;
@@ -0,0 +1,3 @@
+ALTER TABLE access_tokens DROP COLUMN use_count;
+ALTER TABLE access_tokens DROP COLUMN last_used_addr;
+ALTER TABLE access_tokens DROP COLUMN last_used_at;
@@ -0,0 +1,3 @@
+ALTER TABLE access_tokens ADD COLUMN last_used_at DATETIME;
+ALTER TABLE access_tokens ADD COLUMN last_used_addr TEXT NOT NULL DEFAULT '';
+ALTER TABLE access_tokens ADD COLUMN use_count INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,23 @@
+package migrate
+
+import (
+	"context"
+
+	"github.com/charmbracelet/soft-serve/pkg/db"
+)
+
+const (
+	signedCommitsName    = "signed_commits"
+	signedCommitsVersion = 19
+)
+
+var signedCommits = Migration{
+	Name:    signedCommitsName,
+	Version: signedCommitsVersion,
+	Migrate: func(ctx context.Context, tx *db.Tx) error {
+		return migrateUp(ctx, tx, signedCommitsVersion, signedCommitsName)
+	},
+	Rollback: func(ctx context.Context, tx *db.Tx) error {
+		return migrateDown(ctx, tx, signedCommitsVersion, signedCommitsName)
+	},
+}
@@ -0,0 +1 @@
+ALTER TABLE push_policies DROP COLUMN require_signed_commits;
@@ -0,0 +1 @@
+ALTER TABLE push_policies ADD COLUMN require_signed_commits BOOLEAN NOT NULL DEFAULT false;
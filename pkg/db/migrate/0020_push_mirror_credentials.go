@@ -0,0 +1,23 @@
+package migrate
+
+import (
+	"context"
+
+	"github.com/charmbracelet/soft-serve/pkg/db"
+)
+
+const (
+	pushMirrorCredentialsName    = "push_mirror_credentials"
+	pushMirrorCredentialsVersion = 20
+)
+
+var pushMirrorCredentials = Migration{
+	Name:    pushMirrorCredentialsName,
+	Version: pushMirrorCredentialsVersion,
+	Migrate: func(ctx context.Context, tx *db.Tx) error {
+		return migrateUp(ctx, tx, pushMirrorCredentialsVersion, pushMirrorCredentialsName)
+	},
+	Rollback: func(ctx context.Context, tx *db.Tx) error {
+		return migrateDown(ctx, tx, pushMirrorCredentialsVersion, pushMirrorCredentialsName)
+	},
+}
@@ -0,0 +1 @@
+ALTER TABLE push_mirrors ADD COLUMN credentials TEXT NOT NULL DEFAULT '';
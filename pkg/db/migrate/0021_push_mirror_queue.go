@@ -0,0 +1,23 @@
+package migrate
+
+import (
+	"context"
+
+	"github.com/charmbracelet/soft-serve/pkg/db"
+)
+
+const (
+	pushMirrorQueueName    = "push_mirror_queue"
+	pushMirrorQueueVersion = 21
+)
+
+var pushMirrorQueue = Migration{
+	Name:    pushMirrorQueueName,
+	Version: pushMirrorQueueVersion,
+	Migrate: func(ctx context.Context, tx *db.Tx) error {
+		return migrateUp(ctx, tx, pushMirrorQueueVersion, pushMirrorQueueName)
+	},
+	Rollback: func(ctx context.Context, tx *db.Tx) error {
+		return migrateDown(ctx, tx, pushMirrorQueueVersion, pushMirrorQueueName)
+	},
+}
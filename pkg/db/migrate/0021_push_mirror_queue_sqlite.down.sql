@@ -0,0 +1 @@
+ALTER TABLE push_mirrors DROP COLUMN sync_requested;
@@ -0,0 +1 @@
+ALTER TABLE push_mirrors ADD COLUMN sync_requested BOOLEAN NOT NULL DEFAULT false;
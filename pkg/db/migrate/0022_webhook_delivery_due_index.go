@@ -0,0 +1,23 @@
+package migrate
+
+import (
+	"context"
+
+	"github.com/charmbracelet/soft-serve/pkg/db"
+)
+
+const (
+	webhookDeliveryDueIndexName    = "webhook_delivery_due_index"
+	webhookDeliveryDueIndexVersion = 22
+)
+
+var webhookDeliveryDueIndex = Migration{
+	Name:    webhookDeliveryDueIndexName,
+	Version: webhookDeliveryDueIndexVersion,
+	Migrate: func(ctx context.Context, tx *db.Tx) error {
+		return migrateUp(ctx, tx, webhookDeliveryDueIndexVersion, webhookDeliveryDueIndexName)
+	},
+	Rollback: func(ctx context.Context, tx *db.Tx) error {
+		return migrateDown(ctx, tx, webhookDeliveryDueIndexVersion, webhookDeliveryDueIndexName)
+	},
+}
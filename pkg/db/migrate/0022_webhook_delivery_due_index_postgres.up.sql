@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS webhook_deliveries_status_idx;
+CREATE INDEX IF NOT EXISTS webhook_deliveries_status_next_attempt_at_idx ON webhook_deliveries (status, next_attempt_at);
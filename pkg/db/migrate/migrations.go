@@ -18,6 +18,25 @@ var migrations = []Migration{
 	createTables,
 	webhooks,
 	migrateLfsObjects,
+	webhookDeliveryQueue,
+	branchProtections,
+	teams,
+	pullRequests,
+	issues,
+	repoForks,
+	pushPolicies,
+	mirrorCredentials,
+	pushMirrors,
+	mirrorSyncs,
+	quotas,
+	auditLog,
+	accessTokenScopes,
+	accessTokenUsage,
+	deployKeys,
+	signedCommits,
+	pushMirrorCredentials,
+	pushMirrorQueue,
+	webhookDeliveryDueIndex,
 }
 
 func execMigration(ctx context.Context, tx *db.Tx, version int, name string, down bool) error {
@@ -12,6 +12,14 @@ type AccessToken struct {
 	UserID    int64        `db:"user_id"`
 	Token     string       `db:"token"`
 	ExpiresAt sql.NullTime `db:"expires_at"`
-	CreatedAt time.Time    `db:"created_at"`
-	UpdatedAt time.Time    `db:"updated_at"`
+	// Scopes are newline separated scopes, empty for all scopes.
+	Scopes string `db:"scopes"`
+	// Repos are newline separated repository names, empty for any
+	// repository.
+	Repos        string       `db:"repos"`
+	LastUsedAt   sql.NullTime `db:"last_used_at"`
+	LastUsedAddr string       `db:"last_used_addr"`
+	UseCount     int64        `db:"use_count"`
+	CreatedAt    time.Time    `db:"created_at"`
+	UpdatedAt    time.Time    `db:"updated_at"`
 }
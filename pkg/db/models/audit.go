@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// AuditEvent is an entry of the audit log.
+type AuditEvent struct {
+	ID int64 `db:"id"`
+	// Actor is the username of the user who took the action, empty for
+	// anonymous users and the server itself.
+	Actor  string `db:"actor"`
+	Action string `db:"action"`
+	Target string `db:"target"`
+	// Details are space separated key=value pairs.
+	Details string `db:"details"`
+	// Addr is the IP address the action came from.
+	Addr string `db:"addr"`
+	// Transport is the protocol the action came through, e.g. ssh or http.
+	Transport string    `db:"transport"`
+	CreatedAt time.Time `db:"created_at"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+
+	"github.com/charmbracelet/soft-serve/pkg/access"
+)
+
+// BranchProtection is a protected branch rule of a repository.
+type BranchProtection struct {
+	ID             int64              `db:"id"`
+	RepoID         int64              `db:"repo_id"`
+	Pattern        string             `db:"pattern"`
+	BlockForcePush bool               `db:"block_force_push"`
+	BlockDeletion  bool               `db:"block_deletion"`
+	MinAccessLevel access.AccessLevel `db:"min_access_level"`
+	CreatedAt      time.Time          `db:"created_at"`
+	UpdatedAt      time.Time          `db:"updated_at"`
+}
@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+
+	"github.com/charmbracelet/soft-serve/pkg/access"
+)
+
+// DeployKey represents an SSH public key attached to a single repository.
+type DeployKey struct {
+	ID          int64              `db:"id"`
+	RepoID      int64              `db:"repo_id"`
+	Title       string             `db:"title"`
+	PublicKey   string             `db:"public_key"`
+	AccessLevel access.AccessLevel `db:"access_level"`
+	CreatedAt   time.Time          `db:"created_at"`
+	UpdatedAt   time.Time          `db:"updated_at"`
+}
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Issue is an issue of a repository.
+type Issue struct {
+	ID        int64         `db:"id"`
+	RepoID    int64         `db:"repo_id"`
+	Number    int64         `db:"number"`
+	UserID    sql.NullInt64 `db:"user_id"`
+	Title     string        `db:"title"`
+	Body      string        `db:"body"`
+	State     string        `db:"state"`
+	CreatedAt time.Time     `db:"created_at"`
+	UpdatedAt time.Time     `db:"updated_at"`
+}
+
+// IssueComment is a comment on an issue.
+type IssueComment struct {
+	ID        int64         `db:"id"`
+	IssueID   int64         `db:"issue_id"`
+	UserID    sql.NullInt64 `db:"user_id"`
+	Body      string        `db:"body"`
+	CreatedAt time.Time     `db:"created_at"`
+	UpdatedAt time.Time     `db:"updated_at"`
+}
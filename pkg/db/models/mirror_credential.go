@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// MirrorCredential is the credential a mirror repository uses to fetch from
+// its remotes. The secret is encrypted.
+type MirrorCredential struct {
+	ID        int64     `db:"id"`
+	RepoID    int64     `db:"repo_id"`
+	Type      string    `db:"type"`
+	Username  string    `db:"username"`
+	Secret    string    `db:"secret"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// MirrorSync is the sync schedule and status of a mirror repository.
+type MirrorSync struct {
+	ID     int64 `db:"id"`
+	RepoID int64 `db:"repo_id"`
+	// SyncInterval is the number of seconds between syncs, 0 to sync on the
+	// global schedule.
+	SyncInterval  int64        `db:"sync_interval"`
+	LastAttemptAt sql.NullTime `db:"last_attempt_at"`
+	LastSuccessAt sql.NullTime `db:"last_success_at"`
+	LastError     string       `db:"last_error"`
+	// LastDuration is the duration of the last sync in milliseconds.
+	LastDuration int64     `db:"last_duration"`
+	CreatedAt    time.Time `db:"created_at"`
+	UpdatedAt    time.Time `db:"updated_at"`
+}
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// PullRequest is a request to merge a ref into a branch of a repository.
+type PullRequest struct {
+	ID           int64          `db:"id"`
+	RepoID       int64          `db:"repo_id"`
+	Number       int64          `db:"number"`
+	UserID       sql.NullInt64  `db:"user_id"`
+	Title        string         `db:"title"`
+	Description  string         `db:"description"`
+	SourceRef    string         `db:"source_ref"`
+	TargetBranch string         `db:"target_branch"`
+	State        string         `db:"state"`
+	MergeCommit  sql.NullString `db:"merge_commit"`
+	CreatedAt    time.Time      `db:"created_at"`
+	UpdatedAt    time.Time      `db:"updated_at"`
+}
+
+// PullRequestComment is a comment on a pull request.
+type PullRequestComment struct {
+	ID            int64         `db:"id"`
+	PullRequestID int64         `db:"pull_request_id"`
+	UserID        sql.NullInt64 `db:"user_id"`
+	Body          string        `db:"body"`
+	CreatedAt     time.Time     `db:"created_at"`
+	UpdatedAt     time.Time     `db:"updated_at"`
+}
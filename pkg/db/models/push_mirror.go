@@ -0,0 +1,21 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// PushMirror is a downstream remote a repository is pushed to. The
+// credentials of the remote are encrypted.
+type PushMirror struct {
+	ID            int64        `db:"id"`
+	RepoID        int64        `db:"repo_id"`
+	Name          string       `db:"name"`
+	URL           string       `db:"url"`
+	Credentials   string       `db:"credentials"`
+	LastSyncAt    sql.NullTime `db:"last_sync_at"`
+	LastError     string       `db:"last_error"`
+	SyncRequested bool         `db:"sync_requested"`
+	CreatedAt     time.Time    `db:"created_at"`
+	UpdatedAt     time.Time    `db:"updated_at"`
+}
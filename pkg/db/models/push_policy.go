@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// PushPolicy is the push policy of a repository. List fields hold one entry
+// per line.
+type PushPolicy struct {
+	ID                   int64     `db:"id"`
+	RepoID               int64     `db:"repo_id"`
+	MaxFileSize          int64     `db:"max_file_size"`
+	ForbiddenPaths       string    `db:"forbidden_paths"`
+	CommitMessagePattern string    `db:"commit_message_pattern"`
+	AuthorEmailDomains   string    `db:"author_email_domains"`
+	NoMergeBranches      string    `db:"no_merge_branches"`
+	RequireSignedCommits bool      `db:"require_signed_commits"`
+	CreatedAt            time.Time `db:"created_at"`
+	UpdatedAt            time.Time `db:"updated_at"`
+}
@@ -0,0 +1,18 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Quota is the storage quota of a repository or a user. Sizes are in bytes,
+// NULL to use the server default.
+type Quota struct {
+	ID int64 `db:"id"`
+	// Size is the maximum size of the git objects.
+	Size sql.NullInt64 `db:"size"`
+	// LFSSize is the maximum size of the LFS objects.
+	LFSSize   sql.NullInt64 `db:"lfs_size"`
+	CreatedAt time.Time     `db:"created_at"`
+	UpdatedAt time.Time     `db:"updated_at"`
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"github.com/charmbracelet/soft-serve/pkg/access"
+)
+
+// Team represents a group of users.
+type Team struct {
+	ID        int64     `db:"id"`
+	Name      string    `db:"name"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+// TeamCollab represents a team's access to a repository.
+type TeamCollab struct {
+	ID          int64              `db:"id"`
+	TeamID      int64              `db:"team_id"`
+	RepoID      int64              `db:"repo_id"`
+	AccessLevel access.AccessLevel `db:"access_level"`
+	CreatedAt   time.Time          `db:"created_at"`
+	UpdatedAt   time.Time          `db:"updated_at"`
+}
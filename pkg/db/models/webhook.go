@@ -40,5 +40,8 @@ type WebhookDelivery struct {
 	ResponseStatus  int            `db:"response_status"`
 	ResponseHeaders string         `db:"response_headers"`
 	ResponseBody    string         `db:"response_body"`
+	Status          int            `db:"status"`
+	Attempts        int            `db:"attempts"`
+	NextAttemptAt   sql.NullTime   `db:"next_attempt_at"`
 	CreatedAt       time.Time      `db:"created_at"`
 }
@@ -7,12 +7,12 @@ import (
 	"fmt"
 	"io"
 	"path"
-	"path/filepath"
 	"strconv"
 	"time"
 
 	"charm.land/log/v2"
 	"github.com/charmbracelet/git-lfs-transfer/transfer"
+	"github.com/charmbracelet/soft-serve/pkg/backend"
 	"github.com/charmbracelet/soft-serve/pkg/config"
 	"github.com/charmbracelet/soft-serve/pkg/db"
 	"github.com/charmbracelet/soft-serve/pkg/db/models"
@@ -67,6 +67,13 @@ func LFSTransfer(ctx context.Context, cmd ServiceCommand) error {
 		return proto.ErrRepoNotFound
 	}
 
+	cfg := config.FromContext(ctx)
+	strg, err := config.LFSStorage(cfg, repo.ID())
+	if err != nil {
+		logger.Error("error opening lfs storage", "err", err)
+		return err
+	}
+
 	// Advertise capabilities.
 	for _, cap := range transfer.Capabilities {
 		if err := handler.WritePacketText(cap); err != nil {
@@ -80,15 +87,13 @@ func LFSTransfer(ctx context.Context, cmd ServiceCommand) error {
 		return err
 	}
 
-	repoID := strconv.FormatInt(repo.ID(), 10)
-	cfg := config.FromContext(ctx)
 	processor := transfer.NewProcessor(handler, &lfsTransfer{
 		ctx:     ctx,
 		cfg:     cfg,
 		dbx:     db.FromContext(ctx),
 		store:   store.FromContext(ctx),
 		logger:  logger,
-		storage: storage.NewLocalStorage(filepath.Join(cfg.DataPath, "lfs", repoID)),
+		storage: strg,
 		repo:    repo,
 	}, &lfsLogger{logger})
 
@@ -96,7 +101,7 @@ func LFSTransfer(ctx context.Context, cmd ServiceCommand) error {
 }
 
 // Batch implements transfer.Backend.
-func (t *lfsTransfer) Batch(_ string, pointers []transfer.BatchItem, _ transfer.Args) ([]transfer.BatchItem, error) {
+func (t *lfsTransfer) Batch(op string, pointers []transfer.BatchItem, _ transfer.Args) ([]transfer.BatchItem, error) {
 	for i := range pointers {
 		p := transfer.Pointer{Oid: pointers[i].Oid, Size: pointers[i].Size}
 		if !p.IsValid() {
@@ -104,6 +109,7 @@ func (t *lfsTransfer) Batch(_ string, pointers []transfer.BatchItem, _ transfer.
 		}
 	}
 
+	var pending int64
 	for i := range pointers {
 		obj, err := t.store.GetLFSObjectByOid(t.ctx, t.dbx, t.repo.ID(), pointers[i].Oid)
 		if err != nil && !errors.Is(err, db.ErrRecordNotFound) {
@@ -120,6 +126,19 @@ func (t *lfsTransfer) Batch(_ string, pointers []transfer.BatchItem, _ transfer.
 				return pointers, db.WrapError(err)
 			}
 		}
+
+		if !pointers[i].Present {
+			pending += pointers[i].Size
+		}
+	}
+
+	if op == lfs.OperationUpload {
+		if err := backend.FromContext(t.ctx).CheckLFSQuota(t.ctx, t.repo, pending); err != nil {
+			if errors.Is(err, proto.ErrQuotaExceeded) {
+				return pointers, fmt.Errorf("%w: %w", transfer.ErrForbidden, err)
+			}
+			return pointers, err
+		}
 	}
 
 	return pointers, nil
@@ -132,11 +151,8 @@ func (t *lfsTransfer) Download(oid string, _ transfer.Args) (io.ReadCloser, int6
 		return nil, 0, errInvalidOid
 	}
 
-	cfg := config.FromContext(t.ctx)
-	repoID := strconv.FormatInt(t.repo.ID(), 10)
-	strg := storage.NewLocalStorage(filepath.Join(cfg.DataPath, "lfs", repoID))
 	pointer := transfer.Pointer{Oid: oid}
-	obj, err := strg.Open(path.Join("objects", pointer.RelativePath()))
+	obj, err := t.storage.Open(path.Join("objects", pointer.RelativePath()))
 	if err != nil {
 		return nil, 0, err
 	}
@@ -0,0 +1,52 @@
+package hooks
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/charmbracelet/soft-serve/pkg/utils"
+)
+
+// CustomHookPaths returns the custom hook scripts to run for the hook name,
+// in order. These are the global <data path>/hooks/<name> script, the scripts
+// in the global <data path>/hooks/<name>.d directory, and the scripts in the
+// repository's <data path>/hooks/repos/<repo>/<name>.d directory. Scripts in
+// a directory run in lexical order. Repository hooks live under their own
+// root so a repository name can't collide with a global hook directory.
+func CustomHookPaths(dataPath string, repo string, name string) []string {
+	hooksPath := filepath.Join(dataPath, "hooks")
+	paths := []string{filepath.Join(hooksPath, name)}
+	paths = append(paths, hookDirEntries(filepath.Join(hooksPath, name+".d"))...)
+
+	repo = utils.SanitizeRepo(repo)
+	if repo != "" {
+		paths = append(paths, hookDirEntries(filepath.Join(hooksPath, "repos", filepath.FromSlash(repo), name+".d"))...)
+	}
+
+	return paths
+}
+
+// IsCustomHook returns whether path is a custom hook script that runs, that
+// is an executable file. Other paths are skipped.
+func IsCustomHook(path string) bool {
+	stat, err := os.Stat(path)
+	return err == nil && !stat.IsDir() && stat.Mode()&0o111 != 0
+}
+
+// hookDirEntries returns the paths of the files in dir in lexical order.
+func hookDirEntries(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+
+	paths := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		paths = append(paths, filepath.Join(dir, e.Name()))
+	}
+
+	return paths
+}
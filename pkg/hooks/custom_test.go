@@ -0,0 +1,67 @@
+package hooks
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCustomHookPaths(t *testing.T) {
+	dataPath := t.TempDir()
+	hooksPath := filepath.Join(dataPath, "hooks")
+	for _, p := range []string{
+		filepath.Join("pre-receive.d", "20-b"),
+		filepath.Join("pre-receive.d", "10-a"),
+		filepath.Join("update.d", "10-a"),
+		filepath.Join("repos", "org", "repo", "pre-receive.d", "10-c"),
+		filepath.Join("repos", "other", "pre-receive.d", "10-d"),
+		filepath.Join("repos", "update.d", "pre-receive.d", "10-e"),
+	} {
+		p = filepath.Join(hooksPath, p)
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte("#!/bin/sh\n"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.MkdirAll(filepath.Join(hooksPath, "pre-receive.d", "subdir"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	got := CustomHookPaths(dataPath, "org/repo.git", "pre-receive")
+	want := []string{
+		filepath.Join(hooksPath, "pre-receive"),
+		filepath.Join(hooksPath, "pre-receive.d", "10-a"),
+		filepath.Join(hooksPath, "pre-receive.d", "20-b"),
+		filepath.Join(hooksPath, "repos", "org", "repo", "pre-receive.d", "10-c"),
+	}
+	if len(got) != len(want) {
+		t.Fatalf("CustomHookPaths() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("CustomHookPaths() = %v, want %v", got, want)
+		}
+	}
+
+	got = CustomHookPaths(dataPath, "../other", "pre-receive")
+	if got[len(got)-1] != filepath.Join(hooksPath, "repos", "other", "pre-receive.d", "10-d") {
+		t.Fatalf("CustomHookPaths(../other) = %v", got)
+	}
+
+	// A repository named like a global hook directory doesn't collide with
+	// it.
+	got = CustomHookPaths(dataPath, "update.d", "pre-receive")
+	if got[len(got)-1] != filepath.Join(hooksPath, "repos", "update.d", "pre-receive.d", "10-e") {
+		t.Fatalf("CustomHookPaths(update.d) = %v", got)
+	}
+	got = CustomHookPaths(dataPath, "update.d", "update")
+	want = []string{
+		filepath.Join(hooksPath, "update"),
+		filepath.Join(hooksPath, "update.d", "10-a"),
+	}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Fatalf("CustomHookPaths(update.d) = %v, want %v", got, want)
+	}
+}
@@ -13,8 +13,10 @@ type HookArg struct {
 }
 
 // Hooks provides an interface for git server-side hooks.
+//
+// PreReceive is the only hook that can reject a push, by returning an error.
 type Hooks interface {
-	PreReceive(ctx context.Context, stdout io.Writer, stderr io.Writer, repo string, args []HookArg)
+	PreReceive(ctx context.Context, stdout io.Writer, stderr io.Writer, repo string, args []HookArg) error
 	Update(ctx context.Context, stdout io.Writer, stderr io.Writer, repo string, arg HookArg)
 	PostReceive(ctx context.Context, stdout io.Writer, stderr io.Writer, repo string, args []HookArg)
 	PostUpdate(ctx context.Context, stdout io.Writer, stderr io.Writer, repo string, args ...string)
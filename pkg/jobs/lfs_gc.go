@@ -0,0 +1,81 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	gosync "sync"
+
+	"charm.land/log/v2"
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/charmbracelet/soft-serve/pkg/config"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/sync"
+	"github.com/charmbracelet/soft-serve/pkg/task"
+)
+
+func init() {
+	Register("lfs-gc", &lfsGC{})
+}
+
+type lfsGC struct {
+	// running guards against overlapping runs when a run outlasts the
+	// schedule interval.
+	running gosync.Mutex
+}
+
+// Spec derives the spec used for LFS garbage collection and implements
+// Runner.
+func (g *lfsGC) Spec(ctx context.Context) string {
+	cfg := config.FromContext(ctx)
+	if cfg.Jobs.LFSGC != "" {
+		return cfg.Jobs.LFSGC
+	}
+	return "@every 24h"
+}
+
+// Func runs the LFS garbage collection job task and implements Runner.
+func (g *lfsGC) Func(ctx context.Context) func() {
+	cfg := config.FromContext(ctx)
+	logger := log.FromContext(ctx).WithPrefix("jobs.lfs-gc")
+	b := backend.FromContext(ctx)
+	return func() {
+		if !cfg.LFS.Enabled {
+			return
+		}
+
+		if !g.running.TryLock() {
+			logger.Debug("previous lfs garbage collection still in progress")
+			return
+		}
+		defer g.running.Unlock()
+
+		repos, err := b.Repositories(ctx)
+		if err != nil {
+			logger.Error("error getting repositories", "err", err)
+			return
+		}
+
+		wq := sync.NewWorkPool(ctx, runtime.GOMAXPROCS(0),
+			sync.WithWorkPoolLogger(logger.Errorf),
+		)
+
+		logger.Debug("collecting unreferenced lfs objects")
+		for _, repo := range repos {
+			name := repo.Name()
+			wq.Add(name, func() {
+				// Repositories being pushed to or collected on demand are
+				// picked up by the next run.
+				_, err := b.GarbageCollectLFS(ctx, name, false)
+				switch {
+				case errors.Is(err, proto.ErrRepoPushInProgress), errors.Is(err, task.ErrAlreadyStarted):
+					logger.Debug("skipping lfs garbage collection", "repo", name, "err", err)
+				case err != nil:
+					logger.Error("error collecting lfs objects", "repo", name, "err", err)
+				}
+			})
+		}
+
+		wq.Run()
+	}
+}
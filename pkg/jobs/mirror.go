@@ -2,62 +2,25 @@ package jobs
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"path/filepath"
 	"runtime"
-	"strings"
+	"time"
 
 	"charm.land/log/v2"
-	"github.com/charmbracelet/soft-serve/git"
 	"github.com/charmbracelet/soft-serve/pkg/backend"
 	"github.com/charmbracelet/soft-serve/pkg/config"
-	"github.com/charmbracelet/soft-serve/pkg/db"
-	"github.com/charmbracelet/soft-serve/pkg/lfs"
-	"github.com/charmbracelet/soft-serve/pkg/ssrf"
-	"github.com/charmbracelet/soft-serve/pkg/store"
 	"github.com/charmbracelet/soft-serve/pkg/sync"
+	"github.com/charmbracelet/soft-serve/pkg/task"
 )
 
 func init() {
 	Register("mirror-pull", mirrorPull{})
+	Register("mirror-pull-interval", mirrorPullInterval{})
 }
 
 type mirrorPull struct{}
 
-// validateMirrorRemotes validates every remote configured on a mirror
-// repository and returns the git environment that must be applied to the sync
-// commands.
-//
-// `git remote update` fetches from all remotes, not just origin, so every one
-// of them has to pass. Any failure to read or validate skips the sync: a
-// remote that cannot be checked is not one to fetch from.
-func validateMirrorRemotes(r *git.Repository) ([]string, error) {
-	cfg, err := r.Config()
-	if err != nil {
-		return nil, fmt.Errorf("reading git config: %w", err)
-	}
-
-	var remotes []ssrf.ValidatedGitRemote
-	for _, sub := range cfg.Section("remote").Subsections {
-		url := sub.Option("url")
-		if url == "" {
-			continue
-		}
-
-		v, err := ssrf.ValidateGitRemote(url)
-		if err != nil {
-			return nil, fmt.Errorf("remote %q: %w", sub.Name, err)
-		}
-		remotes = append(remotes, v)
-	}
-
-	if len(remotes) == 0 {
-		return nil, fmt.Errorf("no remote url configured")
-	}
-
-	return ssrf.GitEnv(remotes...), nil
-}
-
 // Spec derives the spec used for pull mirrors and implements Runner.
 func (m mirrorPull) Spec(ctx context.Context) string {
 	cfg := config.FromContext(ctx)
@@ -67,114 +30,78 @@ func (m mirrorPull) Spec(ctx context.Context) string {
 	return "@every 10m"
 }
 
-// Func runs the (pull) mirror job task and implements Runner.
+// Func runs the (pull) mirror job task and implements Runner. It syncs the
+// mirrors without their own interval.
 func (m mirrorPull) Func(ctx context.Context) func() {
-	cfg := config.FromContext(ctx)
 	logger := log.FromContext(ctx).WithPrefix("jobs.mirror")
-	b := backend.FromContext(ctx)
-	dbx := db.FromContext(ctx)
-	datastore := store.FromContext(ctx)
 	return func() {
-		repos, err := b.Repositories(ctx)
+		syncMirrors(ctx, logger, func(s backend.MirrorSync, _ time.Time) bool {
+			return s.Interval <= 0
+		})
+	}
+}
+
+// mirrorPullInterval syncs the mirrors with their own interval. It runs as
+// often as the shortest interval allowed, so every interval is honored
+// regardless of the global schedule.
+type mirrorPullInterval struct{}
+
+// Spec returns the spec used for mirrors with their own interval and
+// implements Runner.
+func (m mirrorPullInterval) Spec(context.Context) string {
+	return fmt.Sprintf("@every %s", backend.MinMirrorSyncInterval)
+}
+
+// Func runs the interval mirror job task and implements Runner.
+func (m mirrorPullInterval) Func(ctx context.Context) func() {
+	logger := log.FromContext(ctx).WithPrefix("jobs.mirror")
+	return func() {
+		syncMirrors(ctx, logger, func(s backend.MirrorSync, now time.Time) bool {
+			return s.Interval > 0 && s.Due(now)
+		})
+	}
+}
+
+// syncMirrors syncs the mirror repositories for which due returns true.
+func syncMirrors(ctx context.Context, logger *log.Logger, due func(backend.MirrorSync, time.Time) bool) {
+	b := backend.FromContext(ctx)
+	repos, err := b.Repositories(ctx)
+	if err != nil {
+		logger.Error("error getting repositories", "err", err)
+		return
+	}
+
+	// Divide the work up among the number of CPUs.
+	wq := sync.NewWorkPool(ctx, runtime.GOMAXPROCS(0),
+		sync.WithWorkPoolLogger(logger.Errorf),
+	)
+
+	now := time.Now()
+	logger.Debug("updating mirror repos")
+	for _, repo := range repos {
+		if !repo.IsMirror() {
+			continue
+		}
+
+		name := repo.Name()
+		s, err := b.MirrorSync(ctx, name)
 		if err != nil {
-			logger.Error("error getting repositories", "err", err)
-			return
+			logger.Error("error getting mirror sync status", "repo", name, "err", err)
+			continue
 		}
 
-		// Divide the work up among the number of CPUs.
-		wq := sync.NewWorkPool(ctx, runtime.GOMAXPROCS(0),
-			sync.WithWorkPoolLogger(logger.Errorf),
-		)
-
-		logger.Debug("updating mirror repos")
-		for _, repo := range repos {
-			if repo.IsMirror() {
-				r, err := repo.Open()
-				if err != nil {
-					logger.Error("error opening repository", "repo", repo.Name(), "err", err)
-					continue
-				}
-
-				name := repo.Name()
-				wq.Add(name, func() {
-					repo := repo
-
-					// Re-validate every configured remote before syncing.
-					// `remote update` touches all of them, and a remote may
-					// predate the import-time guard or have been written out
-					// of band. Validation failure skips the repo entirely.
-					remoteEnv, err := validateMirrorRemotes(r)
-					if err != nil {
-						logger.Warn("skipping mirror sync, remote failed validation", "repo", name, "err", err)
-						return
-					}
-
-					// remoteEnv carries the SSRF guard and must reach every
-					// command below, so build the full set once.
-					syncEnv := append(remoteEnv,
-						fmt.Sprintf(`GIT_SSH_COMMAND=ssh -o UserKnownHostsFile="%s" -o StrictHostKeyChecking=no -i "%s"`,
-							filepath.Join(cfg.DataPath, "ssh", "known_hosts"),
-							cfg.SSH.ClientKeyPath,
-						),
-					)
-
-					cmds := []string{
-						"fetch --prune",         // fetch prune before updating remote
-						"remote update --prune", // update remote and prune remote refs
-					}
-
-					for _, c := range cmds {
-						args := strings.Split(c, " ")
-						cmd := git.NewCommand(args...).WithContext(ctx).WithTimeout(-1)
-						cmd.AddEnvs(syncEnv...)
-
-						if _, err := cmd.RunInDir(r.Path); err != nil {
-							logger.Error("error running git remote update", "repo", name, "err", err)
-						}
-					}
-
-					if cfg.LFS.Enabled {
-						rcfg, err := r.Config()
-						if err != nil {
-							logger.Error("error getting git config", "repo", name, "err", err)
-							return
-						}
-
-						lfsEndpoint := rcfg.Section("lfs").Option("url")
-						if lfsEndpoint == "" {
-							// If there is no LFS url defined, means the repo
-							// doesn't use LFS and we can skip it.
-							return
-						}
-
-						// The endpoint is stored in the repo config and may
-						// predate validation, so check it before dialing.
-						if _, err := ssrf.ValidateGitRemote(lfsEndpoint); err != nil {
-							logger.Warn("skipping lfs sync, endpoint failed validation", "repo", name, "err", err)
-							return
-						}
-
-						ep, err := lfs.NewEndpoint(lfsEndpoint)
-						if err != nil {
-							logger.Error("error creating LFS endpoint", "repo", name, "err", err)
-							return
-						}
-
-						client := lfs.NewClient(ep)
-						if client == nil {
-							logger.Errorf("failed to create lfs client: unsupported endpoint %s", lfsEndpoint)
-							return
-						}
-
-						if err := backend.StoreRepoMissingLFSObjects(ctx, repo, dbx, datastore, client); err != nil {
-							logger.Error("failed to store missing lfs objects", "err", err, "path", r.Path)
-							return
-						}
-					}
-				})
-			}
+		if !due(s, now) {
+			continue
 		}
 
-		wq.Run()
+		wq.Add(name, func() {
+			// Failures are recorded on the mirror and logged by the
+			// backend.
+			if err := b.SyncMirror(ctx, name); errors.Is(err, task.ErrAlreadyStarted) {
+				logger.Debug("mirror sync already in progress", "repo", name)
+			}
+		})
 	}
+
+	wq.Run()
 }
@@ -0,0 +1,59 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"runtime"
+
+	"charm.land/log/v2"
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/charmbracelet/soft-serve/pkg/config"
+	"github.com/charmbracelet/soft-serve/pkg/sync"
+	"github.com/charmbracelet/soft-serve/pkg/task"
+)
+
+func init() {
+	Register("mirror-push", mirrorPush{})
+}
+
+type mirrorPush struct{}
+
+// Spec derives the spec used for push mirrors and implements Runner.
+func (m mirrorPush) Spec(ctx context.Context) string {
+	cfg := config.FromContext(ctx)
+	if cfg.Jobs.MirrorPush != "" {
+		return cfg.Jobs.MirrorPush
+	}
+	return "@every 1h"
+}
+
+// Func runs the push mirror job task and implements Runner.
+func (m mirrorPush) Func(ctx context.Context) func() {
+	logger := log.FromContext(ctx).WithPrefix("jobs.mirror-push")
+	b := backend.FromContext(ctx)
+	return func() {
+		repos, err := b.Repositories(ctx)
+		if err != nil {
+			logger.Error("error getting repositories", "err", err)
+			return
+		}
+
+		wq := sync.NewWorkPool(ctx, runtime.GOMAXPROCS(0),
+			sync.WithWorkPoolLogger(logger.Errorf),
+		)
+
+		logger.Debug("pushing to push mirrors")
+		for _, repo := range repos {
+			name := repo.Name()
+			wq.Add(name, func() {
+				// Failures are recorded on the mirrors and logged by the
+				// backend.
+				if err := b.SyncPushMirrors(ctx, name); errors.Is(err, task.ErrAlreadyStarted) {
+					logger.Debug("push mirror sync already in progress", "repo", name)
+				}
+			})
+		}
+
+		wq.Run()
+	}
+}
@@ -0,0 +1,59 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"runtime"
+
+	"charm.land/log/v2"
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/charmbracelet/soft-serve/pkg/config"
+	"github.com/charmbracelet/soft-serve/pkg/sync"
+	"github.com/charmbracelet/soft-serve/pkg/task"
+)
+
+func init() {
+	Register("mirror-push-queue", mirrorPushQueue{})
+}
+
+type mirrorPushQueue struct{}
+
+// Spec derives the spec used for queued push mirror syncs and implements
+// Runner.
+func (m mirrorPushQueue) Spec(ctx context.Context) string {
+	cfg := config.FromContext(ctx)
+	if cfg.Jobs.MirrorPushQueue != "" {
+		return cfg.Jobs.MirrorPushQueue
+	}
+	return "@every 10s"
+}
+
+// Func pushes the repositories queued by the post-update hook to their push
+// mirrors and implements Runner.
+func (m mirrorPushQueue) Func(ctx context.Context) func() {
+	logger := log.FromContext(ctx).WithPrefix("jobs.mirror-push-queue")
+	b := backend.FromContext(ctx)
+	return func() {
+		repos, err := b.QueuedPushMirrorSyncs(ctx)
+		if err != nil {
+			logger.Error("error getting queued push mirror syncs", "err", err)
+			return
+		}
+
+		wq := sync.NewWorkPool(ctx, runtime.GOMAXPROCS(0),
+			sync.WithWorkPoolLogger(logger.Errorf),
+		)
+
+		for _, name := range repos {
+			wq.Add(name, func() {
+				// Failures are recorded on the mirrors and logged by the
+				// backend.
+				if err := b.SyncPushMirrors(ctx, name); errors.Is(err, task.ErrAlreadyStarted) {
+					logger.Debug("push mirror sync already in progress", "repo", name)
+				}
+			})
+		}
+
+		wq.Run()
+	}
+}
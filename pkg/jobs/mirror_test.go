@@ -1,123 +0,0 @@
-package jobs
-
-import (
-	"errors"
-	"os/exec"
-	"path/filepath"
-	"slices"
-	"testing"
-
-	"github.com/charmbracelet/soft-serve/git"
-	"github.com/charmbracelet/soft-serve/pkg/ssrf"
-)
-
-// newRepoWithRemotes creates a bare repo with the given name=url remotes.
-func newRepoWithRemotes(t *testing.T, remotes map[string]string) *git.Repository {
-	t.Helper()
-
-	path := filepath.Join(t.TempDir(), "repo.git")
-	if _, err := git.Init(path, true); err != nil {
-		t.Fatalf("init: %v", err)
-	}
-
-	for name, url := range remotes {
-		cmd := exec.CommandContext(t.Context(), "git", "remote", "add", name, url)
-		cmd.Dir = path
-		if out, err := cmd.CombinedOutput(); err != nil {
-			t.Fatalf("adding remote %s: %v: %s", name, err, out)
-		}
-	}
-
-	r, err := git.Open(path)
-	if err != nil {
-		t.Fatalf("open: %v", err)
-	}
-	return r
-}
-
-func TestValidateMirrorRemotes(t *testing.T) {
-	tests := []struct {
-		name    string
-		remotes map[string]string
-		wantErr error
-	}{
-		{
-			name:    "public origin",
-			remotes: map[string]string{"origin": "https://1.1.1.1/x.git"},
-		},
-		{
-			name:    "ssh origin",
-			remotes: map[string]string{"origin": "ssh://git@10.0.0.1/x.git"},
-		},
-		{
-			name:    "private origin",
-			remotes: map[string]string{"origin": "http://127.0.0.1:8080/x.git"},
-			wantErr: ssrf.ErrPrivateIP,
-		},
-		{
-			name:    "metadata origin",
-			remotes: map[string]string{"origin": "http://169.254.169.254/x.git"},
-			wantErr: ssrf.ErrPrivateIP,
-		},
-		{
-			// `git remote update` fetches from every remote, not just
-			// origin. A guard that only reads origin misses this entirely.
-			name: "private non-origin remote",
-			remotes: map[string]string{
-				"origin": "https://1.1.1.1/x.git",
-				"backup": "http://192.168.1.1/x.git",
-			},
-			wantErr: ssrf.ErrPrivateIP,
-		},
-		{
-			// git:// is a raw TCP connect and is just as usable for SSRF.
-			name:    "private git scheme",
-			remotes: map[string]string{"origin": "git://10.0.0.1/x.git"},
-			wantErr: ssrf.ErrPrivateIP,
-		},
-		{
-			// Go and libcurl disagree on non-canonical IPv4 literals.
-			name:    "octal loopback",
-			remotes: map[string]string{"origin": "http://0177.0.0.1/x.git"},
-			wantErr: ssrf.ErrAmbiguousHost,
-		},
-		{
-			// A remote that cannot be parsed must not be fetched from.
-			// Failing open here was how unvalidated remotes kept firing.
-			name:    "unparseable remote",
-			remotes: map[string]string{"origin": "http://[::1/x.git"},
-			wantErr: ssrf.ErrInvalidURL,
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			r := newRepoWithRemotes(t, tt.remotes)
-			env, err := validateMirrorRemotes(r)
-
-			if tt.wantErr != nil {
-				if !errors.Is(err, tt.wantErr) {
-					t.Fatalf("validateMirrorRemotes() error = %v, want %v", err, tt.wantErr)
-				}
-				return
-			}
-
-			if err != nil {
-				t.Fatalf("validateMirrorRemotes() unexpected error: %v", err)
-			}
-			if !slices.Contains(env, "GIT_CONFIG_KEY_0=http.followRedirects") ||
-				!slices.Contains(env, "GIT_CONFIG_VALUE_0=false") {
-				t.Errorf("sync env did not disable redirects: %v", env)
-			}
-		})
-	}
-}
-
-// TestValidateMirrorRemotesNoRemote verifies a mirror with no remote URL is
-// skipped rather than treated as valid.
-func TestValidateMirrorRemotesNoRemote(t *testing.T) {
-	r := newRepoWithRemotes(t, nil)
-	if _, err := validateMirrorRemotes(r); err == nil {
-		t.Error("validateMirrorRemotes() accepted a repo with no remote")
-	}
-}
@@ -0,0 +1,74 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	gosync "sync"
+
+	"charm.land/log/v2"
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/charmbracelet/soft-serve/pkg/config"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/sync"
+	"github.com/charmbracelet/soft-serve/pkg/task"
+)
+
+func init() {
+	Register("repo-maintenance", &repoMaintenance{})
+}
+
+type repoMaintenance struct {
+	// running guards against overlapping runs when a run outlasts the
+	// schedule interval.
+	running gosync.Mutex
+}
+
+// Spec derives the spec used for repository maintenance and implements
+// Runner.
+func (m *repoMaintenance) Spec(ctx context.Context) string {
+	cfg := config.FromContext(ctx)
+	if cfg.Jobs.RepoMaintenance != "" {
+		return cfg.Jobs.RepoMaintenance
+	}
+	return "@every 24h"
+}
+
+// Func runs the repository maintenance job task and implements Runner.
+func (m *repoMaintenance) Func(ctx context.Context) func() {
+	logger := log.FromContext(ctx).WithPrefix("jobs.repo-maintenance")
+	b := backend.FromContext(ctx)
+	return func() {
+		if !m.running.TryLock() {
+			logger.Debug("previous repository maintenance run still in progress")
+			return
+		}
+		defer m.running.Unlock()
+
+		repos, err := b.Repositories(ctx)
+		if err != nil {
+			logger.Error("error getting repositories", "err", err)
+			return
+		}
+
+		wq := sync.NewWorkPool(ctx, runtime.GOMAXPROCS(0),
+			sync.WithWorkPoolLogger(logger.Errorf),
+		)
+
+		logger.Debug("maintaining repositories")
+		for _, repo := range repos {
+			name := repo.Name()
+			wq.Add(name, func() {
+				// Repositories being pushed to or maintained on demand are
+				// picked up by the next run. Failures are logged by the
+				// backend.
+				_, err := b.MaintainRepository(ctx, name, false)
+				if errors.Is(err, proto.ErrRepoPushInProgress) || errors.Is(err, task.ErrAlreadyStarted) {
+					logger.Debug("skipping repository maintenance", "repo", name, "err", err)
+				}
+			})
+		}
+
+		wq.Run()
+	}
+}
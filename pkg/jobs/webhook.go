@@ -0,0 +1,80 @@
+package jobs
+
+import (
+	"context"
+	"runtime"
+	gosync "sync"
+	"time"
+
+	"charm.land/log/v2"
+	"github.com/charmbracelet/soft-serve/pkg/config"
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/store"
+	"github.com/charmbracelet/soft-serve/pkg/sync"
+	"github.com/charmbracelet/soft-serve/pkg/webhook"
+)
+
+func init() {
+	Register("webhook-deliveries", &webhookDeliveries{})
+}
+
+type webhookDeliveries struct {
+	// running guards against overlapping runs sending the same delivery
+	// twice when a run outlasts the schedule interval.
+	running gosync.Mutex
+}
+
+// Spec derives the spec used for webhook deliveries and implements Runner.
+func (w *webhookDeliveries) Spec(ctx context.Context) string {
+	cfg := config.FromContext(ctx)
+	if cfg.Jobs.WebhookDeliveries != "" {
+		return cfg.Jobs.WebhookDeliveries
+	}
+	return "@every 10s"
+}
+
+// Func sends the due webhook deliveries and implements Runner.
+func (w *webhookDeliveries) Func(ctx context.Context) func() {
+	logger := log.FromContext(ctx).WithPrefix("jobs.webhook")
+	dbx := db.FromContext(ctx)
+	datastore := store.FromContext(ctx)
+	return func() {
+		if !w.running.TryLock() {
+			logger.Debug("previous webhook delivery run still in progress")
+			return
+		}
+		defer w.running.Unlock()
+
+		// Deliveries are never retried once their webhook is gone or
+		// points somewhere else.
+		if err := datastore.FailStaleWebhookDeliveries(ctx, dbx); err != nil {
+			logger.Error("error failing stale webhook deliveries", "err", db.WrapError(err))
+		}
+
+		now := time.Now().UTC()
+		pending, err := datastore.GetPendingWebhookDeliveries(ctx, dbx, now)
+		if err != nil {
+			logger.Error("error getting pending webhook deliveries", "err", db.WrapError(err))
+			return
+		}
+
+		wq := sync.NewWorkPool(ctx, runtime.GOMAXPROCS(0),
+			sync.WithWorkPoolLogger(logger.Errorf),
+		)
+
+		for _, p := range pending {
+			d := webhook.NewDelivery(p)
+			if !d.IsDue(now) {
+				continue
+			}
+
+			wq.Add(d.ID.String(), func() {
+				if err := webhook.Deliver(ctx, d); err != nil {
+					logger.Warn("webhook delivery attempt failed", "delivery", d.ID, "webhook", d.WebhookID, "attempt", d.Attempts+1, "err", err)
+				}
+			})
+		}
+
+		wq.Run()
+	}
+}
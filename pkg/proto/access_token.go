@@ -1,6 +1,52 @@
 package proto
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
+
+// AccessTokenScope is a permission an access token grants.
+type AccessTokenScope string
+
+const (
+	// AccessTokenScopeRead allows reading repositories.
+	AccessTokenScopeRead AccessTokenScope = "read"
+	// AccessTokenScopeWrite allows reading and pushing to repositories.
+	AccessTokenScopeWrite AccessTokenScope = "write"
+	// AccessTokenScopeAdmin allows administering repositories, the server if
+	// the user is an admin, and managing the user's access tokens.
+	AccessTokenScopeAdmin AccessTokenScope = "admin"
+	// AccessTokenScopeLFS allows Git LFS transfers, within the access the
+	// other scopes grant.
+	AccessTokenScopeLFS AccessTokenScope = "lfs"
+)
+
+// AccessTokenScopes are all the access token scopes.
+var AccessTokenScopes = []AccessTokenScope{
+	AccessTokenScopeRead,
+	AccessTokenScopeWrite,
+	AccessTokenScopeAdmin,
+	AccessTokenScopeLFS,
+}
+
+// ParseAccessTokenScope parses an access token scope.
+func ParseAccessTokenScope(s string) (AccessTokenScope, error) {
+	for _, scope := range AccessTokenScopes {
+		if string(scope) == s {
+			return scope, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: %q", ErrInvalidTokenScope, s)
+}
+
+// AccessTokenOptions are options for creating an access token.
+type AccessTokenOptions struct {
+	// Scopes are the permissions of the token, all of them if empty.
+	Scopes []AccessTokenScope
+	// Repos restricts the token to these repositories.
+	Repos []string
+}
 
 // AccessToken represents an access token.
 type AccessToken struct {
@@ -10,4 +56,61 @@ type AccessToken struct {
 	TokenHash string
 	ExpiresAt time.Time
 	CreatedAt time.Time
+	// Scopes are the permissions of the token, all of them if empty.
+	Scopes []AccessTokenScope
+	// Repos are the repositories the token is restricted to, any repository
+	// if empty.
+	Repos []string
+	// LastUsedAt is when the token last authenticated, zero if never.
+	LastUsedAt time.Time
+	// LastUsedAddr is the address the token last authenticated from.
+	LastUsedAddr string
+	// UseCount is the number of times the token authenticated.
+	UseCount int64
+}
+
+// LastActiveAt returns when the token was last used, or created if it never
+// was.
+func (t AccessToken) LastActiveAt() time.Time {
+	if t.LastUsedAt.IsZero() {
+		return t.CreatedAt
+	}
+
+	return t.LastUsedAt
+}
+
+// HasScope returns whether the token grants a scope.
+func (t AccessToken) HasScope(scope AccessTokenScope) bool {
+	if len(t.Scopes) == 0 {
+		return true
+	}
+
+	for _, s := range t.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AllowsRepository returns whether the token may be used with a repository.
+func (t AccessToken) AllowsRepository(repo string) bool {
+	if len(t.Repos) == 0 {
+		return true
+	}
+
+	for _, r := range t.Repos {
+		if r == repo {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsRestricted returns whether the token grants less than the full power of
+// its user.
+func (t AccessToken) IsRestricted() bool {
+	return len(t.Scopes) > 0 || len(t.Repos) > 0
 }
@@ -21,11 +21,81 @@ var (
 	ErrTokenNotFound = errors.New("token not found")
 	// ErrTokenExpired is returned when a token is expired.
 	ErrTokenExpired = errors.New("token expired")
+	// ErrInvalidTokenScope is returned when an access token scope is invalid.
+	ErrInvalidTokenScope = errors.New("invalid token scope")
+	// ErrTokenScope is returned when an access token lacks the scope an action requires.
+	ErrTokenScope = errors.New("access token lacks the required scope")
 	// ErrCollaboratorNotFound is returned when a collaborator is not found.
 	ErrCollaboratorNotFound = errors.New("collaborator not found")
 	// ErrCollaboratorExist is returned when a collaborator already exists.
 	ErrCollaboratorExist = errors.New("collaborator already exists")
+	// ErrDeployKeyNotFound is returned when a deploy key is not found.
+	ErrDeployKeyNotFound = errors.New("deploy key not found")
+	// ErrDeployKeyExist is returned when a deploy key already exists.
+	ErrDeployKeyExist = errors.New("deploy key already exists")
+	// ErrInvalidDeployKeyAccess is returned when a deploy key is given an access level other than read-only or read-write.
+	ErrInvalidDeployKeyAccess = errors.New("deploy keys can only be read-only or read-write")
+	// ErrPublicKeyInUse is returned when a public key is already used by a user or as a deploy key.
+	ErrPublicKeyInUse = errors.New("public key is already in use")
 	// ErrExceedsAccessLevel is returned when an action would grant or revoke
 	// access above the caller's own access level.
 	ErrExceedsAccessLevel = errors.New("cannot change access above your own access level")
+	// ErrTeamNotFound is returned when a team is not found.
+	ErrTeamNotFound = errors.New("team not found")
+	// ErrTeamExist is returned when a team already exists.
+	ErrTeamExist = errors.New("team already exists")
+	// ErrTeamMemberNotFound is returned when a user is not a member of a team.
+	ErrTeamMemberNotFound = errors.New("team member not found")
+	// ErrTeamMemberExist is returned when a user is already a member of a team.
+	ErrTeamMemberExist = errors.New("team member already exists")
+	// ErrTeamAccessNotFound is returned when a team has no grant on a repository.
+	ErrTeamAccessNotFound = errors.New("team has no access to repository")
+	// ErrBranchProtectionNotFound is returned when a branch protection rule is not found.
+	ErrBranchProtectionNotFound = errors.New("branch protection rule not found")
+	// ErrInvalidBranchPattern is returned when a branch protection pattern is invalid.
+	ErrInvalidBranchPattern = errors.New("invalid branch pattern")
+	// ErrProtectedBranch is returned when a push violates a branch protection rule.
+	ErrProtectedBranch = errors.New("push rejected by branch protection rules")
+	// ErrInvalidPushPolicy is returned when a push policy rule is invalid.
+	ErrInvalidPushPolicy = errors.New("invalid push policy")
+	// ErrPushPolicy is returned when a push violates a push policy rule.
+	ErrPushPolicy = errors.New("push rejected by push policy")
+	// ErrPullRequestNotFound is returned when a pull request is not found.
+	ErrPullRequestNotFound = errors.New("pull request not found")
+	// ErrPullRequestNotOpen is returned when acting on a merged or closed pull request.
+	ErrPullRequestNotOpen = errors.New("pull request is not open")
+	// ErrPullRequestSameRef is returned when a pull request would merge a branch into itself.
+	ErrPullRequestSameRef = errors.New("source and target of a pull request must differ")
+	// ErrPullRequestConflict is returned when a pull request cannot be merged cleanly.
+	ErrPullRequestConflict = errors.New("pull request has merge conflicts")
+	// ErrInvalidPullRequestRef is returned when a push to a pull request ref is rejected.
+	ErrInvalidPullRequestRef = errors.New("push rejected: invalid pull request ref")
+	// ErrEmptyComment is returned when a comment has no body.
+	ErrEmptyComment = errors.New("comment cannot be empty")
+	// ErrIssueNotFound is returned when an issue is not found.
+	ErrIssueNotFound = errors.New("issue not found")
+	// ErrIssueNotOpen is returned when closing an issue that is already closed.
+	ErrIssueNotOpen = errors.New("issue is not open")
+	// ErrIssueNotClosed is returned when reopening an issue that is open.
+	ErrIssueNotClosed = errors.New("issue is not closed")
+	// ErrEmptyTitle is returned when an issue has no title.
+	ErrEmptyTitle = errors.New("title cannot be empty")
+	// ErrInvalidArchiveFormat is returned when an archive format is not supported.
+	ErrInvalidArchiveFormat = errors.New("invalid archive format")
+	// ErrInvalidMirrorCredentials is returned when mirror credentials are invalid.
+	ErrInvalidMirrorCredentials = errors.New("invalid mirror credentials")
+	// ErrRepoPushInProgress is returned when an operation can't run while a repository receives a push.
+	ErrRepoPushInProgress = errors.New("repository has a push in progress")
+	// ErrRepoNotMirror is returned when a mirror operation targets a repository that isn't a mirror.
+	ErrRepoNotMirror = errors.New("repository is not a mirror")
+	// ErrInvalidMirrorSyncInterval is returned when a mirror sync interval is invalid.
+	ErrInvalidMirrorSyncInterval = errors.New("invalid mirror sync interval")
+	// ErrPushMirrorExist is returned when a push mirror already exists.
+	ErrPushMirrorExist = errors.New("push mirror already exists")
+	// ErrPushMirrorNotFound is returned when a push mirror is not found.
+	ErrPushMirrorNotFound = errors.New("push mirror not found")
+	// ErrQuotaExceeded is returned when a push or upload would exceed a storage quota.
+	ErrQuotaExceeded = errors.New("storage quota exceeded")
+	// ErrMergeCustomHooks is returned when merging a pull request on the server while custom hooks could reject it.
+	ErrMergeCustomHooks = errors.New("pull requests can't be merged on the server while custom pre-receive or update hooks are configured, merge and push with git instead")
 )
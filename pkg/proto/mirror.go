@@ -0,0 +1,24 @@
+package proto
+
+// MirrorCredentialsType is the kind of credentials a mirror fetches with.
+type MirrorCredentialsType string
+
+const (
+	// MirrorCredentialsBasic authenticates HTTP remotes with a username and
+	// password.
+	MirrorCredentialsBasic MirrorCredentialsType = "basic"
+	// MirrorCredentialsToken authenticates HTTP remotes with a bearer token.
+	MirrorCredentialsToken MirrorCredentialsType = "token"
+	// MirrorCredentialsSSHKey authenticates SSH remotes with a private key.
+	MirrorCredentialsSSHKey MirrorCredentialsType = "ssh-key"
+)
+
+// MirrorCredentials are the credentials a mirror uses to fetch from a
+// private remote.
+type MirrorCredentials struct {
+	Type MirrorCredentialsType
+	// Username is the HTTP basic auth username.
+	Username string
+	// Secret is the password, the token, or the PEM encoded SSH private key.
+	Secret string
+}
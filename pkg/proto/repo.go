@@ -43,6 +43,9 @@ type RepositoryOptions struct {
 	Hidden      bool
 	LFS         bool
 	LFSEndpoint string
+	// MirrorCredentials are used to import from a private remote, and are
+	// kept for mirrors to sync with.
+	MirrorCredentials *MirrorCredentials
 }
 
 // RepositoryDefaultBranch returns the default branch of a repository.
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/spf13/cobra"
+)
+
+// archiveCommand returns a command that writes an archive of a repository.
+func archiveCommand() *cobra.Command {
+	var format string
+	var prefix string
+	var smudge bool
+	cmd := &cobra.Command{
+		Use:   "archive REPOSITORY [REFERENCE]",
+		Short: "Write an archive of a repository to stdout",
+		Long: `Write an archive of the files of a repository at a reference, HEAD by default, to stdout.
+Use --lfs to replace Git LFS pointer files with their objects.`,
+		Args:              cobra.RangeArgs(1, 2),
+		PersistentPreRunE: checkIfReadable,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			var ref string
+			if len(args) > 1 {
+				ref = args[1]
+			}
+
+			return be.ArchiveRepository(ctx, cmd.OutOrStdout(), repoArg(args), ref, backend.ArchiveOptions{
+				Format: format,
+				Prefix: prefix,
+				LFS:    smudge,
+			})
+		},
+	}
+
+	cmd.Flags().StringVarP(&format, "format", "f", git.ArchiveTarGz, "archive format, tar.gz or zip")
+	cmd.Flags().StringVar(&prefix, "prefix", "", "prefix to prepend to every path, e.g. project/")
+	cmd.Flags().BoolVar(&smudge, "lfs", false, "replace Git LFS pointers with their objects")
+
+	return cmd
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"charm.land/lipgloss/v2/table"
+	"github.com/caarlos0/duration"
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/charmbracelet/soft-serve/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+// AuditCommand returns a command that shows the audit log.
+func AuditCommand() *cobra.Command {
+	var f backend.AuditFilter
+	var since string
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Show the audit log",
+		Long: `Show the audit log of administrative and security related actions, most recent first.
+An action also matches the actions it namespaces, "repo" matches "repo.delete".`,
+		Args:              cobra.NoArgs,
+		PersistentPreRunE: checkIfServerAdmin,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+
+			if since != "" {
+				d, err := duration.Parse(since)
+				if err != nil {
+					return fmt.Errorf("invalid since: %w", err)
+				}
+
+				f.Since = time.Now().Add(-d)
+			}
+
+			events, err := be.AuditEvents(ctx, f)
+			if err != nil {
+				return err
+			}
+
+			if len(events) == 0 {
+				cmd.Println("No events found")
+				return nil
+			}
+
+			table := table.New().Headers("Time", "Actor", "Action", "Target", "Source", "Details")
+			for _, e := range events {
+				actor := e.Actor
+				if actor == "" {
+					actor = "-"
+				}
+
+				source := e.Transport
+				if e.Addr != "" {
+					source += " " + e.Addr
+				}
+
+				table = table.Row(e.CreatedAt.UTC().Format(time.RFC3339),
+					sanitizeAuditField(actor),
+					string(e.Action),
+					sanitizeAuditField(e.Target),
+					sanitizeAuditField(source),
+					sanitizeAuditField(e.Details),
+				)
+			}
+			cmd.Println(table)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&f.Actor, "actor", "", "only show actions taken by this user")
+	cmd.Flags().StringVar(&f.Action, "action", "", "only show this action, e.g. repo.delete or repo")
+	cmd.Flags().StringVar(&f.Target, "target", "", "only show actions on this repository, user or team")
+	cmd.Flags().StringVar(&since, "since", "", "only show actions newer than this (e.g. 1d, 2w, 1h30m)")
+	cmd.Flags().IntVarP(&f.Limit, "limit", "n", 50, "maximum number of events, 0 for no limit")
+
+	return cmd
+}
+
+// sanitizeAuditField strips escape sequences and control characters from an
+// audit log field, since targets and details can hold user input.
+func sanitizeAuditField(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return ' '
+		}
+		return r
+	}, utils.Sanitize(s))
+}
@@ -0,0 +1,17 @@
+package cmd
+
+import "testing"
+
+func TestSanitizeAuditField(t *testing.T) {
+	for in, want := range map[string]string{
+		"repo1":                         "repo1",
+		"\x1b[31mred\x1b[0m":            "red",
+		"fake\r2024-01-01 admin":        "fake 2024-01-01 admin",
+		"a\x1b]0;title\x07b\nc\bd":      "ab c d",
+		"url=https://example.com/x.git": "url=https://example.com/x.git",
+	} {
+		if got := sanitizeAuditField(in); got != want {
+			t.Errorf("sanitizeAuditField(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 	gitm "github.com/aymanbagabas/git-module"
 	"github.com/charmbracelet/soft-serve/git"
 	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/charmbracelet/soft-serve/pkg/hooks"
 	"github.com/charmbracelet/soft-serve/pkg/proto"
 	"github.com/charmbracelet/soft-serve/pkg/webhook"
 	"github.com/spf13/cobra"
@@ -22,6 +23,7 @@ func branchCommand() *cobra.Command {
 		branchListCommand(),
 		branchDefaultCommand(),
 		branchDeleteCommand(),
+		branchProtectCommand(),
 	)
 
 	return cmd
@@ -190,6 +192,23 @@ func branchDeleteCommand() *cobra.Command {
 				return err
 			}
 
+			// Deleting a branch here skips the git hooks, so apply the
+			// branch protection rules the pre-receive hook would.
+			violations, err := be.BranchProtectionViolations(ctx, rr, proto.UserFromContext(ctx), []hooks.HookArg{
+				{
+					OldSha:  branchCommit.ID.String(),
+					NewSha:  git.ZeroID,
+					RefName: git.RefsHeads + branch,
+				},
+			})
+			if err != nil {
+				return err
+			}
+
+			if len(violations) > 0 {
+				return fmt.Errorf("%w: %s", proto.ErrProtectedBranch, violations[0])
+			}
+
 			if err := r.DeleteBranch(branch, gitm.DeleteBranchOptions{Force: true}); err != nil {
 				return err
 			}
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"strconv"
+	"strings"
+
+	"charm.land/lipgloss/v2/table"
+	"github.com/charmbracelet/soft-serve/pkg/access"
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/spf13/cobra"
+)
+
+func branchProtectCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "protect",
+		Aliases: []string{"protection", "protections"},
+		Short:   "Manage protected branches",
+	}
+
+	cmd.AddCommand(
+		branchProtectSetCommand(),
+		branchProtectListCommand(),
+		branchProtectRemoveCommand(),
+	)
+
+	return cmd
+}
+
+func branchProtectSetCommand() *cobra.Command {
+	var allowForcePush bool
+	var allowDeletion bool
+	var level string
+	var users []string
+	cmd := &cobra.Command{
+		Use:   "set REPOSITORY PATTERN",
+		Short: "Protect branches matching a pattern",
+		Long: `Protect branches matching a pattern. PATTERN is a branch name or a glob such as "release/*".
+Protected branches reject force pushes and deletion unless allowed. LEVEL can be one of: read-write or admin-access.`,
+		Args:              cobra.ExactArgs(2),
+		PersistentPreRunE: checkIfRepoAdmin,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			repo := repoArg(args)
+
+			minLevel := access.ParseAccessLevel(level)
+			if minLevel < access.ReadWriteAccess {
+				return access.ErrInvalidAccessLevel
+			}
+
+			return be.SetBranchProtection(ctx, repo, backend.BranchProtection{
+				Pattern:        args[1],
+				BlockForcePush: !allowForcePush,
+				BlockDeletion:  !allowDeletion,
+				MinAccessLevel: minLevel,
+				Users:          users,
+			})
+		},
+	}
+
+	cmd.Flags().BoolVar(&allowForcePush, "allow-force-push", false, "allow force pushes to matching branches")
+	cmd.Flags().BoolVar(&allowDeletion, "allow-deletion", false, "allow deleting matching branches")
+	cmd.Flags().StringVarP(&level, "level", "l", access.ReadWriteAccess.String(), "minimum access level required to push")
+	cmd.Flags().StringSliceVarP(&users, "users", "u", nil, "only allow these users to push")
+
+	return cmd
+}
+
+func branchProtectListCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "list REPOSITORY",
+		Short:             "List protected branch rules",
+		Args:              cobra.ExactArgs(1),
+		PersistentPreRunE: checkIfReadableAndCollab,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			bps, err := be.BranchProtections(ctx, repoArg(args))
+			if err != nil {
+				return err
+			}
+
+			table := table.New().Headers("Pattern", "Force Push", "Deletion", "Level", "Users")
+			for _, bp := range bps {
+				table = table.Row(
+					bp.Pattern,
+					strconv.FormatBool(!bp.BlockForcePush),
+					strconv.FormatBool(!bp.BlockDeletion),
+					bp.MinAccessLevel.String(),
+					strings.Join(bp.Users, ","),
+				)
+			}
+			cmd.Println(table)
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+func branchProtectRemoveCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "remove REPOSITORY PATTERN",
+		Aliases:           []string{"rm", "delete"},
+		Short:             "Remove a protected branch rule",
+		Args:              cobra.ExactArgs(2),
+		PersistentPreRunE: checkIfRepoAdmin,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			return be.RemoveBranchProtection(ctx, repoArg(args), args[1])
+		},
+	}
+
+	return cmd
+}
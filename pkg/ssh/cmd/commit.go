@@ -55,6 +55,11 @@ func commitCommand() *cobra.Command {
 				return err
 			}
 
+			sig, err := be.VerifyCommit(ctx, r, commit.ID.String())
+			if err != nil {
+				return err
+			}
+
 			commonStyle := styles.DefaultStyles()
 			style := commonStyle.Log
 
@@ -62,6 +67,7 @@ func commitCommand() *cobra.Command {
 			commitLine := "commit " + commitSHA
 			authorLine := "Author: " + utils.Sanitize(commit.Author.Name)
 			dateLine := "Date:   " + commit.Committer.When.UTC().Format(time.UnixDate)
+			sigLine := "Signature: " + sig.String()
 			msgLine := strings.ReplaceAll(utils.Sanitize(commit.Message), "\r\n", "\n")
 			statsLine := renderStats(diff, commonStyle, color)
 			diffLine := renderDiff(patch, color)
@@ -74,20 +80,21 @@ func commitCommand() *cobra.Command {
 			}
 
 			if color {
-				s.WriteString(fmt.Sprintf("%s\n%s\n%s\n%s\n",
-					style.CommitHash.Render(commitLine),
-					style.CommitAuthor.Render(authorLine),
-					style.CommitDate.Render(dateLine),
-					style.CommitBody.Render(msgLine),
-				))
-			} else {
-				s.WriteString(fmt.Sprintf("%s\n%s\n%s\n%s\n",
-					commitLine,
-					authorLine,
-					dateLine,
-					msgLine,
-				))
+				commitLine = style.CommitHash.Render(commitLine)
+				authorLine = style.CommitAuthor.Render(authorLine)
+				dateLine = style.CommitDate.Render(dateLine)
+				sigLine = style.CommitSignature.Render(sigLine)
+				msgLine = style.CommitBody.Render(msgLine)
+			}
+
+			s.WriteString(commitLine + "\n")
+			s.WriteString(authorLine + "\n")
+			s.WriteString(dateLine + "\n")
+			// Only signed commits get a signature line.
+			if sig.IsSigned() {
+				s.WriteString(sigLine + "\n")
 			}
+			s.WriteString(msgLine + "\n")
 
 			s.WriteString(fmt.Sprintf("\n%s\n%s",
 				statsLine,
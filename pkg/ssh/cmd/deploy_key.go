@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"charm.land/lipgloss/v2/table"
+	"github.com/charmbracelet/soft-serve/pkg/access"
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/charmbracelet/soft-serve/pkg/sshutils"
+	"github.com/charmbracelet/soft-serve/pkg/utils"
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+)
+
+func deployKeyCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "deploy-key",
+		Aliases: []string{"deploy-keys"},
+		Short:   "Manage repository deploy keys",
+		Long: `Manage repository deploy keys.
+Deploy keys are SSH public keys attached to a single repository, they give read-only or read-write access to it without a user account.`,
+	}
+
+	cmd.AddCommand(
+		deployKeyAddCommand(),
+		deployKeyListCommand(),
+		deployKeyRemoveCommand(),
+	)
+
+	return cmd
+}
+
+func deployKeyAddCommand() *cobra.Command {
+	var level string
+	cmd := &cobra.Command{
+		Use:               "add REPOSITORY TITLE AUTHORIZED_KEY",
+		Short:             "Add a deploy key to a repository",
+		Args:              cobra.MinimumNArgs(3),
+		PersistentPreRunE: checkIfRepoAdmin,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			al := access.ParseAccessLevel(level)
+			if al < 0 {
+				return access.ErrInvalidAccessLevel
+			}
+
+			pk, _, err := sshutils.ParseAuthorizedKey(strings.Join(args[2:], " "))
+			if err != nil {
+				return err
+			}
+
+			k, err := be.AddDeployKey(ctx, repoArg(args), args[1], pk, al)
+			if err != nil {
+				return err
+			}
+
+			cmd.Printf("Added deploy key %d\n", k.ID)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&level, "access", "a", access.ReadOnlyAccess.String(), "access level of the key, read-only or read-write")
+
+	return cmd
+}
+
+func deployKeyListCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "list REPOSITORY",
+		Aliases:           []string{"ls"},
+		Short:             "List the deploy keys of a repository",
+		Args:              cobra.ExactArgs(1),
+		PersistentPreRunE: checkIfRepoAdmin,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			keys, err := be.DeployKeys(ctx, repoArg(args))
+			if err != nil {
+				return err
+			}
+
+			if len(keys) == 0 {
+				cmd.Println("No deploy keys found")
+				return nil
+			}
+
+			table := table.New().Headers("ID", "Title", "Fingerprint", "Access", "Created At")
+			for _, k := range keys {
+				table = table.Row(
+					strconv.FormatInt(k.ID, 10),
+					utils.Sanitize(k.Title),
+					k.Fingerprint(),
+					k.AccessLevel.String(),
+					humanize.Time(k.CreatedAt),
+				)
+			}
+			cmd.Println(table)
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+func deployKeyRemoveCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "remove REPOSITORY ID",
+		Aliases:           []string{"rm", "delete"},
+		Short:             "Remove a deploy key from a repository",
+		Args:              cobra.ExactArgs(2),
+		PersistentPreRunE: checkIfRepoAdmin,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			id, err := strconv.ParseInt(args[1], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid deploy key id: %s", args[1])
+			}
+
+			return be.RemoveDeployKey(ctx, repoArg(args), id)
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/charmbracelet/soft-serve/pkg/access"
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/charmbracelet/soft-serve/pkg/config"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+// forkCommand is the command for forking a repository.
+func forkCommand() *cobra.Command {
+	var private bool
+	var description string
+	var projectName string
+	var hidden bool
+
+	cmd := &cobra.Command{
+		Use:   "fork SOURCE [REPOSITORY]",
+		Short: "Fork a repository",
+		Long: `Fork a repository into a new repository owned by you.
+
+REPOSITORY defaults to the name of SOURCE under your username, e.g. forking "soft-serve" as "frankie" creates "frankie/soft-serve". Forks of private repositories are private.`,
+		Args:              cobra.RangeArgs(1, 2),
+		PersistentPreRunE: checkIfReadable,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			cfg := config.FromContext(ctx)
+			be := backend.FromContext(ctx)
+			user := proto.UserFromContext(ctx)
+			if user == nil {
+				return proto.ErrUnauthorized
+			}
+
+			source := repoArg(args)
+			name := path.Join(user.Username(), path.Base(utils.SanitizeRepo(source)))
+			if len(args) > 1 {
+				name = args[1]
+			}
+
+			// Forking creates a repository, which needs the same access as
+			// "repo create".
+			if repoAccessLevel(ctx, name) < access.ReadWriteAccess {
+				return proto.ErrUnauthorized
+			}
+
+			r, err := be.ForkRepository(ctx, source, name, user, proto.RepositoryOptions{
+				Private:     private,
+				Description: description,
+				ProjectName: projectName,
+				Hidden:      hidden,
+			})
+			if err != nil {
+				return err
+			}
+
+			cloneurl := fmt.Sprintf("%s/%s.git", cfg.SSH.PublicURL, r.Name())
+			cmd.PrintErrf("Forked repository %s to %s\n", utils.SanitizeRepo(source), r.Name())
+			cmd.Println(cloneurl)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&private, "private", "p", false, "make the repository private")
+	cmd.Flags().StringVarP(&description, "description", "d", "", "set the repository description")
+	cmd.Flags().StringVarP(&projectName, "name", "n", "", "set the project name")
+	cmd.Flags().BoolVarP(&hidden, "hidden", "H", false, "hide the repository from the UI")
+
+	return cmd
+}
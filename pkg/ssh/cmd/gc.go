@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/charmbracelet/soft-serve/pkg/task"
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+)
+
+func gcCommand() *cobra.Command {
+	var auto bool
+	cmd := &cobra.Command{
+		Use:   "gc REPOSITORY",
+		Short: "Run maintenance on a repository",
+		Long: `Run maintenance on a repository.
+This garbage collects and repacks the repository, and writes its commit-graph and multi-pack bitmap, like the repo_maintenance job does on a schedule.`,
+		Args:              cobra.ExactArgs(1),
+		PersistentPreRunE: checkIfRepoAdmin,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			res, err := be.MaintainRepository(ctx, repoArg(args), !auto)
+			if err != nil {
+				if errors.Is(err, task.ErrAlreadyStarted) {
+					return errors.New("maintenance already in progress")
+				}
+
+				return err
+			}
+
+			cmd.Println("Before:", objectCountString(res.Before))
+			cmd.Println("After:", objectCountString(res.After))
+			cmd.Println("Took:", res.Duration.Round(time.Millisecond))
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&auto, "auto", false, "only garbage collect if enough loose objects or packs piled up, like the scheduled job")
+
+	return cmd
+}
+
+// objectCountString describes the object store usage of a repository.
+func objectCountString(c git.ObjectCount) string {
+	return fmt.Sprintf("%s in %d loose objects and %d packs",
+		humanize.IBytes(uint64(c.Size())), //nolint: gosec
+		c.LooseObjects,
+		c.Packs,
+	)
+}
@@ -3,6 +3,7 @@ package cmd
 import (
 	"bytes"
 	"context"
+	"path/filepath"
 	"testing"
 
 	"github.com/charmbracelet/soft-serve/pkg/backend"
@@ -30,6 +31,8 @@ func newAuthTestContext(t *testing.T) (context.Context, *backend.Backend) {
 	dp := t.TempDir()
 	cfg := config.DefaultConfig()
 	cfg.DataPath = dp
+	cfg.SSH.KeyPath = filepath.Join(dp, "ssh", "soft_serve_host_ed25519")
+	cfg.SSH.ClientKeyPath = filepath.Join(dp, "ssh", "soft_serve_client_ed25519")
 	cfg.DB.Driver = "sqlite"
 	cfg.DB.DataSource = dp + "/test.db"
 
@@ -18,6 +18,7 @@ func importCommand() *cobra.Command {
 	var hidden bool
 	var lfs bool
 	var lfsEndpoint string
+	var readCredentials func() (*proto.MirrorCredentials, error)
 
 	cmd := &cobra.Command{
 		Use:               "import REPOSITORY REMOTE",
@@ -30,14 +31,20 @@ func importCommand() *cobra.Command {
 			user := proto.UserFromContext(ctx)
 			name := args[0]
 			remote := args[1]
+			creds, err := readCredentials()
+			if err != nil {
+				return err
+			}
+
 			if _, err := be.ImportRepository(ctx, name, user, remote, proto.RepositoryOptions{
-				Private:     private,
-				Description: description,
-				ProjectName: projectName,
-				Mirror:      mirror,
-				Hidden:      hidden,
-				LFS:         lfs,
-				LFSEndpoint: lfsEndpoint,
+				Private:           private,
+				Description:       description,
+				ProjectName:       projectName,
+				Mirror:            mirror,
+				Hidden:            hidden,
+				LFS:               lfs,
+				LFSEndpoint:       lfsEndpoint,
+				MirrorCredentials: creds,
 			}); err != nil {
 				if errors.Is(err, task.ErrAlreadyStarted) {
 					return errors.New("import already in progress")
@@ -57,6 +64,7 @@ func importCommand() *cobra.Command {
 	cmd.Flags().StringVarP(&description, "description", "d", "", "set the repository description")
 	cmd.Flags().StringVarP(&projectName, "name", "n", "", "set the project name")
 	cmd.Flags().BoolVarP(&hidden, "hidden", "H", false, "hide the repository from the UI")
+	readCredentials = addMirrorCredentialsFlags(cmd)
 
 	return cmd
 }
@@ -0,0 +1,314 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"charm.land/lipgloss/v2/table"
+	"github.com/charmbracelet/soft-serve/pkg/access"
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+func issueCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "issue",
+		Aliases: []string{"issues"},
+		Short:   "Manage issues",
+	}
+
+	cmd.AddCommand(
+		issueCreateCommand(),
+		issueListCommand(),
+		issueShowCommand(),
+		issueCommentCommand(),
+		issueCloseCommand(),
+		issueReopenCommand(),
+		issueLabelCommand(),
+		issueUnlabelCommand(),
+	)
+
+	return cmd
+}
+
+// issueNumberArg parses an issue number argument, with or without a leading
+// "#".
+func issueNumberArg(arg string) (int64, error) {
+	number, err := strconv.ParseInt(strings.TrimPrefix(arg, "#"), 10, 64)
+	if err != nil || number <= 0 {
+		return 0, fmt.Errorf("invalid issue number: %s", arg)
+	}
+
+	return number, nil
+}
+
+// checkIfIssueAuthorOrCollab returns an error unless the user opened the
+// issue or has write access to the repository.
+func checkIfIssueAuthorOrCollab(cmd *cobra.Command, repo string, number int64) error {
+	ctx := cmd.Context()
+	be := backend.FromContext(ctx)
+	user := proto.UserFromContext(ctx)
+	issue, err := be.Issue(ctx, repo, number)
+	if err != nil {
+		return err
+	}
+
+	isAuthor := user != nil && issue.Author != "" && user.Username() == issue.Author
+	if !isAuthor && repoAccessLevel(ctx, repo) < access.ReadWriteAccess {
+		return proto.ErrUnauthorized
+	}
+
+	return nil
+}
+
+func issueCreateCommand() *cobra.Command {
+	var body string
+	var labels []string
+	cmd := &cobra.Command{
+		Use:               "create REPOSITORY TITLE...",
+		Short:             "Open an issue",
+		Args:              cobra.MinimumNArgs(2),
+		PersistentPreRunE: checkIfReadable,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			user := proto.UserFromContext(ctx)
+			if user == nil {
+				return proto.ErrUnauthorized
+			}
+
+			issue, err := be.CreateIssue(ctx, repoArg(args), user, strings.Join(args[1:], " "), body, labels)
+			if err != nil {
+				return err
+			}
+
+			cmd.Printf("Created issue #%d\n", issue.Number)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&body, "body", "b", "", "issue body")
+	cmd.Flags().StringSliceVarP(&labels, "label", "l", nil, "issue labels")
+
+	return cmd
+}
+
+func issueListCommand() *cobra.Command {
+	var state string
+	var label string
+	cmd := &cobra.Command{
+		Use:               "list REPOSITORY",
+		Aliases:           []string{"ls"},
+		Short:             "List issues",
+		Long:              "List issues. STATE can be one of: open, closed, or all.",
+		Args:              cobra.ExactArgs(1),
+		PersistentPreRunE: checkIfReadable,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+
+			var st backend.IssueState
+			if state != "all" {
+				st = backend.ParseIssueState(state)
+				if st == "" {
+					return fmt.Errorf("invalid state: %s", state)
+				}
+			}
+
+			issues, err := be.Issues(ctx, repoArg(args), st, label)
+			if err != nil {
+				return err
+			}
+
+			table := table.New().Headers("#", "Title", "Labels", "Author", "State")
+			for _, issue := range issues {
+				table = table.Row(
+					strconv.FormatInt(issue.Number, 10),
+					utils.Sanitize(issue.Title),
+					strings.Join(issue.Labels, ", "),
+					issue.Author,
+					string(issue.State),
+				)
+			}
+			cmd.Println(table)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&state, "state", "s", string(backend.IssueStateOpen), "only list issues in this state")
+	cmd.Flags().StringVarP(&label, "label", "l", "", "only list issues with this label")
+
+	return cmd
+}
+
+func issueShowCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "show REPOSITORY NUMBER",
+		Aliases:           []string{"info"},
+		Short:             "Show an issue and its comments",
+		Args:              cobra.ExactArgs(2),
+		PersistentPreRunE: checkIfReadable,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			repo := repoArg(args)
+			number, err := issueNumberArg(args[1])
+			if err != nil {
+				return err
+			}
+
+			issue, err := be.Issue(ctx, repo, number)
+			if err != nil {
+				return err
+			}
+
+			comments, err := be.IssueComments(ctx, repo, number)
+			if err != nil {
+				return err
+			}
+
+			cmd.Printf("#%d %s\n", issue.Number, utils.Sanitize(issue.Title))
+			cmd.Println("State:", issue.State)
+			cmd.Println("Author:", issue.Author)
+			if len(issue.Labels) > 0 {
+				cmd.Println("Labels:", strings.Join(issue.Labels, ", "))
+			}
+			cmd.Println("Created:", issue.CreatedAt.UTC().Format(time.UnixDate))
+			if issue.Body != "" {
+				cmd.Printf("\n%s\n", utils.Sanitize(issue.Body))
+			}
+
+			for _, c := range comments {
+				cmd.Printf("\n%s commented on %s:\n%s\n", c.Author, c.CreatedAt.UTC().Format(time.UnixDate), utils.Sanitize(c.Body))
+			}
+
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+func issueCommentCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "comment REPOSITORY NUMBER COMMENT...",
+		Short:             "Comment on an issue",
+		Args:              cobra.MinimumNArgs(3),
+		PersistentPreRunE: checkIfReadable,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			user := proto.UserFromContext(ctx)
+			if user == nil {
+				return proto.ErrUnauthorized
+			}
+
+			number, err := issueNumberArg(args[1])
+			if err != nil {
+				return err
+			}
+
+			return be.CommentIssue(ctx, repoArg(args), number, user, strings.Join(args[2:], " "))
+		},
+	}
+
+	return cmd
+}
+
+func issueCloseCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "close REPOSITORY NUMBER",
+		Short:             "Close an issue",
+		Args:              cobra.ExactArgs(2),
+		PersistentPreRunE: checkIfReadable,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			repo := repoArg(args)
+			number, err := issueNumberArg(args[1])
+			if err != nil {
+				return err
+			}
+
+			if err := checkIfIssueAuthorOrCollab(cmd, repo, number); err != nil {
+				return err
+			}
+
+			return be.CloseIssue(ctx, repo, number, proto.UserFromContext(ctx))
+		},
+	}
+
+	return cmd
+}
+
+func issueReopenCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "reopen REPOSITORY NUMBER",
+		Short:             "Reopen a closed issue",
+		Args:              cobra.ExactArgs(2),
+		PersistentPreRunE: checkIfReadable,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			repo := repoArg(args)
+			number, err := issueNumberArg(args[1])
+			if err != nil {
+				return err
+			}
+
+			if err := checkIfIssueAuthorOrCollab(cmd, repo, number); err != nil {
+				return err
+			}
+
+			return be.ReopenIssue(ctx, repo, number, proto.UserFromContext(ctx))
+		},
+	}
+
+	return cmd
+}
+
+func issueLabelCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "label REPOSITORY NUMBER LABEL...",
+		Short:             "Add labels to an issue",
+		Args:              cobra.MinimumNArgs(3),
+		PersistentPreRunE: checkIfReadableAndCollab,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			number, err := issueNumberArg(args[1])
+			if err != nil {
+				return err
+			}
+
+			return be.AddIssueLabels(ctx, repoArg(args), number, proto.UserFromContext(ctx), args[2:]...)
+		},
+	}
+
+	return cmd
+}
+
+func issueUnlabelCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "unlabel REPOSITORY NUMBER LABEL...",
+		Short:             "Remove labels from an issue",
+		Args:              cobra.MinimumNArgs(3),
+		PersistentPreRunE: checkIfReadableAndCollab,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			number, err := issueNumberArg(args[1])
+			if err != nil {
+				return err
+			}
+
+			return be.RemoveIssueLabels(ctx, repoArg(args), number, proto.UserFromContext(ctx), args[2:]...)
+		},
+	}
+
+	return cmd
+}
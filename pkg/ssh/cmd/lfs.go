@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"errors"
+
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/charmbracelet/soft-serve/pkg/task"
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+)
+
+func lfsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "lfs",
+		Short: "Manage repository Git LFS objects",
+	}
+
+	cmd.AddCommand(
+		lfsGCCommand(),
+	)
+
+	return cmd
+}
+
+func lfsGCCommand() *cobra.Command {
+	var dryRun bool
+	cmd := &cobra.Command{
+		Use:   "gc REPOSITORY",
+		Short: "Delete Git LFS objects no ref points to",
+		Long: `Delete Git LFS objects no ref points to.
+Objects are kept for the lfs.gc_grace_period after they were uploaded, like the lfs_gc job does on a schedule.`,
+		Args:              cobra.ExactArgs(1),
+		PersistentPreRunE: checkIfRepoAdmin,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			res, err := be.GarbageCollectLFS(ctx, repoArg(args), dryRun)
+			if err != nil {
+				if errors.Is(err, task.ErrAlreadyStarted) {
+					return errors.New("lfs garbage collection already in progress")
+				}
+
+				return err
+			}
+
+			verb := "Deleted"
+			if dryRun {
+				verb = "Would delete"
+				for _, p := range res.Objects {
+					cmd.Println(p.Oid, humanize.IBytes(uint64(p.Size))) //nolint: gosec
+				}
+			}
+
+			cmd.Printf("%s %d objects (%s)\n", verb, len(res.Objects), humanize.IBytes(uint64(res.Size))) //nolint: gosec
+			if res.Kept > 0 {
+				cmd.Printf("Kept %d unreferenced objects within the grace period\n", res.Kept)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "only report the objects that would be deleted")
+
+	return cmd
+}
@@ -1,10 +1,23 @@
 package cmd
 
 import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
 	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/task"
+	"github.com/charmbracelet/soft-serve/pkg/utils"
+	"github.com/dustin/go-humanize"
 	"github.com/spf13/cobra"
 )
 
+// maxMirrorSecretSize is the maximum size of a mirror secret read from stdin.
+const maxMirrorSecretSize = 64 * 1024
+
 func mirrorCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:               "mirror REPOSITORY [true|false]",
@@ -39,5 +52,272 @@ func mirrorCommand() *cobra.Command {
 		},
 	}
 
+	cmd.AddCommand(
+		mirrorCredentialsCommand(),
+		mirrorIntervalCommand(),
+		mirrorStatusCommand(),
+		mirrorSyncCommand(),
+	)
+
+	return cmd
+}
+
+func mirrorCredentialsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "credentials",
+		Aliases: []string{"creds"},
+		Short:   "Manage the credentials a mirror fetches with",
+		Long: `Manage the credentials a mirror fetches with.
+Secrets are encrypted at rest and are never printed back.`,
+	}
+
+	cmd.AddCommand(
+		mirrorCredentialsSetCommand(),
+		mirrorCredentialsShowCommand(),
+		mirrorCredentialsRemoveCommand(),
+	)
+
+	return cmd
+}
+
+// addMirrorCredentialsFlags adds the flags to pass mirror credentials to cmd.
+// The returned function reads the credentials, nil if no flag was set. Secrets
+// are read from stdin so they don't end up in command logs.
+func addMirrorCredentialsFlags(cmd *cobra.Command) func() (*proto.MirrorCredentials, error) {
+	var username string
+	var token bool
+	var sshKey bool
+	cmd.Flags().StringVar(&username, "username", "", "authenticate to HTTP remotes as username, with the password read from stdin")
+	cmd.Flags().BoolVar(&token, "token", false, "authenticate to HTTP remotes with a token read from stdin")
+	cmd.Flags().BoolVar(&sshKey, "ssh-key", false, "authenticate to SSH remotes with a private key read from stdin")
+	cmd.MarkFlagsMutuallyExclusive("username", "token", "ssh-key")
+
+	return func() (*proto.MirrorCredentials, error) {
+		var c proto.MirrorCredentials
+		switch {
+		case username != "":
+			c.Type = proto.MirrorCredentialsBasic
+			c.Username = username
+		case token:
+			c.Type = proto.MirrorCredentialsToken
+		case sshKey:
+			c.Type = proto.MirrorCredentialsSSHKey
+		default:
+			return nil, nil
+		}
+
+		secret, err := io.ReadAll(io.LimitReader(cmd.InOrStdin(), maxMirrorSecretSize+1))
+		if err != nil {
+			return nil, err
+		}
+		if len(secret) > maxMirrorSecretSize {
+			return nil, fmt.Errorf("%w: secret is too large", proto.ErrInvalidMirrorCredentials)
+		}
+
+		c.Secret = strings.TrimSpace(string(secret))
+		if c.Type == proto.MirrorCredentialsSSHKey {
+			c.Secret += "\n"
+		}
+
+		return &c, nil
+	}
+}
+
+func mirrorCredentialsSetCommand() *cobra.Command {
+	var readCredentials func() (*proto.MirrorCredentials, error)
+	cmd := &cobra.Command{
+		Use:   "set REPOSITORY",
+		Short: "Set the credentials of a mirror",
+		Long: `Set the credentials a mirror fetches its remotes with, replacing the current ones.
+The password, token, or private key is read from stdin.`,
+		Args:              cobra.ExactArgs(1),
+		PersistentPreRunE: checkIfRepoAdmin,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			c, err := readCredentials()
+			if err != nil {
+				return err
+			}
+			if c == nil {
+				return errors.New("one of --username, --token, or --ssh-key is required")
+			}
+
+			return be.SetMirrorCredentials(ctx, repoArg(args), *c)
+		},
+	}
+
+	readCredentials = addMirrorCredentialsFlags(cmd)
+
 	return cmd
 }
+
+func mirrorCredentialsShowCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "show REPOSITORY",
+		Short:             "Show the credentials of a mirror, without secrets",
+		Args:              cobra.ExactArgs(1),
+		PersistentPreRunE: checkIfReadableAndCollab,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			c, err := be.MirrorCredentials(ctx, repoArg(args))
+			if err != nil {
+				return err
+			}
+
+			if c == nil {
+				cmd.Println("Type: none")
+				return nil
+			}
+
+			cmd.Println("Type:", c.Type)
+			if c.Username != "" {
+				cmd.Println("Username:", c.Username)
+			}
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+func mirrorCredentialsRemoveCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "remove REPOSITORY",
+		Aliases:           []string{"rm", "delete"},
+		Short:             "Remove the credentials of a mirror",
+		Args:              cobra.ExactArgs(1),
+		PersistentPreRunE: checkIfRepoAdmin,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			return be.DeleteMirrorCredentials(ctx, repoArg(args))
+		},
+	}
+
+	return cmd
+}
+
+func mirrorIntervalCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "interval REPOSITORY [DURATION|default]",
+		Short: "Set or get the sync interval of a mirror",
+		Long: `Set or get the sync interval of a mirror.
+Mirrors without an interval are synced on the global mirror_pull schedule. Mirrors with an interval are synced once it elapsed, independently of that schedule. The interval is checked every minute.`,
+		Args:              cobra.RangeArgs(1, 2),
+		PersistentPreRunE: checkIfReadableAndCollab,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			rn := repoArg(args)
+
+			switch len(args) {
+			case 1:
+				s, err := be.MirrorSync(ctx, rn)
+				if err != nil {
+					return err
+				}
+
+				cmd.Println(mirrorSyncInterval(s))
+			case 2:
+				var interval time.Duration
+				if args[1] != "default" {
+					var err error
+					interval, err = time.ParseDuration(args[1])
+					if err != nil {
+						return fmt.Errorf("%w: %w", proto.ErrInvalidMirrorSyncInterval, err)
+					}
+				}
+
+				return be.SetMirrorSyncInterval(ctx, rn, interval)
+			}
+
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+func mirrorStatusCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "status REPOSITORY",
+		Short:             "Show the sync status of a mirror",
+		Args:              cobra.ExactArgs(1),
+		PersistentPreRunE: checkIfReadableAndCollab,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			s, err := be.MirrorSync(ctx, repoArg(args))
+			if err != nil {
+				return err
+			}
+
+			cmd.Println("Interval:", mirrorSyncInterval(s))
+			cmd.Println("Last Attempt:", mirrorSyncTime(s.LastAttemptAt))
+			cmd.Println("Last Success:", mirrorSyncTime(s.LastSuccessAt))
+			if !s.LastAttemptAt.IsZero() {
+				cmd.Println("Last Duration:", s.LastDuration)
+			}
+			cmd.Println("Status:", mirrorSyncStatus(s))
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+func mirrorSyncCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "sync REPOSITORY",
+		Short:             "Fetch a mirror from its remotes now",
+		Args:              cobra.ExactArgs(1),
+		PersistentPreRunE: checkIfRepoCollab,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			if err := be.SyncMirror(ctx, repoArg(args)); err != nil {
+				if errors.Is(err, task.ErrAlreadyStarted) {
+					return errors.New("sync already in progress")
+				}
+
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// mirrorSyncInterval returns the sync interval of a mirror.
+func mirrorSyncInterval(s backend.MirrorSync) string {
+	if s.Interval == 0 {
+		return "default"
+	}
+
+	return s.Interval.String()
+}
+
+// mirrorSyncTime returns when a mirror sync happened.
+func mirrorSyncTime(t time.Time) string {
+	if t.IsZero() {
+		return "never"
+	}
+
+	return humanize.Time(t)
+}
+
+// mirrorSyncStatus returns the outcome of the last sync of a mirror.
+func mirrorSyncStatus(s backend.MirrorSync) string {
+	switch {
+	case s.LastAttemptAt.IsZero():
+		return "pending"
+	case s.LastError != "":
+		return "failed: " + utils.Sanitize(s.LastError)
+	default:
+		return "ok"
+	}
+}
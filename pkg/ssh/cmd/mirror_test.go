@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/keygen"
+	"github.com/charmbracelet/soft-serve/pkg/access"
+	"github.com/charmbracelet/soft-serve/pkg/config"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/matryer/is"
+	_ "modernc.org/sqlite"
+)
+
+// runRepoStdin runs a `repo` subcommand with stdin and returns its combined
+// output alongside the error.
+func runRepoStdin(t *testing.T, ctx context.Context, stdin string, args ...string) (string, error) {
+	t.Helper()
+	c := RepoCommand()
+	var out bytes.Buffer
+	c.SetIn(strings.NewReader(stdin))
+	c.SetOut(&out)
+	c.SetErr(&out)
+	c.SetArgs(args)
+	err := c.ExecuteContext(ctx)
+	return out.String(), err
+}
+
+// TestMirrorCredentialsAreNeverPrinted verifies mirror secrets are read from
+// stdin and never echoed back, and that only repository admins can set them.
+func TestMirrorCredentialsAreNeverPrinted(t *testing.T) {
+	is := is.New(t)
+	ctx, be := newAuthTestContext(t)
+
+	// Mirror secrets are encrypted with the server's host key.
+	_, err := keygen.New(config.FromContext(ctx).SSH.KeyPath, keygen.WithKeyType(keygen.Ed25519), keygen.WithWrite())
+	is.NoErr(err)
+
+	ownerCtx := withUser(t, ctx, be, "owner", false)
+	owner := proto.UserFromContext(ownerCtx)
+	_, err = be.CreateRepository(ownerCtx, "repo", owner, proto.RepositoryOptions{})
+	is.NoErr(err)
+
+	collabCtx := withUser(t, ctx, be, "collab", false)
+	is.NoErr(be.AddCollaborator(ownerCtx, "repo", "collab", access.ReadWriteAccess))
+
+	const secret = "s3cret-password"
+	out, err := runRepoStdin(t, ownerCtx, secret+"\n", "mirror", "credentials", "set", "repo", "--username", "bob")
+	is.NoErr(err)
+	is.True(!strings.Contains(out, secret))
+
+	creds, err := be.MirrorCredentials(ctx, "repo")
+	is.NoErr(err)
+	is.Equal(*creds, proto.MirrorCredentials{Type: proto.MirrorCredentialsBasic, Username: "bob", Secret: secret})
+
+	out, err = runRepoStdin(t, collabCtx, "", "mirror", "credentials", "show", "repo")
+	is.NoErr(err)
+	is.Equal(out, "Type: basic\nUsername: bob\n")
+
+	// Collaborators can see the credentials exist, but not replace them.
+	_, err = runRepoStdin(t, collabCtx, "other", "mirror", "credentials", "set", "repo", "--token")
+	is.True(err != nil)
+	_, err = runRepoStdin(t, collabCtx, "", "mirror", "credentials", "remove", "repo")
+	is.True(err != nil)
+
+	// A secret that fails validation is not echoed in the error either.
+	out, err = runRepoStdin(t, ownerCtx, "not-a-key-"+secret, "mirror", "credentials", "set", "repo", "--ssh-key")
+	is.True(err != nil)
+	is.True(!strings.Contains(out, secret))
+	is.True(!strings.Contains(err.Error(), secret))
+
+	_, err = runRepoStdin(t, ownerCtx, "", "mirror", "credentials", "remove", "repo")
+	is.NoErr(err)
+	out, err = runRepoStdin(t, ownerCtx, "", "mirror", "credentials", "show", "repo")
+	is.NoErr(err)
+	is.Equal(out, "Type: none\n")
+}
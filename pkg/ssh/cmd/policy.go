@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+)
+
+func policyCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "policy",
+		Aliases: []string{"push-policy"},
+		Short:   "Manage repository push policies",
+		Long: `Manage repository push policies.
+Push policies are checked against every commit a push introduces, in addition to the server's policy.`,
+	}
+
+	cmd.AddCommand(
+		policySetCommand(),
+		policyShowCommand(),
+		policyRemoveCommand(),
+	)
+
+	return cmd
+}
+
+func policySetCommand() *cobra.Command {
+	var maxFileSize string
+	var forbiddenPaths []string
+	var message string
+	var domains []string
+	var noMerge []string
+	var requireSigned bool
+	cmd := &cobra.Command{
+		Use:   "set REPOSITORY",
+		Short: "Set the push policy of a repository",
+		Long: `Set the push policy of a repository, replacing the current one.
+Paths and branches are globs where "*" does not cross "/" boundaries and "**" does.`,
+		Args:              cobra.ExactArgs(1),
+		PersistentPreRunE: checkIfRepoAdmin,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+
+			var size uint64
+			if maxFileSize != "" {
+				var err error
+				size, err = humanize.ParseBytes(maxFileSize)
+				if err != nil {
+					return fmt.Errorf("%w: max file size: %w", proto.ErrInvalidPushPolicy, err)
+				}
+			}
+
+			return be.SetPushPolicy(ctx, repoArg(args), backend.PushPolicy{
+				MaxFileSize:          int64(size), //nolint: gosec
+				ForbiddenPaths:       forbiddenPaths,
+				CommitMessagePattern: message,
+				AuthorEmailDomains:   domains,
+				NoMergeBranches:      noMerge,
+				RequireSignedCommits: requireSigned,
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&maxFileSize, "max-file-size", "", "maximum size of pushed files, e.g. 10MB")
+	cmd.Flags().StringArrayVar(&forbiddenPaths, "forbidden-path", nil, "glob of paths pushes may not add or modify")
+	cmd.Flags().StringVar(&message, "commit-message", "", "regular expression commit messages must match")
+	cmd.Flags().StringSliceVar(&domains, "author-domain", nil, "email domains commit authors must use")
+	cmd.Flags().StringArrayVar(&noMerge, "no-merge", nil, "glob of branches that reject merge commits")
+	cmd.Flags().BoolVar(&requireSigned, "require-signed-commits", false, "reject commits not signed with a registered SSH key")
+
+	return cmd
+}
+
+func policyShowCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "show REPOSITORY",
+		Short:             "Show the push policy of a repository",
+		Args:              cobra.ExactArgs(1),
+		PersistentPreRunE: checkIfReadableAndCollab,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			p, err := be.PushPolicy(ctx, repoArg(args))
+			if err != nil {
+				return err
+			}
+
+			maxFileSize := "unlimited"
+			if p.MaxFileSize > 0 {
+				maxFileSize = humanize.IBytes(uint64(p.MaxFileSize)) //nolint: gosec
+			}
+
+			cmd.Println("Max File Size:", maxFileSize)
+			printList := func(name string, list []string) {
+				if len(list) == 0 {
+					return
+				}
+				cmd.Println(name + ":")
+				for _, v := range list {
+					cmd.Println("  -", v)
+				}
+			}
+			printList("Forbidden Paths", p.ForbiddenPaths)
+			if p.CommitMessagePattern != "" {
+				cmd.Println("Commit Message Pattern:", p.CommitMessagePattern)
+			}
+			printList("Author Email Domains", p.AuthorEmailDomains)
+			printList("No Merge Branches", p.NoMergeBranches)
+			if p.RequireSignedCommits {
+				cmd.Println("Require Signed Commits: true")
+			}
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+func policyRemoveCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "remove REPOSITORY",
+		Aliases:           []string{"rm", "delete"},
+		Short:             "Remove the push policy of a repository",
+		Args:              cobra.ExactArgs(1),
+		PersistentPreRunE: checkIfRepoAdmin,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			return be.SetPushPolicy(ctx, repoArg(args), backend.PushPolicy{})
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,295 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"charm.land/lipgloss/v2/table"
+	"github.com/charmbracelet/soft-serve/pkg/access"
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/ui/styles"
+	"github.com/charmbracelet/soft-serve/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+func pullRequestCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "pr",
+		Aliases: []string{"prs", "pull-request", "pull-requests"},
+		Short:   "Manage pull requests",
+		Long: `Manage pull requests.
+
+Pull requests can also be opened by pushing to refs/for/BRANCH, e.g. "git push origin HEAD:refs/for/main".`,
+	}
+
+	cmd.AddCommand(
+		pullRequestCreateCommand(),
+		pullRequestListCommand(),
+		pullRequestShowCommand(),
+		pullRequestDiffCommand(),
+		pullRequestCommentCommand(),
+		pullRequestMergeCommand(),
+		pullRequestCloseCommand(),
+	)
+
+	return cmd
+}
+
+// pullRequestNumberArg parses a pull request number argument, with or
+// without a leading "#".
+func pullRequestNumberArg(arg string) (int64, error) {
+	number, err := strconv.ParseInt(strings.TrimPrefix(arg, "#"), 10, 64)
+	if err != nil || number <= 0 {
+		return 0, fmt.Errorf("invalid pull request number: %s", arg)
+	}
+
+	return number, nil
+}
+
+func pullRequestCreateCommand() *cobra.Command {
+	var title string
+	var description string
+	cmd := &cobra.Command{
+		Use:               "create REPOSITORY SOURCE TARGET",
+		Short:             "Open a pull request",
+		Long:              "Open a pull request to merge the SOURCE branch into the TARGET branch. The title defaults to the summary of the latest commit on SOURCE.",
+		Args:              cobra.ExactArgs(3),
+		PersistentPreRunE: checkIfReadableAndCollab,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			pr, err := be.CreatePullRequest(ctx, repoArg(args), proto.UserFromContext(ctx), args[1], args[2], title, description)
+			if err != nil {
+				return err
+			}
+
+			cmd.Printf("Created pull request #%d\n", pr.Number)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&title, "title", "t", "", "pull request title")
+	cmd.Flags().StringVarP(&description, "description", "d", "", "pull request description")
+
+	return cmd
+}
+
+func pullRequestListCommand() *cobra.Command {
+	var state string
+	cmd := &cobra.Command{
+		Use:               "list REPOSITORY",
+		Aliases:           []string{"ls"},
+		Short:             "List pull requests",
+		Long:              "List pull requests. STATE can be one of: open, merged, closed, or all.",
+		Args:              cobra.ExactArgs(1),
+		PersistentPreRunE: checkIfReadable,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+
+			var st backend.PullRequestState
+			if state != "all" {
+				st = backend.ParsePullRequestState(state)
+				if st == "" {
+					return fmt.Errorf("invalid state: %s", state)
+				}
+			}
+
+			prs, err := be.PullRequests(ctx, repoArg(args), st)
+			if err != nil {
+				return err
+			}
+
+			table := table.New().Headers("#", "Title", "Source", "Target", "Author", "State")
+			for _, pr := range prs {
+				table = table.Row(
+					strconv.FormatInt(pr.Number, 10),
+					utils.Sanitize(pr.Title),
+					pr.Source(),
+					pr.TargetBranch,
+					pr.Author,
+					string(pr.State),
+				)
+			}
+			cmd.Println(table)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&state, "state", "s", string(backend.PullRequestStateOpen), "only list pull requests in this state")
+
+	return cmd
+}
+
+func pullRequestShowCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "show REPOSITORY NUMBER",
+		Aliases:           []string{"info"},
+		Short:             "Show a pull request and its comments",
+		Args:              cobra.ExactArgs(2),
+		PersistentPreRunE: checkIfReadable,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			repo := repoArg(args)
+			number, err := pullRequestNumberArg(args[1])
+			if err != nil {
+				return err
+			}
+
+			pr, err := be.PullRequest(ctx, repo, number)
+			if err != nil {
+				return err
+			}
+
+			comments, err := be.PullRequestComments(ctx, repo, number)
+			if err != nil {
+				return err
+			}
+
+			cmd.Printf("#%d %s\n", pr.Number, utils.Sanitize(pr.Title))
+			cmd.Println("State:", pr.State)
+			cmd.Println("Author:", pr.Author)
+			cmd.Printf("Merge: %s -> %s\n", pr.Source(), pr.TargetBranch)
+			if pr.MergeCommit != "" {
+				cmd.Println("Merge Commit:", pr.MergeCommit)
+			}
+			cmd.Println("Created:", pr.CreatedAt.UTC().Format(time.UnixDate))
+			if pr.Description != "" {
+				cmd.Printf("\n%s\n", utils.Sanitize(pr.Description))
+			}
+
+			for _, c := range comments {
+				cmd.Printf("\n%s commented on %s:\n%s\n", c.Author, c.CreatedAt.UTC().Format(time.UnixDate), utils.Sanitize(c.Body))
+			}
+
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+func pullRequestDiffCommand() *cobra.Command {
+	var color bool
+	cmd := &cobra.Command{
+		Use:               "diff REPOSITORY NUMBER",
+		Short:             "Print the changes of a pull request",
+		Args:              cobra.ExactArgs(2),
+		PersistentPreRunE: checkIfReadable,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			number, err := pullRequestNumberArg(args[1])
+			if err != nil {
+				return err
+			}
+
+			diff, err := be.PullRequestDiff(ctx, repoArg(args), number)
+			if err != nil {
+				return err
+			}
+
+			cmd.Printf("%s\n%s",
+				renderStats(diff, styles.DefaultStyles(), color),
+				renderDiff(diff.Patch(), color),
+			)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&color, "color", "c", false, "Colorize output")
+
+	return cmd
+}
+
+func pullRequestCommentCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "comment REPOSITORY NUMBER COMMENT...",
+		Short:             "Comment on a pull request",
+		Args:              cobra.MinimumNArgs(3),
+		PersistentPreRunE: checkIfReadable,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			user := proto.UserFromContext(ctx)
+			if user == nil {
+				return proto.ErrUnauthorized
+			}
+
+			number, err := pullRequestNumberArg(args[1])
+			if err != nil {
+				return err
+			}
+
+			return be.CommentPullRequest(ctx, repoArg(args), number, user, strings.Join(args[2:], " "))
+		},
+	}
+
+	return cmd
+}
+
+func pullRequestMergeCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "merge REPOSITORY NUMBER",
+		Short:             "Merge a pull request into its target branch",
+		Args:              cobra.ExactArgs(2),
+		PersistentPreRunE: checkIfReadableAndCollab,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			number, err := pullRequestNumberArg(args[1])
+			if err != nil {
+				return err
+			}
+
+			id, err := be.MergePullRequest(ctx, repoArg(args), number, proto.UserFromContext(ctx))
+			if err != nil {
+				return err
+			}
+
+			cmd.Printf("Merged pull request #%d as %s\n", number, id)
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+func pullRequestCloseCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "close REPOSITORY NUMBER",
+		Short:             "Close a pull request without merging it",
+		Args:              cobra.ExactArgs(2),
+		PersistentPreRunE: checkIfReadable,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			repo := repoArg(args)
+			user := proto.UserFromContext(ctx)
+			number, err := pullRequestNumberArg(args[1])
+			if err != nil {
+				return err
+			}
+
+			pr, err := be.PullRequest(ctx, repo, number)
+			if err != nil {
+				return err
+			}
+
+			// Authors may withdraw their own pull requests.
+			isAuthor := user != nil && pr.Author != "" && user.Username() == pr.Author
+			if !isAuthor && repoAccessLevel(ctx, repo) < access.ReadWriteAccess {
+				return proto.ErrUnauthorized
+			}
+
+			return be.ClosePullRequest(ctx, repo, number, user)
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"errors"
+
+	"charm.land/lipgloss/v2/table"
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/charmbracelet/soft-serve/pkg/task"
+	"github.com/charmbracelet/soft-serve/pkg/utils"
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+)
+
+func pushMirrorCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "push-mirror",
+		Aliases: []string{"push-mirrors"},
+		Short:   "Manage repository push mirrors",
+		Long: `Manage repository push mirrors.
+Repositories are pushed to their push mirrors with "git push --mirror" after every push and on a schedule.
+SSH remotes are pushed to with the server's client key.`,
+	}
+
+	cmd.AddCommand(
+		pushMirrorAddCommand(),
+		pushMirrorListCommand(),
+		pushMirrorRemoveCommand(),
+		pushMirrorSyncCommand(),
+	)
+
+	return cmd
+}
+
+func pushMirrorAddCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "add REPOSITORY NAME URL",
+		Short:             "Add a push mirror to a repository",
+		Args:              cobra.ExactArgs(3),
+		PersistentPreRunE: checkIfRepoAdmin,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			return be.AddPushMirror(ctx, repoArg(args), args[1], args[2])
+		},
+	}
+
+	return cmd
+}
+
+func pushMirrorListCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "list REPOSITORY",
+		Aliases:           []string{"ls"},
+		Short:             "List the push mirrors of a repository",
+		Args:              cobra.ExactArgs(1),
+		PersistentPreRunE: checkIfReadableAndCollab,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			mirrors, err := be.PushMirrors(ctx, repoArg(args))
+			if err != nil {
+				return err
+			}
+
+			table := table.New().Headers("Name", "URL", "Last Sync", "Status")
+			for _, m := range mirrors {
+				table = table.Row(
+					m.Name,
+					utils.Sanitize(m.RedactedURL()),
+					pushMirrorLastSync(m),
+					pushMirrorStatus(m),
+				)
+			}
+			cmd.Println(table)
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+func pushMirrorRemoveCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "remove REPOSITORY NAME",
+		Aliases:           []string{"rm", "delete"},
+		Short:             "Remove a push mirror from a repository",
+		Args:              cobra.ExactArgs(2),
+		PersistentPreRunE: checkIfRepoAdmin,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			return be.RemovePushMirror(ctx, repoArg(args), args[1])
+		},
+	}
+
+	return cmd
+}
+
+func pushMirrorSyncCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "sync REPOSITORY",
+		Short:             "Push a repository to its push mirrors now",
+		Args:              cobra.ExactArgs(1),
+		PersistentPreRunE: checkIfRepoAdmin,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			if err := be.SyncPushMirrors(ctx, repoArg(args)); err != nil {
+				if errors.Is(err, task.ErrAlreadyStarted) {
+					return errors.New("sync already in progress")
+				}
+
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// pushMirrorLastSync returns when a push mirror was last pushed to.
+func pushMirrorLastSync(m backend.PushMirror) string {
+	if m.LastSyncAt.IsZero() {
+		return "never"
+	}
+
+	return humanize.Time(m.LastSyncAt)
+}
+
+// pushMirrorStatus returns the outcome of the last push to a push mirror.
+func pushMirrorStatus(m backend.PushMirror) string {
+	switch {
+	case m.LastSyncAt.IsZero():
+		return "pending"
+	case m.LastError != "":
+		return "failed: " + utils.Sanitize(m.LastError)
+	default:
+		return "ok"
+	}
+}
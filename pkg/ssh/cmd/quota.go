@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+)
+
+func quotaCommand() *cobra.Command {
+	var size, lfsSize string
+	cmd := &cobra.Command{
+		Use:   "quota REPOSITORY",
+		Short: "Show or set the storage quota of a repository",
+		Long: `Show or set the storage quota of a repository.
+Sizes are like "10MB" or "1GiB", 0 means no limit and "default" resets a limit to the server default. Only admins can set quotas.`,
+		Args:              cobra.ExactArgs(1),
+		PersistentPreRunE: checkIfReadable,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			rn := repoArg(args)
+
+			opts, err := quotaOptionsFromFlags(cmd, size, lfsSize)
+			if err != nil {
+				return err
+			}
+
+			if opts != nil {
+				if err := checkIfServerAdmin(cmd, args); err != nil {
+					return err
+				}
+
+				return be.SetRepositoryQuota(ctx, rn, *opts)
+			}
+
+			q, err := be.RepositoryQuota(ctx, rn)
+			if err != nil {
+				return err
+			}
+
+			u, err := be.RepositoryUsage(ctx, rn)
+			if err != nil {
+				return err
+			}
+
+			printQuota(cmd, q, u)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&size, "size", "", "maximum size of the Git objects, or \"default\"")
+	cmd.Flags().StringVar(&lfsSize, "lfs-size", "", "maximum size of the LFS objects, or \"default\"")
+
+	return cmd
+}
+
+// quotaOptionsFromFlags returns the quota changes requested with the --size
+// and --lfs-size flags, or nil if neither is set.
+func quotaOptionsFromFlags(cmd *cobra.Command, size, lfsSize string) (*backend.QuotaOptions, error) {
+	parse := func(flag, v string) (*int64, error) {
+		if !cmd.Flags().Changed(flag) {
+			return nil, nil
+		}
+
+		n := int64(-1)
+		if v != "default" {
+			b, err := humanize.ParseBytes(v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s: %w", flag, err)
+			}
+			n = int64(b) //nolint: gosec
+		}
+
+		return &n, nil
+	}
+
+	var opts backend.QuotaOptions
+	var err error
+	if opts.Size, err = parse("size", size); err != nil {
+		return nil, err
+	}
+	if opts.LFSSize, err = parse("lfs-size", lfsSize); err != nil {
+		return nil, err
+	}
+	if opts.Size == nil && opts.LFSSize == nil {
+		return nil, nil
+	}
+
+	return &opts, nil
+}
+
+// printQuota prints storage usage against a quota.
+func printQuota(cmd *cobra.Command, q backend.Quota, u backend.QuotaUsage) {
+	cmd.Println("Size:", quotaString(u.Size, q.Size))
+	cmd.Println("LFS Size:", quotaString(u.LFSSize, q.LFSSize))
+}
+
+// quotaString describes how much of a limit is used.
+func quotaString(used, limit int64) string {
+	s := "unlimited"
+	if limit > 0 {
+		s = humanize.IBytes(uint64(limit)) //nolint: gosec
+	}
+
+	return humanize.IBytes(uint64(used)) + " / " + s //nolint: gosec
+}
@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/charmbracelet/soft-serve/pkg/access"
 	"github.com/charmbracelet/soft-serve/pkg/backend"
 	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/utils"
 	"github.com/spf13/cobra"
 )
 
@@ -18,19 +20,29 @@ func RepoCommand() *cobra.Command {
 	}
 
 	cmd.AddCommand(
+		archiveCommand(),
 		blobCommand(),
 		branchCommand(),
 		collabCommand(),
 		commitCommand(),
 		createCommand(),
 		deleteCommand(),
+		deployKeyCommand(),
 		descriptionCommand(),
+		forkCommand(),
+		gcCommand(),
 		hiddenCommand(),
 		importCommand(),
+		issueCommand(),
+		lfsCommand(),
 		listCommand(),
 		mirrorCommand(),
+		policyCommand(),
 		privateCommand(),
 		projectName(),
+		pullRequestCommand(),
+		pushMirrorCommand(),
+		quotaCommand(),
 		renameCommand(),
 		tagCommand(),
 		treeCommand(),
@@ -81,6 +93,13 @@ func RepoCommand() *cobra.Command {
 				cmd.Println("Private:", rr.IsPrivate())
 				cmd.Println("Hidden:", rr.IsHidden())
 				cmd.Println("Mirror:", rr.IsMirror())
+				parent, err := be.ForkParent(ctx, rr.Name())
+				if err != nil {
+					return err
+				}
+				if parent != "" && repoAccessLevel(ctx, parent) >= access.ReadOnlyAccess {
+					cmd.Println("Forked From:", parent)
+				}
 				if owner != nil {
 					cmd.Println(strings.TrimSpace(fmt.Sprint("Owner: ", owner.Username())))
 				}
@@ -97,6 +116,32 @@ func RepoCommand() *cobra.Command {
 						cmd.Println("  -", t)
 					}
 				}
+				// Usage is only shown against a limit, repositories without
+				// one have nothing to report.
+				q, err := be.RepositoryQuota(ctx, rr.Name())
+				if err != nil {
+					return err
+				}
+				if !q.IsEmpty() {
+					u, err := be.RepositoryUsage(ctx, rr.Name())
+					if err != nil {
+						return err
+					}
+					printQuota(cmd, q, u)
+				}
+				if repoAccessLevel(ctx, rr.Name()) >= access.ReadWriteAccess {
+					mirrors, err := be.PushMirrors(ctx, rr.Name())
+					if err != nil {
+						return err
+					}
+					if len(mirrors) > 0 {
+						cmd.Println("Push Mirrors:")
+						for _, m := range mirrors {
+							cmd.Println("  -", m.Name, utils.Sanitize(m.RedactedURL()),
+								"(last sync: "+pushMirrorLastSync(m)+", "+pushMirrorStatus(m)+")")
+						}
+					}
+				}
 
 				return nil
 			},
@@ -48,7 +48,13 @@ func tagListCommand() *cobra.Command {
 
 			tags, _ := r.Tags()
 			for _, t := range tags {
-				cmd.Println(t)
+				sig, err := be.VerifyTag(ctx, r, t)
+				if err != nil || !sig.IsSigned() {
+					cmd.Println(t)
+					continue
+				}
+
+				cmd.Printf("%s\t%s\n", t, sig)
 			}
 
 			return nil
@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"charm.land/lipgloss/v2/table"
+	"github.com/charmbracelet/soft-serve/pkg/access"
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/spf13/cobra"
+)
+
+// TeamCommand returns the team subcommand.
+func TeamCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "team",
+		Aliases: []string{"teams"},
+		Short:   "Manage teams",
+		// Teams grant access across repositories, so managing them is a
+		// server admin task like managing users.
+		PersistentPreRunE: checkIfServerAdmin,
+	}
+
+	teamCreateCommand := &cobra.Command{
+		Use:   "create TEAM",
+		Short: "Create a new team",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			return be.CreateTeam(ctx, args[0])
+		},
+	}
+
+	teamDeleteCommand := &cobra.Command{
+		Use:   "delete TEAM",
+		Short: "Delete a team",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			return be.DeleteTeam(ctx, args[0])
+		},
+	}
+
+	teamListCommand := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List teams",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			teams, err := be.Teams(ctx)
+			if err != nil {
+				return err
+			}
+
+			for _, t := range teams {
+				cmd.Println(t)
+			}
+
+			return nil
+		},
+	}
+
+	teamInfoCommand := &cobra.Command{
+		Use:   "info TEAM",
+		Short: "Show the members and repositories of a team",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			team := args[0]
+
+			members, err := be.TeamMembers(ctx, team)
+			if err != nil {
+				return err
+			}
+
+			repos, err := be.TeamRepositories(ctx, team)
+			if err != nil {
+				return err
+			}
+
+			cmd.Printf("Team: %s\n", team)
+			cmd.Printf("Members:\n")
+			for _, m := range members {
+				cmd.Printf("  %s\n", m)
+			}
+
+			cmd.Printf("Repositories:\n")
+			table := table.New().Headers("Repository", "Access Level")
+			for _, r := range repos {
+				table = table.Row(r.Repo, r.AccessLevel.String())
+			}
+			cmd.Println(table)
+
+			return nil
+		},
+	}
+
+	teamAddMemberCommand := &cobra.Command{
+		Use:   "add-member TEAM USERNAME",
+		Short: "Add a user to a team",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			return be.AddTeamMember(ctx, args[0], args[1])
+		},
+	}
+
+	teamRemoveMemberCommand := &cobra.Command{
+		Use:   "remove-member TEAM USERNAME",
+		Short: "Remove a user from a team",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			return be.RemoveTeamMember(ctx, args[0], args[1])
+		},
+	}
+
+	teamGrantCommand := &cobra.Command{
+		Use:   "grant TEAM REPOSITORY [LEVEL]",
+		Short: "Grant a team access to a repository",
+		Long:  "Grant a team access to a repository. LEVEL can be one of: no-access, read-only, read-write, or admin-access. Defaults to read-write.",
+		Args:  cobra.RangeArgs(2, 3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			level := access.ReadWriteAccess
+			if len(args) > 2 {
+				level = access.ParseAccessLevel(args[2])
+				if level < 0 {
+					return access.ErrInvalidAccessLevel
+				}
+			}
+
+			return be.SetTeamAccess(ctx, args[0], args[1], level)
+		},
+	}
+
+	teamRevokeCommand := &cobra.Command{
+		Use:   "revoke TEAM REPOSITORY",
+		Short: "Revoke a team's access to a repository",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			return be.RemoveTeamAccess(ctx, args[0], args[1])
+		},
+	}
+
+	cmd.AddCommand(
+		teamCreateCommand,
+		teamDeleteCommand,
+		teamListCommand,
+		teamInfoCommand,
+		teamAddMemberCommand,
+		teamRemoveMemberCommand,
+		teamGrantCommand,
+		teamRevokeCommand,
+	)
+
+	return cmd
+}
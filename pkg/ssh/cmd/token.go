@@ -22,10 +22,13 @@ func TokenCommand() *cobra.Command {
 	}
 
 	var createExpiresIn string
+	var createScopes, createRepos []string
 	createCmd := &cobra.Command{
 		Use:   "create NAME",
 		Short: "Create a new access token",
-		Args:  cobra.MinimumNArgs(1),
+		Long: `Create a new access token.
+Tokens have all the scopes (read, write, admin, lfs) and work with any repository unless restricted with --scope and --repo.`,
+		Args: cobra.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
 			be := backend.FromContext(ctx)
@@ -48,7 +51,16 @@ func TokenCommand() *cobra.Command {
 				expiresAt = time.Now().Add(d)
 			}
 
-			token, err := be.CreateAccessToken(ctx, user, name, expiresAt)
+			opts := proto.AccessTokenOptions{Repos: createRepos}
+			for _, s := range createScopes {
+				scope, err := proto.ParseAccessTokenScope(s)
+				if err != nil {
+					return err
+				}
+				opts.Scopes = append(opts.Scopes, scope)
+			}
+
+			token, err := be.CreateAccessToken(ctx, user, name, expiresAt, opts)
 			if err != nil {
 				return err
 			}
@@ -66,6 +78,8 @@ func TokenCommand() *cobra.Command {
 	}
 
 	createCmd.Flags().StringVar(&createExpiresIn, "expires-in", "", "Token expiration time (e.g. 1y, 3mo, 2w, 5d4h, 1h30m)")
+	createCmd.Flags().StringSliceVar(&createScopes, "scope", nil, "Token scopes: read, write, admin, lfs (default all)")
+	createCmd.Flags().StringSliceVar(&createRepos, "repo", nil, "Restrict the token to a repository, can be repeated")
 
 	listCmd := &cobra.Command{
 		Use:     "list",
@@ -92,7 +106,7 @@ func TokenCommand() *cobra.Command {
 			}
 
 			now := time.Now()
-			table := table.New().Headers("ID", "Name", "Created At", "Expires In")
+			table := table.New().Headers("ID", "Name", "Scopes", "Repositories", "Created At", "Expires In", "Last Used", "Uses")
 			for _, token := range tokens {
 				expiresAt := "-"
 				if !token.ExpiresAt.IsZero() {
@@ -103,10 +117,24 @@ func TokenCommand() *cobra.Command {
 					}
 				}
 
+				scopes := "all"
+				if len(token.Scopes) > 0 {
+					scopes = joinScopes(token.Scopes)
+				}
+
+				repos := "all"
+				if len(token.Repos) > 0 {
+					repos = strings.Join(token.Repos, ",")
+				}
+
 				table = table.Row(strconv.FormatInt(token.ID, 10),
 					token.Name,
+					scopes,
+					repos,
 					humanize.Time(token.CreatedAt),
 					expiresAt,
+					lastUsed(token),
+					strconv.FormatInt(token.UseCount, 10),
 				)
 			}
 			cmd.Println(table)
@@ -150,3 +178,27 @@ func TokenCommand() *cobra.Command {
 
 	return cmd
 }
+
+// joinScopes joins access token scopes with commas.
+func joinScopes(scopes []proto.AccessTokenScope) string {
+	ss := make([]string, len(scopes))
+	for i, s := range scopes {
+		ss[i] = string(s)
+	}
+
+	return strings.Join(ss, ",")
+}
+
+// lastUsed describes when and where from an access token was last used.
+func lastUsed(token proto.AccessToken) string {
+	if token.LastUsedAt.IsZero() {
+		return "never"
+	}
+
+	s := humanize.Time(token.LastUsedAt)
+	if token.LastUsedAddr != "" {
+		s += " from " + token.LastUsedAddr
+	}
+
+	return s
+}
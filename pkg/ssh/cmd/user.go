@@ -1,12 +1,18 @@
 package cmd
 
 import (
+	"fmt"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
+	"charm.land/lipgloss/v2/table"
+	"github.com/caarlos0/duration"
 	"github.com/charmbracelet/soft-serve/pkg/backend"
 	"github.com/charmbracelet/soft-serve/pkg/proto"
 	"github.com/charmbracelet/soft-serve/pkg/sshutils"
+	"github.com/dustin/go-humanize"
 	"github.com/spf13/cobra"
 	"golang.org/x/crypto/ssh"
 )
@@ -164,6 +170,30 @@ func UserCommand() *cobra.Command {
 				cmd.Printf("  %s\n", sshutils.MarshalAuthorizedKey(pk))
 			}
 
+			teams, err := be.UserTeams(ctx, user.Username())
+			if err != nil {
+				return err
+			}
+
+			if len(teams) > 0 {
+				cmd.Printf("Teams:\n")
+				for _, t := range teams {
+					cmd.Printf("  %s\n", t)
+				}
+			}
+
+			q, err := be.UserQuota(ctx, user.Username())
+			if err != nil {
+				return err
+			}
+			if !q.IsEmpty() {
+				u, err := be.UserUsage(ctx, user.Username())
+				if err != nil {
+					return err
+				}
+				printQuota(cmd, q, u)
+			}
+
 			return nil
 		},
 	}
@@ -182,15 +212,138 @@ func UserCommand() *cobra.Command {
 		},
 	}
 
+	var quotaSize, quotaLFSSize string
+	userQuotaCommand := &cobra.Command{
+		Use:   "quota USERNAME",
+		Short: "Show or set the storage quota of a user",
+		Long: `Show or set the storage quota of a user, which applies to all the repositories the user owns.
+Sizes are like "10MB" or "1GiB", 0 means no limit and "default" resets a limit to the server default.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			username := args[0]
+
+			opts, err := quotaOptionsFromFlags(cmd, quotaSize, quotaLFSSize)
+			if err != nil {
+				return err
+			}
+
+			if opts != nil {
+				return be.SetUserQuota(ctx, username, *opts)
+			}
+
+			q, err := be.UserQuota(ctx, username)
+			if err != nil {
+				return err
+			}
+
+			u, err := be.UserUsage(ctx, username)
+			if err != nil {
+				return err
+			}
+
+			printQuota(cmd, q, u)
+			return nil
+		},
+	}
+
+	userQuotaCommand.Flags().StringVar(&quotaSize, "size", "", "maximum size of the Git objects, or \"default\"")
+	userQuotaCommand.Flags().StringVar(&quotaLFSSize, "lfs-size", "", "maximum size of the LFS objects, or \"default\"")
+
+	var tokensStale string
+	var tokensRevoke bool
+	userTokensCommand := &cobra.Command{
+		Use:   "tokens",
+		Short: "List or revoke the access tokens of all users",
+		Long: `List the access tokens of all users.
+With --stale, only list the tokens that expired or haven't been used, or created if never used, for that long. Add --revoke to delete them.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+
+			if tokensRevoke && tokensStale == "" {
+				return fmt.Errorf("--revoke requires --stale")
+			}
+
+			tokens, err := be.AllAccessTokens(ctx)
+			if err != nil {
+				return err
+			}
+
+			now := time.Now()
+			if tokensStale != "" {
+				d, err := duration.Parse(tokensStale)
+				if err != nil {
+					return fmt.Errorf("invalid stale duration: %w", err)
+				}
+
+				cutoff := now.Add(-d)
+				stale := tokens[:0]
+				for _, t := range tokens {
+					expired := !t.ExpiresAt.IsZero() && now.After(t.ExpiresAt)
+					if expired || t.LastActiveAt().Before(cutoff) {
+						stale = append(stale, t)
+					}
+				}
+				tokens = stale
+			}
+
+			if len(tokens) == 0 {
+				cmd.Println("No tokens found")
+				return nil
+			}
+
+			table := table.New().Headers("ID", "User", "Name", "Created At", "Expires In", "Last Used", "Uses")
+			for _, t := range tokens {
+				expiresAt := "-"
+				if !t.ExpiresAt.IsZero() {
+					if now.After(t.ExpiresAt) {
+						expiresAt = "expired"
+					} else {
+						expiresAt = humanize.Time(t.ExpiresAt)
+					}
+				}
+
+				table = table.Row(strconv.FormatInt(t.ID, 10),
+					t.Username,
+					t.Name,
+					humanize.Time(t.CreatedAt),
+					expiresAt,
+					lastUsed(t.AccessToken),
+					strconv.FormatInt(t.UseCount, 10),
+				)
+			}
+			cmd.Println(table)
+
+			if !tokensRevoke {
+				return nil
+			}
+
+			if err := be.RevokeAccessTokens(ctx, tokens); err != nil {
+				return err
+			}
+
+			cmd.PrintErrf("Revoked %d access token(s)\n", len(tokens))
+			return nil
+		},
+	}
+
+	userTokensCommand.Flags().StringVar(&tokensStale, "stale", "", "only tokens unused for this long (e.g. 90d, 6mo, 1y)")
+	userTokensCommand.Flags().BoolVar(&tokensRevoke, "revoke", false, "revoke the listed tokens")
+
 	cmd.AddCommand(
 		userCreateCommand,
 		userAddPubkeyCommand,
 		userInfoCommand,
 		userListCommand,
 		userDeleteCommand,
+		userQuotaCommand,
 		userRemovePubkeyCommand,
 		userSetAdminCommand,
 		userSetUsernameCommand,
+		userTokensCommand,
 	)
 
 	return cmd
@@ -293,16 +293,13 @@ func webhookDeliveriesListCommand() *cobra.Command {
 				return err
 			}
 
-			table := table.New().Headers("Status", "ID", "Event", "Created At")
+			table := table.New().Headers("Status", "ID", "Event", "Attempts", "Created At")
 			for _, d := range dels {
-				status := "❌"
-				if d.ResponseStatus >= 200 && d.ResponseStatus < 300 {
-					status = "✅"
-				}
 				table = table.Row(
-					status,
+					d.Status.String(),
 					d.ID.String(),
 					d.Event.String(),
+					strconv.Itoa(d.Attempts),
 					humanize.Time(d.CreatedAt),
 				)
 			}
@@ -375,8 +372,13 @@ func webhookDeliveriesGetCommand() *cobra.Command {
 			}
 
 			out := cmd.OutOrStdout()
-			fmt.Fprintf(out, "ID: %s\n", del.ID)                             //nolint:errcheck
-			fmt.Fprintf(out, "Event: %s\n", del.Event)                       //nolint:errcheck
+			fmt.Fprintf(out, "ID: %s\n", del.ID)             //nolint:errcheck
+			fmt.Fprintf(out, "Event: %s\n", del.Event)       //nolint:errcheck
+			fmt.Fprintf(out, "Status: %s\n", del.Status)     //nolint:errcheck
+			fmt.Fprintf(out, "Attempts: %d\n", del.Attempts) //nolint:errcheck
+			if del.NextAttemptAt.Valid {
+				fmt.Fprintf(out, "Next Attempt: %s\n", humanize.Time(del.NextAttemptAt.Time)) //nolint:errcheck
+			}
 			fmt.Fprintf(out, "Request URL: %s\n", del.RequestURL)            //nolint:errcheck
 			fmt.Fprintf(out, "Request Method: %s\n", del.RequestMethod)      //nolint:errcheck
 			fmt.Fprintf(out, "Request Error: %s\n", del.RequestError.String) //nolint:errcheck
@@ -4,6 +4,7 @@ import (
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/charmbracelet/soft-serve/pkg/db"
 	"github.com/charmbracelet/soft-serve/pkg/proto"
@@ -48,10 +49,14 @@ func TestWebhookDeliveriesAreScopedToRepository(t *testing.T) {
 		secretSignature = "X-SoftServe-Signature: sha256=leaked-signature\n"
 	)
 	deliveryID := uuid.MustParse("00000000-0000-0000-0000-000000000001")
-	is.NoErr(store.FromContext(ctx).CreateWebhookDelivery(
+	datastore := store.FromContext(ctx)
+	is.NoErr(datastore.CreateWebhookDelivery(
 		ctx, db.FromContext(ctx), deliveryID, victimHookID, int(webhook.EventPush),
-		"http://example.com/hook", "POST", nil,
-		secretSignature, secretBody, 200, "Content-Type: text/plain\n", "victim response body",
+		"http://example.com/hook", "POST", secretSignature, secretBody,
+	))
+	is.NoErr(datastore.UpdateWebhookDeliveryByID(
+		ctx, db.FromContext(ctx), deliveryID, int(webhook.DeliveryStatusSucceeded), 1, time.Time{},
+		nil, 200, "Content-Type: text/plain\n", "victim response body",
 	))
 
 	// The attacker owns their own repository, so they hold admin access to
@@ -62,10 +62,16 @@ func AuthenticationMiddleware(sh ssh.Handler) ssh.Handler {
 			return
 		}
 
-		// Set the auth'd user, or anon, in the context
+		// Set the auth'd user, or anon, in the context. Deploy keys are
+		// resolved first, their sessions are anonymous with the deploy key's
+		// access to its repository.
 		var user proto.User
 		if pk != nil {
-			user, _ = be.UserByPublicKey(ctx, pk)
+			if k, _ := be.DeployKeyByPublicKey(ctx, pk); k != nil {
+				ctx.SetValue(backend.ContextKeyDeployKey, k)
+			} else {
+				user, _ = be.UserByPublicKey(ctx, pk)
+			}
 		}
 		ctx.SetValue(proto.ContextKeyUser, user)
 
@@ -84,6 +90,7 @@ func ContextMiddleware(cfg *config.Config, dbx *db.DB, datastore store.Store, be
 			ctx.SetValue(store.ContextKey, datastore)
 			ctx.SetValue(backend.ContextKey, be)
 			ctx.SetValue(log.ContextKey, logger.WithPrefix("ssh"))
+			ctx.SetValue(backend.ContextKeyAuditSource, backend.NewAuditSource(s.RemoteAddr().String(), "ssh"))
 			sh(s)
 		}
 	}
@@ -100,13 +107,19 @@ var cliCommandCounter = promauto.NewCounterVec(prometheus.CounterOpts{
 // This middleware must be run after the ContextMiddleware.
 func CommandMiddleware(sh ssh.Handler) ssh.Handler {
 	return func(s ssh.Session) {
+		ctx := s.Context()
 		_, _, ptyReq := s.Pty()
 		if ptyReq {
+			// Deploy keys have no access to the UI either.
+			if backend.DeployKeyFromContext(ctx) != nil {
+				wish.Fatalln(s, ErrPermissionDenied)
+				return
+			}
+
 			sh(s)
 			return
 		}
 
-		ctx := s.Context()
 		cfg := config.FromContext(ctx)
 
 		args := s.Command()
@@ -123,16 +136,25 @@ func CommandMiddleware(sh ssh.Handler) ssh.Handler {
 			cmd.GitUploadPackCommand(),
 			cmd.GitUploadArchiveCommand(),
 			cmd.GitReceivePackCommand(),
-			cmd.RepoCommand(),
-			cmd.SettingsCommand(),
-			cmd.UserCommand(),
-			cmd.InfoCommand(),
-			cmd.PubkeyCommand(),
-			cmd.SetUsernameCommand(),
-			cmd.JWTCommand(),
-			cmd.TokenCommand(),
 		)
 
+		// Deploy keys only give access to the git transport of their
+		// repository, never to the commands that manage it.
+		if backend.DeployKeyFromContext(ctx) == nil {
+			rootCmd.AddCommand(
+				cmd.RepoCommand(),
+				cmd.SettingsCommand(),
+				cmd.UserCommand(),
+				cmd.TeamCommand(),
+				cmd.InfoCommand(),
+				cmd.PubkeyCommand(),
+				cmd.SetUsernameCommand(),
+				cmd.JWTCommand(),
+				cmd.TokenCommand(),
+				cmd.AuditCommand(),
+			)
+		}
+
 		if cfg.LFS.Enabled {
 			rootCmd.AddCommand(
 				cmd.GitLFSAuthenticateCommand(),
@@ -194,6 +216,10 @@ func LoggingMiddleware(sh ssh.Handler) ssh.Handler {
 			}
 		}
 
+		if cert, ok := s.PublicKey().(*gossh.Certificate); ok {
+			logArgs = append(logArgs, "key-id", cert.KeyId)
+		}
+
 		if config.IsVerbose() {
 			logArgs = append(logArgs,
 				"key", hpk,
@@ -17,6 +17,7 @@ import (
 	"github.com/charmbracelet/soft-serve/pkg/backend"
 	"github.com/charmbracelet/soft-serve/pkg/config"
 	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/sshutils"
 	"github.com/charmbracelet/soft-serve/pkg/store"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
@@ -165,6 +166,21 @@ func (s *SSHServer) PublicKeyHandler(ctx ssh.Context, pk ssh.PublicKey) (allowed
 		return false
 	}
 
+	// Certificates signed by a trusted authority must be valid for a user,
+	// others are treated as plain public keys.
+	if cert, ok := pk.(*gossh.Certificate); ok && s.be.IsTrustedCertificate(cert) {
+		user, err := s.be.UserByCertificate(ctx, cert)
+		if err == nil {
+			err = sshutils.CheckSourceAddress(cert, ctx.RemoteAddr())
+		}
+		if err != nil {
+			s.logger.Info("rejected certificate", "key-id", cert.KeyId, "serial", cert.Serial, "principals", cert.ValidPrincipals, "addr", ctx.RemoteAddr(), "err", err)
+			return false
+		}
+
+		s.logger.Debug("accepted certificate", "key-id", cert.KeyId, "serial", cert.Serial, "username", user.Username(), "addr", ctx.RemoteAddr())
+	}
+
 	allowed = true
 
 	// XXX: store the first "approved" public-key fingerprint in the
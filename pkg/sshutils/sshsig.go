@@ -0,0 +1,134 @@
+package sshutils
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"hash"
+
+	gossh "golang.org/x/crypto/ssh"
+)
+
+// SSH signatures are described in
+// https://github.com/openssh/openssh-portable/blob/master/PROTOCOL.sshsig
+
+const (
+	sshsigMagic   = "SSHSIG"
+	sshsigVersion = 1
+	sshsigBegin   = "-----BEGIN SSH SIGNATURE-----"
+	sshsigEnd     = "-----END SSH SIGNATURE-----"
+)
+
+// ErrInvalidSignature is returned when an SSH signature is malformed or
+// doesn't verify.
+var ErrInvalidSignature = errors.New("invalid ssh signature")
+
+// Signature is an SSH signature, as made by "ssh-keygen -Y sign".
+type Signature struct {
+	// PublicKey is the key that made the signature.
+	PublicKey gossh.PublicKey
+	// Namespace is the domain the signature was made for, e.g. "git".
+	Namespace string
+
+	hashAlgorithm string
+	signature     *gossh.Signature
+}
+
+type sshsigBlob struct {
+	Version       uint32
+	PublicKey     []byte
+	Namespace     string
+	Reserved      []byte
+	HashAlgorithm string
+	Signature     []byte
+}
+
+type sshsigSignedData struct {
+	Namespace     string
+	Reserved      []byte
+	HashAlgorithm string
+	Hash          []byte
+}
+
+// ParseSignature parses an armored SSH signature.
+func ParseSignature(armored []byte) (*Signature, error) {
+	armored = bytes.TrimSpace(armored)
+	body, ok := bytes.CutPrefix(armored, []byte(sshsigBegin))
+	if !ok {
+		return nil, fmt.Errorf("%w: missing header", ErrInvalidSignature)
+	}
+
+	body, ok = bytes.CutSuffix(body, []byte(sshsigEnd))
+	if !ok {
+		return nil, fmt.Errorf("%w: missing footer", ErrInvalidSignature)
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(string(bytes.Join(bytes.Fields(body), nil)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSignature, err)
+	}
+
+	raw, ok = bytes.CutPrefix(raw, []byte(sshsigMagic))
+	if !ok {
+		return nil, fmt.Errorf("%w: bad magic", ErrInvalidSignature)
+	}
+
+	var blob sshsigBlob
+	if err := gossh.Unmarshal(raw, &blob); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSignature, err)
+	}
+
+	if blob.Version != sshsigVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidSignature, blob.Version)
+	}
+
+	pk, err := gossh.ParsePublicKey(blob.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSignature, err)
+	}
+
+	sig := new(gossh.Signature)
+	if err := gossh.Unmarshal(blob.Signature, sig); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSignature, err)
+	}
+
+	return &Signature{
+		PublicKey:     pk,
+		Namespace:     blob.Namespace,
+		hashAlgorithm: blob.HashAlgorithm,
+		signature:     sig,
+	}, nil
+}
+
+// Verify checks that the signature signs message in the given namespace.
+func (s *Signature) Verify(message []byte, namespace string) error {
+	if s.Namespace != namespace {
+		return fmt.Errorf("%w: namespace %q, want %q", ErrInvalidSignature, s.Namespace, namespace)
+	}
+
+	var h hash.Hash
+	switch s.hashAlgorithm {
+	case "sha256":
+		h = sha256.New()
+	case "sha512":
+		h = sha512.New()
+	default:
+		return fmt.Errorf("%w: unsupported hash algorithm %q", ErrInvalidSignature, s.hashAlgorithm)
+	}
+	h.Write(message)
+
+	signed := append([]byte(sshsigMagic), gossh.Marshal(sshsigSignedData{
+		Namespace:     s.Namespace,
+		HashAlgorithm: s.hashAlgorithm,
+		Hash:          h.Sum(nil),
+	})...)
+
+	if err := s.PublicKey.Verify(signed, s.signature); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidSignature, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package sshutils
+
+import (
+	"errors"
+	"testing"
+)
+
+// Signatures of "hello world\n" in the git namespace, made with
+// "ssh-keygen -Y sign -n git".
+const (
+	ed25519SigningKey = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIF3ii3JRQN+Wx1Hlj20vEEXFCCuRuECsK9Tg3c8QdbQB"
+	ed25519Signature  = `-----BEGIN SSH SIGNATURE-----
+U1NIU0lHAAAAAQAAADMAAAALc3NoLWVkMjU1MTkAAAAgXeKLclFA35bHUeWPbS8QRcUIK5
+G4QKwr1ODdzxB1tAEAAAADZ2l0AAAAAAAAAAZzaGE1MTIAAABTAAAAC3NzaC1lZDI1NTE5
+AAAAQJsczqM0KN8PgvY9qEAmPre8tQUSjx0ELR5KsYjC3gmJxXjCHxuyZCu74uBbZr37ck
+knhZsNj7i8PKvio0astgY=
+-----END SSH SIGNATURE-----`
+	rsaSigningKey = "ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAABAQCfOJX4/oA9iAI7MxsODvfYTn1cmwT2s3qie1E9ZuTEI+9BErg/Ws68qrYxFPyBqh2ZSs91wbVDJtssqlEmPDnkusb0JGwUksdOAiYjVl+a+1jlwuc5OUyYzX/q08dSouEoy9T7OkRXOqODTdqjeSj9LjWd+zr0TgMlF5sxwuDgnz6ef7lJjmJzIOT20soWZpDXaev8NvM+m9BlhiXK/FmsGHUc8zO58SktllAOH+/6u/0TdZdvn1DAzHptTSWTeGvWK3DIg0w9W2VphKsloF0ZcVcCkMTj1Cmtg3CDekN89wbzBuKCAtqfM1Y09nGlEDKUWm7W4qxM11WeXATedOUN"
+	rsaSignature  = `-----BEGIN SSH SIGNATURE-----
+U1NIU0lHAAAAAQAAARcAAAAHc3NoLXJzYQAAAAMBAAEAAAEBAJ84lfj+gD2IAjszGw4O99
+hOfVybBPazeqJ7UT1m5MQj70ESuD9azryqtjEU/IGqHZlKz3XBtUMm2yyqUSY8OeS6xvQk
+bBSSx04CJiNWX5r7WOXC5zk5TJjNf+rTx1Ki4SjL1Ps6RFc6o4NN2qN5KP0uNZ37OvROAy
+UXmzHC4OCfPp5/uUmOYnMg5PbSyhZmkNdp6/w28z6b0GWGJcr8WawYdRzzM7nxKS2WUA4f
+7/q7/RN1l2+fUMDMem1NJZN4a9YrcMiDTD1bZWmEqyWgXRlxVwKQxOPUKa2DcIN6Q3z3Bv
+MG4oIC2p8zVjT2caUQMpRabtbirEzXVZ5cBN505Q0AAAADZ2l0AAAAAAAAAAZzaGE1MTIA
+AAEUAAAADHJzYS1zaGEyLTUxMgAAAQCAqtIlzjFHn36S6QvXKBK8eTH7kRE0Fh1l/wvd7Z
+i9OdDW9GkhAGoUq4DyYI8mnzJ+2TXlS2XQNsEY9gpLzmfB7JE3CivZU/oxvXWqp9nXqz3I
+2sz1PNH6LxwPmC5Tjz2vU+ZLtr6XKeLiIWurp3TGpcVcvvZUPCLlP9WkDWZxVPhhdowt88
+OZtF8K9BttVbKrbJOvJJ/UMlQl4Pu6cgGQP9PP3yOZJvUiWIJ8DG0arKvQsC1AtBzhFCu0
+gZAWrTD+Zi45S1SSeIlBvGoV7Lj0yRqHGu+9BemiYOVi1/qkVJhwrRgxUCaDMbIkxDGErJ
+jtyGfMzTTal5wdDAalksID
+-----END SSH SIGNATURE-----`
+)
+
+func TestVerifySignature(t *testing.T) {
+	message := []byte("hello world\n")
+	for _, c := range []struct {
+		name, key, sig string
+	}{
+		{"ed25519", ed25519SigningKey, ed25519Signature},
+		{"rsa", rsaSigningKey, rsaSignature},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			pk, _, err := ParseAuthorizedKey(c.key)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			sig, err := ParseSignature([]byte(c.sig + "\n"))
+			if err != nil {
+				t.Fatalf("ParseSignature() => %v, want nil error", err)
+			}
+
+			if !KeysEqual(sig.PublicKey, pk) {
+				t.Errorf("ParseSignature() key = %s, want %s", MarshalAuthorizedKey(sig.PublicKey), c.key)
+			}
+
+			if err := sig.Verify(message, "git"); err != nil {
+				t.Errorf("Verify() => %v, want nil error", err)
+			}
+
+			if err := sig.Verify([]byte("hello world"), "git"); !errors.Is(err, ErrInvalidSignature) {
+				t.Errorf("Verify() of another message => %v, want ErrInvalidSignature", err)
+			}
+
+			if err := sig.Verify(message, "file"); !errors.Is(err, ErrInvalidSignature) {
+				t.Errorf("Verify() in another namespace => %v, want ErrInvalidSignature", err)
+			}
+		})
+	}
+}
+
+func TestParseInvalidSignature(t *testing.T) {
+	for _, sig := range []string{
+		"",
+		"-----BEGIN PGP SIGNATURE-----\nabc\n-----END PGP SIGNATURE-----",
+		"-----BEGIN SSH SIGNATURE-----\n!!!\n-----END SSH SIGNATURE-----",
+		"-----BEGIN SSH SIGNATURE-----\naGVsbG8=\n-----END SSH SIGNATURE-----",
+		ed25519Signature[:len(ed25519Signature)-30],
+	} {
+		if _, err := ParseSignature([]byte(sig)); !errors.Is(err, ErrInvalidSignature) {
+			t.Errorf("ParseSignature(%q) => %v, want ErrInvalidSignature", sig, err)
+		}
+	}
+}
@@ -3,6 +3,9 @@ package sshutils
 import (
 	"bytes"
 	"context"
+	"fmt"
+	"net"
+	"strings"
 
 	"charm.land/ssh"
 	gossh "golang.org/x/crypto/ssh"
@@ -49,3 +52,38 @@ func SessionFromContext(ctx context.Context) ssh.Session {
 	}
 	return nil
 }
+
+// CheckSourceAddress checks that addr is allowed by the certificate's
+// source-address critical option, if any.
+func CheckSourceAddress(cert *gossh.Certificate, addr net.Addr) error {
+	allowed, ok := cert.CriticalOptions["source-address"]
+	if !ok {
+		return nil
+	}
+
+	tcpAddr, ok := addr.(*net.TCPAddr)
+	if !ok {
+		return fmt.Errorf("source-address: unsupported address %q", addr)
+	}
+
+	for _, source := range strings.Split(allowed, ",") {
+		source = strings.TrimSpace(source)
+		if ip := net.ParseIP(source); ip != nil {
+			if ip.Equal(tcpAddr.IP) {
+				return nil
+			}
+			continue
+		}
+
+		_, ipNet, err := net.ParseCIDR(source)
+		if err != nil {
+			return fmt.Errorf("source-address: invalid address %q", source)
+		}
+
+		if ipNet.Contains(tcpAddr.IP) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("source-address: address %q is not allowed", tcpAddr.IP)
+}
@@ -1,6 +1,7 @@
 package sshutils
 
 import (
+	"net"
 	"testing"
 
 	"github.com/charmbracelet/keygen"
@@ -122,3 +123,35 @@ func TestKeysEqual(t *testing.T) {
 		}
 	}
 }
+
+func TestCheckSourceAddress(t *testing.T) {
+	addr := &net.TCPAddr{IP: net.ParseIP("192.0.2.10"), Port: 2222}
+	cases := []struct {
+		option string
+		ok     bool
+	}{
+		{"", true},
+		{"192.0.2.10", true},
+		{"198.51.100.0/24, 192.0.2.0/28", true},
+		{"192.0.2.11", false},
+		{"198.51.100.0/24", false},
+		{"nope", false},
+	}
+
+	for _, c := range cases {
+		cert := &ssh.Certificate{}
+		if c.option != "" {
+			cert.CriticalOptions = map[string]string{"source-address": c.option}
+		}
+
+		err := CheckSourceAddress(cert, addr)
+		if (err == nil) != c.ok {
+			t.Errorf("CheckSourceAddress(%q) returned %v, expected ok %v", c.option, err, c.ok)
+		}
+	}
+
+	cert := &ssh.Certificate{Permissions: ssh.Permissions{CriticalOptions: map[string]string{"source-address": "192.0.2.10"}}}
+	if err := CheckSourceAddress(cert, &net.UnixAddr{Name: "sock"}); err == nil {
+		t.Errorf("CheckSourceAddress() with a unix address returned nil, expected an error")
+	}
+}
@@ -0,0 +1,634 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// S3Options are the options of an S3Storage.
+type S3Options struct {
+	// Endpoint is the URL of the S3-compatible server, e.g.
+	// "https://s3.us-east-1.amazonaws.com" or "http://localhost:9000".
+	Endpoint string
+
+	// Region is the region requests are signed for.
+	Region string
+
+	// Bucket is the bucket objects are stored in.
+	Bucket string
+
+	// Prefix is prepended to object names to form their keys.
+	Prefix string
+
+	// AccessKeyID and SecretAccessKey are the credentials requests are
+	// signed with.
+	AccessKeyID     string
+	SecretAccessKey string
+
+	// PathStyle addresses the bucket in the path of the URL rather than in
+	// its host name. Most self-hosted servers, like MinIO, need it.
+	PathStyle bool
+
+	// Client is the HTTP client requests are sent with. It defaults to
+	// http.DefaultClient.
+	Client *http.Client
+}
+
+// S3Storage is a storage implementation that stores objects in an
+// S3-compatible object storage.
+type S3Storage struct {
+	opts     S3Options
+	endpoint *url.URL
+	client   *http.Client
+
+	// now returns the time requests are signed at.
+	now func() time.Time
+}
+
+var (
+	_ Storage   = (*S3Storage)(nil)
+	_ Presigner = (*S3Storage)(nil)
+)
+
+// NewS3Storage creates a new S3Storage.
+func NewS3Storage(opts S3Options) (*S3Storage, error) {
+	if opts.Bucket == "" {
+		return nil, errors.New("s3: missing bucket")
+	}
+
+	if opts.Region == "" {
+		opts.Region = "us-east-1"
+	}
+
+	if opts.Endpoint == "" {
+		opts.Endpoint = "https://s3." + opts.Region + ".amazonaws.com"
+	}
+
+	u, err := url.Parse(opts.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("s3: invalid endpoint: %w", err)
+	}
+
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("s3: invalid endpoint %q", opts.Endpoint)
+	}
+
+	client := opts.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	opts.Prefix = strings.Trim(opts.Prefix, "/")
+	return &S3Storage{
+		opts:     opts,
+		endpoint: u,
+		client:   client,
+		now:      time.Now,
+	}, nil
+}
+
+// S3Error is an error response from an S3-compatible server.
+type S3Error struct {
+	StatusCode int
+	Code       string `xml:"Code"`
+	Message    string `xml:"Message"`
+}
+
+// Error implements error.
+func (e *S3Error) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("s3: %s", http.StatusText(e.StatusCode))
+	}
+	return fmt.Sprintf("s3: %s: %s", e.Code, e.Message)
+}
+
+// Is reports a missing object as fs.ErrNotExist, like LocalStorage does.
+func (e *S3Error) Is(target error) bool {
+	return target == fs.ErrNotExist && e.StatusCode == http.StatusNotFound && e.Code != "NoSuchBucket"
+}
+
+// Delete implements Storage.
+func (s *S3Storage) Delete(name string) error {
+	key, err := s.key(name)
+	if err != nil {
+		return err
+	}
+
+	res, err := s.do(http.MethodDelete, key, nil, -1, nil)
+	if err != nil {
+		return err
+	}
+
+	return res.Body.Close()
+}
+
+// Open implements Storage.
+func (s *S3Storage) Open(name string) (Object, error) {
+	key, err := s.key(name)
+	if err != nil {
+		return nil, err
+	}
+
+	fi, err := s.stat(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &s3Object{s: s, key: key, name: name, fi: fi}, nil
+}
+
+// Stat implements Storage.
+func (s *S3Storage) Stat(name string) (fs.FileInfo, error) {
+	key, err := s.key(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.stat(key)
+}
+
+// Put implements Storage.
+func (s *S3Storage) Put(name string, r io.Reader) (int64, error) {
+	key, err := s.key(name)
+	if err != nil {
+		return 0, err
+	}
+
+	// S3 needs the length of an object upfront. Spool readers of unknown
+	// length to a temporary file to learn it.
+	size := readerLen(r)
+	if size < 0 {
+		f, err := os.CreateTemp("", "soft-serve-s3-*")
+		if err != nil {
+			return 0, err
+		}
+
+		defer os.Remove(f.Name()) //nolint: errcheck
+		defer f.Close()           //nolint: errcheck
+		if size, err = io.Copy(f, r); err != nil {
+			return 0, err
+		}
+
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return 0, err
+		}
+
+		r = f
+	}
+
+	res, err := s.do(http.MethodPut, key, r, size, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	return size, res.Body.Close()
+}
+
+// Exists implements Storage.
+func (s *S3Storage) Exists(name string) (bool, error) {
+	key, err := s.key(name)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = s.stat(key)
+	if err == nil {
+		return true, nil
+	}
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	return false, err
+}
+
+// Rename implements Storage. S3 has no rename, the object is copied to its
+// new name and then deleted.
+func (s *S3Storage) Rename(oldName, newName string) error {
+	oldKey, err := s.key(oldName)
+	if err != nil {
+		return err
+	}
+
+	newKey, err := s.key(newName)
+	if err != nil {
+		return err
+	}
+
+	hdr := http.Header{}
+	hdr.Set("X-Amz-Copy-Source", uriEncode("/"+s.opts.Bucket+"/"+oldKey, false))
+	res, err := s.do(http.MethodPut, newKey, nil, 0, hdr)
+	if err != nil {
+		return err
+	}
+
+	// A copy can fail after the response started, with an error in a 200
+	// response body.
+	body, err := io.ReadAll(res.Body)
+	res.Body.Close() //nolint: errcheck
+	if err != nil {
+		return err
+	}
+
+	if e := parseS3Error(http.StatusInternalServerError, body); e.Code != "" {
+		return e
+	}
+
+	return s.Delete(oldName)
+}
+
+// PresignGet implements Presigner.
+func (s *S3Storage) PresignGet(name string, expires time.Duration) (string, error) {
+	key, err := s.key(name)
+	if err != nil {
+		return "", err
+	}
+
+	return s.presign(http.MethodGet, key, -1, expires), nil
+}
+
+// PresignPut implements Presigner.
+func (s *S3Storage) PresignPut(name string, size int64, expires time.Duration) (string, error) {
+	key, err := s.key(name)
+	if err != nil {
+		return "", err
+	}
+
+	return s.presign(http.MethodPut, key, size, expires), nil
+}
+
+// key returns the object key of name, and ensures it stays under the prefix
+// like LocalStorage.fixPath does for its root.
+func (s *S3Storage) key(name string) (string, error) {
+	if path.IsAbs(name) {
+		return "", ErrPathTraversal
+	}
+
+	key := path.Join(s.opts.Prefix, name)
+	if strings.HasPrefix(key, "../") || key == ".." || key == "." {
+		return "", ErrPathTraversal
+	}
+
+	if s.opts.Prefix != "" && !strings.HasPrefix(key, s.opts.Prefix+"/") {
+		return "", ErrPathTraversal
+	}
+
+	return key, nil
+}
+
+// stat returns the file info of the object key.
+func (s *S3Storage) stat(key string) (fs.FileInfo, error) {
+	res, err := s.do(http.MethodHead, key, nil, -1, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res.Body.Close() //nolint: errcheck
+	modTime, _ := http.ParseTime(res.Header.Get("Last-Modified"))
+	return &s3FileInfo{
+		name:    path.Base(key),
+		size:    res.ContentLength,
+		modTime: modTime,
+	}, nil
+}
+
+// objectURL returns the URL of the object key.
+func (s *S3Storage) objectURL(key string) *url.URL {
+	u := *s.endpoint
+	p := "/" + key
+	if s.opts.PathStyle {
+		p = "/" + s.opts.Bucket + p
+	} else {
+		u.Host = s.opts.Bucket + "." + u.Host
+	}
+
+	u.Path = strings.TrimSuffix(u.Path, "/") + p
+	u.RawPath = uriEncode(u.Path, false)
+	return &u
+}
+
+// do sends a signed request for the object key. size is the length of body,
+// or -1 without one. It returns an *S3Error for non-2xx responses.
+func (s *S3Storage) do(method, key string, body io.Reader, size int64, hdr http.Header) (*http.Response, error) {
+	req, err := http.NewRequest(method, s.objectURL(key).String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	for k, v := range hdr {
+		req.Header[k] = v
+	}
+
+	if size >= 0 {
+		req.ContentLength = size
+		if size == 0 {
+			req.Body = http.NoBody
+		}
+	}
+
+	s.sign(req)
+	res, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		defer res.Body.Close() //nolint: errcheck
+		body, _ := io.ReadAll(io.LimitReader(res.Body, 64*1024))
+		return nil, parseS3Error(res.StatusCode, body)
+	}
+
+	return res, nil
+}
+
+// parseS3Error parses an S3 error response body.
+func parseS3Error(statusCode int, body []byte) *S3Error {
+	e := &S3Error{StatusCode: statusCode}
+	_ = xml.Unmarshal(body, e)
+	return e
+}
+
+const (
+	s3Algorithm       = "AWS4-HMAC-SHA256"
+	s3TimeFormat      = "20060102T150405Z"
+	s3UnsignedPayload = "UNSIGNED-PAYLOAD"
+)
+
+// sign signs req with AWS Signature Version 4 in its Authorization header.
+// The payload isn't part of the signature, so bodies can be streamed.
+func (s *S3Storage) sign(req *http.Request) {
+	now := s.now().UTC()
+	req.Header.Set("X-Amz-Date", now.Format(s3TimeFormat))
+	req.Header.Set("X-Amz-Content-Sha256", s3UnsignedPayload)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for k, v := range req.Header {
+		if k := strings.ToLower(k); strings.HasPrefix(k, "x-amz-") {
+			headers[k] = strings.Join(v, ",")
+		}
+	}
+
+	canonicalHeaders, signedHeaders := canonicalS3Headers(headers)
+	scope := s.scope(now)
+	signature := s.signature(now, scope, strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		canonicalS3Query(req.URL.Query()),
+		canonicalHeaders,
+		signedHeaders,
+		s3UnsignedPayload,
+	}, "\n"))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3Algorithm, s.opts.AccessKeyID, scope, signedHeaders, signature))
+}
+
+// presign returns a URL for the object key, signed with AWS Signature
+// Version 4 in its query. A non-negative size is signed as the
+// Content-Length the request must have.
+func (s *S3Storage) presign(method, key string, size int64, expires time.Duration) string {
+	now := s.now().UTC()
+	u := s.objectURL(key)
+	headers := map[string]string{"host": u.Host}
+	if size >= 0 {
+		headers["content-length"] = strconv.FormatInt(size, 10)
+	}
+
+	canonicalHeaders, signedHeaders := canonicalS3Headers(headers)
+	scope := s.scope(now)
+	q := url.Values{}
+	q.Set("X-Amz-Algorithm", s3Algorithm)
+	q.Set("X-Amz-Credential", s.opts.AccessKeyID+"/"+scope)
+	q.Set("X-Amz-Date", now.Format(s3TimeFormat))
+	q.Set("X-Amz-Expires", strconv.FormatInt(int64(expires/time.Second), 10))
+	q.Set("X-Amz-SignedHeaders", signedHeaders)
+
+	signature := s.signature(now, scope, strings.Join([]string{
+		method,
+		u.EscapedPath(),
+		canonicalS3Query(q),
+		canonicalHeaders,
+		signedHeaders,
+		s3UnsignedPayload,
+	}, "\n"))
+
+	u.RawQuery = canonicalS3Query(q) + "&X-Amz-Signature=" + signature
+	return u.String()
+}
+
+// scope returns the credential scope of a request signed at t.
+func (s *S3Storage) scope(t time.Time) string {
+	return t.Format("20060102") + "/" + s.opts.Region + "/s3/aws4_request"
+}
+
+// signature returns the signature of a canonical request signed at t.
+func (s *S3Storage) signature(t time.Time, scope, canonicalRequest string) string {
+	hash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		s3Algorithm,
+		t.Format(s3TimeFormat),
+		scope,
+		hex.EncodeToString(hash[:]),
+	}, "\n")
+
+	key := []byte("AWS4" + s.opts.SecretAccessKey)
+	for _, v := range []string{t.Format("20060102"), s.opts.Region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, v)
+	}
+
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+// canonicalS3Headers returns the canonical headers and signed headers of
+// lowercase headers.
+func canonicalS3Headers(headers map[string]string) (string, string) {
+	keys := make([]string, 0, len(headers))
+	for k := range headers {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+	var b strings.Builder
+	for _, k := range keys {
+		b.WriteString(k + ":" + strings.TrimSpace(headers[k]) + "\n")
+	}
+
+	return b.String(), strings.Join(keys, ";")
+}
+
+// canonicalS3Query returns the canonical query string of q.
+func canonicalS3Query(q url.Values) string {
+	keys := make([]string, 0, len(q))
+	for k := range q {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		vs := append([]string(nil), q[k]...)
+		sort.Strings(vs)
+		for _, v := range vs {
+			parts = append(parts, uriEncode(k, true)+"="+uriEncode(v, true))
+		}
+	}
+
+	return strings.Join(parts, "&")
+}
+
+// uriEncode percent-encodes every byte of s but the unreserved characters of
+// RFC 3986, and slashes unless encodeSlash is set, as Signature Version 4
+// requires.
+func uriEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+
+	return b.String()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data)) //nolint: errcheck
+	return h.Sum(nil)
+}
+
+// readerLen returns the number of bytes left in r, or -1 if unknown.
+func readerLen(r io.Reader) int64 {
+	switch r := r.(type) {
+	case *bytes.Reader:
+		return int64(r.Len())
+	case *bytes.Buffer:
+		return int64(r.Len())
+	case *strings.Reader:
+		return int64(r.Len())
+	}
+
+	return -1
+}
+
+// s3Object is an object read from an S3Storage. It's fetched lazily, from
+// the current offset, so seeking doesn't download what's skipped.
+type s3Object struct {
+	s    *S3Storage
+	key  string
+	name string
+	fi   fs.FileInfo
+
+	offset int64
+	body   io.ReadCloser
+}
+
+var _ Object = (*s3Object)(nil)
+
+// Name implements Object.
+func (o *s3Object) Name() string {
+	return o.name
+}
+
+// Stat implements fs.File.
+func (o *s3Object) Stat() (fs.FileInfo, error) {
+	return o.fi, nil
+}
+
+// Read implements fs.File.
+func (o *s3Object) Read(p []byte) (int, error) {
+	if o.offset >= o.fi.Size() {
+		return 0, io.EOF
+	}
+
+	if o.body == nil {
+		var hdr http.Header
+		if o.offset > 0 {
+			hdr = http.Header{}
+			hdr.Set("Range", fmt.Sprintf("bytes=%d-", o.offset))
+		}
+
+		res, err := o.s.do(http.MethodGet, o.key, nil, -1, hdr)
+		if err != nil {
+			return 0, err
+		}
+
+		o.body = res.Body
+	}
+
+	n, err := o.body.Read(p)
+	o.offset += int64(n)
+	return n, err
+}
+
+// Seek implements io.Seeker.
+func (o *s3Object) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += o.offset
+	case io.SeekEnd:
+		offset += o.fi.Size()
+	default:
+		return 0, errors.New("s3: invalid whence")
+	}
+
+	if offset < 0 {
+		return 0, errors.New("s3: negative position")
+	}
+
+	if offset != o.offset && o.body != nil {
+		o.body.Close() //nolint: errcheck
+		o.body = nil
+	}
+
+	o.offset = offset
+	return offset, nil
+}
+
+// Close implements fs.File.
+func (o *s3Object) Close() error {
+	if o.body == nil {
+		return nil
+	}
+
+	err := o.body.Close()
+	o.body = nil
+	return err
+}
+
+// s3FileInfo is the fs.FileInfo of an S3 object.
+type s3FileInfo struct {
+	name    string
+	size    int64
+	modTime time.Time
+}
+
+var _ fs.FileInfo = (*s3FileInfo)(nil)
+
+func (fi *s3FileInfo) Name() string       { return fi.name }
+func (fi *s3FileInfo) Size() int64        { return fi.size }
+func (fi *s3FileInfo) Mode() fs.FileMode  { return 0o444 }
+func (fi *s3FileInfo) ModTime() time.Time { return fi.modTime }
+func (fi *s3FileInfo) IsDir() bool        { return false }
+func (fi *s3FileInfo) Sys() any           { return nil }
@@ -3,6 +3,7 @@ package storage
 import (
 	"io"
 	"io/fs"
+	"time"
 )
 
 // Object is an interface for objects that can be stored.
@@ -21,3 +22,14 @@ type Storage interface {
 	Exists(name string) (bool, error)
 	Rename(oldName, newName string) error
 }
+
+// Presigner is implemented by storages that can hand out URLs to transfer
+// an object directly, without going through the server.
+type Presigner interface {
+	// PresignGet returns a URL to download the object name, valid for
+	// expires.
+	PresignGet(name string, expires time.Duration) (string, error)
+	// PresignPut returns a URL to upload the object name of the given size,
+	// valid for expires.
+	PresignPut(name string, size int64, expires time.Duration) (string, error)
+}
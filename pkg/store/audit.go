@@ -0,0 +1,16 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/db/models"
+)
+
+// AuditStore is an interface for the audit log. The log is append-only,
+// events are never updated or deleted.
+type AuditStore interface {
+	CreateAuditEvent(ctx context.Context, h db.Handler, event models.AuditEvent) error
+	GetAuditEvents(ctx context.Context, h db.Handler, actor string, action string, target string, since time.Time, limit int) ([]models.AuditEvent, error)
+}
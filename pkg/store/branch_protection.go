@@ -0,0 +1,22 @@
+package store
+
+import (
+	"context"
+
+	"github.com/charmbracelet/soft-serve/pkg/access"
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/db/models"
+)
+
+// BranchProtectionStore is an interface for managing protected branch rules.
+type BranchProtectionStore interface {
+	GetBranchProtectionByRepoAndPattern(ctx context.Context, h db.Handler, repo string, pattern string) (models.BranchProtection, error)
+	ListBranchProtectionsByRepo(ctx context.Context, h db.Handler, repo string) ([]models.BranchProtection, error)
+	CreateBranchProtection(ctx context.Context, h db.Handler, repo string, pattern string, blockForcePush bool, blockDeletion bool, minAccessLevel access.AccessLevel) (int64, error)
+	UpdateBranchProtectionByID(ctx context.Context, h db.Handler, id int64, blockForcePush bool, blockDeletion bool, minAccessLevel access.AccessLevel) error
+	DeleteBranchProtectionByRepoAndPattern(ctx context.Context, h db.Handler, repo string, pattern string) error
+
+	AddBranchProtectionUserByUsername(ctx context.Context, h db.Handler, id int64, username string) error
+	RemoveBranchProtectionUsers(ctx context.Context, h db.Handler, id int64) error
+	ListBranchProtectionUsers(ctx context.Context, h db.Handler, id int64) ([]models.User, error)
+}
@@ -14,14 +14,14 @@ type accessTokenStore struct{}
 var _ store.AccessTokenStore = (*accessTokenStore)(nil)
 
 // CreateAccessToken implements store.AccessTokenStore.
-func (s *accessTokenStore) CreateAccessToken(ctx context.Context, h db.Handler, name string, userID int64, token string, expiresAt time.Time) (models.AccessToken, error) {
-	queryWithoutExpires := `INSERT INTO access_tokens (name, user_id, token, created_at, updated_at)
-	VALUES (?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP) RETURNING id`
-	queryWithExpires := `INSERT INTO access_tokens (name, user_id, token, expires_at, created_at, updated_at)
-	VALUES (?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP) RETURNING id`
+func (s *accessTokenStore) CreateAccessToken(ctx context.Context, h db.Handler, name string, userID int64, token string, expiresAt time.Time, scopes string, repos string) (models.AccessToken, error) {
+	queryWithoutExpires := `INSERT INTO access_tokens (name, user_id, token, scopes, repos, created_at, updated_at)
+	VALUES (?, ?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP) RETURNING id`
+	queryWithExpires := `INSERT INTO access_tokens (name, user_id, token, scopes, repos, expires_at, created_at, updated_at)
+	VALUES (?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP) RETURNING id`
 
 	query := queryWithoutExpires
-	values := []interface{}{name, userID, token}
+	values := []interface{}{name, userID, token, scopes, repos}
 	if !expiresAt.IsZero() {
 		query = queryWithExpires
 		values = append(values, expiresAt.UTC())
@@ -65,6 +65,22 @@ func (*accessTokenStore) GetAccessTokensByUserID(ctx context.Context, h db.Handl
 	return m, err
 }
 
+// GetAllAccessTokens implements store.AccessTokenStore.
+func (*accessTokenStore) GetAllAccessTokens(ctx context.Context, h db.Handler) ([]models.AccessToken, error) {
+	query := h.Rebind(`SELECT * FROM access_tokens ORDER BY id`)
+	var m []models.AccessToken
+	err := h.SelectContext(ctx, &m, query)
+	return m, err
+}
+
+// TouchAccessToken implements store.AccessTokenStore.
+func (*accessTokenStore) TouchAccessToken(ctx context.Context, h db.Handler, id int64, addr string) error {
+	query := h.Rebind(`UPDATE access_tokens SET last_used_at = CURRENT_TIMESTAMP, last_used_addr = ?,
+	use_count = use_count + 1 WHERE id = ?`)
+	_, err := h.ExecContext(ctx, query, addr, id)
+	return err
+}
+
 // GetAccessTokenByToken implements store.AccessTokenStore.
 func (*accessTokenStore) GetAccessTokenByToken(ctx context.Context, h db.Handler, token string) (models.AccessToken, error) {
 	query := h.Rebind(`SELECT * FROM access_tokens WHERE token = ?`)
@@ -0,0 +1,62 @@
+package database
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/db/models"
+	"github.com/charmbracelet/soft-serve/pkg/store"
+)
+
+type auditStore struct{}
+
+var _ store.AuditStore = (*auditStore)(nil)
+
+// CreateAuditEvent implements store.AuditStore.
+func (*auditStore) CreateAuditEvent(ctx context.Context, tx db.Handler, event models.AuditEvent) error {
+	query := tx.Rebind(`INSERT INTO audit_log (actor, action, target, details, addr, transport, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?);`)
+	_, err := tx.ExecContext(ctx, query, event.Actor, event.Action, event.Target,
+		event.Details, event.Addr, event.Transport, event.CreatedAt)
+	return err
+}
+
+// GetAuditEvents implements store.AuditStore.
+func (*auditStore) GetAuditEvents(ctx context.Context, tx db.Handler, actor string, action string, target string, since time.Time, limit int) ([]models.AuditEvent, error) {
+	var where []string
+	var args []interface{}
+	if actor != "" {
+		where = append(where, "actor = ?")
+		args = append(args, actor)
+	}
+	if action != "" {
+		// An action also matches the actions it prefixes, "repo" matches
+		// "repo.delete".
+		where = append(where, "(action = ? OR action LIKE ?)")
+		args = append(args, action, action+".%")
+	}
+	if target != "" {
+		where = append(where, "target = ?")
+		args = append(args, target)
+	}
+	if !since.IsZero() {
+		where = append(where, "created_at >= ?")
+		args = append(args, since.UTC())
+	}
+
+	query := `SELECT id, actor, action, target, details, addr, transport, created_at FROM audit_log`
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY id DESC"
+	if limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, limit)
+	}
+
+	var events []models.AuditEvent
+	err := tx.SelectContext(ctx, &events, tx.Rebind(query), args...)
+	return events, err
+}
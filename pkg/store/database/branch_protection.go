@@ -0,0 +1,129 @@
+package database
+
+import (
+	"context"
+	"strings"
+
+	"github.com/charmbracelet/soft-serve/pkg/access"
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/db/models"
+	"github.com/charmbracelet/soft-serve/pkg/store"
+	"github.com/charmbracelet/soft-serve/pkg/utils"
+)
+
+type branchProtectionStore struct{}
+
+var _ store.BranchProtectionStore = (*branchProtectionStore)(nil)
+
+// CreateBranchProtection implements store.BranchProtectionStore.
+func (*branchProtectionStore) CreateBranchProtection(ctx context.Context, tx db.Handler, repo string, pattern string, blockForcePush bool, blockDeletion bool, minAccessLevel access.AccessLevel) (int64, error) {
+	var id int64
+	repo = utils.SanitizeRepo(repo)
+	query := tx.Rebind(`INSERT INTO branch_protections (repo_id, pattern, block_force_push, block_deletion, min_access_level, updated_at)
+			VALUES (
+				(
+					SELECT id FROM repos WHERE name = ?
+				),
+				?, ?, ?, ?, CURRENT_TIMESTAMP
+			) RETURNING id;`)
+	err := tx.GetContext(ctx, &id, query, repo, pattern, blockForcePush, blockDeletion, minAccessLevel)
+	return id, err
+}
+
+// GetBranchProtectionByRepoAndPattern implements store.BranchProtectionStore.
+func (*branchProtectionStore) GetBranchProtectionByRepoAndPattern(ctx context.Context, tx db.Handler, repo string, pattern string) (models.BranchProtection, error) {
+	var m models.BranchProtection
+	repo = utils.SanitizeRepo(repo)
+	err := tx.GetContext(ctx, &m, tx.Rebind(`
+		SELECT
+			branch_protections.*
+		FROM
+			branch_protections
+		INNER JOIN repos ON repos.id = branch_protections.repo_id
+		WHERE
+			repos.name = ? AND branch_protections.pattern = ?
+	`), repo, pattern)
+	return m, err
+}
+
+// ListBranchProtectionsByRepo implements store.BranchProtectionStore.
+func (*branchProtectionStore) ListBranchProtectionsByRepo(ctx context.Context, tx db.Handler, repo string) ([]models.BranchProtection, error) {
+	var m []models.BranchProtection
+	repo = utils.SanitizeRepo(repo)
+	err := tx.SelectContext(ctx, &m, tx.Rebind(`
+		SELECT
+			branch_protections.*
+		FROM
+			branch_protections
+		INNER JOIN repos ON repos.id = branch_protections.repo_id
+		WHERE
+			repos.name = ?
+		ORDER BY
+			branch_protections.pattern ASC
+	`), repo)
+	return m, err
+}
+
+// UpdateBranchProtectionByID implements store.BranchProtectionStore.
+func (*branchProtectionStore) UpdateBranchProtectionByID(ctx context.Context, tx db.Handler, id int64, blockForcePush bool, blockDeletion bool, minAccessLevel access.AccessLevel) error {
+	query := tx.Rebind(`UPDATE branch_protections SET block_force_push = ?, block_deletion = ?, min_access_level = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?;`)
+	_, err := tx.ExecContext(ctx, query, blockForcePush, blockDeletion, minAccessLevel, id)
+	return err
+}
+
+// DeleteBranchProtectionByRepoAndPattern implements store.BranchProtectionStore.
+func (*branchProtectionStore) DeleteBranchProtectionByRepoAndPattern(ctx context.Context, tx db.Handler, repo string, pattern string) error {
+	repo = utils.SanitizeRepo(repo)
+	query := tx.Rebind(`
+		DELETE FROM
+			branch_protections
+		WHERE
+			repo_id = (
+				SELECT id FROM repos WHERE name = ?
+			) AND pattern = ?
+	`)
+	_, err := tx.ExecContext(ctx, query, repo, pattern)
+	return err
+}
+
+// AddBranchProtectionUserByUsername implements store.BranchProtectionStore.
+func (*branchProtectionStore) AddBranchProtectionUserByUsername(ctx context.Context, tx db.Handler, id int64, username string) error {
+	username = strings.ToLower(username)
+	if err := utils.ValidateUsername(username); err != nil {
+		return err
+	}
+
+	query := tx.Rebind(`INSERT INTO branch_protection_users (branch_protection_id, user_id)
+			VALUES (
+				?,
+				(
+					SELECT id FROM users WHERE username = ?
+				)
+			);`)
+	_, err := tx.ExecContext(ctx, query, id, username)
+	return err
+}
+
+// RemoveBranchProtectionUsers implements store.BranchProtectionStore.
+func (*branchProtectionStore) RemoveBranchProtectionUsers(ctx context.Context, tx db.Handler, id int64) error {
+	query := tx.Rebind(`DELETE FROM branch_protection_users WHERE branch_protection_id = ?;`)
+	_, err := tx.ExecContext(ctx, query, id)
+	return err
+}
+
+// ListBranchProtectionUsers implements store.BranchProtectionStore.
+func (*branchProtectionStore) ListBranchProtectionUsers(ctx context.Context, tx db.Handler, id int64) ([]models.User, error) {
+	var m []models.User
+	err := tx.SelectContext(ctx, &m, tx.Rebind(`
+		SELECT
+			users.*
+		FROM
+			users
+		INNER JOIN branch_protection_users ON branch_protection_users.user_id = users.id
+		WHERE
+			branch_protection_users.branch_protection_id = ?
+		ORDER BY
+			users.username ASC
+	`), id)
+	return m, err
+}
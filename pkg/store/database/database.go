@@ -19,6 +19,17 @@ type datastore struct {
 	*repoStore
 	*userStore
 	*collabStore
+	*deployKeyStore
+	*branchProtectionStore
+	*teamStore
+	*pullRequestStore
+	*issueStore
+	*pushPolicyStore
+	*mirrorCredentialStore
+	*pushMirrorStore
+	*mirrorSyncStore
+	*quotaStore
+	*auditStore
 	*lfsStore
 	*accessTokenStore
 	*webhookStore
@@ -35,12 +46,23 @@ func New(ctx context.Context, db *db.DB) store.Store {
 		db:     db,
 		logger: logger,
 
-		settingsStore:    &settingsStore{},
-		repoStore:        &repoStore{},
-		userStore:        &userStore{},
-		collabStore:      &collabStore{},
-		lfsStore:         &lfsStore{},
-		accessTokenStore: &accessTokenStore{},
+		settingsStore:         &settingsStore{},
+		repoStore:             &repoStore{},
+		userStore:             &userStore{},
+		collabStore:           &collabStore{},
+		deployKeyStore:        &deployKeyStore{},
+		branchProtectionStore: &branchProtectionStore{},
+		teamStore:             &teamStore{},
+		pullRequestStore:      &pullRequestStore{},
+		issueStore:            &issueStore{},
+		pushPolicyStore:       &pushPolicyStore{},
+		mirrorCredentialStore: &mirrorCredentialStore{},
+		pushMirrorStore:       &pushMirrorStore{},
+		mirrorSyncStore:       &mirrorSyncStore{},
+		quotaStore:            &quotaStore{},
+		auditStore:            &auditStore{},
+		lfsStore:              &lfsStore{},
+		accessTokenStore:      &accessTokenStore{},
 	}
 
 	return s
@@ -0,0 +1,72 @@
+package database
+
+import (
+	"context"
+
+	"github.com/charmbracelet/soft-serve/pkg/access"
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/db/models"
+	"github.com/charmbracelet/soft-serve/pkg/sshutils"
+	"github.com/charmbracelet/soft-serve/pkg/store"
+	"github.com/charmbracelet/soft-serve/pkg/utils"
+	"golang.org/x/crypto/ssh"
+)
+
+type deployKeyStore struct{}
+
+var _ store.DeployKeyStore = (*deployKeyStore)(nil)
+
+// CreateDeployKeyByRepo implements store.DeployKeyStore.
+func (*deployKeyStore) CreateDeployKeyByRepo(ctx context.Context, tx db.Handler, repo string, title string, pk ssh.PublicKey, level access.AccessLevel) (models.DeployKey, error) {
+	var m models.DeployKey
+	repo = utils.SanitizeRepo(repo)
+	query := tx.Rebind(`INSERT INTO deploy_keys (repo_id, title, public_key, access_level, updated_at)
+			VALUES (
+				(
+					SELECT id FROM repos WHERE name = ?
+				),
+				?, ?, ?, CURRENT_TIMESTAMP
+			) RETURNING *;`)
+	err := tx.GetContext(ctx, &m, query, repo, title, sshutils.MarshalAuthorizedKey(pk), level)
+	return m, err
+}
+
+// ListDeployKeysByRepo implements store.DeployKeyStore.
+func (*deployKeyStore) ListDeployKeysByRepo(ctx context.Context, tx db.Handler, repo string) ([]models.DeployKey, error) {
+	var m []models.DeployKey
+	repo = utils.SanitizeRepo(repo)
+	err := tx.SelectContext(ctx, &m, tx.Rebind(`
+		SELECT
+			deploy_keys.*
+		FROM
+			deploy_keys
+		INNER JOIN repos ON repos.id = deploy_keys.repo_id
+		WHERE
+			repos.name = ?
+		ORDER BY
+			deploy_keys.id ASC
+	`), repo)
+	return m, err
+}
+
+// FindDeployKeyByPublicKey implements store.DeployKeyStore.
+func (*deployKeyStore) FindDeployKeyByPublicKey(ctx context.Context, tx db.Handler, pk ssh.PublicKey) (models.DeployKey, error) {
+	var m models.DeployKey
+	query := tx.Rebind(`SELECT * FROM deploy_keys WHERE public_key = ?;`)
+	err := tx.GetContext(ctx, &m, query, sshutils.MarshalAuthorizedKey(pk))
+	return m, err
+}
+
+// DeleteDeployKeyByRepo implements store.DeployKeyStore.
+func (*deployKeyStore) DeleteDeployKeyByRepo(ctx context.Context, tx db.Handler, repo string, id int64) error {
+	repo = utils.SanitizeRepo(repo)
+	query := tx.Rebind(`
+		DELETE FROM
+			deploy_keys
+		WHERE
+			id = ? AND repo_id = (
+				SELECT id FROM repos WHERE name = ?
+			);`)
+	_, err := tx.ExecContext(ctx, query, id, repo)
+	return err
+}
@@ -0,0 +1,153 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/db/models"
+	"github.com/charmbracelet/soft-serve/pkg/store"
+	"github.com/charmbracelet/soft-serve/pkg/utils"
+)
+
+type issueStore struct{}
+
+var _ store.IssueStore = (*issueStore)(nil)
+
+// CreateIssue implements store.IssueStore.
+//
+// Issues are numbered per repository, starting at 1. It returns the number
+// of the new issue.
+func (*issueStore) CreateIssue(ctx context.Context, tx db.Handler, repo string, userID int64, title string, body string) (int64, error) {
+	var number int64
+	repo = utils.SanitizeRepo(repo)
+	query := tx.Rebind(`INSERT INTO issues (repo_id, number, user_id, title, body, state, updated_at)
+			VALUES (
+				(
+					SELECT id FROM repos WHERE name = ?
+				),
+				(
+					SELECT COALESCE(MAX(issues.number), 0) + 1
+					FROM issues
+					INNER JOIN repos ON repos.id = issues.repo_id
+					WHERE repos.name = ?
+				),
+				?, ?, ?, 'open', CURRENT_TIMESTAMP
+			) RETURNING number;`)
+	err := tx.GetContext(ctx, &number, query, repo, repo, sql.NullInt64{Int64: userID, Valid: userID > 0}, title, body)
+	return number, err
+}
+
+// GetIssueByRepoAndNumber implements store.IssueStore.
+func (*issueStore) GetIssueByRepoAndNumber(ctx context.Context, tx db.Handler, repo string, number int64) (models.Issue, error) {
+	var m models.Issue
+	repo = utils.SanitizeRepo(repo)
+	err := tx.GetContext(ctx, &m, tx.Rebind(`
+		SELECT
+			issues.*
+		FROM
+			issues
+		INNER JOIN repos ON repos.id = issues.repo_id
+		WHERE
+			repos.name = ? AND issues.number = ?
+	`), repo, number)
+	return m, err
+}
+
+// ListIssuesByRepo implements store.IssueStore.
+//
+// An empty state lists issues in any state, and an empty label lists issues
+// regardless of their labels.
+func (*issueStore) ListIssuesByRepo(ctx context.Context, tx db.Handler, repo string, state string, label string) ([]models.Issue, error) {
+	var m []models.Issue
+	repo = utils.SanitizeRepo(repo)
+	values := []interface{}{repo}
+	query := `
+		SELECT
+			issues.*
+		FROM
+			issues
+		INNER JOIN repos ON repos.id = issues.repo_id
+		WHERE
+			repos.name = ?`
+	if state != "" {
+		query += ` AND issues.state = ?`
+		values = append(values, state)
+	}
+	if label != "" {
+		query += ` AND issues.id IN (
+				SELECT issue_id FROM issue_labels WHERE name = ?
+			)`
+		values = append(values, label)
+	}
+	query += `
+		ORDER BY
+			issues.number DESC`
+	err := tx.SelectContext(ctx, &m, tx.Rebind(query), values...)
+	return m, err
+}
+
+// UpdateIssueStateByID implements store.IssueStore.
+func (*issueStore) UpdateIssueStateByID(ctx context.Context, tx db.Handler, id int64, state string) error {
+	query := tx.Rebind(`UPDATE issues SET state = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?;`)
+	_, err := tx.ExecContext(ctx, query, state, id)
+	return err
+}
+
+// AddIssueLabel implements store.IssueStore.
+//
+// Adding a label the issue already has is a no-op.
+func (*issueStore) AddIssueLabel(ctx context.Context, tx db.Handler, id int64, label string) error {
+	query := tx.Rebind(`INSERT INTO issue_labels (issue_id, name)
+			VALUES (?, ?)
+			ON CONFLICT (issue_id, name) DO NOTHING;`)
+	_, err := tx.ExecContext(ctx, query, id, label)
+	return err
+}
+
+// RemoveIssueLabel implements store.IssueStore.
+func (*issueStore) RemoveIssueLabel(ctx context.Context, tx db.Handler, id int64, label string) error {
+	query := tx.Rebind(`DELETE FROM issue_labels WHERE issue_id = ? AND name = ?;`)
+	_, err := tx.ExecContext(ctx, query, id, label)
+	return err
+}
+
+// ListIssueLabels implements store.IssueStore.
+func (*issueStore) ListIssueLabels(ctx context.Context, tx db.Handler, id int64) ([]string, error) {
+	var labels []string
+	err := tx.SelectContext(ctx, &labels, tx.Rebind(`
+		SELECT
+			name
+		FROM
+			issue_labels
+		WHERE
+			issue_id = ?
+		ORDER BY
+			name ASC
+	`), id)
+	return labels, err
+}
+
+// CreateIssueComment implements store.IssueStore.
+func (*issueStore) CreateIssueComment(ctx context.Context, tx db.Handler, id int64, userID int64, body string) error {
+	query := tx.Rebind(`INSERT INTO issue_comments (issue_id, user_id, body, updated_at)
+			VALUES (?, ?, ?, CURRENT_TIMESTAMP);`)
+	_, err := tx.ExecContext(ctx, query, id, sql.NullInt64{Int64: userID, Valid: userID > 0}, body)
+	return err
+}
+
+// ListIssueComments implements store.IssueStore.
+func (*issueStore) ListIssueComments(ctx context.Context, tx db.Handler, id int64) ([]models.IssueComment, error) {
+	var m []models.IssueComment
+	err := tx.SelectContext(ctx, &m, tx.Rebind(`
+		SELECT
+			*
+		FROM
+			issue_comments
+		WHERE
+			issue_id = ?
+		ORDER BY
+			id ASC
+	`), id)
+	return m, err
+}
@@ -0,0 +1,63 @@
+package database
+
+import (
+	"context"
+
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/db/models"
+	"github.com/charmbracelet/soft-serve/pkg/store"
+	"github.com/charmbracelet/soft-serve/pkg/utils"
+)
+
+type mirrorCredentialStore struct{}
+
+var _ store.MirrorCredentialStore = (*mirrorCredentialStore)(nil)
+
+// GetMirrorCredentialByRepo implements store.MirrorCredentialStore.
+func (*mirrorCredentialStore) GetMirrorCredentialByRepo(ctx context.Context, tx db.Handler, repo string) (models.MirrorCredential, error) {
+	var m models.MirrorCredential
+	repo = utils.SanitizeRepo(repo)
+	err := tx.GetContext(ctx, &m, tx.Rebind(`
+		SELECT
+			mirror_credentials.*
+		FROM
+			mirror_credentials
+		INNER JOIN repos ON repos.id = mirror_credentials.repo_id
+		WHERE
+			repos.name = ?
+	`), repo)
+	return m, err
+}
+
+// SetMirrorCredentialByRepo implements store.MirrorCredentialStore.
+func (*mirrorCredentialStore) SetMirrorCredentialByRepo(ctx context.Context, tx db.Handler, repo string, cred models.MirrorCredential) error {
+	repo = utils.SanitizeRepo(repo)
+	query := tx.Rebind(`INSERT INTO mirror_credentials (repo_id, type, username, secret, updated_at)
+			VALUES (
+				(
+					SELECT id FROM repos WHERE name = ?
+				),
+				?, ?, ?, CURRENT_TIMESTAMP
+			)
+			ON CONFLICT (repo_id) DO UPDATE SET
+				type = excluded.type,
+				username = excluded.username,
+				secret = excluded.secret,
+				updated_at = CURRENT_TIMESTAMP;`)
+	_, err := tx.ExecContext(ctx, query, repo, cred.Type, cred.Username, cred.Secret)
+	return err
+}
+
+// DeleteMirrorCredentialByRepo implements store.MirrorCredentialStore.
+func (*mirrorCredentialStore) DeleteMirrorCredentialByRepo(ctx context.Context, tx db.Handler, repo string) error {
+	repo = utils.SanitizeRepo(repo)
+	query := tx.Rebind(`
+		DELETE FROM
+			mirror_credentials
+		WHERE
+			repo_id = (
+				SELECT id FROM repos WHERE name = ?
+			);`)
+	_, err := tx.ExecContext(ctx, query, repo)
+	return err
+}
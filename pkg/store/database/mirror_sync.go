@@ -0,0 +1,78 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/db/models"
+	"github.com/charmbracelet/soft-serve/pkg/store"
+	"github.com/charmbracelet/soft-serve/pkg/utils"
+)
+
+type mirrorSyncStore struct{}
+
+var _ store.MirrorSyncStore = (*mirrorSyncStore)(nil)
+
+// GetMirrorSyncByRepo implements store.MirrorSyncStore.
+func (*mirrorSyncStore) GetMirrorSyncByRepo(ctx context.Context, tx db.Handler, repo string) (models.MirrorSync, error) {
+	var m models.MirrorSync
+	repo = utils.SanitizeRepo(repo)
+	err := tx.GetContext(ctx, &m, tx.Rebind(`
+		SELECT
+			mirror_syncs.*
+		FROM
+			mirror_syncs
+		INNER JOIN repos ON repos.id = mirror_syncs.repo_id
+		WHERE
+			repos.name = ?
+	`), repo)
+	return m, err
+}
+
+// GetMirrorSyncs implements store.MirrorSyncStore.
+func (*mirrorSyncStore) GetMirrorSyncs(ctx context.Context, tx db.Handler) ([]models.MirrorSync, error) {
+	var m []models.MirrorSync
+	err := tx.SelectContext(ctx, &m, `SELECT * FROM mirror_syncs ORDER BY repo_id ASC;`)
+	return m, err
+}
+
+// SetMirrorSyncIntervalByRepo implements store.MirrorSyncStore.
+func (*mirrorSyncStore) SetMirrorSyncIntervalByRepo(ctx context.Context, tx db.Handler, repo string, interval int64) error {
+	repo = utils.SanitizeRepo(repo)
+	query := tx.Rebind(`INSERT INTO mirror_syncs (repo_id, sync_interval, updated_at)
+			VALUES (
+				(
+					SELECT id FROM repos WHERE name = ?
+				),
+				?, CURRENT_TIMESTAMP
+			)
+			ON CONFLICT (repo_id) DO UPDATE SET
+				sync_interval = excluded.sync_interval,
+				updated_at = CURRENT_TIMESTAMP;`)
+	_, err := tx.ExecContext(ctx, query, repo, interval)
+	return err
+}
+
+// SetMirrorSyncStatusByRepo implements store.MirrorSyncStore.
+func (*mirrorSyncStore) SetMirrorSyncStatusByRepo(ctx context.Context, tx db.Handler, repo string, attemptedAt time.Time, lastError string, duration int64) error {
+	repo = utils.SanitizeRepo(repo)
+	query := tx.Rebind(`INSERT INTO mirror_syncs (repo_id, last_attempt_at, last_success_at, last_error, last_duration, updated_at)
+			VALUES (
+				(
+					SELECT id FROM repos WHERE name = ?
+				),
+				?, ?, ?, ?, CURRENT_TIMESTAMP
+			)
+			ON CONFLICT (repo_id) DO UPDATE SET
+				last_attempt_at = excluded.last_attempt_at,
+				last_success_at = COALESCE(excluded.last_success_at, mirror_syncs.last_success_at),
+				last_error = excluded.last_error,
+				last_duration = excluded.last_duration,
+				updated_at = CURRENT_TIMESTAMP;`)
+	// A failed sync keeps the time of the last successful one.
+	lastSuccessAt := sql.NullTime{Time: attemptedAt, Valid: lastError == ""}
+	_, err := tx.ExecContext(ctx, query, repo, attemptedAt, lastSuccessAt, lastError, duration)
+	return err
+}
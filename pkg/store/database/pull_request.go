@@ -0,0 +1,119 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/db/models"
+	"github.com/charmbracelet/soft-serve/pkg/store"
+	"github.com/charmbracelet/soft-serve/pkg/utils"
+)
+
+type pullRequestStore struct{}
+
+var _ store.PullRequestStore = (*pullRequestStore)(nil)
+
+// CreatePullRequest implements store.PullRequestStore.
+//
+// Pull requests are numbered per repository, starting at 1. It returns the
+// number of the new pull request.
+func (*pullRequestStore) CreatePullRequest(ctx context.Context, tx db.Handler, repo string, userID int64, title string, description string, sourceRef string, targetBranch string) (int64, error) {
+	var number int64
+	repo = utils.SanitizeRepo(repo)
+	query := tx.Rebind(`INSERT INTO pull_requests (repo_id, number, user_id, title, description, source_ref, target_branch, state, updated_at)
+			VALUES (
+				(
+					SELECT id FROM repos WHERE name = ?
+				),
+				(
+					SELECT COALESCE(MAX(pull_requests.number), 0) + 1
+					FROM pull_requests
+					INNER JOIN repos ON repos.id = pull_requests.repo_id
+					WHERE repos.name = ?
+				),
+				?, ?, ?, ?, ?, 'open', CURRENT_TIMESTAMP
+			) RETURNING number;`)
+	err := tx.GetContext(ctx, &number, query, repo, repo, sql.NullInt64{Int64: userID, Valid: userID > 0}, title, description, sourceRef, targetBranch)
+	return number, err
+}
+
+// GetPullRequestByRepoAndNumber implements store.PullRequestStore.
+func (*pullRequestStore) GetPullRequestByRepoAndNumber(ctx context.Context, tx db.Handler, repo string, number int64) (models.PullRequest, error) {
+	var m models.PullRequest
+	repo = utils.SanitizeRepo(repo)
+	err := tx.GetContext(ctx, &m, tx.Rebind(`
+		SELECT
+			pull_requests.*
+		FROM
+			pull_requests
+		INNER JOIN repos ON repos.id = pull_requests.repo_id
+		WHERE
+			repos.name = ? AND pull_requests.number = ?
+	`), repo, number)
+	return m, err
+}
+
+// ListPullRequestsByRepo implements store.PullRequestStore.
+//
+// An empty state lists pull requests in any state.
+func (*pullRequestStore) ListPullRequestsByRepo(ctx context.Context, tx db.Handler, repo string, state string) ([]models.PullRequest, error) {
+	var m []models.PullRequest
+	repo = utils.SanitizeRepo(repo)
+	values := []interface{}{repo}
+	query := `
+		SELECT
+			pull_requests.*
+		FROM
+			pull_requests
+		INNER JOIN repos ON repos.id = pull_requests.repo_id
+		WHERE
+			repos.name = ?`
+	if state != "" {
+		query += ` AND pull_requests.state = ?`
+		values = append(values, state)
+	}
+	query += `
+		ORDER BY
+			pull_requests.number DESC`
+	err := tx.SelectContext(ctx, &m, tx.Rebind(query), values...)
+	return m, err
+}
+
+// UpdatePullRequestStateByID implements store.PullRequestStore.
+func (*pullRequestStore) UpdatePullRequestStateByID(ctx context.Context, tx db.Handler, id int64, state string, mergeCommit string) error {
+	query := tx.Rebind(`UPDATE pull_requests SET state = ?, merge_commit = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?;`)
+	_, err := tx.ExecContext(ctx, query, state, sql.NullString{String: mergeCommit, Valid: mergeCommit != ""}, id)
+	return err
+}
+
+// UpdatePullRequestSourceRefByID implements store.PullRequestStore.
+func (*pullRequestStore) UpdatePullRequestSourceRefByID(ctx context.Context, tx db.Handler, id int64, sourceRef string) error {
+	query := tx.Rebind(`UPDATE pull_requests SET source_ref = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?;`)
+	_, err := tx.ExecContext(ctx, query, sourceRef, id)
+	return err
+}
+
+// CreatePullRequestComment implements store.PullRequestStore.
+func (*pullRequestStore) CreatePullRequestComment(ctx context.Context, tx db.Handler, id int64, userID int64, body string) error {
+	query := tx.Rebind(`INSERT INTO pull_request_comments (pull_request_id, user_id, body, updated_at)
+			VALUES (?, ?, ?, CURRENT_TIMESTAMP);`)
+	_, err := tx.ExecContext(ctx, query, id, sql.NullInt64{Int64: userID, Valid: userID > 0}, body)
+	return err
+}
+
+// ListPullRequestComments implements store.PullRequestStore.
+func (*pullRequestStore) ListPullRequestComments(ctx context.Context, tx db.Handler, id int64) ([]models.PullRequestComment, error) {
+	var m []models.PullRequestComment
+	err := tx.SelectContext(ctx, &m, tx.Rebind(`
+		SELECT
+			*
+		FROM
+			pull_request_comments
+		WHERE
+			pull_request_id = ?
+		ORDER BY
+			id ASC
+	`), id)
+	return m, err
+}
@@ -0,0 +1,123 @@
+package database
+
+import (
+	"context"
+
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/db/models"
+	"github.com/charmbracelet/soft-serve/pkg/store"
+	"github.com/charmbracelet/soft-serve/pkg/utils"
+)
+
+type pushMirrorStore struct{}
+
+var _ store.PushMirrorStore = (*pushMirrorStore)(nil)
+
+// GetPushMirrorsByRepo implements store.PushMirrorStore.
+func (*pushMirrorStore) GetPushMirrorsByRepo(ctx context.Context, tx db.Handler, repo string) ([]models.PushMirror, error) {
+	var m []models.PushMirror
+	repo = utils.SanitizeRepo(repo)
+	err := tx.SelectContext(ctx, &m, tx.Rebind(`
+		SELECT
+			push_mirrors.*
+		FROM
+			push_mirrors
+		INNER JOIN repos ON repos.id = push_mirrors.repo_id
+		WHERE
+			repos.name = ?
+		ORDER BY
+			push_mirrors.name ASC
+	`), repo)
+	return m, err
+}
+
+// CreatePushMirror implements store.PushMirrorStore.
+func (*pushMirrorStore) CreatePushMirror(ctx context.Context, tx db.Handler, repo string, name string, url string, credentials string) error {
+	repo = utils.SanitizeRepo(repo)
+	query := tx.Rebind(`INSERT INTO push_mirrors (repo_id, name, url, credentials, updated_at)
+			VALUES (
+				(
+					SELECT id FROM repos WHERE name = ?
+				),
+				?, ?, ?, CURRENT_TIMESTAMP
+			);`)
+	_, err := tx.ExecContext(ctx, query, repo, name, url, credentials)
+	return err
+}
+
+// DeletePushMirrorByRepoAndName implements store.PushMirrorStore.
+func (*pushMirrorStore) DeletePushMirrorByRepoAndName(ctx context.Context, tx db.Handler, repo string, name string) error {
+	repo = utils.SanitizeRepo(repo)
+	query := tx.Rebind(`
+		DELETE FROM
+			push_mirrors
+		WHERE
+			repo_id = (
+				SELECT id FROM repos WHERE name = ?
+			) AND name = ?;`)
+	_, err := tx.ExecContext(ctx, query, repo, name)
+	return err
+}
+
+// SetPushMirrorURLByID implements store.PushMirrorStore.
+func (*pushMirrorStore) SetPushMirrorURLByID(ctx context.Context, tx db.Handler, id int64, url string, credentials string) error {
+	query := tx.Rebind(`
+		UPDATE
+			push_mirrors
+		SET
+			url = ?,
+			credentials = ?,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE
+			id = ?;`)
+	_, err := tx.ExecContext(ctx, query, url, credentials, id)
+	return err
+}
+
+// GetReposWithRequestedPushMirrorSync implements store.PushMirrorStore.
+func (*pushMirrorStore) GetReposWithRequestedPushMirrorSync(ctx context.Context, tx db.Handler) ([]string, error) {
+	var repos []string
+	err := tx.SelectContext(ctx, &repos, tx.Rebind(`
+		SELECT DISTINCT
+			repos.name
+		FROM
+			push_mirrors
+		INNER JOIN repos ON repos.id = push_mirrors.repo_id
+		WHERE
+			push_mirrors.sync_requested = ?
+		ORDER BY
+			repos.name ASC
+	`), true)
+	return repos, err
+}
+
+// SetPushMirrorSyncRequestedByRepo implements store.PushMirrorStore.
+func (*pushMirrorStore) SetPushMirrorSyncRequestedByRepo(ctx context.Context, tx db.Handler, repo string, requested bool) error {
+	repo = utils.SanitizeRepo(repo)
+	query := tx.Rebind(`
+		UPDATE
+			push_mirrors
+		SET
+			sync_requested = ?
+		WHERE
+			repo_id = (
+				SELECT id FROM repos WHERE name = ?
+			);`)
+	_, err := tx.ExecContext(ctx, query, requested, repo)
+	return err
+}
+
+// SetPushMirrorStatusByID implements store.PushMirrorStore.
+func (*pushMirrorStore) SetPushMirrorStatusByID(ctx context.Context, tx db.Handler, id int64, lastError string) error {
+	query := tx.Rebind(`
+		UPDATE
+			push_mirrors
+		SET
+			last_sync_at = CURRENT_TIMESTAMP,
+			last_error = ?,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE
+			id = ?;`)
+	_, err := tx.ExecContext(ctx, query, lastError, id)
+	return err
+}
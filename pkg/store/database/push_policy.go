@@ -0,0 +1,67 @@
+package database
+
+import (
+	"context"
+
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/db/models"
+	"github.com/charmbracelet/soft-serve/pkg/store"
+	"github.com/charmbracelet/soft-serve/pkg/utils"
+)
+
+type pushPolicyStore struct{}
+
+var _ store.PushPolicyStore = (*pushPolicyStore)(nil)
+
+// GetPushPolicyByRepo implements store.PushPolicyStore.
+func (*pushPolicyStore) GetPushPolicyByRepo(ctx context.Context, tx db.Handler, repo string) (models.PushPolicy, error) {
+	var m models.PushPolicy
+	repo = utils.SanitizeRepo(repo)
+	err := tx.GetContext(ctx, &m, tx.Rebind(`
+		SELECT
+			push_policies.*
+		FROM
+			push_policies
+		INNER JOIN repos ON repos.id = push_policies.repo_id
+		WHERE
+			repos.name = ?
+	`), repo)
+	return m, err
+}
+
+// SetPushPolicyByRepo implements store.PushPolicyStore.
+func (*pushPolicyStore) SetPushPolicyByRepo(ctx context.Context, tx db.Handler, repo string, policy models.PushPolicy) error {
+	repo = utils.SanitizeRepo(repo)
+	query := tx.Rebind(`INSERT INTO push_policies (repo_id, max_file_size, forbidden_paths, commit_message_pattern, author_email_domains, no_merge_branches, require_signed_commits, updated_at)
+			VALUES (
+				(
+					SELECT id FROM repos WHERE name = ?
+				),
+				?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP
+			)
+			ON CONFLICT (repo_id) DO UPDATE SET
+				max_file_size = excluded.max_file_size,
+				forbidden_paths = excluded.forbidden_paths,
+				commit_message_pattern = excluded.commit_message_pattern,
+				author_email_domains = excluded.author_email_domains,
+				no_merge_branches = excluded.no_merge_branches,
+				require_signed_commits = excluded.require_signed_commits,
+				updated_at = CURRENT_TIMESTAMP;`)
+	_, err := tx.ExecContext(ctx, query, repo, policy.MaxFileSize, policy.ForbiddenPaths,
+		policy.CommitMessagePattern, policy.AuthorEmailDomains, policy.NoMergeBranches, policy.RequireSignedCommits)
+	return err
+}
+
+// DeletePushPolicyByRepo implements store.PushPolicyStore.
+func (*pushPolicyStore) DeletePushPolicyByRepo(ctx context.Context, tx db.Handler, repo string) error {
+	repo = utils.SanitizeRepo(repo)
+	query := tx.Rebind(`
+		DELETE FROM
+			push_policies
+		WHERE
+			repo_id = (
+				SELECT id FROM repos WHERE name = ?
+			);`)
+	_, err := tx.ExecContext(ctx, query, repo)
+	return err
+}
@@ -0,0 +1,100 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/db/models"
+	"github.com/charmbracelet/soft-serve/pkg/store"
+	"github.com/charmbracelet/soft-serve/pkg/utils"
+)
+
+type quotaStore struct{}
+
+var _ store.QuotaStore = (*quotaStore)(nil)
+
+// GetRepoQuotaByName implements store.QuotaStore.
+func (*quotaStore) GetRepoQuotaByName(ctx context.Context, tx db.Handler, repo string) (models.Quota, error) {
+	var q models.Quota
+	repo = utils.SanitizeRepo(repo)
+	err := tx.GetContext(ctx, &q, tx.Rebind(`
+		SELECT
+			repo_quotas.id, repo_quotas.size, repo_quotas.lfs_size,
+			repo_quotas.created_at, repo_quotas.updated_at
+		FROM
+			repo_quotas
+		INNER JOIN repos ON repos.id = repo_quotas.repo_id
+		WHERE
+			repos.name = ?
+	`), repo)
+	return q, err
+}
+
+// SetRepoQuotaByName implements store.QuotaStore.
+func (*quotaStore) SetRepoQuotaByName(ctx context.Context, tx db.Handler, repo string, size, lfsSize sql.NullInt64) error {
+	repo = utils.SanitizeRepo(repo)
+	query := tx.Rebind(`INSERT INTO repo_quotas (repo_id, size, lfs_size, updated_at)
+			VALUES (
+				(
+					SELECT id FROM repos WHERE name = ?
+				),
+				?, ?, CURRENT_TIMESTAMP
+			)
+			ON CONFLICT (repo_id) DO UPDATE SET
+				size = excluded.size,
+				lfs_size = excluded.lfs_size,
+				updated_at = CURRENT_TIMESTAMP;`)
+	_, err := tx.ExecContext(ctx, query, repo, size, lfsSize)
+	return err
+}
+
+// GetUserQuotaByID implements store.QuotaStore.
+func (*quotaStore) GetUserQuotaByID(ctx context.Context, tx db.Handler, userID int64) (models.Quota, error) {
+	var q models.Quota
+	err := tx.GetContext(ctx, &q, tx.Rebind(`
+		SELECT
+			id, size, lfs_size, created_at, updated_at
+		FROM
+			user_quotas
+		WHERE
+			user_id = ?
+	`), userID)
+	return q, err
+}
+
+// SetUserQuotaByID implements store.QuotaStore.
+func (*quotaStore) SetUserQuotaByID(ctx context.Context, tx db.Handler, userID int64, size, lfsSize sql.NullInt64) error {
+	query := tx.Rebind(`INSERT INTO user_quotas (user_id, size, lfs_size, updated_at)
+			VALUES (?, ?, ?, CURRENT_TIMESTAMP)
+			ON CONFLICT (user_id) DO UPDATE SET
+				size = excluded.size,
+				lfs_size = excluded.lfs_size,
+				updated_at = CURRENT_TIMESTAMP;`)
+	_, err := tx.ExecContext(ctx, query, userID, size, lfsSize)
+	return err
+}
+
+// GetLFSObjectsSizeByRepoID implements store.QuotaStore.
+func (*quotaStore) GetLFSObjectsSizeByRepoID(ctx context.Context, tx db.Handler, repoID int64) (int64, error) {
+	var size int64
+	err := tx.GetContext(ctx, &size, tx.Rebind(`
+		SELECT COALESCE(SUM(size), 0) FROM lfs_objects WHERE repo_id = ?
+	`), repoID)
+	return size, err
+}
+
+// GetLFSObjectsSizeByUserID implements store.QuotaStore.
+func (*quotaStore) GetLFSObjectsSizeByUserID(ctx context.Context, tx db.Handler, userID int64) (int64, error) {
+	var size int64
+	err := tx.GetContext(ctx, &size, tx.Rebind(`
+		SELECT
+			COALESCE(SUM(lfs_objects.size), 0)
+		FROM
+			lfs_objects
+		INNER JOIN repos ON repos.id = lfs_objects.repo_id
+		WHERE
+			repos.user_id = ?
+	`), userID)
+	return size, err
+}
@@ -158,3 +158,27 @@ func (*repoStore) SetRepoProjectNameByName(ctx context.Context, tx db.Handler, n
 	_, err := tx.ExecContext(ctx, query, projectName, name)
 	return db.WrapError(err)
 }
+
+// GetRepoParentNameByName implements store.RepositoryStore.
+func (*repoStore) GetRepoParentNameByName(ctx context.Context, tx db.Handler, name string) (string, error) {
+	var parent string
+	name = utils.SanitizeRepo(name)
+	query := tx.Rebind(`
+		SELECT
+			parents.name
+		FROM
+			repos
+		INNER JOIN repos AS parents ON parents.id = repos.parent_id
+		WHERE
+			repos.name = ?;`)
+	err := tx.GetContext(ctx, &parent, query, name)
+	return parent, db.WrapError(err)
+}
+
+// SetRepoParentIDByName implements store.RepositoryStore.
+func (*repoStore) SetRepoParentIDByName(ctx context.Context, tx db.Handler, name string, parentID int64) error {
+	name = utils.SanitizeRepo(name)
+	query := tx.Rebind("UPDATE repos SET parent_id = ? WHERE name = ?;")
+	_, err := tx.ExecContext(ctx, query, parentID, name)
+	return db.WrapError(err)
+}
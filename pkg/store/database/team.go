@@ -0,0 +1,227 @@
+package database
+
+import (
+	"context"
+	"strings"
+
+	"github.com/charmbracelet/soft-serve/pkg/access"
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/db/models"
+	"github.com/charmbracelet/soft-serve/pkg/store"
+	"github.com/charmbracelet/soft-serve/pkg/utils"
+)
+
+type teamStore struct{}
+
+var _ store.TeamStore = (*teamStore)(nil)
+
+// CreateTeam implements store.TeamStore.
+func (*teamStore) CreateTeam(ctx context.Context, tx db.Handler, name string) error {
+	name = strings.ToLower(name)
+	if err := utils.ValidateTeam(name); err != nil {
+		return err
+	}
+
+	query := tx.Rebind(`INSERT INTO teams (name, updated_at)
+			VALUES (?, CURRENT_TIMESTAMP);`)
+	_, err := tx.ExecContext(ctx, query, name)
+	return err
+}
+
+// GetTeamByName implements store.TeamStore.
+func (*teamStore) GetTeamByName(ctx context.Context, tx db.Handler, name string) (models.Team, error) {
+	var m models.Team
+	name = strings.ToLower(name)
+	query := tx.Rebind(`SELECT * FROM teams WHERE name = ?;`)
+	err := tx.GetContext(ctx, &m, query, name)
+	return m, err
+}
+
+// GetAllTeams implements store.TeamStore.
+func (*teamStore) GetAllTeams(ctx context.Context, tx db.Handler) ([]models.Team, error) {
+	var m []models.Team
+	query := tx.Rebind(`SELECT * FROM teams ORDER BY name ASC;`)
+	err := tx.SelectContext(ctx, &m, query)
+	return m, err
+}
+
+// DeleteTeamByName implements store.TeamStore.
+func (*teamStore) DeleteTeamByName(ctx context.Context, tx db.Handler, name string) error {
+	name = strings.ToLower(name)
+	query := tx.Rebind(`DELETE FROM teams WHERE name = ?;`)
+	_, err := tx.ExecContext(ctx, query, name)
+	return err
+}
+
+// AddTeamMemberByUsername implements store.TeamStore.
+func (*teamStore) AddTeamMemberByUsername(ctx context.Context, tx db.Handler, team string, username string) error {
+	team = strings.ToLower(team)
+	username = strings.ToLower(username)
+	if err := utils.ValidateUsername(username); err != nil {
+		return err
+	}
+
+	query := tx.Rebind(`INSERT INTO team_members (team_id, user_id)
+			VALUES (
+				(
+					SELECT id FROM teams WHERE name = ?
+				),
+				(
+					SELECT id FROM users WHERE username = ?
+				)
+			);`)
+	_, err := tx.ExecContext(ctx, query, team, username)
+	return err
+}
+
+// RemoveTeamMemberByUsername implements store.TeamStore.
+func (*teamStore) RemoveTeamMemberByUsername(ctx context.Context, tx db.Handler, team string, username string) error {
+	team = strings.ToLower(team)
+	username = strings.ToLower(username)
+	query := tx.Rebind(`
+		DELETE FROM
+			team_members
+		WHERE
+			team_id = (
+				SELECT id FROM teams WHERE name = ?
+			) AND user_id = (
+				SELECT id FROM users WHERE username = ?
+			)
+	`)
+	_, err := tx.ExecContext(ctx, query, team, username)
+	return err
+}
+
+// ListTeamMembers implements store.TeamStore.
+func (*teamStore) ListTeamMembers(ctx context.Context, tx db.Handler, team string) ([]models.User, error) {
+	var m []models.User
+	team = strings.ToLower(team)
+	query := tx.Rebind(`
+		SELECT
+			users.*
+		FROM
+			users
+		INNER JOIN team_members ON team_members.user_id = users.id
+		INNER JOIN teams ON teams.id = team_members.team_id
+		WHERE
+			teams.name = ?
+		ORDER BY
+			users.username ASC
+	`)
+	err := tx.SelectContext(ctx, &m, query, team)
+	return m, err
+}
+
+// ListTeamsByUsername implements store.TeamStore.
+func (*teamStore) ListTeamsByUsername(ctx context.Context, tx db.Handler, username string) ([]models.Team, error) {
+	var m []models.Team
+	username = strings.ToLower(username)
+	query := tx.Rebind(`
+		SELECT
+			teams.*
+		FROM
+			teams
+		INNER JOIN team_members ON team_members.team_id = teams.id
+		INNER JOIN users ON users.id = team_members.user_id
+		WHERE
+			users.username = ?
+		ORDER BY
+			teams.name ASC
+	`)
+	err := tx.SelectContext(ctx, &m, query, username)
+	return m, err
+}
+
+// AddTeamCollabByTeamAndRepo implements store.TeamStore.
+func (*teamStore) AddTeamCollabByTeamAndRepo(ctx context.Context, tx db.Handler, team string, repo string, level access.AccessLevel) error {
+	team = strings.ToLower(team)
+	repo = utils.SanitizeRepo(repo)
+	query := tx.Rebind(`INSERT INTO team_collabs (access_level, team_id, repo_id, updated_at)
+			VALUES (
+				?,
+				(
+					SELECT id FROM teams WHERE name = ?
+				),
+				(
+					SELECT id FROM repos WHERE name = ?
+				),
+				CURRENT_TIMESTAMP
+			);`)
+	_, err := tx.ExecContext(ctx, query, level, team, repo)
+	return err
+}
+
+// RemoveTeamCollabByTeamAndRepo implements store.TeamStore.
+func (*teamStore) RemoveTeamCollabByTeamAndRepo(ctx context.Context, tx db.Handler, team string, repo string) error {
+	team = strings.ToLower(team)
+	repo = utils.SanitizeRepo(repo)
+	query := tx.Rebind(`
+		DELETE FROM
+			team_collabs
+		WHERE
+			team_id = (
+				SELECT id FROM teams WHERE name = ?
+			) AND repo_id = (
+				SELECT id FROM repos WHERE name = ?
+			)
+	`)
+	_, err := tx.ExecContext(ctx, query, team, repo)
+	return err
+}
+
+// ListTeamCollabsByTeam implements store.TeamStore.
+func (*teamStore) ListTeamCollabsByTeam(ctx context.Context, tx db.Handler, team string) ([]models.TeamCollab, error) {
+	var m []models.TeamCollab
+	team = strings.ToLower(team)
+	query := tx.Rebind(`
+		SELECT
+			team_collabs.*
+		FROM
+			team_collabs
+		INNER JOIN teams ON teams.id = team_collabs.team_id
+		WHERE
+			teams.name = ?
+	`)
+	err := tx.SelectContext(ctx, &m, query, team)
+	return m, err
+}
+
+// ListTeamReposByTeam implements store.TeamStore.
+func (*teamStore) ListTeamReposByTeam(ctx context.Context, tx db.Handler, team string) ([]models.Repo, error) {
+	var m []models.Repo
+	team = strings.ToLower(team)
+	query := tx.Rebind(`
+		SELECT
+			repos.*
+		FROM
+			repos
+		INNER JOIN team_collabs ON team_collabs.repo_id = repos.id
+		INNER JOIN teams ON teams.id = team_collabs.team_id
+		WHERE
+			teams.name = ?
+		ORDER BY
+			repos.name ASC
+	`)
+	err := tx.SelectContext(ctx, &m, query, team)
+	return m, err
+}
+
+// ListTeamCollabsByUsernameAndRepo implements store.TeamStore.
+func (*teamStore) ListTeamCollabsByUsernameAndRepo(ctx context.Context, tx db.Handler, username string, repo string) ([]models.TeamCollab, error) {
+	var m []models.TeamCollab
+	username = strings.ToLower(username)
+	repo = utils.SanitizeRepo(repo)
+	query := tx.Rebind(`
+		SELECT
+			team_collabs.*
+		FROM
+			team_collabs
+		INNER JOIN team_members ON team_members.team_id = team_collabs.team_id
+		INNER JOIN users ON users.id = team_members.user_id
+		INNER JOIN repos ON repos.id = team_collabs.repo_id
+		WHERE
+			users.username = ? AND repos.name = ?
+	`)
+	err := tx.SelectContext(ctx, &m, query, username, repo)
+	return m, err
+}
@@ -2,6 +2,8 @@ package database
 
 import (
 	"context"
+	"database/sql"
+	"time"
 
 	"github.com/charmbracelet/soft-serve/pkg/db"
 	"github.com/charmbracelet/soft-serve/pkg/db/models"
@@ -28,14 +30,10 @@ func (*webhookStore) CreateWebhook(ctx context.Context, h db.Handler, repoID int
 }
 
 // CreateWebhookDelivery implements store.WebhookStore.
-func (*webhookStore) CreateWebhookDelivery(ctx context.Context, h db.Handler, id uuid.UUID, webhookID int64, event int, url string, method string, requestError error, requestHeaders string, requestBody string, responseStatus int, responseHeaders string, responseBody string) error {
-	query := h.Rebind(`INSERT INTO webhook_deliveries (id, webhook_id, event, request_url, request_method, request_error, request_headers, request_body, response_status, response_headers, response_body)
-			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);`)
-	var reqErr string
-	if requestError != nil {
-		reqErr = requestError.Error()
-	}
-	_, err := h.ExecContext(ctx, query, id, webhookID, event, url, method, reqErr, requestHeaders, requestBody, responseStatus, responseHeaders, responseBody)
+func (*webhookStore) CreateWebhookDelivery(ctx context.Context, h db.Handler, id uuid.UUID, webhookID int64, event int, url string, method string, requestHeaders string, requestBody string) error {
+	query := h.Rebind(`INSERT INTO webhook_deliveries (id, webhook_id, event, request_url, request_method, request_headers, request_body, response_status, response_headers, response_body)
+			VALUES (?, ?, ?, ?, ?, ?, ?, 0, '', '');`)
+	_, err := h.ExecContext(ctx, query, id, webhookID, event, url, method, requestHeaders, requestBody)
 	return err
 }
 
@@ -149,9 +147,38 @@ func (*webhookStore) GetWebhooksByRepoIDWhereEvent(ctx context.Context, h db.Han
 	return whs, err
 }
 
+// GetPendingWebhookDeliveries implements store.WebhookStore.
+func (*webhookStore) GetPendingWebhookDeliveries(ctx context.Context, h db.Handler, now time.Time) ([]models.WebhookDelivery, error) {
+	// Status 0 is webhook.DeliveryStatusPending.
+	query := h.Rebind(`SELECT webhook_deliveries.*
+			FROM webhook_deliveries
+			INNER JOIN webhooks ON webhooks.id = webhook_deliveries.webhook_id
+			WHERE webhook_deliveries.status = 0
+			AND (webhook_deliveries.next_attempt_at IS NULL OR webhook_deliveries.next_attempt_at <= ?)
+			AND webhooks.active = ? AND webhooks.url = webhook_deliveries.request_url
+			ORDER BY webhook_deliveries.created_at ASC;`)
+	var whds []models.WebhookDelivery
+	err := h.SelectContext(ctx, &whds, query, now, true)
+	return whds, err
+}
+
+// FailStaleWebhookDeliveries implements store.WebhookStore.
+func (*webhookStore) FailStaleWebhookDeliveries(ctx context.Context, h db.Handler) error {
+	// Status 0 is webhook.DeliveryStatusPending, 1 is
+	// webhook.DeliveryStatusFailed.
+	query := h.Rebind(`UPDATE webhook_deliveries SET status = 1, next_attempt_at = NULL, request_error = ?
+			WHERE status = 0 AND NOT EXISTS (
+				SELECT 1 FROM webhooks
+				WHERE webhooks.id = webhook_deliveries.webhook_id
+				AND webhooks.active = ? AND webhooks.url = webhook_deliveries.request_url
+			);`)
+	_, err := h.ExecContext(ctx, query, "webhook was deleted, deactivated, or changed", true)
+	return err
+}
+
 // ListWebhookDeliveriesByWebhookID implements store.WebhookStore.
 func (*webhookStore) ListWebhookDeliveriesByWebhookID(ctx context.Context, h db.Handler, webhookID int64) ([]models.WebhookDelivery, error) {
-	query := h.Rebind(`SELECT id, response_status, event FROM webhook_deliveries WHERE webhook_id = ?;`)
+	query := h.Rebind(`SELECT id, response_status, event, status, attempts, created_at FROM webhook_deliveries WHERE webhook_id = ? ORDER BY created_at ASC;`)
 	var whds []models.WebhookDelivery
 	err := h.SelectContext(ctx, &whds, query, webhookID)
 	return whds, err
@@ -163,3 +190,18 @@ func (*webhookStore) UpdateWebhookByID(ctx context.Context, h db.Handler, repoID
 	_, err := h.ExecContext(ctx, query, url, secret, contentType, active, repoID, id)
 	return err
 }
+
+// UpdateWebhookDeliveryByID implements store.WebhookStore.
+func (*webhookStore) UpdateWebhookDeliveryByID(ctx context.Context, h db.Handler, id uuid.UUID, status int, attempts int, nextAttemptAt time.Time, requestError error, responseStatus int, responseHeaders string, responseBody string) error {
+	query := h.Rebind(`UPDATE webhook_deliveries SET status = ?, attempts = ?, next_attempt_at = ?, request_error = ?, response_status = ?, response_headers = ?, response_body = ? WHERE id = ?;`)
+	var reqErr sql.NullString
+	if requestError != nil {
+		reqErr = sql.NullString{String: requestError.Error(), Valid: true}
+	}
+	var next sql.NullTime
+	if !nextAttemptAt.IsZero() {
+		next = sql.NullTime{Time: nextAttemptAt, Valid: true}
+	}
+	_, err := h.ExecContext(ctx, query, status, attempts, next, reqErr, responseStatus, responseHeaders, responseBody, id)
+	return err
+}
@@ -0,0 +1,18 @@
+package store
+
+import (
+	"context"
+
+	"github.com/charmbracelet/soft-serve/pkg/access"
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/db/models"
+	"golang.org/x/crypto/ssh"
+)
+
+// DeployKeyStore is an interface for managing repository deploy keys.
+type DeployKeyStore interface {
+	CreateDeployKeyByRepo(ctx context.Context, h db.Handler, repo string, title string, pk ssh.PublicKey, level access.AccessLevel) (models.DeployKey, error)
+	ListDeployKeysByRepo(ctx context.Context, h db.Handler, repo string) ([]models.DeployKey, error)
+	FindDeployKeyByPublicKey(ctx context.Context, h db.Handler, pk ssh.PublicKey) (models.DeployKey, error)
+	DeleteDeployKeyByRepo(ctx context.Context, h db.Handler, repo string, id int64) error
+}
@@ -0,0 +1,24 @@
+package store
+
+import (
+	"context"
+
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/db/models"
+)
+
+// IssueStore is an interface for managing issues, their labels, and their
+// comments.
+type IssueStore interface {
+	CreateIssue(ctx context.Context, h db.Handler, repo string, userID int64, title string, body string) (int64, error)
+	GetIssueByRepoAndNumber(ctx context.Context, h db.Handler, repo string, number int64) (models.Issue, error)
+	ListIssuesByRepo(ctx context.Context, h db.Handler, repo string, state string, label string) ([]models.Issue, error)
+	UpdateIssueStateByID(ctx context.Context, h db.Handler, id int64, state string) error
+
+	AddIssueLabel(ctx context.Context, h db.Handler, id int64, label string) error
+	RemoveIssueLabel(ctx context.Context, h db.Handler, id int64, label string) error
+	ListIssueLabels(ctx context.Context, h db.Handler, id int64) ([]string, error)
+
+	CreateIssueComment(ctx context.Context, h db.Handler, id int64, userID int64, body string) error
+	ListIssueComments(ctx context.Context, h db.Handler, id int64) ([]models.IssueComment, error)
+}
@@ -0,0 +1,15 @@
+package store
+
+import (
+	"context"
+
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/db/models"
+)
+
+// MirrorCredentialStore is an interface for managing mirror credentials.
+type MirrorCredentialStore interface {
+	GetMirrorCredentialByRepo(ctx context.Context, h db.Handler, repo string) (models.MirrorCredential, error)
+	SetMirrorCredentialByRepo(ctx context.Context, h db.Handler, repo string, cred models.MirrorCredential) error
+	DeleteMirrorCredentialByRepo(ctx context.Context, h db.Handler, repo string) error
+}
@@ -0,0 +1,18 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/db/models"
+)
+
+// MirrorSyncStore is an interface for managing the sync schedule and status
+// of mirror repositories.
+type MirrorSyncStore interface {
+	GetMirrorSyncByRepo(ctx context.Context, h db.Handler, repo string) (models.MirrorSync, error)
+	GetMirrorSyncs(ctx context.Context, h db.Handler) ([]models.MirrorSync, error)
+	SetMirrorSyncIntervalByRepo(ctx context.Context, h db.Handler, repo string, interval int64) error
+	SetMirrorSyncStatusByRepo(ctx context.Context, h db.Handler, repo string, attemptedAt time.Time, lastError string, duration int64) error
+}
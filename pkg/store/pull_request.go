@@ -0,0 +1,21 @@
+package store
+
+import (
+	"context"
+
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/db/models"
+)
+
+// PullRequestStore is an interface for managing pull requests and their
+// comments.
+type PullRequestStore interface {
+	CreatePullRequest(ctx context.Context, h db.Handler, repo string, userID int64, title string, description string, sourceRef string, targetBranch string) (int64, error)
+	GetPullRequestByRepoAndNumber(ctx context.Context, h db.Handler, repo string, number int64) (models.PullRequest, error)
+	ListPullRequestsByRepo(ctx context.Context, h db.Handler, repo string, state string) ([]models.PullRequest, error)
+	UpdatePullRequestStateByID(ctx context.Context, h db.Handler, id int64, state string, mergeCommit string) error
+	UpdatePullRequestSourceRefByID(ctx context.Context, h db.Handler, id int64, sourceRef string) error
+
+	CreatePullRequestComment(ctx context.Context, h db.Handler, id int64, userID int64, body string) error
+	ListPullRequestComments(ctx context.Context, h db.Handler, id int64) ([]models.PullRequestComment, error)
+}
@@ -0,0 +1,19 @@
+package store
+
+import (
+	"context"
+
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/db/models"
+)
+
+// PushMirrorStore is an interface for managing repository push mirrors.
+type PushMirrorStore interface {
+	GetPushMirrorsByRepo(ctx context.Context, h db.Handler, repo string) ([]models.PushMirror, error)
+	CreatePushMirror(ctx context.Context, h db.Handler, repo string, name string, url string, credentials string) error
+	DeletePushMirrorByRepoAndName(ctx context.Context, h db.Handler, repo string, name string) error
+	SetPushMirrorURLByID(ctx context.Context, h db.Handler, id int64, url string, credentials string) error
+	GetReposWithRequestedPushMirrorSync(ctx context.Context, h db.Handler) ([]string, error)
+	SetPushMirrorSyncRequestedByRepo(ctx context.Context, h db.Handler, repo string, requested bool) error
+	SetPushMirrorStatusByID(ctx context.Context, h db.Handler, id int64, lastError string) error
+}
@@ -0,0 +1,15 @@
+package store
+
+import (
+	"context"
+
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/db/models"
+)
+
+// PushPolicyStore is an interface for managing repository push policies.
+type PushPolicyStore interface {
+	GetPushPolicyByRepo(ctx context.Context, h db.Handler, repo string) (models.PushPolicy, error)
+	SetPushPolicyByRepo(ctx context.Context, h db.Handler, repo string, policy models.PushPolicy) error
+	DeletePushPolicyByRepo(ctx context.Context, h db.Handler, repo string) error
+}
@@ -0,0 +1,20 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/db/models"
+)
+
+// QuotaStore is an interface for managing the storage quotas of repositories
+// and users, and the LFS usage they are checked against.
+type QuotaStore interface {
+	GetRepoQuotaByName(ctx context.Context, h db.Handler, repo string) (models.Quota, error)
+	SetRepoQuotaByName(ctx context.Context, h db.Handler, repo string, size, lfsSize sql.NullInt64) error
+	GetUserQuotaByID(ctx context.Context, h db.Handler, userID int64) (models.Quota, error)
+	SetUserQuotaByID(ctx context.Context, h db.Handler, userID int64, size, lfsSize sql.NullInt64) error
+	GetLFSObjectsSizeByRepoID(ctx context.Context, h db.Handler, repoID int64) (int64, error)
+	GetLFSObjectsSizeByUserID(ctx context.Context, h db.Handler, userID int64) (int64, error)
+}
@@ -26,4 +26,6 @@ type RepositoryStore interface {
 	SetRepoIsHiddenByName(ctx context.Context, h db.Handler, name string, isHidden bool) error
 	GetRepoIsMirrorByName(ctx context.Context, h db.Handler, name string) (bool, error)
 	SetRepoIsMirrorByName(ctx context.Context, h db.Handler, name string, isMirror bool) error
+	GetRepoParentNameByName(ctx context.Context, h db.Handler, name string) (string, error)
+	SetRepoParentIDByName(ctx context.Context, h db.Handler, name string, parentID int64) error
 }
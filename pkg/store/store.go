@@ -5,6 +5,17 @@ type Store interface {
 	RepositoryStore
 	UserStore
 	CollaboratorStore
+	DeployKeyStore
+	BranchProtectionStore
+	TeamStore
+	PullRequestStore
+	IssueStore
+	PushPolicyStore
+	MirrorCredentialStore
+	PushMirrorStore
+	MirrorSyncStore
+	QuotaStore
+	AuditStore
 	SettingStore
 	LFSStore
 	AccessTokenStore
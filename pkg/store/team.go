@@ -0,0 +1,29 @@
+package store
+
+import (
+	"context"
+
+	"github.com/charmbracelet/soft-serve/pkg/access"
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/db/models"
+)
+
+// TeamStore is an interface for managing teams, their members, and their
+// repository grants.
+type TeamStore interface {
+	CreateTeam(ctx context.Context, h db.Handler, name string) error
+	GetTeamByName(ctx context.Context, h db.Handler, name string) (models.Team, error)
+	GetAllTeams(ctx context.Context, h db.Handler) ([]models.Team, error)
+	DeleteTeamByName(ctx context.Context, h db.Handler, name string) error
+
+	AddTeamMemberByUsername(ctx context.Context, h db.Handler, team string, username string) error
+	RemoveTeamMemberByUsername(ctx context.Context, h db.Handler, team string, username string) error
+	ListTeamMembers(ctx context.Context, h db.Handler, team string) ([]models.User, error)
+	ListTeamsByUsername(ctx context.Context, h db.Handler, username string) ([]models.Team, error)
+
+	AddTeamCollabByTeamAndRepo(ctx context.Context, h db.Handler, team string, repo string, level access.AccessLevel) error
+	RemoveTeamCollabByTeamAndRepo(ctx context.Context, h db.Handler, team string, repo string) error
+	ListTeamCollabsByTeam(ctx context.Context, h db.Handler, team string) ([]models.TeamCollab, error)
+	ListTeamReposByTeam(ctx context.Context, h db.Handler, team string) ([]models.Repo, error)
+	ListTeamCollabsByUsernameAndRepo(ctx context.Context, h db.Handler, username string, repo string) ([]models.TeamCollab, error)
+}
@@ -2,6 +2,7 @@ package store
 
 import (
 	"context"
+	"time"
 
 	"github.com/charmbracelet/soft-serve/pkg/db"
 	"github.com/charmbracelet/soft-serve/pkg/db/models"
@@ -41,8 +42,18 @@ type WebhookStore interface {
 	// ListWebhookDeliveriesByWebhookID returns all webhook deliveries for a webhook.
 	// This only returns the delivery ID, response status, and event.
 	ListWebhookDeliveriesByWebhookID(ctx context.Context, h db.Handler, webhookID int64) ([]models.WebhookDelivery, error)
-	// CreateWebhookDelivery creates a webhook delivery.
-	CreateWebhookDelivery(ctx context.Context, h db.Handler, id uuid.UUID, webhookID int64, event int, url string, method string, requestError error, requestHeaders string, requestBody string, responseStatus int, responseHeaders string, responseBody string) error
+	// GetPendingWebhookDeliveries returns the webhook deliveries that are
+	// still waiting to be delivered and due at the given time, for active
+	// webhooks that still point at the delivery URL.
+	GetPendingWebhookDeliveries(ctx context.Context, h db.Handler, now time.Time) ([]models.WebhookDelivery, error)
+	// FailStaleWebhookDeliveries marks the pending deliveries of webhooks
+	// that were deleted, deactivated, or pointed at another URL as failed.
+	FailStaleWebhookDeliveries(ctx context.Context, h db.Handler) error
+	// CreateWebhookDelivery creates a pending webhook delivery.
+	CreateWebhookDelivery(ctx context.Context, h db.Handler, id uuid.UUID, webhookID int64, event int, url string, method string, requestHeaders string, requestBody string) error
+	// UpdateWebhookDeliveryByID records the outcome of a webhook delivery attempt.
+	// A zero nextAttemptAt means no further attempt is scheduled.
+	UpdateWebhookDeliveryByID(ctx context.Context, h db.Handler, id uuid.UUID, status int, attempts int, nextAttemptAt time.Time, requestError error, responseStatus int, responseHeaders string, responseBody string) error
 	// DeleteWebhookDeliveryByID deletes a webhook delivery by its ID.
 	DeleteWebhookDeliveryByID(ctx context.Context, h db.Handler, webhookID int64, id uuid.UUID) error
 }
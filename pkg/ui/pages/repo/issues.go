@@ -0,0 +1,297 @@
+package repo
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"charm.land/bubbles/v2/key"
+	"charm.land/bubbles/v2/spinner"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/ui/common"
+	"github.com/charmbracelet/soft-serve/pkg/ui/components/code"
+	"github.com/charmbracelet/soft-serve/pkg/ui/components/selector"
+	"github.com/charmbracelet/soft-serve/pkg/utils"
+)
+
+type issuesState int
+
+const (
+	issuesStateLoading issuesState = iota
+	issuesStateList
+	issuesStateDetail
+)
+
+// IssueListMsg is a message sent when the issue list is loaded.
+type IssueListMsg []backend.Issue
+
+// IssueDetailMsg is a message sent when an issue's comments are loaded.
+type IssueDetailMsg struct {
+	Issue    backend.Issue
+	Comments []backend.IssueComment
+}
+
+// Issues is the issues component page.
+type Issues struct {
+	common        common.Common
+	code          *code.Code
+	repo          proto.Repository
+	spinner       spinner.Model
+	list          *selector.Selector
+	state         issuesState
+	currentDetail IssueDetailMsg
+}
+
+// NewIssues creates a new issues model.
+func NewIssues(common common.Common) *Issues {
+	code := code.New(common, "", "")
+	s := spinner.New(spinner.WithSpinner(spinner.Dot),
+		spinner.WithStyle(common.Styles.Spinner))
+	selector := selector.New(common, []selector.IdentifiableItem{}, IssueItemDelegate{&common})
+	selector.SetShowFilter(false)
+	selector.SetShowHelp(false)
+	selector.SetShowPagination(false)
+	selector.SetShowStatusBar(false)
+	selector.SetShowTitle(false)
+	selector.SetFilteringEnabled(false)
+	selector.DisableQuitKeybindings()
+	selector.KeyMap.NextPage = common.KeyMap.NextPage
+	selector.KeyMap.PrevPage = common.KeyMap.PrevPage
+	return &Issues{
+		code:    code,
+		common:  common,
+		spinner: s,
+		list:    selector,
+	}
+}
+
+// Path implements common.TabComponent.
+func (p *Issues) Path() string {
+	return ""
+}
+
+// TabName returns the name of the tab.
+func (p *Issues) TabName() string {
+	return "Issues"
+}
+
+// SetSize implements common.Component.
+func (p *Issues) SetSize(width, height int) {
+	p.common.SetSize(width, height)
+	p.code.SetSize(width, height)
+	p.list.SetSize(width, height)
+}
+
+// ShortHelp implements help.KeyMap.
+func (p *Issues) ShortHelp() []key.Binding {
+	return []key.Binding{
+		p.common.KeyMap.Select,
+		p.common.KeyMap.Back,
+		p.common.KeyMap.UpDown,
+	}
+}
+
+// FullHelp implements help.KeyMap.
+func (p *Issues) FullHelp() [][]key.Binding {
+	b := [][]key.Binding{
+		{
+			p.common.KeyMap.Select,
+			p.common.KeyMap.Back,
+			p.common.KeyMap.Copy,
+		},
+		{
+			p.code.KeyMap.Down,
+			p.code.KeyMap.Up,
+			p.common.KeyMap.GotoTop,
+			p.common.KeyMap.GotoBottom,
+		},
+	}
+	return b
+}
+
+// StatusBarValue implements common.Component.
+func (p *Issues) StatusBarValue() string {
+	item, ok := p.list.SelectedItem().(IssueItem)
+	if !ok {
+		return " "
+	}
+	return fmt.Sprintf("%s %s", item.ID(), item.Title())
+}
+
+// StatusBarInfo implements common.Component.
+func (p *Issues) StatusBarInfo() string {
+	switch p.state {
+	case issuesStateList:
+		totalPages := p.list.TotalPages()
+		if totalPages <= 1 {
+			return "p. 1/1"
+		}
+		return fmt.Sprintf("p. %d/%d", p.list.Page()+1, totalPages)
+	case issuesStateDetail:
+		return common.ScrollPercent(p.code.ScrollPosition())
+	default:
+		return ""
+	}
+}
+
+// SpinnerID implements common.Component.
+func (p *Issues) SpinnerID() int {
+	return p.spinner.ID()
+}
+
+// Init initializes the model.
+func (p *Issues) Init() tea.Cmd {
+	p.state = issuesStateLoading
+	return tea.Batch(p.spinner.Tick, p.fetchIssues)
+}
+
+// Update updates the model.
+func (p *Issues) Update(msg tea.Msg) (common.Model, tea.Cmd) {
+	cmds := make([]tea.Cmd, 0)
+	switch msg := msg.(type) {
+	case RepoMsg:
+		p.repo = msg
+	case RefMsg:
+		p.list.Select(0)
+		cmds = append(cmds, p.Init())
+	case tea.WindowSizeMsg:
+		p.SetSize(msg.Width, msg.Height)
+	case spinner.TickMsg:
+		if p.state == issuesStateLoading && p.spinner.ID() == msg.ID {
+			sp, cmd := p.spinner.Update(msg)
+			p.spinner = sp
+			if cmd != nil {
+				cmds = append(cmds, cmd)
+			}
+		}
+	case tea.KeyPressMsg:
+		switch p.state {
+		case issuesStateList:
+			switch {
+			case key.Matches(msg, p.common.KeyMap.BackItem):
+				cmds = append(cmds, goBackCmd)
+			case key.Matches(msg, p.common.KeyMap.Copy):
+				if item, ok := p.list.SelectedItem().(IssueItem); ok {
+					cmds = append(cmds, copyCmd(item.Title(), "Issue title copied to clipboard"))
+				}
+			}
+		case issuesStateDetail:
+			switch {
+			case key.Matches(msg, p.common.KeyMap.BackItem):
+				cmds = append(cmds, goBackCmd)
+			case key.Matches(msg, p.common.KeyMap.Copy):
+				cmds = append(cmds, copyCmd(p.currentDetail.Issue.Body, "Issue body copied to clipboard"))
+			}
+		}
+	case IssueListMsg:
+		p.state = issuesStateList
+		items := make([]selector.IdentifiableItem, len(msg))
+		for i, issue := range msg {
+			items[i] = IssueItem{issue}
+		}
+		cmds = append(cmds, p.list.SetItems(items))
+	case IssueDetailMsg:
+		p.state = issuesStateDetail
+		p.currentDetail = msg
+		cmds = append(cmds, p.code.SetContent(p.renderDetail(msg), ".txt"))
+		p.code.GotoTop()
+	case selector.SelectMsg:
+		switch msg.IdentifiableItem.(type) {
+		case IssueItem:
+			cmds = append(cmds, p.fetchIssueDetail)
+		}
+	case GoBackMsg:
+		if p.state == issuesStateList {
+			p.list.Select(0)
+		}
+		p.state = issuesStateList
+	}
+	switch p.state {
+	case issuesStateList:
+		l, cmd := p.list.Update(msg)
+		p.list = l.(*selector.Selector)
+		if cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+	case issuesStateDetail:
+		c, cmd := p.code.Update(msg)
+		p.code = c.(*code.Code)
+		if cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+	}
+	return p, tea.Batch(cmds...)
+}
+
+// View returns the view.
+func (p *Issues) View() string {
+	switch p.state {
+	case issuesStateLoading:
+		return renderLoading(p.common, p.spinner)
+	case issuesStateList:
+		return p.list.View()
+	case issuesStateDetail:
+		return p.code.View()
+	}
+	return ""
+}
+
+func (p *Issues) renderDetail(msg IssueDetailMsg) string {
+	s := p.common.Styles.Issue
+	issue := msg.Issue
+	lines := []string{
+		s.Title.Render(fmt.Sprintf("#%d %s", issue.Number, utils.Sanitize(issue.Title))),
+		s.Meta.Render(fmt.Sprintf("%s · opened by %s %s",
+			issue.State, issue.Author, issue.CreatedAt.Format(time.RFC822))),
+	}
+	if len(issue.Labels) > 0 {
+		lines = append(lines, s.Label.Render(strings.Join(issue.Labels, " ")))
+	}
+	if issue.Body != "" {
+		lines = append(lines, "", utils.Sanitize(issue.Body))
+	}
+
+	for _, c := range msg.Comments {
+		lines = append(lines,
+			"",
+			s.Author.Render(c.Author)+s.Meta.Render(" commented "+c.CreatedAt.Format(time.RFC822)),
+			strings.TrimSpace(utils.Sanitize(c.Body)),
+		)
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
+
+func (p *Issues) fetchIssues() tea.Msg {
+	if p.repo == nil {
+		return IssueListMsg(nil)
+	}
+
+	be := p.common.Backend()
+	issues, err := be.Issues(p.common.Context(), p.repo.Name(), "", "")
+	if err != nil {
+		return common.ErrorMsg(err)
+	}
+
+	return IssueListMsg(issues)
+}
+
+func (p *Issues) fetchIssueDetail() tea.Msg {
+	item, ok := p.list.SelectedItem().(IssueItem)
+	if p.repo == nil || !ok {
+		return IssueDetailMsg{}
+	}
+
+	comments, err := p.common.Backend().IssueComments(p.common.Context(), p.repo.Name(), item.Number)
+	if err != nil {
+		return common.ErrorMsg(err)
+	}
+
+	return IssueDetailMsg{
+		Issue:    item.Issue,
+		Comments: comments,
+	}
+}
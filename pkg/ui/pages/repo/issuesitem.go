@@ -0,0 +1,101 @@
+package repo
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"charm.land/bubbles/v2/key"
+	"charm.land/bubbles/v2/list"
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/charmbracelet/soft-serve/pkg/ui/common"
+	"github.com/charmbracelet/soft-serve/pkg/utils"
+)
+
+// IssueItem represents an issue item.
+type IssueItem struct{ backend.Issue }
+
+// ID returns the ID of the issue item.
+func (i IssueItem) ID() string {
+	return fmt.Sprintf("#%d", i.Number)
+}
+
+// Title returns the title of the issue item.
+func (i IssueItem) Title() string {
+	return utils.Sanitize(i.Issue.Title)
+}
+
+// Description returns the description of the issue item.
+func (i IssueItem) Description() string {
+	return utils.Sanitize(i.Issue.Body)
+}
+
+// FilterValue implements list.Item.
+func (i IssueItem) FilterValue() string { return i.Title() }
+
+// IssueItemDelegate is a delegate for issue items.
+type IssueItemDelegate struct {
+	common *common.Common
+}
+
+// Height returns the height of the issue item list. Implements list.ItemDelegate.
+func (d IssueItemDelegate) Height() int { return 1 }
+
+// Spacing implements list.ItemDelegate.
+func (d IssueItemDelegate) Spacing() int { return 0 }
+
+// Update implements list.ItemDelegate.
+func (d IssueItemDelegate) Update(msg tea.Msg, m *list.Model) tea.Cmd {
+	item, ok := m.SelectedItem().(IssueItem)
+	if !ok {
+		return nil
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyPressMsg:
+		switch {
+		case key.Matches(msg, d.common.KeyMap.Copy):
+			return copyCmd(item.Title(), fmt.Sprintf("Issue title %q copied to clipboard", item.Title()))
+		}
+	}
+
+	return nil
+}
+
+// Render implements list.ItemDelegate.
+func (d IssueItemDelegate) Render(w io.Writer, m list.Model, index int, listItem list.Item) {
+	item, ok := listItem.(IssueItem)
+	if !ok {
+		return
+	}
+
+	s := d.common.Styles.Issue
+
+	st := s.Normal.Message
+	selector := " "
+	if index == m.Index() {
+		selector = "> "
+		st = s.Active.Message
+	}
+
+	selector = s.Selector.Render(selector)
+	title := st.Render(fmt.Sprintf("%s %s", item.ID(), item.Title()))
+	meta := s.Meta.Render(fmt.Sprintf(" %s · %s", item.State, item.Author))
+	var labels string
+	if len(item.Labels) > 0 {
+		labels = s.Label.Render(" " + strings.Join(item.Labels, " "))
+	}
+	fmt.Fprint(w, d.common.Zone.Mark( //nolint:errcheck
+		item.ID(),
+		common.TruncateString(fmt.Sprintf("%s%s%s%s",
+			selector,
+			title,
+			labels,
+			meta,
+		), m.Width()-
+			s.Selector.GetWidth()-
+			st.GetHorizontalFrameSize(),
+		),
+	))
+}
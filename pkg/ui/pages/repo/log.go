@@ -11,6 +11,7 @@ import (
 	gansi "charm.land/glamour/v2/ansi"
 	"charm.land/lipgloss/v2"
 	"github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/backend"
 	"github.com/charmbracelet/soft-serve/pkg/proto"
 	"github.com/charmbracelet/soft-serve/pkg/ui/common"
 	"github.com/charmbracelet/soft-serve/pkg/ui/components/footer"
@@ -42,6 +43,10 @@ type LogCommitMsg *git.Commit
 // LogDiffMsg is a message that contains a git diff.
 type LogDiffMsg *git.Diff
 
+// LogSignatureMsg is a message that contains the signature verification of
+// the selected commit.
+type LogSignatureMsg backend.SignatureVerification
+
 // Log is a model that displays a list of commits and their diffs.
 type Log struct {
 	common         common.Common
@@ -54,6 +59,7 @@ type Log struct {
 	nextPage       int
 	activeCommit   *git.Commit
 	selectedCommit *git.Commit
+	signature      backend.SignatureVerification
 	currentDiff    *git.Diff
 	loadingTime    time.Time
 	spinner        spinner.Model
@@ -282,7 +288,10 @@ func (l *Log) Update(msg tea.Msg) (common.Model, tea.Cmd) {
 		}
 	case LogCommitMsg:
 		l.selectedCommit = msg
-		cmds = append(cmds, l.loadDiffCmd)
+		l.signature = backend.SignatureVerification{}
+		cmds = append(cmds, tea.Sequence(l.verifyCommitCmd, l.loadDiffCmd))
+	case LogSignatureMsg:
+		l.signature = backend.SignatureVerification(msg)
 	case LogDiffMsg:
 		l.currentDiff = msg
 		l.vp.SetContent(
@@ -495,17 +504,37 @@ func (l *Log) loadDiffCmd() tea.Msg {
 	return LogDiffMsg(diff)
 }
 
+func (l *Log) verifyCommitCmd() tea.Msg {
+	if l.selectedCommit == nil {
+		return nil
+	}
+	r, err := l.repo.Open()
+	if err != nil {
+		l.common.Logger.Debugf("ui: error loading signature repository: %v", err)
+		return LogSignatureMsg{}
+	}
+	sig, err := l.common.Backend().VerifyCommit(l.common.Context(), r, l.selectedCommit.ID.String())
+	if err != nil {
+		l.common.Logger.Debugf("ui: error verifying commit signature: %v", err)
+		return LogSignatureMsg{}
+	}
+	return LogSignatureMsg(sig)
+}
+
 func (l *Log) renderCommit(c *git.Commit) string {
 	s := strings.Builder{}
 	// FIXME: lipgloss prints empty lines when CRLF is used
 	// sanitize commit message from CRLF
 	msg := strings.ReplaceAll(c.Message, "\r\n", "\n")
-	s.WriteString(fmt.Sprintf("%s\n%s\n%s\n%s\n",
+	s.WriteString(fmt.Sprintf("%s\n%s\n%s\n",
 		l.common.Styles.Log.CommitHash.Render("commit "+c.ID.String()),
 		l.common.Styles.Log.CommitAuthor.Render(fmt.Sprintf("Author: %s <%s>", c.Author.Name, c.Author.Email)),
 		l.common.Styles.Log.CommitDate.Render("Date:   "+c.Committer.When.Format(time.UnixDate)),
-		l.common.Styles.Log.CommitBody.Render(msg),
 	))
+	if l.signature.IsSigned() {
+		s.WriteString(l.common.Styles.Log.CommitSignature.Render("Signature: "+l.signature.String()) + "\n")
+	}
+	s.WriteString(l.common.Styles.Log.CommitBody.Render(msg) + "\n")
 	return wrap.String(s.String(), l.common.Width-2)
 }
 
@@ -0,0 +1,317 @@
+package repo
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"charm.land/bubbles/v2/key"
+	"charm.land/bubbles/v2/spinner"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/ui/common"
+	"github.com/charmbracelet/soft-serve/pkg/ui/components/code"
+	"github.com/charmbracelet/soft-serve/pkg/ui/components/selector"
+	"github.com/charmbracelet/soft-serve/pkg/utils"
+)
+
+type pullRequestsState int
+
+const (
+	pullRequestsStateLoading pullRequestsState = iota
+	pullRequestsStateList
+	pullRequestsStateDetail
+)
+
+// PullRequestListMsg is a message sent when the pull request list is loaded.
+type PullRequestListMsg []backend.PullRequest
+
+// PullRequestDetailMsg is a message sent when a pull request's comments and
+// diff are loaded.
+type PullRequestDetailMsg struct {
+	PullRequest backend.PullRequest
+	Comments    []backend.PullRequestComment
+	// Diff is nil if the diff could not be computed, e.g. when the source
+	// branch was deleted.
+	Diff *git.Diff
+}
+
+// PullRequests is the pull requests component page.
+type PullRequests struct {
+	common        common.Common
+	code          *code.Code
+	repo          proto.Repository
+	spinner       spinner.Model
+	list          *selector.Selector
+	state         pullRequestsState
+	currentDetail PullRequestDetailMsg
+}
+
+// NewPullRequests creates a new pull requests model.
+func NewPullRequests(common common.Common) *PullRequests {
+	code := code.New(common, "", "")
+	s := spinner.New(spinner.WithSpinner(spinner.Dot),
+		spinner.WithStyle(common.Styles.Spinner))
+	selector := selector.New(common, []selector.IdentifiableItem{}, PullRequestItemDelegate{&common})
+	selector.SetShowFilter(false)
+	selector.SetShowHelp(false)
+	selector.SetShowPagination(false)
+	selector.SetShowStatusBar(false)
+	selector.SetShowTitle(false)
+	selector.SetFilteringEnabled(false)
+	selector.DisableQuitKeybindings()
+	selector.KeyMap.NextPage = common.KeyMap.NextPage
+	selector.KeyMap.PrevPage = common.KeyMap.PrevPage
+	return &PullRequests{
+		code:    code,
+		common:  common,
+		spinner: s,
+		list:    selector,
+	}
+}
+
+// Path implements common.TabComponent.
+func (p *PullRequests) Path() string {
+	return ""
+}
+
+// TabName returns the name of the tab.
+func (p *PullRequests) TabName() string {
+	return "Pull Requests"
+}
+
+// SetSize implements common.Component.
+func (p *PullRequests) SetSize(width, height int) {
+	p.common.SetSize(width, height)
+	p.code.SetSize(width, height)
+	p.list.SetSize(width, height)
+}
+
+// ShortHelp implements help.KeyMap.
+func (p *PullRequests) ShortHelp() []key.Binding {
+	return []key.Binding{
+		p.common.KeyMap.Select,
+		p.common.KeyMap.Back,
+		p.common.KeyMap.UpDown,
+	}
+}
+
+// FullHelp implements help.KeyMap.
+func (p *PullRequests) FullHelp() [][]key.Binding {
+	b := [][]key.Binding{
+		{
+			p.common.KeyMap.Select,
+			p.common.KeyMap.Back,
+			p.common.KeyMap.Copy,
+		},
+		{
+			p.code.KeyMap.Down,
+			p.code.KeyMap.Up,
+			p.common.KeyMap.GotoTop,
+			p.common.KeyMap.GotoBottom,
+		},
+	}
+	return b
+}
+
+// StatusBarValue implements common.Component.
+func (p *PullRequests) StatusBarValue() string {
+	item, ok := p.list.SelectedItem().(PullRequestItem)
+	if !ok {
+		return " "
+	}
+	return fmt.Sprintf("%s %s", item.ID(), item.Title())
+}
+
+// StatusBarInfo implements common.Component.
+func (p *PullRequests) StatusBarInfo() string {
+	switch p.state {
+	case pullRequestsStateList:
+		totalPages := p.list.TotalPages()
+		if totalPages <= 1 {
+			return "p. 1/1"
+		}
+		return fmt.Sprintf("p. %d/%d", p.list.Page()+1, totalPages)
+	case pullRequestsStateDetail:
+		return common.ScrollPercent(p.code.ScrollPosition())
+	default:
+		return ""
+	}
+}
+
+// SpinnerID implements common.Component.
+func (p *PullRequests) SpinnerID() int {
+	return p.spinner.ID()
+}
+
+// Init initializes the model.
+func (p *PullRequests) Init() tea.Cmd {
+	p.state = pullRequestsStateLoading
+	return tea.Batch(p.spinner.Tick, p.fetchPullRequests)
+}
+
+// Update updates the model.
+func (p *PullRequests) Update(msg tea.Msg) (common.Model, tea.Cmd) {
+	cmds := make([]tea.Cmd, 0)
+	switch msg := msg.(type) {
+	case RepoMsg:
+		p.repo = msg
+	case RefMsg:
+		p.list.Select(0)
+		cmds = append(cmds, p.Init())
+	case tea.WindowSizeMsg:
+		p.SetSize(msg.Width, msg.Height)
+	case spinner.TickMsg:
+		if p.state == pullRequestsStateLoading && p.spinner.ID() == msg.ID {
+			sp, cmd := p.spinner.Update(msg)
+			p.spinner = sp
+			if cmd != nil {
+				cmds = append(cmds, cmd)
+			}
+		}
+	case tea.KeyPressMsg:
+		switch p.state {
+		case pullRequestsStateList:
+			switch {
+			case key.Matches(msg, p.common.KeyMap.BackItem):
+				cmds = append(cmds, goBackCmd)
+			case key.Matches(msg, p.common.KeyMap.Copy):
+				if item, ok := p.list.SelectedItem().(PullRequestItem); ok {
+					cmds = append(cmds, copyCmd(item.Title(), "Pull request title copied to clipboard"))
+				}
+			}
+		case pullRequestsStateDetail:
+			switch {
+			case key.Matches(msg, p.common.KeyMap.BackItem):
+				cmds = append(cmds, goBackCmd)
+			case key.Matches(msg, p.common.KeyMap.Copy):
+				if p.currentDetail.Diff != nil {
+					cmds = append(cmds, copyCmd(p.currentDetail.Diff.Patch(), "Pull request patch copied to clipboard"))
+				}
+			}
+		}
+	case PullRequestListMsg:
+		p.state = pullRequestsStateList
+		items := make([]selector.IdentifiableItem, len(msg))
+		for i, pr := range msg {
+			items[i] = PullRequestItem{pr}
+		}
+		cmds = append(cmds, p.list.SetItems(items))
+	case PullRequestDetailMsg:
+		p.state = pullRequestsStateDetail
+		p.currentDetail = msg
+		cmds = append(cmds, p.code.SetContent(p.renderDetail(msg), ".diff"))
+		p.code.GotoTop()
+	case selector.SelectMsg:
+		switch msg.IdentifiableItem.(type) {
+		case PullRequestItem:
+			cmds = append(cmds, p.fetchPullRequestDetail)
+		}
+	case GoBackMsg:
+		if p.state == pullRequestsStateList {
+			p.list.Select(0)
+		}
+		p.state = pullRequestsStateList
+	}
+	switch p.state {
+	case pullRequestsStateList:
+		l, cmd := p.list.Update(msg)
+		p.list = l.(*selector.Selector)
+		if cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+	case pullRequestsStateDetail:
+		c, cmd := p.code.Update(msg)
+		p.code = c.(*code.Code)
+		if cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+	}
+	return p, tea.Batch(cmds...)
+}
+
+// View returns the view.
+func (p *PullRequests) View() string {
+	switch p.state {
+	case pullRequestsStateLoading:
+		return renderLoading(p.common, p.spinner)
+	case pullRequestsStateList:
+		return p.list.View()
+	case pullRequestsStateDetail:
+		return p.code.View()
+	}
+	return ""
+}
+
+func (p *PullRequests) renderDetail(msg PullRequestDetailMsg) string {
+	s := p.common.Styles.PullRequest
+	pr := msg.PullRequest
+	lines := []string{
+		s.Title.Render(fmt.Sprintf("#%d %s", pr.Number, utils.Sanitize(pr.Title))),
+		s.Meta.Render(fmt.Sprintf("%s · %s → %s · opened by %s %s",
+			pr.State, pr.Source(), pr.TargetBranch, pr.Author, pr.CreatedAt.Format(time.RFC822))),
+	}
+	if pr.Description != "" {
+		lines = append(lines, "", utils.Sanitize(pr.Description))
+	}
+
+	for _, c := range msg.Comments {
+		lines = append(lines,
+			"",
+			s.Author.Render(c.Author)+s.Meta.Render(" commented "+c.CreatedAt.Format(time.RFC822)),
+			strings.TrimSpace(utils.Sanitize(c.Body)),
+		)
+	}
+
+	lines = append(lines, "")
+	if msg.Diff != nil {
+		lines = append(lines,
+			renderSummary(msg.Diff, p.common.Styles, p.common.Width),
+			renderDiff(msg.Diff, p.common.Width),
+		)
+	} else {
+		lines = append(lines, s.Meta.Render("The changes of this pull request are no longer available."))
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
+
+func (p *PullRequests) fetchPullRequests() tea.Msg {
+	if p.repo == nil {
+		return PullRequestListMsg(nil)
+	}
+
+	be := p.common.Backend()
+	prs, err := be.PullRequests(p.common.Context(), p.repo.Name(), "")
+	if err != nil {
+		return common.ErrorMsg(err)
+	}
+
+	return PullRequestListMsg(prs)
+}
+
+func (p *PullRequests) fetchPullRequestDetail() tea.Msg {
+	item, ok := p.list.SelectedItem().(PullRequestItem)
+	if p.repo == nil || !ok {
+		return PullRequestDetailMsg{}
+	}
+
+	ctx := p.common.Context()
+	be := p.common.Backend()
+	comments, err := be.PullRequestComments(ctx, p.repo.Name(), item.Number)
+	if err != nil {
+		return common.ErrorMsg(err)
+	}
+
+	// The source branch may be gone, the pull request is still shown.
+	diff, _ := be.PullRequestDiff(ctx, p.repo.Name(), item.Number)
+
+	return PullRequestDetailMsg{
+		PullRequest: item.PullRequest,
+		Comments:    comments,
+		Diff:        diff,
+	}
+}
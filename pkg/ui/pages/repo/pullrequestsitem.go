@@ -0,0 +1,95 @@
+package repo
+
+import (
+	"fmt"
+	"io"
+
+	"charm.land/bubbles/v2/key"
+	"charm.land/bubbles/v2/list"
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/charmbracelet/soft-serve/pkg/ui/common"
+	"github.com/charmbracelet/soft-serve/pkg/utils"
+)
+
+// PullRequestItem represents a pull request item.
+type PullRequestItem struct{ backend.PullRequest }
+
+// ID returns the ID of the pull request item.
+func (i PullRequestItem) ID() string {
+	return fmt.Sprintf("#%d", i.Number)
+}
+
+// Title returns the title of the pull request item.
+func (i PullRequestItem) Title() string {
+	return utils.Sanitize(i.PullRequest.Title)
+}
+
+// Description returns the description of the pull request item.
+func (i PullRequestItem) Description() string {
+	return i.PullRequest.Description
+}
+
+// FilterValue implements list.Item.
+func (i PullRequestItem) FilterValue() string { return i.Title() }
+
+// PullRequestItemDelegate is a delegate for pull request items.
+type PullRequestItemDelegate struct {
+	common *common.Common
+}
+
+// Height returns the height of the pull request item list. Implements list.ItemDelegate.
+func (d PullRequestItemDelegate) Height() int { return 1 }
+
+// Spacing implements list.ItemDelegate.
+func (d PullRequestItemDelegate) Spacing() int { return 0 }
+
+// Update implements list.ItemDelegate.
+func (d PullRequestItemDelegate) Update(msg tea.Msg, m *list.Model) tea.Cmd {
+	item, ok := m.SelectedItem().(PullRequestItem)
+	if !ok {
+		return nil
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyPressMsg:
+		switch {
+		case key.Matches(msg, d.common.KeyMap.Copy):
+			return copyCmd(item.Title(), fmt.Sprintf("Pull request title %q copied to clipboard", item.Title()))
+		}
+	}
+
+	return nil
+}
+
+// Render implements list.ItemDelegate.
+func (d PullRequestItemDelegate) Render(w io.Writer, m list.Model, index int, listItem list.Item) {
+	item, ok := listItem.(PullRequestItem)
+	if !ok {
+		return
+	}
+
+	s := d.common.Styles.PullRequest
+
+	st := s.Normal.Message
+	selector := " "
+	if index == m.Index() {
+		selector = "> "
+		st = s.Active.Message
+	}
+
+	selector = s.Selector.Render(selector)
+	title := st.Render(fmt.Sprintf("%s %s", item.ID(), item.Title()))
+	meta := s.Meta.Render(fmt.Sprintf(" %s · %s → %s", item.State, item.Source(), item.TargetBranch))
+	fmt.Fprint(w, d.common.Zone.Mark( //nolint:errcheck
+		item.ID(),
+		common.TruncateString(fmt.Sprintf("%s%s%s",
+			selector,
+			title,
+			meta,
+		), m.Width()-
+			s.Selector.GetWidth()-
+			st.GetHorizontalFrameSize(),
+		),
+	))
+}
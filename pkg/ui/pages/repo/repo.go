@@ -10,6 +10,7 @@ import (
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
 	"github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/access"
 	"github.com/charmbracelet/soft-serve/pkg/proto"
 	"github.com/charmbracelet/soft-serve/pkg/ui/common"
 	"github.com/charmbracelet/soft-serve/pkg/ui/components/footer"
@@ -34,6 +35,10 @@ type CopyURLMsg struct{}
 // RepoMsg is a message that contains a git.Repository.
 type RepoMsg proto.Repository //nolint:revive
 
+// ForkParentMsg is a message that contains the name of the repository the
+// selected repository was forked from.
+type ForkParentMsg string
+
 // GoBackMsg is a message to go back to the previous view.
 type GoBackMsg struct{}
 
@@ -50,6 +55,7 @@ type SwitchTabMsg common.TabComponent
 type Repo struct {
 	common       common.Common
 	selectedRepo proto.Repository
+	forkParent   string
 	activeTab    int
 	tabs         *tabs.Tabs
 	statusbar    *statusbar.Model
@@ -153,11 +159,15 @@ func (r *Repo) Update(msg tea.Msg) (common.Model, tea.Cmd) {
 	case RepoMsg:
 		// Set the state to loading when we get a new repository.
 		r.selectedRepo = msg
+		r.forkParent = ""
 		cmds = append(cmds,
 			r.Init(),
 			// This will set the selected repo in each pane's model.
 			r.updateModels(msg),
+			r.fetchForkParent(msg),
 		)
+	case ForkParentMsg:
+		r.forkParent = string(msg)
 	case RefMsg:
 		r.ref = msg
 		cmds = append(cmds, r.updateModels(msg))
@@ -216,7 +226,7 @@ func (r *Repo) Update(msg tea.Msg) (common.Model, tea.Cmd) {
 		cmds = append(cmds, r.updateTabComponent(&Readme{}, msg))
 	case FileItemsMsg, FileContentMsg:
 		cmds = append(cmds, r.updateTabComponent(&Files{}, msg))
-	case LogItemsMsg, LogDiffMsg, LogCountMsg:
+	case LogItemsMsg, LogDiffMsg, LogCountMsg, LogSignatureMsg:
 		cmds = append(cmds, r.updateTabComponent(&Log{}, msg))
 	case RefItemsMsg:
 		cmds = append(cmds, r.updateTabComponent(&Refs{refPrefix: msg.prefix}, msg))
@@ -336,6 +346,12 @@ func (r *Repo) headerView() string {
 			r.common.Styles.Repo.HeaderDesc.Render(desc),
 		)
 	}
+	if r.forkParent != "" {
+		header = lipgloss.JoinVertical(lipgloss.Left,
+			header,
+			r.common.Styles.Repo.HeaderDesc.Render("forked from "+r.forkParent),
+		)
+	}
 	urlStyle := r.common.Styles.URLStyle.
 		Width(r.common.Width - lipgloss.Width(header) - 1).
 		Align(lipgloss.Right)
@@ -357,6 +373,29 @@ func (r *Repo) headerView() string {
 	)
 }
 
+func (r *Repo) fetchForkParent(repo proto.Repository) tea.Cmd {
+	return func() tea.Msg {
+		be := r.common.Backend()
+		if be == nil {
+			return nil
+		}
+
+		ctx := r.common.Context()
+		parent, err := be.ForkParent(ctx, repo.Name())
+		if err != nil {
+			r.common.Logger.Debugf("failed to get fork parent: %v", err)
+			return nil
+		}
+
+		// Don't reveal parents the user can't see.
+		if parent == "" || be.AccessLevelByPublicKey(ctx, parent, r.common.PublicKey()) < access.ReadOnlyAccess {
+			return nil
+		}
+
+		return ForkParentMsg(parent)
+	}
+}
+
 func (r *Repo) setStatusBarInfo() {
 	if r.selectedRepo == nil {
 		return
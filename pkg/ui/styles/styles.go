@@ -79,14 +79,15 @@ type Styles struct {
 	}
 
 	Log struct {
-		Commit         lipgloss.Style
-		CommitHash     lipgloss.Style
-		CommitAuthor   lipgloss.Style
-		CommitDate     lipgloss.Style
-		CommitBody     lipgloss.Style
-		CommitStatsAdd lipgloss.Style
-		CommitStatsDel lipgloss.Style
-		Paginator      lipgloss.Style
+		Commit          lipgloss.Style
+		CommitHash      lipgloss.Style
+		CommitAuthor    lipgloss.Style
+		CommitDate      lipgloss.Style
+		CommitSignature lipgloss.Style
+		CommitBody      lipgloss.Style
+		CommitStatsAdd  lipgloss.Style
+		CommitStatsDel  lipgloss.Style
+		Paginator       lipgloss.Style
 	}
 
 	Ref struct {
@@ -143,6 +144,33 @@ type Styles struct {
 		Selector lipgloss.Style
 	}
 
+	PullRequest struct {
+		Normal struct {
+			Message lipgloss.Style
+		}
+		Active struct {
+			Message lipgloss.Style
+		}
+		Title    lipgloss.Style
+		Selector lipgloss.Style
+		Meta     lipgloss.Style
+		Author   lipgloss.Style
+	}
+
+	Issue struct {
+		Normal struct {
+			Message lipgloss.Style
+		}
+		Active struct {
+			Message lipgloss.Style
+		}
+		Title    lipgloss.Style
+		Selector lipgloss.Style
+		Meta     lipgloss.Style
+		Author   lipgloss.Style
+		Label    lipgloss.Style
+	}
+
 	Spinner          lipgloss.Style
 	SpinnerContainer lipgloss.Style
 
@@ -523,5 +551,39 @@ func DefaultStyles() *Styles {
 		Width(1).
 		Foreground(selectorColor)
 
+	s.PullRequest.Normal.Message = lipgloss.NewStyle().MarginLeft(1)
+
+	s.PullRequest.Active.Message = s.PullRequest.Normal.Message.Foreground(selectorColor)
+
+	s.PullRequest.Title = lipgloss.NewStyle().
+		Foreground(hashColor).
+		Bold(true)
+
+	s.PullRequest.Selector = lipgloss.NewStyle().
+		Width(1).
+		Foreground(selectorColor)
+
+	s.PullRequest.Meta = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("243"))
+
+	s.PullRequest.Author = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("250")).
+		Bold(true)
+
+	s.Issue.Normal.Message = s.PullRequest.Normal.Message
+
+	s.Issue.Active.Message = s.PullRequest.Active.Message
+
+	s.Issue.Title = s.PullRequest.Title
+
+	s.Issue.Selector = s.PullRequest.Selector
+
+	s.Issue.Meta = s.PullRequest.Meta
+
+	s.Issue.Author = s.PullRequest.Author
+
+	s.Issue.Label = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("212"))
+
 	return s
 }
@@ -47,6 +47,41 @@ func ValidateUsername(username string) error {
 	return nil
 }
 
+// ValidateTeam returns an error if the given team name is invalid. Team names
+// follow the same rules as usernames.
+func ValidateTeam(team string) error {
+	if team == "" {
+		return fmt.Errorf("team name cannot be empty")
+	}
+
+	if !unicode.IsLetter(rune(team[0])) {
+		return fmt.Errorf("team name must start with a letter")
+	}
+
+	for _, r := range team {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' {
+			return fmt.Errorf("team name can only contain letters, numbers, and hyphens")
+		}
+	}
+
+	return nil
+}
+
+// ValidateLabel returns an error if the given issue label is invalid.
+func ValidateLabel(label string) error {
+	if label == "" {
+		return fmt.Errorf("label cannot be empty")
+	}
+
+	for _, r := range label {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != '_' && r != '.' {
+			return fmt.Errorf("label can only contain letters, numbers, hyphens, underscores, and periods")
+		}
+	}
+
+	return nil
+}
+
 // ValidateRepo returns an error if the given repository name is invalid.
 func ValidateRepo(repo string) error {
 	if repo == "" {
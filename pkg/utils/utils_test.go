@@ -35,6 +35,19 @@ func TestValidateRepo(t *testing.T) {
 	})
 }
 
+func TestValidateLabel(t *testing.T) {
+	for _, label := range []string{"bug", "good-first-issue", "v1.0", "needs_review"} {
+		if err := ValidateLabel(label); err != nil {
+			t.Errorf("%q: expected no error, got %v", label, err)
+		}
+	}
+	for _, label := range []string{"", "two words", "with/slash", "with,comma"} {
+		if err := ValidateLabel(label); err == nil {
+			t.Errorf("%q: expected an error, got nil", label)
+		}
+	}
+}
+
 func TestSanitizeRepo(t *testing.T) {
 	cases := []struct {
 		in, out string
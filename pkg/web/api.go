@@ -0,0 +1,274 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"charm.land/log/v2"
+	"github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/access"
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/ssrf"
+	"github.com/charmbracelet/soft-serve/pkg/utils"
+	"github.com/charmbracelet/soft-serve/pkg/webhook"
+	"github.com/gorilla/mux"
+)
+
+// apiPrefix is the path prefix of the versioned JSON API.
+const apiPrefix = "/api/v1"
+
+// apiMaxBodySize is the maximum size of a JSON API request body.
+const apiMaxBodySize = 1 << 20
+
+// APIController registers the JSON API routes for the web server.
+//
+// It must be registered before the Git routes, which match any path.
+func APIController(_ context.Context, r *mux.Router) {
+	api := r.PathPrefix(apiPrefix).Subrouter()
+	api.Use(withAPIAccess)
+	api.NotFoundHandler = http.HandlerFunc(renderAPINotFound)
+
+	// Users
+	api.HandleFunc("/user", getAPIUser).Methods(http.MethodGet)
+	api.HandleFunc("/users", getAPIUsers).Methods(http.MethodGet)
+	api.HandleFunc("/users/{username}", getAPIUserByName).Methods(http.MethodGet)
+
+	// Access tokens
+	api.HandleFunc("/tokens", getAPITokens).Methods(http.MethodGet)
+	api.HandleFunc("/tokens", postAPIToken).Methods(http.MethodPost)
+	api.HandleFunc("/tokens/{id:[0-9]+}", deleteAPIToken).Methods(http.MethodDelete)
+
+	// Repositories
+	//
+	// Repository names may contain slashes, so the routes with a suffix must
+	// be registered before the bare repository routes.
+	api.HandleFunc("/repos", getAPIRepos).Methods(http.MethodGet)
+	api.HandleFunc("/repos", postAPIRepo).Methods(http.MethodPost)
+
+	api.HandleFunc("/repos/{repo:.+}/branches", getAPIBranches).Methods(http.MethodGet)
+	api.HandleFunc("/repos/{repo:.+}/branches/{branch:.+}", deleteAPIBranch).Methods(http.MethodDelete)
+	api.HandleFunc("/repos/{repo:.+}/tags", getAPITags).Methods(http.MethodGet)
+	api.HandleFunc("/repos/{repo:.+}/tags/{tag:.+}", deleteAPITag).Methods(http.MethodDelete)
+	api.HandleFunc("/repos/{repo:.+}/commits", getAPICommits).Methods(http.MethodGet)
+	api.HandleFunc("/repos/{repo:.+}/commits/{sha:[0-9a-fA-F]{4,64}}", getAPICommit).Methods(http.MethodGet)
+	api.HandleFunc("/repos/{repo:.+}/tree", getAPITree).Methods(http.MethodGet)
+	api.HandleFunc("/repos/{repo:.+}/blob", getAPIBlob).Methods(http.MethodGet)
+
+	api.HandleFunc("/repos/{repo:.+}/collaborators", getAPICollaborators).Methods(http.MethodGet)
+	api.HandleFunc("/repos/{repo:.+}/collaborators", postAPICollaborator).Methods(http.MethodPost)
+	api.HandleFunc("/repos/{repo:.+}/collaborators/{username}", deleteAPICollaborator).Methods(http.MethodDelete)
+
+	api.HandleFunc("/repos/{repo:.+}/webhooks", getAPIWebhooks).Methods(http.MethodGet)
+	api.HandleFunc("/repos/{repo:.+}/webhooks", postAPIWebhook).Methods(http.MethodPost)
+	api.HandleFunc("/repos/{repo:.+}/webhooks/{id:[0-9]+}", getAPIWebhook).Methods(http.MethodGet)
+	api.HandleFunc("/repos/{repo:.+}/webhooks/{id:[0-9]+}", patchAPIWebhook).Methods(http.MethodPatch)
+	api.HandleFunc("/repos/{repo:.+}/webhooks/{id:[0-9]+}", deleteAPIWebhook).Methods(http.MethodDelete)
+
+	api.HandleFunc("/repos/{repo:.+}", getAPIRepo).Methods(http.MethodGet)
+	api.HandleFunc("/repos/{repo:.+}", patchAPIRepo).Methods(http.MethodPatch)
+	api.HandleFunc("/repos/{repo:.+}", deleteAPIRepo).Methods(http.MethodDelete)
+}
+
+// withAPIAccess authenticates API requests.
+//
+// Credentials are accepted in the same forms as the Git routes: access
+// tokens, JWTs and basic auth. Requests for a repository have the repository
+// stored in the context so that repository-scoped JWTs can be verified.
+// Authorization is left to the handlers since it depends on the endpoint.
+func withAPIAccess(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		logger := log.FromContext(ctx).WithPrefix("http.api")
+		be := backend.FromContext(ctx)
+
+		if name, ok := mux.Vars(r)["repo"]; ok {
+			if repo, err := be.Repository(ctx, utils.SanitizeRepo(name)); err == nil {
+				ctx = proto.WithRepositoryContext(ctx, repo)
+				r = r.WithContext(ctx)
+			}
+		}
+
+		user, err := authenticate(r)
+		if err != nil && r.Header.Get("Authorization") != "" {
+			// Unlike the Git routes, the API never falls back to anonymous
+			// access when the caller sent credentials that do not work.
+			logger.Debug("failed to authenticate", "err", err)
+			renderAPIError(w, http.StatusUnauthorized, "invalid credentials")
+			return
+		}
+
+		if user == nil && !be.AllowKeyless(ctx) {
+			askCredentials(w, r)
+			renderAPIError(w, http.StatusUnauthorized, "authentication required")
+			return
+		}
+
+		if user != nil {
+			logger.Debug("authenticated", "username", user.Username())
+		}
+
+		ctx = proto.WithUserContext(ctx, user)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// apiRepo returns the repository of an API request if the caller has at least
+// the given access level on it.
+//
+// Repositories the caller cannot read are reported as not found so that
+// their existence is not disclosed. Otherwise, the caller is denied if their
+// access level is too low. Admin access additionally requires an
+// authenticated user, like the repository admin SSH commands.
+//
+// It renders the error response and returns false if access is denied.
+func apiRepo(w http.ResponseWriter, r *http.Request, level access.AccessLevel) (proto.Repository, bool) {
+	ctx := r.Context()
+	be := backend.FromContext(ctx)
+	user := proto.UserFromContext(ctx)
+	repo := proto.RepositoryFromContext(ctx)
+	if repo == nil {
+		renderAPIErr(w, r, proto.ErrRepoNotFound)
+		return nil, false
+	}
+
+	accessLevel := be.AccessLevelForUser(ctx, repo.Name(), user)
+	if accessLevel < access.ReadOnlyAccess {
+		renderAPIErr(w, r, proto.ErrRepoNotFound)
+		return nil, false
+	}
+
+	if level > access.ReadOnlyAccess && user == nil {
+		renderAPIError(w, http.StatusUnauthorized, "authentication required")
+		return nil, false
+	}
+
+	if accessLevel < level {
+		renderAPIErr(w, r, proto.ErrUnauthorized)
+		return nil, false
+	}
+
+	return repo, true
+}
+
+// apiUser returns the authenticated user of an API request.
+//
+// It renders the error response and returns false for anonymous requests.
+func apiUser(w http.ResponseWriter, r *http.Request) (proto.User, bool) {
+	user := proto.UserFromContext(r.Context())
+	if user == nil {
+		renderAPIError(w, http.StatusUnauthorized, "authentication required")
+		return nil, false
+	}
+
+	return user, true
+}
+
+// apiIDVar parses the numeric "id" route variable.
+func apiIDVar(r *http.Request) int64 {
+	// The route only matches digits, so this can only fail on overflow,
+	// which leaves an ID that does not exist.
+	id, _ := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	return id
+}
+
+// errAPIInvalidBody is returned when a request body cannot be decoded.
+var errAPIInvalidBody = errors.New("invalid request body")
+
+// decodeAPIBody decodes the JSON request body into v.
+func decodeAPIBody(r *http.Request, v interface{}) error {
+	dec := json.NewDecoder(io.LimitReader(r.Body, apiMaxBodySize))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return errAPIInvalidBody
+	}
+
+	return nil
+}
+
+// apiErrorResponse is the body of API error responses.
+type apiErrorResponse struct {
+	Message string `json:"message"`
+}
+
+// renderAPIJSON writes v as a JSON response.
+func renderAPIJSON(w http.ResponseWriter, statusCode int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Error("error encoding json", "err", err)
+	}
+}
+
+// renderAPIError writes an API error response.
+func renderAPIError(w http.ResponseWriter, statusCode int, message string) {
+	renderAPIJSON(w, statusCode, apiErrorResponse{Message: message})
+}
+
+// renderAPIErr writes the API error response matching err.
+//
+// Known errors map to client error statuses and are reported as is. Anything
+// else is logged and reported as an internal server error.
+func renderAPIErr(w http.ResponseWriter, r *http.Request, err error) {
+	var status int
+	switch {
+	case errors.Is(err, proto.ErrRepoNotFound),
+		errors.Is(err, proto.ErrUserNotFound),
+		errors.Is(err, proto.ErrTokenNotFound),
+		errors.Is(err, proto.ErrCollaboratorNotFound),
+		errors.Is(err, proto.ErrFileNotFound),
+		errors.Is(err, git.ErrFileNotFound),
+		errors.Is(err, git.ErrDirectoryNotFound),
+		errors.Is(err, git.ErrReferenceNotExist),
+		errors.Is(err, git.ErrRevisionNotExist),
+		errors.Is(err, db.ErrRecordNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, proto.ErrRepoExist),
+		errors.Is(err, proto.ErrCollaboratorExist),
+		errors.Is(err, db.ErrDuplicateKey):
+		status = http.StatusConflict
+	case errors.Is(err, proto.ErrUnauthorized),
+		errors.Is(err, proto.ErrExceedsAccessLevel),
+		errors.Is(err, proto.ErrProtectedBranch),
+		errors.Is(err, proto.ErrTokenScope):
+		status = http.StatusForbidden
+	case errors.Is(err, errAPIInvalidBody),
+		errors.Is(err, access.ErrInvalidAccessLevel),
+		errors.Is(err, proto.ErrInvalidTokenScope),
+		errors.Is(err, webhook.ErrInvalidContentType),
+		errors.Is(err, webhook.ErrInvalidEvent),
+		errors.Is(err, ssrf.ErrInvalidURL),
+		errors.Is(err, ssrf.ErrInvalidScheme),
+		errors.Is(err, ssrf.ErrPrivateIP),
+		errors.Is(err, errAPIInvalidRequest):
+		status = http.StatusBadRequest
+	default:
+		log.FromContext(r.Context()).Error("api request failed", "path", r.URL.Path, "err", err)
+		renderAPIError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	// Not found errors from the database carry no useful message.
+	message := err.Error()
+	if errors.Is(err, db.ErrRecordNotFound) {
+		message = http.StatusText(status)
+	}
+
+	renderAPIError(w, status, message)
+}
+
+// errAPIInvalidRequest is wrapped by errors about invalid request parameters.
+var errAPIInvalidRequest = errors.New("invalid request")
+
+func renderAPINotFound(w http.ResponseWriter, _ *http.Request) {
+	renderAPIError(w, http.StatusNotFound, http.StatusText(http.StatusNotFound))
+}
+
+func renderAPINoContent(w http.ResponseWriter) {
+	w.WriteHeader(http.StatusNoContent)
+}
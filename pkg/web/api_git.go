@@ -0,0 +1,573 @@
+package web
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	gitm "github.com/aymanbagabas/git-module"
+	"github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/access"
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/charmbracelet/soft-serve/pkg/hooks"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/webhook"
+	"github.com/gorilla/mux"
+)
+
+const (
+	// apiDefaultPerPage is the default number of commits per page.
+	apiDefaultPerPage = 30
+	// apiMaxPerPage is the maximum number of commits per page.
+	apiMaxPerPage = 100
+)
+
+// apiRefResponse is the API representation of a branch or a tag.
+type apiRefResponse struct {
+	Name     string `json:"name"`
+	CommitID string `json:"commit_id"`
+	// Default is only set for branches.
+	Default *bool `json:"default,omitempty"`
+}
+
+// apiRev returns the revision named by the "ref" query parameter, or HEAD.
+func apiRev(r *http.Request) (string, error) {
+	ref := r.URL.Query().Get("ref")
+	if ref == "" {
+		return git.HEAD, nil
+	}
+
+	// Revisions are passed to git as arguments, don't let them pass as
+	// options.
+	if strings.HasPrefix(ref, "-") {
+		return "", fmt.Errorf("%w: invalid ref %q", errAPIInvalidRequest, ref)
+	}
+
+	return ref, nil
+}
+
+// GET /api/v1/repos/{repo}/branches
+func getAPIBranches(w http.ResponseWriter, r *http.Request) {
+	repo, ok := apiRepo(w, r, access.ReadOnlyAccess)
+	if !ok {
+		return
+	}
+
+	rr, err := repo.Open()
+	if err != nil {
+		renderAPIErr(w, r, err)
+		return
+	}
+
+	// An empty repository has neither a HEAD nor branches.
+	var head string
+	if ref, err := rr.HEAD(); err == nil {
+		head = ref.Name().Short()
+	}
+
+	branches, _ := rr.Branches()
+	res := make([]apiRefResponse, 0, len(branches))
+	for _, b := range branches {
+		id, err := rr.BranchCommitID(b)
+		if err != nil {
+			renderAPIErr(w, r, err)
+			return
+		}
+
+		isDefault := b == head
+		res = append(res, apiRefResponse{
+			Name:     b,
+			CommitID: id,
+			Default:  &isDefault,
+		})
+	}
+
+	renderAPIJSON(w, http.StatusOK, res)
+}
+
+// DELETE /api/v1/repos/{repo}/branches/{branch}
+func deleteAPIBranch(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	be := backend.FromContext(ctx)
+	repo, ok := apiRepo(w, r, access.ReadWriteAccess)
+	if !ok {
+		return
+	}
+
+	rr, err := repo.Open()
+	if err != nil {
+		renderAPIErr(w, r, err)
+		return
+	}
+
+	branch := mux.Vars(r)["branch"]
+	branches, _ := rr.Branches()
+	var exists bool
+	for _, b := range branches {
+		if branch == b {
+			exists = true
+			break
+		}
+	}
+
+	if !exists {
+		renderAPIErr(w, r, git.ErrReferenceNotExist)
+		return
+	}
+
+	head, err := rr.HEAD()
+	if err != nil {
+		renderAPIErr(w, r, err)
+		return
+	}
+
+	if head.Name().Short() == branch {
+		renderAPIErr(w, r, fmt.Errorf("%w: cannot delete the default branch", errAPIInvalidRequest))
+		return
+	}
+
+	branchCommit, err := rr.BranchCommit(branch)
+	if err != nil {
+		renderAPIErr(w, r, err)
+		return
+	}
+
+	// Deleting a branch here skips the git hooks, so apply the branch
+	// protection rules the pre-receive hook would.
+	violations, err := be.BranchProtectionViolations(ctx, repo, proto.UserFromContext(ctx), []hooks.HookArg{
+		{
+			OldSha:  branchCommit.ID.String(),
+			NewSha:  git.ZeroID,
+			RefName: git.RefsHeads + branch,
+		},
+	})
+	if err != nil {
+		renderAPIErr(w, r, err)
+		return
+	}
+
+	if len(violations) > 0 {
+		renderAPIErr(w, r, fmt.Errorf("%w: %s", proto.ErrProtectedBranch, violations[0]))
+		return
+	}
+
+	if err := rr.DeleteBranch(branch, gitm.DeleteBranchOptions{Force: true}); err != nil {
+		renderAPIErr(w, r, err)
+		return
+	}
+
+	wh, err := webhook.NewBranchTagEvent(ctx, proto.UserFromContext(ctx), repo, git.RefsHeads+branch, branchCommit.ID.String(), git.ZeroID)
+	if err != nil {
+		renderAPIErr(w, r, err)
+		return
+	}
+
+	if err := webhook.SendEvent(ctx, wh); err != nil {
+		renderAPIErr(w, r, err)
+		return
+	}
+
+	renderAPINoContent(w)
+}
+
+// GET /api/v1/repos/{repo}/tags
+func getAPITags(w http.ResponseWriter, r *http.Request) {
+	repo, ok := apiRepo(w, r, access.ReadOnlyAccess)
+	if !ok {
+		return
+	}
+
+	rr, err := repo.Open()
+	if err != nil {
+		renderAPIErr(w, r, err)
+		return
+	}
+
+	tags, _ := rr.Tags()
+	res := make([]apiRefResponse, 0, len(tags))
+	for _, t := range tags {
+		commit, err := rr.TagCommit(t)
+		if err != nil {
+			renderAPIErr(w, r, err)
+			return
+		}
+
+		res = append(res, apiRefResponse{
+			Name:     t,
+			CommitID: commit.ID.String(),
+		})
+	}
+
+	renderAPIJSON(w, http.StatusOK, res)
+}
+
+// DELETE /api/v1/repos/{repo}/tags/{tag}
+func deleteAPITag(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	repo, ok := apiRepo(w, r, access.ReadWriteAccess)
+	if !ok {
+		return
+	}
+
+	rr, err := repo.Open()
+	if err != nil {
+		renderAPIErr(w, r, err)
+		return
+	}
+
+	tag := mux.Vars(r)["tag"]
+	tags, _ := rr.Tags()
+	var exists bool
+	for _, t := range tags {
+		if tag == t {
+			exists = true
+			break
+		}
+	}
+
+	if !exists {
+		renderAPIErr(w, r, git.ErrReferenceNotExist)
+		return
+	}
+
+	tagCommit, err := rr.TagCommit(tag)
+	if err != nil {
+		renderAPIErr(w, r, err)
+		return
+	}
+
+	if err := rr.DeleteTag(tag); err != nil {
+		renderAPIErr(w, r, err)
+		return
+	}
+
+	wh, err := webhook.NewBranchTagEvent(ctx, proto.UserFromContext(ctx), repo, git.RefsTags+tag, tagCommit.ID.String(), git.ZeroID)
+	if err != nil {
+		renderAPIErr(w, r, err)
+		return
+	}
+
+	if err := webhook.SendEvent(ctx, wh); err != nil {
+		renderAPIErr(w, r, err)
+		return
+	}
+
+	renderAPINoContent(w)
+}
+
+// apiSignatureResponse is the API representation of a commit author or
+// committer.
+type apiSignatureResponse struct {
+	Name  string    `json:"name"`
+	Email string    `json:"email"`
+	Date  time.Time `json:"date"`
+}
+
+// apiCommitResponse is the API representation of a commit.
+type apiCommitResponse struct {
+	ID        string               `json:"id"`
+	Message   string               `json:"message"`
+	Author    apiSignatureResponse `json:"author"`
+	Committer apiSignatureResponse `json:"committer"`
+	Parents   []string             `json:"parents"`
+	// Files and Patch are only set for single commits.
+	Files []apiCommitFileResponse `json:"files,omitempty"`
+	Patch string                  `json:"patch,omitempty"`
+}
+
+// apiCommitFileResponse is the API representation of a file changed by a
+// commit.
+type apiCommitFileResponse struct {
+	Name      string `json:"name"`
+	OldName   string `json:"old_name,omitempty"`
+	Additions int    `json:"additions"`
+	Deletions int    `json:"deletions"`
+}
+
+func newAPICommitResponse(c *git.Commit) apiCommitResponse {
+	parents := make([]string, 0, c.ParentsCount())
+	for i := 0; i < c.ParentsCount(); i++ {
+		id, err := c.ParentID(i)
+		if err != nil {
+			break
+		}
+		parents = append(parents, id.String())
+	}
+
+	return apiCommitResponse{
+		ID:      c.ID.String(),
+		Message: c.Message,
+		Author: apiSignatureResponse{
+			Name:  c.Author.Name,
+			Email: c.Author.Email,
+			Date:  c.Author.When,
+		},
+		Committer: apiSignatureResponse{
+			Name:  c.Committer.Name,
+			Email: c.Committer.Email,
+			Date:  c.Committer.When,
+		},
+		Parents: parents,
+	}
+}
+
+// GET /api/v1/repos/{repo}/commits
+func getAPICommits(w http.ResponseWriter, r *http.Request) {
+	repo, ok := apiRepo(w, r, access.ReadOnlyAccess)
+	if !ok {
+		return
+	}
+
+	rev, err := apiRev(r)
+	if err != nil {
+		renderAPIErr(w, r, err)
+		return
+	}
+
+	page, err := apiIntQuery(r, "page", 1)
+	if err != nil {
+		renderAPIErr(w, r, err)
+		return
+	}
+
+	perPage, err := apiIntQuery(r, "per_page", apiDefaultPerPage)
+	if err != nil {
+		renderAPIErr(w, r, err)
+		return
+	}
+
+	if perPage > apiMaxPerPage {
+		perPage = apiMaxPerPage
+	}
+
+	rr, err := repo.Open()
+	if err != nil {
+		renderAPIErr(w, r, err)
+		return
+	}
+
+	commits, err := rr.Repository.CommitsByPage(rev, page, perPage)
+	if err != nil {
+		renderAPIErr(w, r, fmt.Errorf("%w: %s", git.ErrRevisionNotExist, rev))
+		return
+	}
+
+	res := make([]apiCommitResponse, len(commits))
+	for i, c := range commits {
+		res[i] = newAPICommitResponse(c)
+	}
+
+	renderAPIJSON(w, http.StatusOK, res)
+}
+
+// GET /api/v1/repos/{repo}/commits/{sha}
+func getAPICommit(w http.ResponseWriter, r *http.Request) {
+	repo, ok := apiRepo(w, r, access.ReadOnlyAccess)
+	if !ok {
+		return
+	}
+
+	rr, err := repo.Open()
+	if err != nil {
+		renderAPIErr(w, r, err)
+		return
+	}
+
+	commit, err := rr.CommitByRevision(mux.Vars(r)["sha"])
+	if err != nil {
+		renderAPIErr(w, r, err)
+		return
+	}
+
+	diff, err := rr.Diff(commit)
+	if err != nil {
+		renderAPIErr(w, r, err)
+		return
+	}
+
+	res := newAPICommitResponse(commit)
+	for _, f := range diff.Files {
+		file := apiCommitFileResponse{
+			Name:      f.Name,
+			Additions: f.NumAdditions(),
+			Deletions: f.NumDeletions(),
+		}
+		if f.IsRenamed() {
+			file.OldName = f.OldName()
+		}
+		res.Files = append(res.Files, file)
+	}
+	res.Patch = diff.Patch()
+
+	renderAPIJSON(w, http.StatusOK, res)
+}
+
+// apiTreeEntryResponse is the API representation of a tree entry.
+type apiTreeEntryResponse struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+	// Type is one of blob, tree or commit (submodules).
+	Type string `json:"type"`
+	Mode string `json:"mode"`
+	Size int64  `json:"size"`
+	ID   string `json:"id"`
+}
+
+// GET /api/v1/repos/{repo}/tree
+func getAPITree(w http.ResponseWriter, r *http.Request) {
+	repo, ok := apiRepo(w, r, access.ReadOnlyAccess)
+	if !ok {
+		return
+	}
+
+	rev, err := apiRev(r)
+	if err != nil {
+		renderAPIErr(w, r, err)
+		return
+	}
+
+	rr, err := repo.Open()
+	if err != nil {
+		renderAPIErr(w, r, err)
+		return
+	}
+
+	tree, err := rr.LsTree(rev)
+	if err != nil {
+		renderAPIErr(w, r, fmt.Errorf("%w: %s", git.ErrRevisionNotExist, rev))
+		return
+	}
+
+	path := strings.Trim(r.URL.Query().Get("path"), "/")
+	if path != "" {
+		te, err := tree.TreeEntry(path)
+		if err != nil {
+			renderAPIErr(w, r, git.ErrDirectoryNotFound)
+			return
+		}
+
+		if !te.IsTree() {
+			renderAPIErr(w, r, git.ErrDirectoryNotFound)
+			return
+		}
+
+		tree, err = tree.SubTree(path)
+		if err != nil {
+			renderAPIErr(w, r, err)
+			return
+		}
+	}
+
+	ents, err := tree.Entries()
+	if err != nil {
+		renderAPIErr(w, r, err)
+		return
+	}
+
+	ents.Sort()
+	res := make([]apiTreeEntryResponse, len(ents))
+	for i, e := range ents {
+		var size int64
+		if e.IsBlob() {
+			size = e.Size()
+		}
+
+		res[i] = apiTreeEntryResponse{
+			Name: e.Name(),
+			Path: e.File().Path(),
+			Type: string(e.Type()),
+			Mode: fmt.Sprintf("%06o", e.TreeEntry.Mode()),
+			Size: size,
+			ID:   e.ID().String(),
+		}
+	}
+
+	renderAPIJSON(w, http.StatusOK, res)
+}
+
+// apiBlobResponse is the API representation of a file.
+type apiBlobResponse struct {
+	Path string `json:"path"`
+	ID   string `json:"id"`
+	Size int64  `json:"size"`
+	// Encoding is utf-8 for text files and base64 for binary files.
+	Encoding string `json:"encoding"`
+	Content  string `json:"content"`
+}
+
+// GET /api/v1/repos/{repo}/blob
+func getAPIBlob(w http.ResponseWriter, r *http.Request) {
+	repo, ok := apiRepo(w, r, access.ReadOnlyAccess)
+	if !ok {
+		return
+	}
+
+	rev, err := apiRev(r)
+	if err != nil {
+		renderAPIErr(w, r, err)
+		return
+	}
+
+	path := strings.Trim(r.URL.Query().Get("path"), "/")
+	if path == "" {
+		renderAPIErr(w, r, fmt.Errorf("%w: path is required", errAPIInvalidRequest))
+		return
+	}
+
+	rr, err := repo.Open()
+	if err != nil {
+		renderAPIErr(w, r, err)
+		return
+	}
+
+	tree, err := rr.LsTree(rev)
+	if err != nil {
+		renderAPIErr(w, r, fmt.Errorf("%w: %s", git.ErrRevisionNotExist, rev))
+		return
+	}
+
+	te, err := tree.TreeEntry(path)
+	if err != nil || !te.IsBlob() {
+		renderAPIErr(w, r, git.ErrFileNotFound)
+		return
+	}
+
+	bts, err := te.Contents()
+	if err != nil {
+		renderAPIErr(w, r, err)
+		return
+	}
+
+	res := apiBlobResponse{
+		Path:     path,
+		ID:       te.ID().String(),
+		Size:     te.Size(),
+		Encoding: "utf-8",
+		Content:  string(bts),
+	}
+	if isBin, _ := te.File().IsBinary(); isBin {
+		res.Encoding = "base64"
+		res.Content = base64.StdEncoding.EncodeToString(bts)
+	}
+
+	renderAPIJSON(w, http.StatusOK, res)
+}
+
+// apiIntQuery returns the positive integer query parameter key, or def if it
+// is not set.
+func apiIntQuery(r *http.Request, key string, def int) (int, error) {
+	s := r.URL.Query().Get(key)
+	if s == "" {
+		return def, nil
+	}
+
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("%w: invalid %s %q", errAPIInvalidRequest, key, s)
+	}
+
+	return n, nil
+}
@@ -0,0 +1,652 @@
+package web
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/soft-serve/pkg/access"
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/charmbracelet/soft-serve/pkg/config"
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/utils"
+	"github.com/charmbracelet/soft-serve/pkg/webhook"
+	"github.com/gorilla/mux"
+)
+
+// apiRepoResponse is the API representation of a repository.
+type apiRepoResponse struct {
+	Name        string    `json:"name"`
+	ProjectName string    `json:"project_name"`
+	Description string    `json:"description"`
+	Private     bool      `json:"private"`
+	Mirror      bool      `json:"mirror"`
+	Hidden      bool      `json:"hidden"`
+	SSHURL      string    `json:"ssh_url"`
+	HTTPURL     string    `json:"http_url"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func newAPIRepoResponse(cfg *config.Config, repo proto.Repository) apiRepoResponse {
+	return apiRepoResponse{
+		Name:        repo.Name(),
+		ProjectName: repo.ProjectName(),
+		Description: repo.Description(),
+		Private:     repo.IsPrivate(),
+		Mirror:      repo.IsMirror(),
+		Hidden:      repo.IsHidden(),
+		SSHURL:      fmt.Sprintf("%s/%s.git", cfg.SSH.PublicURL, repo.Name()),
+		HTTPURL:     fmt.Sprintf("%s/%s.git", cfg.HTTP.PublicURL, repo.Name()),
+		CreatedAt:   repo.CreatedAt(),
+		UpdatedAt:   repo.UpdatedAt(),
+	}
+}
+
+// GET /api/v1/repos
+func getAPIRepos(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	cfg := config.FromContext(ctx)
+	be := backend.FromContext(ctx)
+	user := proto.UserFromContext(ctx)
+
+	repos, err := be.Repositories(ctx)
+	if err != nil {
+		renderAPIErr(w, r, err)
+		return
+	}
+
+	res := make([]apiRepoResponse, 0, len(repos))
+	for _, repo := range repos {
+		if be.AccessLevelForUser(ctx, repo.Name(), user) >= access.ReadOnlyAccess {
+			res = append(res, newAPIRepoResponse(cfg, repo))
+		}
+	}
+
+	renderAPIJSON(w, http.StatusOK, res)
+}
+
+// apiRepoRequest is the body of repository creation and update requests.
+//
+// Fields left out of an update request are left unchanged.
+type apiRepoRequest struct {
+	Name        *string `json:"name"`
+	ProjectName *string `json:"project_name"`
+	Description *string `json:"description"`
+	Private     *bool   `json:"private"`
+	Hidden      *bool   `json:"hidden"`
+}
+
+// POST /api/v1/repos
+func postAPIRepo(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	cfg := config.FromContext(ctx)
+	be := backend.FromContext(ctx)
+	user, ok := apiUser(w, r)
+	if !ok {
+		return
+	}
+
+	var req apiRepoRequest
+	if err := decodeAPIBody(r, &req); err != nil {
+		renderAPIErr(w, r, err)
+		return
+	}
+
+	if req.Name == nil {
+		renderAPIErr(w, r, fmt.Errorf("%w: name is required", errAPIInvalidRequest))
+		return
+	}
+
+	name := utils.SanitizeRepo(*req.Name)
+	if err := utils.ValidateRepo(name); err != nil {
+		renderAPIErr(w, r, fmt.Errorf("%w: %w", errAPIInvalidRequest, err))
+		return
+	}
+
+	if be.AccessLevelForUser(ctx, name, user) < access.ReadWriteAccess {
+		renderAPIErr(w, r, proto.ErrUnauthorized)
+		return
+	}
+
+	var opts proto.RepositoryOptions
+	if req.ProjectName != nil {
+		opts.ProjectName = *req.ProjectName
+	}
+	if req.Description != nil {
+		opts.Description = *req.Description
+	}
+	if req.Private != nil {
+		opts.Private = *req.Private
+	}
+	if req.Hidden != nil {
+		opts.Hidden = *req.Hidden
+	}
+
+	repo, err := be.CreateRepository(ctx, name, user, opts)
+	if err != nil {
+		renderAPIErr(w, r, err)
+		return
+	}
+
+	renderAPIJSON(w, http.StatusCreated, newAPIRepoResponse(cfg, repo))
+}
+
+// GET /api/v1/repos/{repo}
+func getAPIRepo(w http.ResponseWriter, r *http.Request) {
+	cfg := config.FromContext(r.Context())
+	repo, ok := apiRepo(w, r, access.ReadOnlyAccess)
+	if !ok {
+		return
+	}
+
+	renderAPIJSON(w, http.StatusOK, newAPIRepoResponse(cfg, repo))
+}
+
+// PATCH /api/v1/repos/{repo}
+func patchAPIRepo(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	cfg := config.FromContext(ctx)
+	be := backend.FromContext(ctx)
+	repo, ok := apiRepo(w, r, access.ReadWriteAccess)
+	if !ok {
+		return
+	}
+
+	var req apiRepoRequest
+	if err := decodeAPIBody(r, &req); err != nil {
+		renderAPIErr(w, r, err)
+		return
+	}
+
+	name := repo.Name()
+	if req.Name != nil {
+		newName := utils.SanitizeRepo(*req.Name)
+		if err := utils.ValidateRepo(newName); err != nil {
+			renderAPIErr(w, r, fmt.Errorf("%w: %w", errAPIInvalidRequest, err))
+			return
+		}
+		req.Name = &newName
+	}
+
+	if req.ProjectName != nil {
+		if err := be.SetProjectName(ctx, name, *req.ProjectName); err != nil {
+			renderAPIErr(w, r, err)
+			return
+		}
+	}
+
+	if req.Description != nil {
+		if err := be.SetDescription(ctx, name, *req.Description); err != nil {
+			renderAPIErr(w, r, err)
+			return
+		}
+	}
+
+	if req.Private != nil {
+		if err := be.SetPrivate(ctx, name, *req.Private); err != nil {
+			renderAPIErr(w, r, err)
+			return
+		}
+	}
+
+	if req.Hidden != nil {
+		if err := be.SetHidden(ctx, name, *req.Hidden); err != nil {
+			renderAPIErr(w, r, err)
+			return
+		}
+	}
+
+	if req.Name != nil && *req.Name != name {
+		if err := be.RenameRepository(ctx, name, *req.Name); err != nil {
+			renderAPIErr(w, r, err)
+			return
+		}
+		name = *req.Name
+	}
+
+	// The repository in the context is stale now, look it up again.
+	repo, err := be.Repository(ctx, name)
+	if err != nil {
+		renderAPIErr(w, r, err)
+		return
+	}
+
+	renderAPIJSON(w, http.StatusOK, newAPIRepoResponse(cfg, repo))
+}
+
+// DELETE /api/v1/repos/{repo}
+func deleteAPIRepo(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	be := backend.FromContext(ctx)
+	repo, ok := apiRepo(w, r, access.ReadWriteAccess)
+	if !ok {
+		return
+	}
+
+	if err := be.DeleteRepository(ctx, repo.Name()); err != nil {
+		renderAPIErr(w, r, err)
+		return
+	}
+
+	renderAPINoContent(w)
+}
+
+// apiCollaboratorResponse is the API representation of a collaborator.
+type apiCollaboratorResponse struct {
+	Username    string             `json:"username"`
+	AccessLevel access.AccessLevel `json:"access_level"`
+}
+
+// GET /api/v1/repos/{repo}/collaborators
+func getAPICollaborators(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	be := backend.FromContext(ctx)
+	repo, ok := apiRepo(w, r, access.ReadWriteAccess)
+	if !ok {
+		return
+	}
+
+	usernames, err := be.Collaborators(ctx, repo.Name())
+	if err != nil {
+		renderAPIErr(w, r, err)
+		return
+	}
+
+	res := make([]apiCollaboratorResponse, 0, len(usernames))
+	for _, username := range usernames {
+		level, _, err := be.IsCollaborator(ctx, repo.Name(), username)
+		if err != nil {
+			renderAPIErr(w, r, err)
+			return
+		}
+
+		res = append(res, apiCollaboratorResponse{
+			Username:    username,
+			AccessLevel: level,
+		})
+	}
+
+	renderAPIJSON(w, http.StatusOK, res)
+}
+
+// apiCollaboratorRequest is the body of a collaborator creation request.
+type apiCollaboratorRequest struct {
+	Username string `json:"username"`
+	// AccessLevel defaults to read-write.
+	AccessLevel *access.AccessLevel `json:"access_level"`
+}
+
+// POST /api/v1/repos/{repo}/collaborators
+func postAPICollaborator(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	be := backend.FromContext(ctx)
+	repo, ok := apiRepo(w, r, access.ReadWriteAccess)
+	if !ok {
+		return
+	}
+
+	var req apiCollaboratorRequest
+	if err := decodeAPIBody(r, &req); err != nil {
+		renderAPIErr(w, r, err)
+		return
+	}
+
+	if req.Username == "" {
+		renderAPIErr(w, r, fmt.Errorf("%w: username is required", errAPIInvalidRequest))
+		return
+	}
+
+	level := access.ReadWriteAccess
+	if req.AccessLevel != nil {
+		level = *req.AccessLevel
+	}
+
+	if err := checkAPICollabChange(r, repo.Name(), req.Username, level); err != nil {
+		renderAPIErr(w, r, err)
+		return
+	}
+
+	if err := be.AddCollaborator(ctx, repo.Name(), req.Username, level); err != nil {
+		renderAPIErr(w, r, err)
+		return
+	}
+
+	renderAPIJSON(w, http.StatusCreated, apiCollaboratorResponse{
+		Username:    strings.ToLower(req.Username),
+		AccessLevel: level,
+	})
+}
+
+// DELETE /api/v1/repos/{repo}/collaborators/{username}
+func deleteAPICollaborator(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	be := backend.FromContext(ctx)
+	repo, ok := apiRepo(w, r, access.ReadWriteAccess)
+	if !ok {
+		return
+	}
+
+	username := mux.Vars(r)["username"]
+	if _, isCollab, _ := be.IsCollaborator(ctx, repo.Name(), username); !isCollab {
+		renderAPIErr(w, r, proto.ErrCollaboratorNotFound)
+		return
+	}
+
+	if err := checkAPICollabChange(r, repo.Name(), username, access.NoAccess); err != nil {
+		renderAPIErr(w, r, err)
+		return
+	}
+
+	if err := be.RemoveCollaborator(ctx, repo.Name(), username); err != nil {
+		renderAPIErr(w, r, err)
+		return
+	}
+
+	renderAPINoContent(w)
+}
+
+// checkAPICollabChange reports whether the caller may set the access level of
+// a collaborator, or remove them with access.NoAccess.
+//
+// Like the collab SSH commands, callers cannot grant more than their own
+// access level, nor change collaborators who have more access than them.
+func checkAPICollabChange(r *http.Request, repo string, username string, level access.AccessLevel) error {
+	ctx := r.Context()
+	be := backend.FromContext(ctx)
+	user := proto.UserFromContext(ctx)
+	if user.IsAdmin() {
+		return nil
+	}
+
+	caller := be.AccessLevelForUser(ctx, repo, user)
+	if level > caller {
+		return proto.ErrExceedsAccessLevel
+	}
+
+	current, isCollab, err := be.IsCollaborator(ctx, repo, username)
+	if err != nil {
+		// A missing row just means the user is not a collaborator yet.
+		if !errors.Is(err, db.ErrRecordNotFound) {
+			return err
+		}
+		return nil
+	}
+
+	if isCollab && current > caller {
+		return proto.ErrExceedsAccessLevel
+	}
+
+	return nil
+}
+
+// apiWebhookResponse is the API representation of a webhook.
+type apiWebhookResponse struct {
+	ID          int64               `json:"id"`
+	URL         string              `json:"url"`
+	ContentType webhook.ContentType `json:"content_type"`
+	Events      []webhook.Event     `json:"events"`
+	Active      bool                `json:"active"`
+	CreatedAt   time.Time           `json:"created_at"`
+	UpdatedAt   time.Time           `json:"updated_at"`
+}
+
+func newAPIWebhookResponse(wh webhook.Hook) apiWebhookResponse {
+	return apiWebhookResponse{
+		ID:          wh.ID,
+		URL:         wh.URL,
+		ContentType: wh.ContentType,
+		Events:      wh.Events,
+		Active:      wh.Active,
+		CreatedAt:   wh.CreatedAt,
+		UpdatedAt:   wh.UpdatedAt,
+	}
+}
+
+// apiWebhookRequest is the body of webhook creation and update requests.
+//
+// Fields left out of an update request are left unchanged.
+type apiWebhookRequest struct {
+	URL *string `json:"url"`
+	// ContentType is either json or form.
+	ContentType *string  `json:"content_type"`
+	Secret      *string  `json:"secret"`
+	Events      []string `json:"events"`
+	Active      *bool    `json:"active"`
+}
+
+func parseAPIWebhookContentType(s string) (webhook.ContentType, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "json":
+		return webhook.ContentTypeJSON, nil
+	case "form":
+		return webhook.ContentTypeForm, nil
+	default:
+		return webhook.ParseContentType(s)
+	}
+}
+
+func parseAPIWebhookEvents(events []string) ([]webhook.Event, error) {
+	evs := make([]webhook.Event, 0, len(events))
+	for _, e := range events {
+		ev, err := webhook.ParseEvent(e)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", err, e)
+		}
+
+		evs = append(evs, ev)
+	}
+
+	return evs, nil
+}
+
+// GET /api/v1/repos/{repo}/webhooks
+func getAPIWebhooks(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	be := backend.FromContext(ctx)
+	repo, ok := apiRepo(w, r, access.AdminAccess)
+	if !ok {
+		return
+	}
+
+	whs, err := be.ListWebhooks(ctx, repo)
+	if err != nil {
+		renderAPIErr(w, r, err)
+		return
+	}
+
+	res := make([]apiWebhookResponse, len(whs))
+	for i, wh := range whs {
+		res[i] = newAPIWebhookResponse(wh)
+	}
+
+	renderAPIJSON(w, http.StatusOK, res)
+}
+
+// POST /api/v1/repos/{repo}/webhooks
+func postAPIWebhook(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	be := backend.FromContext(ctx)
+	repo, ok := apiRepo(w, r, access.AdminAccess)
+	if !ok {
+		return
+	}
+
+	var req apiWebhookRequest
+	if err := decodeAPIBody(r, &req); err != nil {
+		renderAPIErr(w, r, err)
+		return
+	}
+
+	if req.URL == nil {
+		renderAPIErr(w, r, fmt.Errorf("%w: url is required", errAPIInvalidRequest))
+		return
+	}
+
+	ct := webhook.ContentTypeJSON
+	if req.ContentType != nil {
+		var err error
+		ct, err = parseAPIWebhookContentType(*req.ContentType)
+		if err != nil {
+			renderAPIErr(w, r, err)
+			return
+		}
+	}
+
+	evs, err := parseAPIWebhookEvents(req.Events)
+	if err != nil {
+		renderAPIErr(w, r, err)
+		return
+	}
+
+	var secret string
+	if req.Secret != nil {
+		secret = *req.Secret
+	}
+
+	active := true
+	if req.Active != nil {
+		active = *req.Active
+	}
+
+	url := strings.TrimSpace(utils.Sanitize(*req.URL))
+	if err := be.CreateWebhook(ctx, repo, url, ct, secret, evs, active); err != nil {
+		renderAPIErr(w, r, err)
+		return
+	}
+
+	// CreateWebhook does not return the new webhook, the latest one with
+	// this URL is it.
+	whs, err := be.ListWebhooks(ctx, repo)
+	if err != nil {
+		renderAPIErr(w, r, err)
+		return
+	}
+
+	var created *webhook.Hook
+	for i, wh := range whs {
+		if wh.URL == url && (created == nil || wh.ID > created.ID) {
+			created = &whs[i]
+		}
+	}
+
+	if created == nil {
+		renderAPIErr(w, r, db.ErrRecordNotFound)
+		return
+	}
+
+	renderAPIJSON(w, http.StatusCreated, newAPIWebhookResponse(*created))
+}
+
+// GET /api/v1/repos/{repo}/webhooks/{id}
+func getAPIWebhook(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	be := backend.FromContext(ctx)
+	repo, ok := apiRepo(w, r, access.AdminAccess)
+	if !ok {
+		return
+	}
+
+	wh, err := be.Webhook(ctx, repo, apiIDVar(r))
+	if err != nil {
+		renderAPIErr(w, r, err)
+		return
+	}
+
+	renderAPIJSON(w, http.StatusOK, newAPIWebhookResponse(wh))
+}
+
+// PATCH /api/v1/repos/{repo}/webhooks/{id}
+func patchAPIWebhook(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	be := backend.FromContext(ctx)
+	repo, ok := apiRepo(w, r, access.AdminAccess)
+	if !ok {
+		return
+	}
+
+	var req apiWebhookRequest
+	if err := decodeAPIBody(r, &req); err != nil {
+		renderAPIErr(w, r, err)
+		return
+	}
+
+	id := apiIDVar(r)
+	wh, err := be.Webhook(ctx, repo, id)
+	if err != nil {
+		renderAPIErr(w, r, err)
+		return
+	}
+
+	url := wh.URL
+	if req.URL != nil {
+		url = strings.TrimSpace(utils.Sanitize(*req.URL))
+	}
+
+	ct := wh.ContentType
+	if req.ContentType != nil {
+		ct, err = parseAPIWebhookContentType(*req.ContentType)
+		if err != nil {
+			renderAPIErr(w, r, err)
+			return
+		}
+	}
+
+	secret := wh.Secret
+	if req.Secret != nil {
+		secret = *req.Secret
+	}
+
+	evs := wh.Events
+	if req.Events != nil {
+		evs, err = parseAPIWebhookEvents(req.Events)
+		if err != nil {
+			renderAPIErr(w, r, err)
+			return
+		}
+	}
+
+	active := wh.Active
+	if req.Active != nil {
+		active = *req.Active
+	}
+
+	if err := be.UpdateWebhook(ctx, repo, id, url, ct, secret, evs, active); err != nil {
+		renderAPIErr(w, r, err)
+		return
+	}
+
+	wh, err = be.Webhook(ctx, repo, id)
+	if err != nil {
+		renderAPIErr(w, r, err)
+		return
+	}
+
+	renderAPIJSON(w, http.StatusOK, newAPIWebhookResponse(wh))
+}
+
+// DELETE /api/v1/repos/{repo}/webhooks/{id}
+func deleteAPIWebhook(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	be := backend.FromContext(ctx)
+	repo, ok := apiRepo(w, r, access.AdminAccess)
+	if !ok {
+		return
+	}
+
+	id := apiIDVar(r)
+	if _, err := be.Webhook(ctx, repo, id); err != nil {
+		renderAPIErr(w, r, err)
+		return
+	}
+
+	if err := be.DeleteWebhook(ctx, repo, id); err != nil {
+		renderAPIErr(w, r, err)
+		return
+	}
+
+	renderAPINoContent(w)
+}
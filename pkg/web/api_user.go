@@ -0,0 +1,281 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/caarlos0/duration"
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/sshutils"
+	"github.com/charmbracelet/soft-serve/pkg/utils"
+	"github.com/gorilla/mux"
+)
+
+// apiUserResponse is the API representation of a user.
+type apiUserResponse struct {
+	ID         int64    `json:"id"`
+	Username   string   `json:"username"`
+	Admin      bool     `json:"admin"`
+	PublicKeys []string `json:"public_keys"`
+}
+
+func newAPIUserResponse(user proto.User) apiUserResponse {
+	pks := user.PublicKeys()
+	keys := make([]string, len(pks))
+	for i, pk := range pks {
+		keys[i] = sshutils.MarshalAuthorizedKey(pk)
+	}
+
+	return apiUserResponse{
+		ID:         user.ID(),
+		Username:   user.Username(),
+		Admin:      user.IsAdmin(),
+		PublicKeys: keys,
+	}
+}
+
+// GET /api/v1/user
+func getAPIUser(w http.ResponseWriter, r *http.Request) {
+	user, ok := apiUser(w, r)
+	if !ok {
+		return
+	}
+
+	renderAPIJSON(w, http.StatusOK, newAPIUserResponse(user))
+}
+
+// GET /api/v1/users
+func getAPIUsers(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	be := backend.FromContext(ctx)
+	user, ok := apiUser(w, r)
+	if !ok {
+		return
+	}
+
+	if !user.IsAdmin() {
+		renderAPIErr(w, r, proto.ErrUnauthorized)
+		return
+	}
+
+	usernames, err := be.Users(ctx)
+	if err != nil {
+		renderAPIErr(w, r, err)
+		return
+	}
+
+	users := make([]apiUserResponse, 0, len(usernames))
+	for _, username := range usernames {
+		u, err := be.User(ctx, username)
+		if err != nil {
+			renderAPIErr(w, r, err)
+			return
+		}
+
+		users = append(users, newAPIUserResponse(u))
+	}
+
+	renderAPIJSON(w, http.StatusOK, users)
+}
+
+// GET /api/v1/users/{username}
+func getAPIUserByName(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	be := backend.FromContext(ctx)
+	user, ok := apiUser(w, r)
+	if !ok {
+		return
+	}
+
+	// Users can only look themselves up unless they are admins.
+	username := mux.Vars(r)["username"]
+	if !user.IsAdmin() && username != user.Username() {
+		renderAPIErr(w, r, proto.ErrUserNotFound)
+		return
+	}
+
+	u, err := be.User(ctx, username)
+	if err != nil {
+		renderAPIErr(w, r, err)
+		return
+	}
+
+	renderAPIJSON(w, http.StatusOK, newAPIUserResponse(u))
+}
+
+// apiTokenResponse is the API representation of an access token.
+type apiTokenResponse struct {
+	ID        int64      `json:"id"`
+	Name      string     `json:"name"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt *time.Time `json:"expires_at"`
+	Scopes    []string   `json:"scopes"`
+	// Repos are the repositories the token is restricted to, empty for
+	// any repository.
+	Repos []string `json:"repos"`
+	// Token is only set when the token is created.
+	Token string `json:"token,omitempty"`
+}
+
+func newAPITokenResponse(token proto.AccessToken) apiTokenResponse {
+	scopes := token.Scopes
+	if len(scopes) == 0 {
+		scopes = proto.AccessTokenScopes
+	}
+
+	res := apiTokenResponse{
+		ID:        token.ID,
+		Name:      token.Name,
+		CreatedAt: token.CreatedAt,
+		Scopes:    make([]string, len(scopes)),
+		Repos:     append([]string{}, token.Repos...),
+	}
+	for i, s := range scopes {
+		res.Scopes[i] = string(s)
+	}
+	if !token.ExpiresAt.IsZero() {
+		res.ExpiresAt = &token.ExpiresAt
+	}
+
+	return res
+}
+
+// GET /api/v1/tokens
+func getAPITokens(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	be := backend.FromContext(ctx)
+	user, ok := apiUser(w, r)
+	if !ok {
+		return
+	}
+
+	tokens, err := be.ListAccessTokens(ctx, user)
+	if err != nil {
+		renderAPIErr(w, r, err)
+		return
+	}
+
+	res := make([]apiTokenResponse, len(tokens))
+	for i, t := range tokens {
+		res[i] = newAPITokenResponse(t)
+	}
+
+	renderAPIJSON(w, http.StatusOK, res)
+}
+
+// apiTokenRequest is the body of a token creation request.
+type apiTokenRequest struct {
+	Name string `json:"name"`
+	// ExpiresIn is the token lifetime, e.g. 1y, 3mo, 2w, 5d4h, 1h30m.
+	ExpiresIn string `json:"expires_in"`
+	// Scopes are the token scopes, all of them if empty.
+	Scopes []string `json:"scopes"`
+	// Repos restricts the token to these repositories.
+	Repos []string `json:"repos"`
+}
+
+// POST /api/v1/tokens
+func postAPIToken(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	be := backend.FromContext(ctx)
+	user, ok := apiUser(w, r)
+	if !ok || !apiTokenScope(w, r, user, proto.AccessTokenScopeAdmin) {
+		return
+	}
+
+	var req apiTokenRequest
+	if err := decodeAPIBody(r, &req); err != nil {
+		renderAPIErr(w, r, err)
+		return
+	}
+
+	if req.Name == "" {
+		renderAPIErr(w, r, fmt.Errorf("%w: name is required", errAPIInvalidRequest))
+		return
+	}
+
+	var expiresAt time.Time
+	if req.ExpiresIn != "" {
+		d, err := duration.Parse(req.ExpiresIn)
+		if err != nil {
+			renderAPIErr(w, r, fmt.Errorf("%w: %w", errAPIInvalidRequest, err))
+			return
+		}
+
+		expiresAt = time.Now().Add(d)
+	}
+
+	opts := proto.AccessTokenOptions{Repos: req.Repos}
+	for _, s := range req.Scopes {
+		scope, err := proto.ParseAccessTokenScope(s)
+		if err != nil {
+			renderAPIErr(w, r, err)
+			return
+		}
+		opts.Scopes = append(opts.Scopes, scope)
+	}
+	for _, repo := range req.Repos {
+		if err := utils.ValidateRepo(utils.SanitizeRepo(repo)); err != nil {
+			renderAPIErr(w, r, fmt.Errorf("%w: %w", errAPIInvalidRequest, err))
+			return
+		}
+	}
+
+	token, err := be.CreateAccessToken(ctx, user, req.Name, expiresAt, opts)
+	if err != nil {
+		renderAPIErr(w, r, err)
+		return
+	}
+
+	// Look the new token up to report its ID and creation time.
+	tokens, err := be.ListAccessTokens(ctx, user)
+	if err != nil {
+		renderAPIErr(w, r, err)
+		return
+	}
+
+	hash := backend.HashToken(token)
+	for _, t := range tokens {
+		if t.TokenHash == hash {
+			res := newAPITokenResponse(t)
+			res.Token = token
+			renderAPIJSON(w, http.StatusCreated, res)
+			return
+		}
+	}
+
+	renderAPIErr(w, r, proto.ErrTokenNotFound)
+}
+
+// DELETE /api/v1/tokens/{id}
+func deleteAPIToken(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	be := backend.FromContext(ctx)
+	user, ok := apiUser(w, r)
+	if !ok || !apiTokenScope(w, r, user, proto.AccessTokenScopeAdmin) {
+		return
+	}
+
+	if err := be.DeleteAccessToken(ctx, user, apiIDVar(r)); err != nil {
+		renderAPIErr(w, r, err)
+		return
+	}
+
+	renderAPINoContent(w)
+}
+
+// apiTokenScope denies users who authenticated with an access token that
+// lacks a scope or is restricted to some repositories, so that a restricted
+// token can't be traded for a more powerful one.
+//
+// It renders the error response and returns false if access is denied.
+func apiTokenScope(w http.ResponseWriter, r *http.Request, user proto.User, scope proto.AccessTokenScope) bool {
+	if t := backend.UserAccessToken(user); t != nil && (!t.HasScope(scope) || len(t.Repos) > 0) {
+		renderAPIErr(w, r, fmt.Errorf("%w: %s", proto.ErrTokenScope, scope))
+		return false
+	}
+
+	return true
+}
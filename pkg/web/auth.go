@@ -42,7 +42,7 @@ func parseUsernamePassword(ctx context.Context, username, password string) (prot
 		}
 
 		// Try to authenticate using access token as the password
-		user, err = be.UserByAccessToken(ctx, password)
+		user, err = userByAccessToken(ctx, password)
 		if err == nil {
 			return user, nil
 		}
@@ -52,7 +52,7 @@ func parseUsernamePassword(ctx context.Context, username, password string) (prot
 	} else if username != "" {
 		// Try to authenticate using access token as the username
 		logger.Debug("trying to authenticate using access token as username", "username", username)
-		user, err := be.UserByAccessToken(ctx, username)
+		user, err := userByAccessToken(ctx, username)
 		if err == nil {
 			return user, nil
 		}
@@ -64,6 +64,25 @@ func parseUsernamePassword(ctx context.Context, username, password string) (prot
 	return nil, proto.ErrUserNotFound
 }
 
+// userByAccessToken finds the user of an access token and records that the
+// token was used.
+func userByAccessToken(ctx context.Context, token string) (proto.User, error) {
+	be := backend.FromContext(ctx)
+	user, err := be.UserByAccessToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	if t := backend.UserAccessToken(user); t != nil {
+		addr := backend.AuditSourceFromContext(ctx).Addr
+		if err := be.RecordAccessTokenUse(ctx, t.ID, addr); err != nil {
+			log.FromContext(ctx).Error("failed to record access token use", "id", t.ID, "err", err)
+		}
+	}
+
+	return user, nil
+}
+
 // ErrInvalidHeader is returned when the authorization header is invalid.
 var ErrInvalidHeader = errors.New("invalid authorization header")
 
@@ -87,7 +106,7 @@ func parseAuthHdr(r *http.Request) (proto.User, error) {
 
 	switch strings.ToLower(parts[0]) {
 	case "token":
-		user, err := be.UserByAccessToken(ctx, parts[1])
+		user, err := userByAccessToken(ctx, parts[1])
 		if err != nil {
 			logger.Error("failed to get user", "err", err)
 			return nil, err
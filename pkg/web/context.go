@@ -31,6 +31,7 @@ func NewContextHandler(ctx context.Context) func(http.Handler) http.Handler {
 			))
 			ctx = db.WithContext(ctx, dbx)
 			ctx = store.WithContext(ctx, datastore)
+			ctx = backend.WithAuditSourceContext(ctx, r.RemoteAddr, "http")
 			r = r.WithContext(ctx)
 
 			next.ServeHTTP(w, r)
@@ -156,6 +156,12 @@ var gitRoutes = []GitRoute{
 		handler: getIdxFile,
 		path:    "/objects/pack/{_:pack-[0-9a-f]{40}\\.idx$}",
 	},
+	// Archives
+	{
+		method:  []string{http.MethodGet},
+		handler: getArchive,
+		path:    "/archive/{ref:.+\\.(?:tar\\.gz|zip)$}",
+	},
 	// Git LFS
 	{
 		method:  []string{http.MethodPost},
@@ -268,6 +274,14 @@ func withAccess(next http.Handler) http.HandlerFunc {
 				return
 			}
 
+			// Access tokens also need the lfs scope.
+			if t := backend.UserAccessToken(user); t != nil && !t.HasScope(proto.AccessTokenScopeLFS) {
+				renderJSON(w, http.StatusForbidden, lfs.ErrorResponse{
+					Message: proto.ErrTokenScope.Error() + ": lfs",
+				})
+				return
+			}
+
 			switch {
 			case strings.HasPrefix(file, "info/lfs/locks"):
 				switch {
@@ -0,0 +1,97 @@
+package web
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"charm.land/log/v2"
+	gitb "github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var gitHttpArchiveCounter = promauto.NewCounterVec(prometheus.CounterOpts{ //nolint:revive
+	Namespace: "soft_serve",
+	Subsystem: "http",
+	Name:      "git_archive_total",
+	Help:      "The total number of archive downloads",
+}, []string{"repo", "format"})
+
+// archiveContentTypes maps archive formats to their content types.
+var archiveContentTypes = map[string]string{
+	gitb.ArchiveTarGz: "application/gzip",
+	gitb.ArchiveZip:   "application/zip",
+}
+
+// countingWriter records whether anything was written to the response.
+type countingWriter struct {
+	http.ResponseWriter
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(p)
+	w.n += int64(n)
+	return n, err
+}
+
+// GET /{repo}/archive/{ref}.tar.gz
+// GET /{repo}/archive/{ref}.zip
+//
+// The archive paths are prefixed with "{repo}-{ref}/". Set the "lfs" query
+// parameter to true to replace Git LFS pointers with their objects.
+func getArchive(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := log.FromContext(ctx)
+	be := backend.FromContext(ctx)
+	repo := proto.RepositoryFromContext(ctx)
+	ref := mux.Vars(r)["ref"]
+
+	var format string
+	for f := range archiveContentTypes {
+		if rest, ok := strings.CutSuffix(ref, "."+f); ok {
+			ref, format = rest, f
+			break
+		}
+	}
+
+	smudge, _ := strconv.ParseBool(r.URL.Query().Get("lfs"))
+	name := path.Base(repo.Name()) + "-" + strings.ReplaceAll(ref, "/", "-")
+
+	w.Header().Set("Content-Type", archiveContentTypes[format])
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name+"."+format))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+
+	cw := &countingWriter{ResponseWriter: w}
+	if err := be.ArchiveRepository(ctx, cw, repo.Name(), ref, backend.ArchiveOptions{
+		Format: format,
+		Prefix: name + "/",
+		LFS:    smudge,
+	}); err != nil {
+		if cw.n > 0 {
+			// The response has started, all we can do is cut it short.
+			logger.Error("failed to write archive", "repo", repo.Name(), "ref", ref, "err", err)
+			return
+		}
+
+		w.Header().Del("Content-Type")
+		w.Header().Del("Content-Disposition")
+		switch {
+		case errors.Is(err, gitb.ErrRevisionNotExist), errors.Is(err, proto.ErrInvalidArchiveFormat):
+			renderNotFound(w, r)
+		default:
+			logger.Error("failed to archive repository", "repo", repo.Name(), "ref", ref, "err", err)
+			renderInternalServerError(w, r)
+		}
+		return
+	}
+
+	gitHttpArchiveCounter.WithLabelValues(repo.Name(), format).Inc()
+}
@@ -9,9 +9,9 @@ import (
 	"net/http"
 	"net/url"
 	"path"
-	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"charm.land/log/v2"
 	"github.com/charmbracelet/soft-serve/pkg/access"
@@ -88,9 +88,20 @@ func serviceLfsBatch(w http.ResponseWriter, r *http.Request) {
 	cfg := config.FromContext(ctx)
 	dbx := db.FromContext(ctx)
 	datastore := store.FromContext(ctx)
-	// TODO: support S3 storage
-	repoID := strconv.FormatInt(repo.ID(), 10)
-	strg := storage.NewLocalStorage(filepath.Join(cfg.DataPath, "lfs", repoID))
+	strg, err := config.LFSStorage(cfg, repo.ID())
+	if err != nil {
+		logger.Error("error opening lfs storage", "repo", name, "err", err)
+		renderJSON(w, http.StatusInternalServerError, lfs.ErrorResponse{
+			Message: "internal server error",
+		})
+		return
+	}
+
+	// Storages that can pre-sign URLs let clients transfer objects directly,
+	// without going through this server.
+	presigner, presign := strg.(storage.Presigner)
+	presign = presign && cfg.LFS.S3.Presign
+	presignExpiry := time.Duration(cfg.LFS.S3.PresignExpiry) * time.Second
 
 	baseHref := fmt.Sprintf("%s/%s/info/lfs/objects/basic", cfg.HTTP.PublicURL, name+".git")
 
@@ -99,8 +110,6 @@ func serviceLfsBatch(w http.ResponseWriter, r *http.Request) {
 	batchResponse.HashAlgo = lfs.HashAlgorithmSHA256
 
 	objects := make([]*lfs.ObjectResponse, 0, len(batchRequest.Objects))
-	// XXX: We don't support objects TTL for now, probably implement that with
-	// S3 using object "expires_at" & "expires_in"
 	switch batchRequest.Operation {
 	case lfs.OperationDownload:
 		for _, o := range batchRequest.Objects {
@@ -156,7 +165,22 @@ func serviceLfsBatch(w http.ResponseWriter, r *http.Request) {
 				download := &lfs.Link{
 					Href: fmt.Sprintf("%s/%s", baseHref, o.Oid),
 				}
-				if auth := r.Header.Get("Authorization"); auth != "" {
+				if presign {
+					href, err := presigner.PresignGet(path.Join("objects", o.RelativePath()), presignExpiry)
+					if err != nil {
+						logger.Error("error pre-signing object download", "oid", o.Oid, "repo", name, "err", err)
+						renderJSON(w, http.StatusInternalServerError, lfs.ErrorResponse{
+							Message: "internal server error",
+						})
+						return
+					}
+
+					expiresAt := time.Now().Add(presignExpiry)
+					download = &lfs.Link{
+						Href:      href,
+						ExpiresAt: &expiresAt,
+					}
+				} else if auth := r.Header.Get("Authorization"); auth != "" {
 					download.Header = map[string]string{
 						"Authorization": auth,
 					}
@@ -192,6 +216,40 @@ func serviceLfsBatch(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		// Reject the whole batch rather than let the client upload some of
+		// the objects of a push.
+		var pending int64
+		for _, o := range batchRequest.Objects {
+			if !o.IsValid() {
+				continue
+			}
+
+			if _, err := datastore.GetLFSObjectByOid(ctx, dbx, repo.ID(), o.Oid); errors.Is(err, db.ErrRecordNotFound) {
+				pending += o.Size
+			} else if err != nil {
+				logger.Error("error getting object from database", "oid", o.Oid, "repo", name, "err", err)
+				renderJSON(w, http.StatusInternalServerError, lfs.ErrorResponse{
+					Message: "internal server error",
+				})
+				return
+			}
+		}
+
+		if err := backend.FromContext(ctx).CheckLFSQuota(ctx, repo, pending); err != nil {
+			if errors.Is(err, proto.ErrQuotaExceeded) {
+				renderJSON(w, http.StatusInsufficientStorage, lfs.ErrorResponse{
+					Message: err.Error(),
+				})
+				return
+			}
+
+			logger.Error("error checking lfs quota", "repo", name, "err", err)
+			renderJSON(w, http.StatusInternalServerError, lfs.ErrorResponse{
+				Message: "internal server error",
+			})
+			return
+		}
+
 		// Object upload logic happens in the "basic" API route
 		for _, o := range batchRequest.Objects {
 			if !o.IsValid() {
@@ -214,8 +272,26 @@ func serviceLfsBatch(w http.ResponseWriter, r *http.Request) {
 				verify := &lfs.Link{
 					Href: fmt.Sprintf("%s/verify", baseHref),
 				}
+				if presign {
+					// The URL is signed for the object size, so the upload
+					// can't be larger than the client announced.
+					href, err := presigner.PresignPut(path.Join("objects", o.RelativePath()), o.Size, presignExpiry)
+					if err != nil {
+						logger.Error("error pre-signing object upload", "oid", o.Oid, "repo", name, "err", err)
+						renderJSON(w, http.StatusInternalServerError, lfs.ErrorResponse{
+							Message: "internal server error",
+						})
+						return
+					}
+
+					expiresAt := time.Now().Add(presignExpiry)
+					upload.Href = href
+					upload.ExpiresAt = &expiresAt
+				}
 				if auth := r.Header.Get("Authorization"); auth != "" {
-					upload.Header["Authorization"] = auth
+					if !presign {
+						upload.Header["Authorization"] = auth
+					}
 					verify.Header = map[string]string{
 						"Authorization": auth,
 					}
@@ -263,8 +339,14 @@ func serviceLfsBasicDownload(w http.ResponseWriter, r *http.Request) {
 	logger := log.FromContext(ctx).WithPrefix("http.lfs-basic")
 	datastore := store.FromContext(ctx)
 	dbx := db.FromContext(ctx)
-	repoID := strconv.FormatInt(repo.ID(), 10)
-	strg := storage.NewLocalStorage(filepath.Join(cfg.DataPath, "lfs", repoID))
+	strg, err := config.LFSStorage(cfg, repo.ID())
+	if err != nil {
+		logger.Error("error opening lfs storage", "repo", repo.Name(), "err", err)
+		renderJSON(w, http.StatusInternalServerError, lfs.ErrorResponse{
+			Message: "internal server error",
+		})
+		return
+	}
 
 	obj, err := datastore.GetLFSObjectByOid(ctx, dbx, repo.ID(), oid)
 	if err != nil && !errors.Is(err, db.ErrRecordNotFound) {
@@ -324,9 +406,6 @@ func serviceLfsBasicUpload(w http.ResponseWriter, r *http.Request) {
 	dbx := db.FromContext(ctx)
 	datastore := store.FromContext(ctx)
 	logger := log.FromContext(ctx).WithPrefix("http.lfs-basic")
-	repo := proto.RepositoryFromContext(ctx)
-	repoID := strconv.FormatInt(repo.ID(), 10)
-	strg := storage.NewLocalStorage(filepath.Join(cfg.DataPath, "lfs", repoID))
 	name := mux.Vars(r)["repo"]
 
 	defer r.Body.Close() //nolint: errcheck
@@ -338,6 +417,15 @@ func serviceLfsBasicUpload(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	strg, err := config.LFSStorage(cfg, repo.ID())
+	if err != nil {
+		logger.Error("error opening lfs storage", "repo", name, "err", err)
+		renderJSON(w, http.StatusInternalServerError, lfs.ErrorResponse{
+			Message: "internal server error",
+		})
+		return
+	}
+
 	// NOTE: Git LFS client will retry uploading the same object if there was a
 	// partial error, so we need to skip existing objects.
 	if _, err := datastore.GetLFSObjectByOid(ctx, dbx, repo.ID(), oid); err == nil {
@@ -353,20 +441,37 @@ func serviceLfsBasicUpload(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	pointer := lfs.Pointer{Oid: oid}
-	if _, err := strg.Put(path.Join("objects", pointer.RelativePath()), r.Body); err != nil {
-		logger.Error("error writing object", "oid", oid, "err", err)
+	size, err := strconv.ParseInt(r.Header.Get("Content-Length"), 10, 64)
+	if err != nil {
+		logger.Error("error parsing content length", "err", err)
+		renderJSON(w, http.StatusBadRequest, lfs.ErrorResponse{
+			Message: "invalid content length",
+		})
+		return
+	}
+
+	// The batch request checked the quota already, this catches clients
+	// uploading without one.
+	if err := be.CheckLFSQuota(ctx, repo, size); err != nil {
+		if errors.Is(err, proto.ErrQuotaExceeded) {
+			renderJSON(w, http.StatusInsufficientStorage, lfs.ErrorResponse{
+				Message: err.Error(),
+			})
+			return
+		}
+
+		logger.Error("error checking lfs quota", "repo", name, "err", err)
 		renderJSON(w, http.StatusInternalServerError, lfs.ErrorResponse{
 			Message: "internal server error",
 		})
 		return
 	}
 
-	size, err := strconv.ParseInt(r.Header.Get("Content-Length"), 10, 64)
-	if err != nil {
-		logger.Error("error parsing content length", "err", err)
-		renderJSON(w, http.StatusBadRequest, lfs.ErrorResponse{
-			Message: "invalid content length",
+	pointer := lfs.Pointer{Oid: oid}
+	if _, err := strg.Put(path.Join("objects", pointer.RelativePath()), r.Body); err != nil {
+		logger.Error("error writing object", "oid", oid, "err", err)
+		renderJSON(w, http.StatusInternalServerError, lfs.ErrorResponse{
+			Message: "internal server error",
 		})
 		return
 	}
@@ -423,19 +528,19 @@ func serviceLfsBasicVerify(w http.ResponseWriter, r *http.Request) {
 	cfg := config.FromContext(ctx)
 	dbx := db.FromContext(ctx)
 	datastore := store.FromContext(ctx)
-	repoID := strconv.FormatInt(repo.ID(), 10)
-	strg := storage.NewLocalStorage(filepath.Join(cfg.DataPath, "lfs", repoID))
+	strg, err := config.LFSStorage(cfg, repo.ID())
+	if err != nil {
+		logger.Error("error opening lfs storage", "repo", repo.Name(), "err", err)
+		renderJSON(w, http.StatusInternalServerError, lfs.ErrorResponse{
+			Message: "internal server error",
+		})
+		return
+	}
+
 	if stat, err := strg.Stat(path.Join("objects", pointer.RelativePath())); err == nil {
 		// Verify object is in the database.
 		obj, err := datastore.GetLFSObjectByOid(ctx, dbx, repo.ID(), pointer.Oid)
-		if err != nil {
-			if errors.Is(err, db.ErrRecordNotFound) {
-				logger.Error("object not found", "oid", pointer.Oid)
-				renderJSON(w, http.StatusNotFound, lfs.ErrorResponse{
-					Message: "object not found",
-				})
-				return
-			}
+		if err != nil && !errors.Is(err, db.ErrRecordNotFound) {
 			logger.Error("error getting object", "oid", pointer.Oid, "err", err)
 			renderJSON(w, http.StatusInternalServerError, lfs.ErrorResponse{
 				Message: "internal server error",
@@ -443,17 +548,35 @@ func serviceLfsBasicVerify(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		if obj.Size != pointer.Size {
+		if stat.Size() != pointer.Size || (obj.ID != 0 && obj.Size != pointer.Size) {
 			renderJSON(w, http.StatusBadRequest, lfs.ErrorResponse{
 				Message: "object size mismatch",
 			})
 			return
 		}
 
-		if stat.Size() == pointer.Size {
-			renderStatus(http.StatusOK)(w, nil)
-			return
+		if obj.ID == 0 {
+			_, presign := strg.(storage.Presigner)
+			if !presign || !cfg.LFS.S3.Presign {
+				logger.Error("object not found", "oid", pointer.Oid)
+				renderJSON(w, http.StatusNotFound, lfs.ErrorResponse{
+					Message: "object not found",
+				})
+				return
+			}
+
+			// Objects uploaded with a pre-signed URL went straight to the
+			// storage, record them now.
+			if err := datastore.CreateLFSObject(ctx, dbx, repo.ID(), pointer.Oid, pointer.Size); err != nil {
+				logger.Error("error creating object", "oid", pointer.Oid, "err", err)
+				renderJSON(w, http.StatusInternalServerError, lfs.ErrorResponse{
+					Message: "internal server error",
+				})
+				return
+			}
 		}
+
+		renderStatus(http.StatusOK)(w, nil)
 	} else if errors.Is(err, fs.ErrNotExist) {
 		logger.Error("file not found", "oid", pointer.Oid)
 		renderJSON(w, http.StatusNotFound, lfs.ErrorResponse{
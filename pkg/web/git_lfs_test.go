@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -287,7 +288,7 @@ func TestLFSLockCreateRejectsForgedServiceParam(t *testing.T) {
 	// An authenticated user with no more than read access to the repository.
 	attacker, err := be.CreateUser(ctx, "attacker", proto.UserOptions{})
 	is.NoErr(err)
-	token, err := be.CreateAccessToken(ctx, attacker, "test", time.Time{})
+	token, err := be.CreateAccessToken(ctx, attacker, "test", time.Time{}, proto.AccessTokenOptions{})
 	is.NoErr(err)
 
 	router := lfsTestRouter(ctx)
@@ -360,7 +361,7 @@ func TestLFSWriteStillWorksForAuthorizedUser(t *testing.T) {
 	is.NoErr(err)
 	repo, err := be.CreateRepository(ctx, "owner-repo", owner, proto.RepositoryOptions{})
 	is.NoErr(err)
-	token, err := be.CreateAccessToken(ctx, owner, "test", time.Time{})
+	token, err := be.CreateAccessToken(ctx, owner, "test", time.Time{}, proto.AccessTokenOptions{})
 	is.NoErr(err)
 
 	router := lfsTestRouter(ctx)
@@ -395,3 +396,194 @@ func TestLFSWriteStillWorksForAuthorizedUser(t *testing.T) {
 	is.NoErr(err)
 	is.Equal(got.Path, "src/file.bin")
 }
+
+// TestLFSRequiresTokenScope checks that access tokens need the lfs scope to
+// transfer LFS objects, on top of the access level their other scopes grant.
+func TestLFSRequiresTokenScope(t *testing.T) {
+	is := is.New(t)
+	ctx, be, _ := newLFSTestContext(t)
+
+	owner, err := be.CreateUser(ctx, "owner", proto.UserOptions{})
+	is.NoErr(err)
+	_, err = be.CreateRepository(ctx, "owner-repo", owner, proto.RepositoryOptions{})
+	is.NoErr(err)
+
+	router := lfsTestRouter(ctx)
+	oid := strings.Repeat("c", 64)
+	body := "payload"
+	upload := func(scopes ...proto.AccessTokenScope) int {
+		token, err := be.CreateAccessToken(ctx, owner, "test", time.Time{}, proto.AccessTokenOptions{Scopes: scopes})
+		is.NoErr(err)
+
+		req := httptest.NewRequestWithContext(ctx, http.MethodPut,
+			"/owner-repo.git/info/lfs/objects/basic/"+oid, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/octet-stream")
+		req.Header.Set("Content-Length", strconv.Itoa(len(body)))
+		req.Header.Set("Authorization", "token "+token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	is.Equal(upload(proto.AccessTokenScopeWrite), http.StatusForbidden)
+	is.Equal(upload(proto.AccessTokenScopeRead, proto.AccessTokenScopeLFS), http.StatusForbidden)
+	is.Equal(upload(proto.AccessTokenScopeWrite, proto.AccessTokenScopeLFS), http.StatusOK)
+}
+
+// TestLFSBatchPresignsS3Transfers checks that with S3 storage the batch
+// handler sends clients straight to the bucket, and that verify records
+// objects uploaded there.
+func TestLFSBatchPresignsS3Transfers(t *testing.T) {
+	is := is.New(t)
+	ctx, be, datastore := newLFSTestContext(t)
+	dbx := db.FromContext(ctx)
+
+	// An in-memory bucket, enough for the handlers to stat objects.
+	var mu sync.Mutex
+	objects := map[string]int{}
+	s3 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		size, ok := objects[r.URL.Path]
+		if r.Method != http.MethodHead || !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(size))
+	}))
+	t.Cleanup(s3.Close)
+
+	cfg := config.FromContext(ctx)
+	cfg.LFS.Storage = "s3"
+	cfg.LFS.S3.Endpoint = s3.URL
+	cfg.LFS.S3.Bucket = "lfs"
+	cfg.LFS.S3.PathStyle = true
+	cfg.LFS.S3.AccessKeyID = "AKID"
+	cfg.LFS.S3.SecretAccessKey = "secret"
+
+	owner, err := be.CreateUser(ctx, "owner", proto.UserOptions{})
+	is.NoErr(err)
+	repo, err := be.CreateRepository(ctx, "owner-repo", owner, proto.RepositoryOptions{})
+	is.NoErr(err)
+	token, err := be.CreateAccessToken(ctx, owner, "test", time.Time{}, proto.AccessTokenOptions{})
+	is.NoErr(err)
+
+	router := lfsTestRouter(ctx)
+	oid := strings.Repeat("c", 64)
+	key := "/lfs/" + strconv.FormatInt(repo.ID(), 10) + "/objects/cc/cc/" + oid
+
+	batch := func(op string) lfs.BatchResponse {
+		t.Helper()
+		req := httptest.NewRequestWithContext(ctx, http.MethodPost, "/owner-repo.git/info/lfs/objects/batch",
+			strings.NewReader(`{"operation":"`+op+`","objects":[{"oid":"`+oid+`","size":7}]}`))
+		req.Header.Set("Content-Type", lfs.MediaType)
+		req.Header.Set("Accept", lfs.MediaType)
+		req.Header.Set("Authorization", "token "+token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		is.Equal(w.Code, http.StatusOK)
+
+		var resp lfs.BatchResponse
+		is.NoErr(json.NewDecoder(w.Body).Decode(&resp))
+		is.Equal(len(resp.Objects), 1)
+		return resp
+	}
+
+	upload := batch(lfs.OperationUpload).Objects[0].Actions
+	href := upload[lfs.ActionUpload].Href
+	if !strings.HasPrefix(href, s3.URL+key+"?") || !strings.Contains(href, "X-Amz-Signature=") {
+		t.Fatalf("upload not pre-signed: %s", href)
+	}
+	// The token is for this server, it must not leak to the bucket.
+	if _, ok := upload[lfs.ActionUpload].Header["Authorization"]; ok {
+		t.Error("upload action carries the client's credentials")
+	}
+	if upload[lfs.ActionUpload].ExpiresAt == nil {
+		t.Error("upload action has no expiry")
+	}
+	is.Equal(upload[lfs.ActionVerify].Header["Authorization"], "token "+token)
+
+	// The client uploads to the bucket, then asks this server to verify.
+	mu.Lock()
+	objects[key] = 7
+	mu.Unlock()
+
+	verify := func(size int) int {
+		req := httptest.NewRequestWithContext(ctx, http.MethodPost, "/owner-repo.git/info/lfs/objects/basic/verify",
+			strings.NewReader(`{"oid":"`+oid+`","size":`+strconv.Itoa(size)+`}`))
+		req.Header.Set("Content-Type", lfs.MediaType)
+		req.Header.Set("Accept", lfs.MediaType)
+		req.Header.Set("Authorization", "token "+token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	is.Equal(verify(8), http.StatusBadRequest)
+	_, err = datastore.GetLFSObjectByOid(ctx, dbx, repo.ID(), oid)
+	is.True(err != nil) // size mismatch is not recorded
+
+	is.Equal(verify(7), http.StatusOK)
+	obj, err := datastore.GetLFSObjectByOid(ctx, dbx, repo.ID(), oid)
+	is.NoErr(err)
+	is.Equal(obj.Size, int64(7))
+
+	download := batch(lfs.OperationDownload).Objects[0].Actions[lfs.ActionDownload]
+	if download == nil || !strings.HasPrefix(download.Href, s3.URL+key+"?") {
+		t.Fatalf("download not pre-signed: %+v", download)
+	}
+	if _, ok := download.Header["Authorization"]; ok {
+		t.Error("download action carries the client's credentials")
+	}
+}
+
+// TestLFSUploadsRespectQuota checks that batch and basic uploads are rejected
+// once they would exceed the LFS quota, while objects already stored don't
+// count twice.
+func TestLFSUploadsRespectQuota(t *testing.T) {
+	is := is.New(t)
+	ctx, be, datastore := newLFSTestContext(t)
+	dbx := db.FromContext(ctx)
+
+	owner, err := be.CreateUser(ctx, "owner", proto.UserOptions{})
+	is.NoErr(err)
+	repo, err := be.CreateRepository(ctx, "owner-repo", owner, proto.RepositoryOptions{})
+	is.NoErr(err)
+	token, err := be.CreateAccessToken(ctx, owner, "test", time.Time{}, proto.AccessTokenOptions{})
+	is.NoErr(err)
+
+	stored := strings.Repeat("a", 64)
+	is.NoErr(datastore.CreateLFSObject(ctx, dbx, repo.ID(), stored, 8))
+	config.FromContext(ctx).Quota.RepoLFSSize = 10
+
+	router := lfsTestRouter(ctx)
+	batch := func(objects string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequestWithContext(ctx, http.MethodPost, "/owner-repo.git/info/lfs/objects/batch",
+			strings.NewReader(`{"operation":"upload","objects":[`+objects+`]}`))
+		req.Header.Set("Content-Type", lfs.MediaType)
+		req.Header.Set("Accept", lfs.MediaType)
+		req.Header.Set("Authorization", "token "+token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	oid := strings.Repeat("b", 64)
+	is.Equal(batch(`{"oid":"`+stored+`","size":8},{"oid":"`+oid+`","size":2}`).Code, http.StatusOK)
+	w := batch(`{"oid":"` + oid + `","size":3}`)
+	is.Equal(w.Code, http.StatusInsufficientStorage)
+	is.True(strings.Contains(w.Body.String(), "storage quota exceeded"))
+
+	req := httptest.NewRequestWithContext(ctx, http.MethodPut,
+		"/owner-repo.git/info/lfs/objects/basic/"+oid, strings.NewReader("abc"))
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("Content-Length", "3")
+	req.Header.Set("Authorization", "token "+token)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	is.Equal(w.Code, http.StatusInsufficientStorage)
+
+	_, err = datastore.GetLFSObjectByOid(ctx, dbx, repo.ID(), oid)
+	is.True(err != nil) // rejected uploads are not recorded
+}
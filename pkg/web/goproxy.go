@@ -0,0 +1,640 @@
+package web
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"charm.land/log/v2"
+	"github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/access"
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/charmbracelet/soft-serve/pkg/config"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+	modzip "golang.org/x/mod/zip"
+)
+
+var goProxyCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "soft_serve",
+	Subsystem: "http",
+	Name:      "go_proxy_total",
+	Help:      "The total number of Go module proxy requests",
+}, []string{"repo"})
+
+// GoProxyController registers the Go module proxy routes.
+//
+// The server implements the GOPROXY protocol for modules hosted in its
+// repositories. Module paths start with the host of the HTTP public URL, so
+// the public URL can be used as GOPROXY directly.
+//
+// https://go.dev/ref/mod#goproxy-protocol
+func GoProxyController(_ context.Context, r *mux.Router) {
+	basePrefix := "/{module:.+}"
+	r.Handle(basePrefix+"/@v/list", withGoProxyAccess(http.HandlerFunc(getGoProxyList))).Methods(http.MethodGet)
+	r.Handle(basePrefix+"/@v/{version}.info", withGoProxyAccess(http.HandlerFunc(getGoProxyInfo))).Methods(http.MethodGet)
+	r.Handle(basePrefix+"/@v/{version}.mod", withGoProxyAccess(http.HandlerFunc(getGoProxyMod))).Methods(http.MethodGet)
+	r.Handle(basePrefix+"/@v/{version}.zip", withGoProxyAccess(http.HandlerFunc(getGoProxyZip))).Methods(http.MethodGet)
+	r.Handle(basePrefix+"/@latest", withGoProxyAccess(http.HandlerFunc(getGoProxyLatest))).Methods(http.MethodGet)
+}
+
+type goModuleKey struct{}
+
+// goModule is a Go module hosted in a repository.
+type goModule struct {
+	// Path is the module path.
+	Path string
+	// PathMajor is the major version suffix of the module path, e.g. "/v2".
+	PathMajor string
+	// Dir is the directory of the module in the repository, empty for the
+	// root directory. Version tags of the module are prefixed with it.
+	Dir string
+	// Repo is the repository of the module.
+	Repo proto.Repository
+}
+
+// goModuleFromContext returns the Go module of the request.
+func goModuleFromContext(ctx context.Context) *goModule {
+	if m, ok := ctx.Value(goModuleKey{}).(*goModule); ok {
+		return m
+	}
+
+	return nil
+}
+
+// findGoModule returns the module of the given path. It walks up the path
+// until it finds a repository, the rest of the path being the module
+// directory in that repository.
+func findGoModule(ctx context.Context, modPath string) (*goModule, error) {
+	cfg := config.FromContext(ctx)
+	be := backend.FromContext(ctx)
+	publicURL, err := url.Parse(cfg.HTTP.PublicURL)
+	if err != nil {
+		return nil, err
+	}
+
+	// Module paths can't have a port, use the host name only.
+	rest, ok := strings.CutPrefix(modPath, publicURL.Hostname()+"/")
+	if !ok {
+		return nil, proto.ErrRepoNotFound
+	}
+
+	prefix, pathMajor, ok := module.SplitPathVersion(rest)
+	if !ok {
+		return nil, proto.ErrRepoNotFound
+	}
+
+	repo, dir := prefix, ""
+	for {
+		if r, err := be.Repository(ctx, repo); err == nil {
+			return &goModule{
+				Path:      modPath,
+				PathMajor: pathMajor,
+				Dir:       dir,
+				Repo:      r,
+			}, nil
+		}
+
+		i := strings.LastIndex(repo, "/")
+		if i < 0 {
+			return nil, proto.ErrRepoNotFound
+		}
+
+		dir = path.Join(repo[i+1:], dir)
+		repo = repo[:i]
+	}
+}
+
+// withGoProxyAccess finds the module of the request and makes sure the user
+// can read its repository.
+func withGoProxyAccess(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		logger := log.FromContext(ctx).WithPrefix("http.goproxy")
+		be := backend.FromContext(ctx)
+
+		modPath, err := module.UnescapePath(mux.Vars(r)["module"])
+		if err != nil {
+			renderNotFound(w, r)
+			return
+		}
+
+		user, err := authenticate(r)
+		if err != nil && r.Header.Get("Authorization") != "" {
+			logger.Debug("failed to authenticate", "err", err)
+			askCredentials(w, r)
+			renderUnauthorized(w, r)
+			return
+		}
+
+		if user == nil && !be.AllowKeyless(ctx) {
+			askCredentials(w, r)
+			renderUnauthorized(w, r)
+			return
+		}
+
+		ctx = proto.WithUserContext(ctx, user)
+		mod, err := findGoModule(ctx, modPath)
+		if err != nil || be.AccessLevelForUser(ctx, mod.Repo.Name(), user) < access.ReadOnlyAccess {
+			renderNotFound(w, r)
+			return
+		}
+
+		ctx = proto.WithRepositoryContext(ctx, mod.Repo)
+		ctx = context.WithValue(ctx, goModuleKey{}, mod)
+		goProxyCounter.WithLabelValues(mod.Repo.Name()).Inc()
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// tagPrefix returns the prefix of the module version tags.
+func (m *goModule) tagPrefix() string {
+	if m.Dir == "" {
+		return ""
+	}
+
+	return m.Dir + "/"
+}
+
+// versions returns the versions of the module sorted in ascending order.
+// Versions are the canonical semantic version tags matching the major
+// version of the module path.
+func (m *goModule) versions(r *git.Repository) ([]string, error) {
+	tags, err := r.Tags()
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []string
+	for _, tag := range tags {
+		v, ok := strings.CutPrefix(tag, m.tagPrefix())
+		if !ok || !semver.IsValid(v) || semver.Canonical(v) != v ||
+			module.IsPseudoVersion(v) || module.CheckPathMajor(v, m.PathMajor) != nil {
+			continue
+		}
+
+		versions = append(versions, v)
+	}
+
+	semver.Sort(versions)
+	return versions, nil
+}
+
+// major returns the major version of the module path.
+func (m *goModule) major() string {
+	if m.PathMajor == "" {
+		return "v0"
+	}
+
+	return strings.TrimPrefix(m.PathMajor, "/")
+}
+
+// resolve returns the commit of a module version. When query is true, the
+// version can also be a branch, tag, or commit, in which case the returned
+// version is the pseudo-version of its commit.
+func (m *goModule) resolve(r *git.Repository, version string, query bool) (*git.Commit, string, error) {
+	if semver.IsValid(version) && semver.Canonical(version) == version {
+		if err := module.CheckPathMajor(version, m.PathMajor); err != nil {
+			return nil, "", git.ErrRevisionNotExist
+		}
+
+		if module.IsPseudoVersion(version) {
+			rev, err := module.PseudoVersionRev(version)
+			if err != nil {
+				return nil, "", git.ErrRevisionNotExist
+			}
+
+			t, err := module.PseudoVersionTime(version)
+			if err != nil {
+				return nil, "", git.ErrRevisionNotExist
+			}
+
+			c, err := r.CatFileCommit(rev + "^{commit}")
+			if err != nil || !strings.HasPrefix(c.ID.String(), rev) || !c.Committer.When.UTC().Truncate(time.Second).Equal(t) {
+				return nil, "", git.ErrRevisionNotExist
+			}
+
+			return c, version, nil
+		}
+
+		c, err := r.CatFileCommit("refs/tags/" + m.tagPrefix() + version + "^{commit}")
+		if err != nil {
+			return nil, "", git.ErrRevisionNotExist
+		}
+
+		return c, version, nil
+	}
+
+	if !query || version == "" || strings.HasPrefix(version, "-") {
+		return nil, "", git.ErrRevisionNotExist
+	}
+
+	c, err := r.CatFileCommit(version + "^{commit}")
+	if err != nil {
+		return nil, "", git.ErrRevisionNotExist
+	}
+
+	v, err := m.pseudoVersion(r, c)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return c, v, nil
+}
+
+// pseudoVersion returns the version of a commit. That is the highest version
+// tagged on the commit, or a pseudo-version based on the highest version
+// reachable from it.
+func (m *goModule) pseudoVersion(r *git.Repository, c *git.Commit) (string, error) {
+	versions, err := m.versions(r)
+	if err != nil {
+		return "", err
+	}
+
+	id := c.ID.String()
+	var older string
+	for i := len(versions) - 1; i >= 0; i-- {
+		tc, err := r.CatFileCommit("refs/tags/" + m.tagPrefix() + versions[i] + "^{commit}")
+		if err != nil {
+			return "", err
+		}
+
+		if tc.ID.String() == id {
+			return versions[i], nil
+		}
+
+		if older != "" {
+			continue
+		}
+
+		ok, err := r.IsAncestor(tc.ID.String(), id)
+		if err != nil {
+			return "", err
+		}
+
+		if ok {
+			older = versions[i]
+		}
+	}
+
+	return module.PseudoVersion(m.major(), older, c.Committer.When, id[:12]), nil
+}
+
+// goModuleInfo is the JSON response of the .info and @latest endpoints.
+type goModuleInfo struct {
+	Version string
+	Time    time.Time
+}
+
+func renderGoModuleInfo(w http.ResponseWriter, version string, c *git.Commit) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(goModuleInfo{
+		Version: version,
+		Time:    c.Committer.When.UTC(),
+	}); err != nil {
+		log.Error("error encoding json", "err", err)
+	}
+}
+
+// goProxyRepo opens the repository of the module of the request.
+func goProxyRepo(w http.ResponseWriter, r *http.Request) (*goModule, *git.Repository, bool) {
+	ctx := r.Context()
+	logger := log.FromContext(ctx).WithPrefix("http.goproxy")
+	mod := goModuleFromContext(ctx)
+	if mod == nil {
+		renderNotFound(w, r)
+		return nil, nil, false
+	}
+
+	repo, err := mod.Repo.Open()
+	if err != nil {
+		logger.Error("failed to open repository", "repo", mod.Repo.Name(), "err", err)
+		renderInternalServerError(w, r)
+		return nil, nil, false
+	}
+
+	return mod, repo, true
+}
+
+// goProxyVersion resolves the version of the request.
+func goProxyVersion(w http.ResponseWriter, r *http.Request, mod *goModule, repo *git.Repository, query bool) (*git.Commit, string, bool) {
+	version, err := module.UnescapeVersion(mux.Vars(r)["version"])
+	if err != nil {
+		renderNotFound(w, r)
+		return nil, "", false
+	}
+
+	c, version, err := mod.resolve(repo, version, query)
+	if err != nil {
+		if errors.Is(err, git.ErrRevisionNotExist) {
+			renderNotFound(w, r)
+		} else {
+			log.FromContext(r.Context()).Error("failed to resolve module version", "module", mod.Path, "err", err)
+			renderInternalServerError(w, r)
+		}
+		return nil, "", false
+	}
+
+	return c, version, true
+}
+
+// GET /{module}/@v/list
+func getGoProxyList(w http.ResponseWriter, r *http.Request) {
+	mod, repo, ok := goProxyRepo(w, r)
+	if !ok {
+		return
+	}
+
+	versions, err := mod.versions(repo)
+	if err != nil {
+		log.FromContext(r.Context()).Error("failed to list module versions", "module", mod.Path, "err", err)
+		renderInternalServerError(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	for _, v := range versions {
+		fmt.Fprintln(w, v) //nolint: errcheck
+	}
+}
+
+// GET /{module}/@v/{version}.info
+func getGoProxyInfo(w http.ResponseWriter, r *http.Request) {
+	mod, repo, ok := goProxyRepo(w, r)
+	if !ok {
+		return
+	}
+
+	c, version, ok := goProxyVersion(w, r, mod, repo, true)
+	if !ok {
+		return
+	}
+
+	renderGoModuleInfo(w, version, c)
+}
+
+// GET /{module}/@latest
+func getGoProxyLatest(w http.ResponseWriter, r *http.Request) {
+	mod, repo, ok := goProxyRepo(w, r)
+	if !ok {
+		return
+	}
+
+	logger := log.FromContext(r.Context())
+	versions, err := mod.versions(repo)
+	if err != nil {
+		logger.Error("failed to list module versions", "module", mod.Path, "err", err)
+		renderInternalServerError(w, r)
+		return
+	}
+
+	// Prefer the highest release, then the highest pre-release, and fall
+	// back to the pseudo-version of HEAD.
+	var latest string
+	for _, v := range versions {
+		if latest == "" || semver.Prerelease(v) == "" || semver.Prerelease(latest) != "" {
+			latest = v
+		}
+	}
+
+	query := latest
+	if query == "" {
+		query = "HEAD"
+	}
+
+	c, version, err := mod.resolve(repo, query, true)
+	if err != nil {
+		if errors.Is(err, git.ErrRevisionNotExist) {
+			renderNotFound(w, r)
+		} else {
+			logger.Error("failed to resolve latest module version", "module", mod.Path, "err", err)
+			renderInternalServerError(w, r)
+		}
+		return
+	}
+
+	renderGoModuleInfo(w, version, c)
+}
+
+// GET /{module}/@v/{version}.mod
+func getGoProxyMod(w http.ResponseWriter, r *http.Request) {
+	mod, repo, ok := goProxyRepo(w, r)
+	if !ok {
+		return
+	}
+
+	c, _, ok := goProxyVersion(w, r, mod, repo, false)
+	if !ok {
+		return
+	}
+
+	tree, err := repo.LsTree(c.ID.String())
+	if err != nil {
+		log.FromContext(r.Context()).Error("failed to get tree", "module", mod.Path, "err", err)
+		renderInternalServerError(w, r)
+		return
+	}
+
+	// Modules without a go.mod file get a synthesized one, like the go
+	// command does.
+	data := []byte("module " + modfile.AutoQuote(mod.Path) + "\n")
+	if e, err := tree.TreeEntry(path.Join(mod.Dir, "go.mod")); err == nil && !e.IsTree() {
+		data, err = e.Contents()
+		if err != nil {
+			log.FromContext(r.Context()).Error("failed to read go.mod", "module", mod.Path, "err", err)
+			renderInternalServerError(w, r)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Write(data) //nolint: errcheck
+}
+
+// GET /{module}/@v/{version}.zip
+func getGoProxyZip(w http.ResponseWriter, r *http.Request) {
+	mod, repo, ok := goProxyRepo(w, r)
+	if !ok {
+		return
+	}
+
+	c, version, ok := goProxyVersion(w, r, mod, repo, false)
+	if !ok {
+		return
+	}
+
+	ctx := r.Context()
+	cfg := config.FromContext(ctx)
+	logger := log.FromContext(ctx).WithPrefix("http.goproxy")
+
+	// Zips are cached by module version and commit, a moved tag results in a
+	// new zip.
+	key := sha256.Sum256([]byte(mod.Path + "@" + version + "@" + c.ID.String()))
+	name := filepath.Join(cfg.DataPath, "cache", "goproxy", hex.EncodeToString(key[:])+".zip")
+	f, err := os.Open(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		if err := mod.createZip(repo, c, version, name); err != nil {
+			logger.Error("failed to create module zip", "module", mod.Path, "version", version, "err", err)
+			renderInternalServerError(w, r)
+			return
+		}
+
+		f, err = os.Open(name)
+	}
+	if err != nil {
+		logger.Error("failed to open module zip", "module", mod.Path, "version", version, "err", err)
+		renderInternalServerError(w, r)
+		return
+	}
+
+	defer f.Close() //nolint: errcheck
+	w.Header().Set("Content-Type", "application/zip")
+	http.ServeContent(w, r, "", c.Committer.When, f)
+}
+
+// createZip writes the module zip of a version to the file name.
+func (m *goModule) createZip(repo *git.Repository, c *git.Commit, version string, name string) error {
+	tree, err := repo.LsTree(c.ID.String())
+	if err != nil {
+		return err
+	}
+
+	if m.Dir != "" {
+		tree, err = tree.SubTree(m.Dir)
+		if err != nil {
+			return err
+		}
+	}
+
+	files, err := goModuleFiles(tree, "")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(name), os.ModePerm); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(name), "*.zip.tmp")
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(tmp.Name()) //nolint: errcheck
+	if err := modzip.Create(tmp, module.Version{Path: m.Path, Version: version}, files); err != nil {
+		tmp.Close() //nolint: errcheck
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), name)
+}
+
+// goModuleFiles returns the files of a tree, dir being the path of the tree
+// relative to the module root. Submodules are not part of a module.
+func goModuleFiles(t *git.Tree, dir string) ([]modzip.File, error) {
+	entries, err := t.Entries()
+	if err != nil {
+		return nil, err
+	}
+
+	var files []modzip.File
+	for _, e := range entries {
+		p := path.Join(dir, e.Name())
+		switch {
+		case e.IsCommit():
+			continue
+		case e.IsTree():
+			sub, err := t.SubTree(e.Name())
+			if err != nil {
+				return nil, err
+			}
+
+			subFiles, err := goModuleFiles(sub, p)
+			if err != nil {
+				return nil, err
+			}
+
+			files = append(files, subFiles...)
+		default:
+			files = append(files, goModuleFile{path: p, entry: e})
+		}
+	}
+
+	return files, nil
+}
+
+// goModuleFile is a module file backed by a Git tree entry.
+type goModuleFile struct {
+	path  string
+	entry *git.TreeEntry
+}
+
+var _ modzip.File = goModuleFile{}
+
+// Path implements modzip.File.
+func (f goModuleFile) Path() string {
+	return f.path
+}
+
+// Lstat implements modzip.File.
+func (f goModuleFile) Lstat() (fs.FileInfo, error) {
+	mode := fs.FileMode(0o644)
+	switch {
+	case f.entry.IsSymlink():
+		mode = fs.ModeSymlink | fs.ModePerm
+	case f.entry.IsExec():
+		mode = 0o755
+	}
+
+	return goModuleFileInfo{
+		name: path.Base(f.path),
+		size: f.entry.Size(),
+		mode: mode,
+	}, nil
+}
+
+// Open implements modzip.File.
+func (f goModuleFile) Open() (io.ReadCloser, error) {
+	data, err := f.entry.Contents()
+	if err != nil {
+		return nil, err
+	}
+
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+// goModuleFileInfo implements fs.FileInfo for module files.
+type goModuleFileInfo struct {
+	name string
+	size int64
+	mode fs.FileMode
+}
+
+func (fi goModuleFileInfo) Name() string       { return fi.name }
+func (fi goModuleFileInfo) Size() int64        { return fi.size }
+func (fi goModuleFileInfo) Mode() fs.FileMode  { return fi.mode }
+func (fi goModuleFileInfo) ModTime() time.Time { return time.Time{} }
+func (fi goModuleFileInfo) IsDir() bool        { return false }
+func (fi goModuleFileInfo) Sys() any           { return nil }
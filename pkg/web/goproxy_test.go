@@ -0,0 +1,163 @@
+package web
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/soft-serve/pkg/config"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/matryer/is"
+	"golang.org/x/mod/module"
+)
+
+// gitOutput runs git in dir and returns its trimmed output.
+func gitOutput(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	cmd.Env = append(cmd.Environ(),
+		"GIT_CONFIG_GLOBAL=/dev/null",
+		"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com",
+	)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v: %v: %s", args, err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func TestGoProxy(t *testing.T) {
+	is := is.New(t)
+	ctx, be, _ := newLFSTestContext(t)
+	cfg := config.FromContext(ctx)
+	cfg.HTTP.PublicURL = "https://git.example.com:23232"
+
+	_, err := be.CreateRepository(ctx, "mod", nil, proto.RepositoryOptions{})
+	is.NoErr(err)
+	_, err = be.CreateRepository(ctx, "secret", nil, proto.RepositoryOptions{Private: true})
+	is.NoErr(err)
+
+	work := t.TempDir()
+	writeFile := func(name, content string) {
+		t.Helper()
+		p := filepath.Join(work, name)
+		is.NoErr(os.MkdirAll(filepath.Dir(p), os.ModePerm))
+		is.NoErr(os.WriteFile(p, []byte(content), 0o644))
+	}
+	gitOutput(t, work, "init", "-q", "-b", "main")
+	writeFile("go.mod", "module git.example.com/mod\n")
+	writeFile("mod.go", "package mod\n")
+	writeFile("sub/go.mod", "module git.example.com/mod/sub\n")
+	writeFile("sub/sub.go", "package sub\n")
+	writeFile("nomod/nomod.go", "package nomod\n")
+	gitOutput(t, work, "add", ".")
+	gitOutput(t, work, "commit", "-q", "-m", "first")
+	gitOutput(t, work, "tag", "v1.0.0")
+	gitOutput(t, work, "tag", "sub/v0.1.0")
+	gitOutput(t, work, "tag", "nomod/v0.1.0")
+	gitOutput(t, work, "tag", "v2.0.0")
+	gitOutput(t, work, "tag", "not-a-version")
+	writeFile("util.go", "package mod\n")
+	gitOutput(t, work, "add", ".")
+	gitOutput(t, work, "commit", "-q", "-m", "second")
+	head := gitOutput(t, work, "rev-parse", "HEAD")
+	for _, repo := range []string{"mod", "secret"} {
+		gitOutput(t, filepath.Join(cfg.DataPath, "repos", repo+".git"), "fetch", "-q", work, "refs/heads/main:refs/heads/main", "refs/tags/*:refs/tags/*")
+	}
+
+	h := NewRouter(ctx)
+	get := func(p string) *httptest.ResponseRecorder {
+		t.Helper()
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequestWithContext(ctx, http.MethodGet, p, nil))
+		return w
+	}
+	info := func(p string) goModuleInfo {
+		t.Helper()
+		w := get(p)
+		is.Equal(w.Code, http.StatusOK)
+		var i goModuleInfo
+		is.NoErr(json.NewDecoder(w.Body).Decode(&i))
+		return i
+	}
+
+	// Only canonical versions of the module's major version are listed.
+	w := get("/git.example.com/mod/@v/list")
+	is.Equal(w.Code, http.StatusOK)
+	is.Equal(w.Body.String(), "v1.0.0\n")
+	is.Equal(get("/git.example.com/mod/sub/@v/list").Body.String(), "v0.1.0\n")
+	is.Equal(get("/git.example.com/mod/v2/@v/list").Body.String(), "v2.0.0\n")
+
+	is.Equal(info("/git.example.com/mod/@v/v1.0.0.info").Version, "v1.0.0")
+	is.Equal(info("/git.example.com/mod/@latest").Version, "v1.0.0")
+
+	// Branches resolve to a pseudo-version of their commit.
+	pseudo := info("/git.example.com/mod/@v/main.info").Version
+	is.True(module.IsPseudoVersion(pseudo))
+	is.True(strings.HasPrefix(pseudo, "v1.0.1-0."))
+	is.True(strings.HasSuffix(pseudo, head[:12]))
+	is.Equal(info("/git.example.com/mod/@v/"+pseudo+".info").Version, pseudo)
+
+	w = get("/git.example.com/mod/@v/v1.0.0.mod")
+	is.Equal(w.Code, http.StatusOK)
+	is.Equal(w.Body.String(), "module git.example.com/mod\n")
+
+	// Modules without a go.mod file get a synthesized one.
+	is.Equal(get("/git.example.com/mod/nomod/@v/v0.1.0.mod").Body.String(), "module git.example.com/mod/nomod\n")
+
+	zipFiles := func(p string) []string {
+		t.Helper()
+		w := get(p)
+		is.Equal(w.Code, http.StatusOK)
+		zr, err := zip.NewReader(bytes.NewReader(w.Body.Bytes()), int64(w.Body.Len()))
+		is.NoErr(err)
+		var names []string
+		for _, f := range zr.File {
+			names = append(names, f.Name)
+		}
+		sort.Strings(names)
+		return names
+	}
+
+	// Nested modules are not part of the zip.
+	is.Equal(zipFiles("/git.example.com/mod/@v/v1.0.0.zip"), []string{
+		"git.example.com/mod@v1.0.0/go.mod",
+		"git.example.com/mod@v1.0.0/mod.go",
+		"git.example.com/mod@v1.0.0/nomod/nomod.go",
+	})
+	is.Equal(zipFiles("/git.example.com/mod/sub/@v/v0.1.0.zip"), []string{
+		"git.example.com/mod/sub@v0.1.0/go.mod",
+		"git.example.com/mod/sub@v0.1.0/sub.go",
+	})
+	is.Equal(zipFiles("/git.example.com/mod/@v/"+pseudo+".zip"), []string{
+		"git.example.com/mod@" + pseudo + "/go.mod",
+		"git.example.com/mod@" + pseudo + "/mod.go",
+		"git.example.com/mod@" + pseudo + "/nomod/nomod.go",
+		"git.example.com/mod@" + pseudo + "/util.go",
+	})
+
+	// Generated zips are cached in the data path.
+	cached, err := os.ReadDir(filepath.Join(cfg.DataPath, "cache", "goproxy"))
+	is.NoErr(err)
+	is.Equal(len(cached), 3)
+
+	// Only tags and pseudo-versions can be downloaded.
+	is.Equal(get("/git.example.com/mod/@v/main.zip").Code, http.StatusNotFound)
+	is.Equal(get("/git.example.com/mod/@v/v1.1.0.info").Code, http.StatusNotFound)
+	is.Equal(get("/git.example.com/mod/@v/v2.0.0.info").Code, http.StatusNotFound)
+	is.Equal(get("/git.example.com/mod/@v/v1.0.1-0.20000101000000-"+head[:12]+".info").Code, http.StatusNotFound)
+
+	// Unknown and private modules are not found.
+	is.Equal(get("/git.example.com/nope/@v/list").Code, http.StatusNotFound)
+	is.Equal(get("/other.example.com/mod/@v/list").Code, http.StatusNotFound)
+	is.Equal(get("/git.example.com/secret/@v/list").Code, http.StatusNotFound)
+}
@@ -18,6 +18,18 @@ func NewRouter(ctx context.Context) http.Handler {
 	// Health routes
 	HealthController(ctx, router)
 
+	// API routes
+	// These must come before the Git routes which match any path.
+	APIController(ctx, router)
+
+	// Go module proxy routes
+	// These must come before the Git routes which match any path.
+	GoProxyController(ctx, router)
+
+	// Web UI routes
+	// These must come before the Git routes which match any path.
+	UIController(ctx, router)
+
 	// Git routes
 	GitController(ctx, router)
 
@@ -0,0 +1,254 @@
+package web
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"charm.land/log/v2"
+	"github.com/alecthomas/chroma/v2"
+	chromahtml "github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/styles"
+	"github.com/charmbracelet/soft-serve/pkg/access"
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/charmbracelet/soft-serve/pkg/config"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/utils"
+	"github.com/gorilla/mux"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+//go:embed templates/*.html
+var uiTemplatesFS embed.FS
+
+// uiTemplates are the web UI page templates. Every page is rendered inside
+// the layout template.
+var uiTemplates = func() map[string]*template.Template {
+	tpls := make(map[string]*template.Template)
+	for _, page := range []string{"index", "repo", "tree", "blob", "commits", "commit", "error"} {
+		tpls[page] = template.Must(template.ParseFS(uiTemplatesFS, "templates/layout.html", "templates/"+page+".html"))
+	}
+	return tpls
+}()
+
+// uiMarkdown renders READMEs. Raw HTML and dangerous links are left out since
+// goldmark is not configured to allow unsafe content.
+var uiMarkdown = goldmark.New(goldmark.WithExtensions(extension.GFM))
+
+// UIController registers the read-only web UI routes for the web server.
+//
+// Repository pages live under /{repo}/-/ so they can't be mistaken for Git
+// requests. The repository summary page is served at /{repo} only for
+// existing repositories, everything else falls through to the Git routes.
+// It must be registered before the Git routes, which match any path.
+func UIController(_ context.Context, r *mux.Router) {
+	r.Handle("/", withUIAccess(http.HandlerFunc(getUIIndex))).Methods(http.MethodGet)
+
+	r.Handle("/{repo:.+}/-/tree", withUIAccess(http.HandlerFunc(getUITree))).Methods(http.MethodGet)
+	r.Handle("/{repo:.+}/-/tree/{rest:.*}", withUIAccess(http.HandlerFunc(getUITree))).Methods(http.MethodGet)
+	r.Handle("/{repo:.+}/-/blob/{rest:.+}", withUIAccess(http.HandlerFunc(getUIBlob))).Methods(http.MethodGet)
+	r.Handle("/{repo:.+}/-/raw/{rest:.+}", withUIAccess(http.HandlerFunc(getUIRaw))).Methods(http.MethodGet)
+	r.Handle("/{repo:.+}/-/commits", withUIAccess(http.HandlerFunc(getUICommits))).Methods(http.MethodGet)
+	r.Handle("/{repo:.+}/-/commits/{rest:.+}", withUIAccess(http.HandlerFunc(getUICommits))).Methods(http.MethodGet)
+	r.Handle("/{repo:.+}/-/commit/{sha:[0-9a-fA-F]{4,64}}", withUIAccess(http.HandlerFunc(getUICommit))).Methods(http.MethodGet)
+
+	r.Handle("/{repo:.+}", withUIAccess(http.HandlerFunc(getUIRepo))).Methods(http.MethodGet).MatcherFunc(isUIRepoRequest)
+}
+
+// isUIRepoRequest reports whether the request is for the summary page of an
+// existing repository rather than a Git or go-get request.
+func isUIRepoRequest(r *http.Request, _ *mux.RouteMatch) bool {
+	if r.URL.Query().Get("go-get") == "1" {
+		return false
+	}
+
+	ctx := r.Context()
+	be := backend.FromContext(ctx)
+	if be == nil {
+		return false
+	}
+
+	_, err := be.Repository(ctx, utils.SanitizeRepo(r.URL.Path))
+	return err == nil
+}
+
+// withUIAccess authenticates web UI requests and makes sure the caller can
+// read the requested repository, if any.
+//
+// Browsers authenticate with basic auth, using either the user's password or
+// an access token. Anonymous access is allowed if the server allows keyless
+// access.
+func withUIAccess(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		logger := log.FromContext(ctx).WithPrefix("http.ui")
+		be := backend.FromContext(ctx)
+
+		name, isRepo := mux.Vars(r)["repo"]
+		name = utils.SanitizeRepo(name)
+		var repo proto.Repository
+		if isRepo {
+			repo, _ = be.Repository(ctx, name)
+			if repo != nil {
+				ctx = proto.WithRepositoryContext(ctx, repo)
+				r = r.WithContext(ctx)
+			}
+		}
+
+		user, err := authenticate(r)
+		if err != nil && r.Header.Get("Authorization") != "" {
+			logger.Debug("failed to authenticate", "err", err)
+			askCredentials(w, r)
+			renderUIError(w, r, http.StatusUnauthorized, "Invalid credentials.")
+			return
+		}
+
+		if user == nil && !be.AllowKeyless(ctx) {
+			askCredentials(w, r)
+			renderUIError(w, r, http.StatusUnauthorized, "Sign in to browse repositories.")
+			return
+		}
+
+		ctx = proto.WithUserContext(ctx, user)
+		r = r.WithContext(ctx)
+
+		if isRepo {
+			if repo == nil || be.AccessLevelForUser(ctx, name, user) < access.ReadOnlyAccess {
+				renderUIError(w, r, http.StatusNotFound, proto.ErrRepoNotFound.Error())
+				return
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// uiPage holds the fields shared by all web UI pages.
+type uiPage struct {
+	ServerName string
+	Title      string
+}
+
+func newUIPage(ctx context.Context, title string) uiPage {
+	cfg := config.FromContext(ctx)
+	return uiPage{
+		ServerName: cfg.Name,
+		Title:      title,
+	}
+}
+
+// uiErrorPage is the data of the error page.
+type uiErrorPage struct {
+	uiPage
+	Message string
+}
+
+// renderUI renders a web UI page.
+func renderUI(w http.ResponseWriter, r *http.Request, page string, status int, data interface{}) {
+	// Render to a buffer first so a failing template doesn't leave a half
+	// written page behind.
+	var buf bytes.Buffer
+	if err := uiTemplates[page].ExecuteTemplate(&buf, "layout", data); err != nil {
+		log.FromContext(r.Context()).Error("failed to render page", "page", page, "err", err)
+		renderInternalServerError(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	// Pages only ever need their own inline styles and images from READMEs.
+	w.Header().Set("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'; img-src * data:")
+	w.WriteHeader(status)
+	_, _ = w.Write(buf.Bytes())
+}
+
+// renderUIError renders the web UI error page.
+func renderUIError(w http.ResponseWriter, r *http.Request, status int, message string) {
+	renderUI(w, r, "error", status, uiErrorPage{
+		uiPage:  newUIPage(r.Context(), http.StatusText(status)),
+		Message: message,
+	})
+}
+
+// renderUIErr renders the web UI error page matching err.
+func renderUIErr(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, errUINotFound) {
+		renderUIError(w, r, http.StatusNotFound, err.Error())
+		return
+	}
+
+	log.FromContext(r.Context()).Error("failed to render page", "path", r.URL.Path, "err", err)
+	renderUIError(w, r, http.StatusInternalServerError, "Something went wrong.")
+}
+
+// errUINotFound is wrapped by errors about things that don't exist.
+var errUINotFound = errors.New("not found")
+
+// uiURL returns the escaped URL path made of the given path elements.
+// Elements may contain slashes, and empty elements are skipped.
+func uiURL(elems ...string) string {
+	parts := make([]string, 0, len(elems))
+	for _, elem := range elems {
+		if elem = strings.Trim(elem, "/"); elem != "" {
+			parts = append(parts, elem)
+		}
+	}
+
+	p := "/" + strings.Join(parts, "/")
+	return (&url.URL{Path: p}).EscapedPath()
+}
+
+// uiHighlight returns the syntax highlighted HTML of a file's contents. The
+// language is guessed from the file name, or the contents if that fails.
+func uiHighlight(name string, contents string) (template.HTML, error) {
+	lexer := lexers.Match(name)
+	if lexer == nil {
+		lexer = lexers.Analyse(contents)
+	}
+	if lexer == nil {
+		lexer = lexers.Fallback
+	}
+
+	it, err := chroma.Coalesce(lexer).Tokenise(nil, contents)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	formatter := chromahtml.New(chromahtml.WithLineNumbers(true), chromahtml.TabWidth(4))
+	if err := formatter.Format(&buf, styles.Get("github"), it); err != nil {
+		return "", err
+	}
+
+	// Chroma escapes the contents.
+	return template.HTML(buf.String()), nil //nolint:gosec
+}
+
+// uiRenderReadme renders a README as HTML. Markdown files are rendered, and
+// other files are shown as is.
+func uiRenderReadme(path string, contents string) (template.HTML, error) {
+	switch strings.ToLower(path[strings.LastIndex(path, ".")+1:]) {
+	case "md", "markdown", "mkd", "mdown":
+		var buf bytes.Buffer
+		if err := uiMarkdown.Convert([]byte(contents), &buf); err != nil {
+			return "", err
+		}
+
+		// goldmark leaves raw HTML out and escapes everything else.
+		return template.HTML(buf.String()), nil //nolint:gosec
+	default:
+		return template.HTML("<pre>" + template.HTMLEscapeString(contents) + "</pre>"), nil //nolint:gosec
+	}
+}
+
+func uiNotFound(what string) error {
+	return fmt.Errorf("%s %w", what, errUINotFound)
+}
@@ -0,0 +1,641 @@
+package web
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	gitm "github.com/aymanbagabas/git-module"
+	"github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/access"
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/charmbracelet/soft-serve/pkg/config"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/dustin/go-humanize"
+	"github.com/gorilla/mux"
+)
+
+// uiCommitsPerPage is the number of commits shown per page of the log.
+const uiCommitsPerPage = 30
+
+// uiRepo is a repository as shown in the web UI.
+type uiRepo struct {
+	Name        string
+	Description string
+	Private     bool
+	Updated     string
+	URL         string
+	TreeURL     string
+	CommitsURL  string
+	SSHURL      string
+	HTTPURL     string
+}
+
+func newUIRepo(cfg *config.Config, repo proto.Repository) uiRepo {
+	return uiRepo{
+		Name:        repo.Name(),
+		Description: repo.Description(),
+		Private:     repo.IsPrivate(),
+		Updated:     humanize.Time(repo.UpdatedAt()),
+		URL:         uiURL(repo.Name()),
+		TreeURL:     uiURL(repo.Name(), "-", "tree"),
+		CommitsURL:  uiURL(repo.Name(), "-", "commits"),
+		SSHURL:      fmt.Sprintf("%s/%s.git", cfg.SSH.PublicURL, repo.Name()),
+		HTTPURL:     fmt.Sprintf("%s/%s.git", cfg.HTTP.PublicURL, repo.Name()),
+	}
+}
+
+// uiRepoPage holds the fields shared by all repository pages.
+type uiRepoPage struct {
+	uiPage
+	Repo uiRepo
+	// Tab is the selected repository tab, one of readme, files or commits.
+	Tab string
+}
+
+func newUIRepoPage(r *http.Request, repo proto.Repository, tab string, title string) uiRepoPage {
+	ctx := r.Context()
+	if title == "" {
+		title = repo.Name()
+	} else {
+		title = title + " · " + repo.Name()
+	}
+
+	return uiRepoPage{
+		uiPage: newUIPage(ctx, title),
+		Repo:   newUIRepo(config.FromContext(ctx), repo),
+		Tab:    tab,
+	}
+}
+
+// uiLink is a named link.
+type uiLink struct {
+	Name string
+	URL  string
+}
+
+// uiRefPage holds the fields of repository pages showing a reference.
+type uiRefPage struct {
+	uiRepoPage
+	Ref string
+	// Refs links to the same page for the other branches and tags.
+	Refs []uiLink
+}
+
+// uiRefLinks returns links to the given page of the repository for every
+// branch and tag.
+func uiRefLinks(repo proto.Repository, rr *git.Repository, page string) []uiLink {
+	refs, err := rr.References()
+	if err != nil {
+		return nil
+	}
+
+	links := make([]uiLink, 0, len(refs))
+	for _, ref := range refs {
+		if !ref.IsBranch() && !ref.IsTag() {
+			continue
+		}
+
+		name := ref.Name().Short()
+		links = append(links, uiLink{
+			Name: name,
+			URL:  uiURL(repo.Name(), "-", page, name),
+		})
+	}
+
+	return links
+}
+
+// uiResolveRef splits the rest of a path into a reference and a path within
+// the repository.
+//
+// Branch and tag names may contain slashes, so the longest branch or tag
+// that prefixes rest wins. Otherwise, rest must start with a commit hash. An
+// empty rest resolves to HEAD.
+func uiResolveRef(rr *git.Repository, rest string) (*git.Reference, string, error) {
+	rest = strings.Trim(rest, "/")
+	if rest == "" {
+		head, err := rr.HEAD()
+		if err != nil {
+			return nil, "", uiNotFound("reference")
+		}
+
+		return head, "", nil
+	}
+
+	refs, err := rr.References()
+	if err != nil {
+		return nil, "", err
+	}
+
+	var match *git.Reference
+	for _, ref := range refs {
+		if !ref.IsBranch() && !ref.IsTag() {
+			continue
+		}
+
+		name := ref.Name().Short()
+		if rest != name && !strings.HasPrefix(rest, name+"/") {
+			continue
+		}
+
+		if match == nil || len(name) > len(match.Name().Short()) {
+			match = ref
+		}
+	}
+
+	if match != nil {
+		return match, strings.TrimPrefix(strings.TrimPrefix(rest, match.Name().Short()), "/"), nil
+	}
+
+	rev, p, _ := strings.Cut(rest, "/")
+	if !isUIHash(rev) {
+		return nil, "", uiNotFound("reference")
+	}
+
+	commit, err := rr.CommitByRevision(rev)
+	if err != nil {
+		return nil, "", uiNotFound("reference")
+	}
+
+	id := commit.ID.String()
+	return &git.Reference{
+		Reference: &gitm.Reference{
+			ID:      id,
+			Refspec: id,
+		},
+	}, p, nil
+}
+
+// isUIHash reports whether s looks like an abbreviated or full commit hash.
+func isUIHash(s string) bool {
+	if len(s) < 4 || len(s) > 64 {
+		return false
+	}
+
+	for _, c := range s {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') && (c < 'A' || c > 'F') {
+			return false
+		}
+	}
+
+	return true
+}
+
+// uiCrumbs returns the breadcrumb links of a path in a repository. The last
+// element links to page, all others link to the tree.
+func uiCrumbs(repo proto.Repository, ref string, p string, page string) []uiLink {
+	crumbs := []uiLink{{Name: repo.Name(), URL: uiURL(repo.Name(), "-", "tree", ref)}}
+	if p == "" {
+		return crumbs
+	}
+
+	elems := strings.Split(p, "/")
+	for i, elem := range elems {
+		pg := "tree"
+		if i == len(elems)-1 {
+			pg = page
+		}
+
+		crumbs = append(crumbs, uiLink{
+			Name: elem,
+			URL:  uiURL(repo.Name(), "-", pg, ref, strings.Join(elems[:i+1], "/")),
+		})
+	}
+
+	return crumbs
+}
+
+// uiIndexPage is the data of the repository list page.
+type uiIndexPage struct {
+	uiPage
+	Repos []uiRepo
+}
+
+// GET /
+func getUIIndex(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	cfg := config.FromContext(ctx)
+	be := backend.FromContext(ctx)
+	user := proto.UserFromContext(ctx)
+
+	repos, err := be.Repositories(ctx)
+	if err != nil {
+		renderUIErr(w, r, err)
+		return
+	}
+
+	page := uiIndexPage{uiPage: newUIPage(ctx, "")}
+	for _, repo := range repos {
+		// Like the TUI, the list leaves hidden repositories out.
+		if repo.IsHidden() || be.AccessLevelForUser(ctx, repo.Name(), user) < access.ReadOnlyAccess {
+			continue
+		}
+
+		page.Repos = append(page.Repos, newUIRepo(cfg, repo))
+	}
+
+	renderUI(w, r, "index", http.StatusOK, page)
+}
+
+// uiSummaryPage is the data of the repository summary page.
+type uiSummaryPage struct {
+	uiRepoPage
+	Empty      bool
+	Readme     template.HTML
+	ReadmePath string
+}
+
+// GET /{repo}
+func getUIRepo(w http.ResponseWriter, r *http.Request) {
+	repo := proto.RepositoryFromContext(r.Context())
+	rr, err := repo.Open()
+	if err != nil {
+		renderUIErr(w, r, err)
+		return
+	}
+
+	page := uiSummaryPage{uiRepoPage: newUIRepoPage(r, repo, "readme", "")}
+	head, err := rr.HEAD()
+	if err != nil {
+		page.Empty = true
+		renderUI(w, r, "repo", http.StatusOK, page)
+		return
+	}
+
+	if readme, p, err := backend.Readme(repo, head); err == nil && readme != "" {
+		page.Readme, err = uiRenderReadme(p, readme)
+		if err != nil {
+			renderUIErr(w, r, err)
+			return
+		}
+		page.ReadmePath = p
+	}
+
+	renderUI(w, r, "repo", http.StatusOK, page)
+}
+
+// uiTreeEntry is a tree entry as shown in the web UI.
+type uiTreeEntry struct {
+	Name  string
+	Mode  string
+	Size  string
+	URL   string
+	IsDir bool
+}
+
+// uiTreePage is the data of the file tree page.
+type uiTreePage struct {
+	uiRefPage
+	Crumbs  []uiLink
+	Entries []uiTreeEntry
+}
+
+// GET /{repo}/-/tree/{ref}/{path}
+func getUITree(w http.ResponseWriter, r *http.Request) {
+	repo := proto.RepositoryFromContext(r.Context())
+	rr, err := repo.Open()
+	if err != nil {
+		renderUIErr(w, r, err)
+		return
+	}
+
+	ref, p, err := uiResolveRef(rr, mux.Vars(r)["rest"])
+	if err != nil {
+		renderUIErr(w, r, err)
+		return
+	}
+
+	refName := ref.Name().Short()
+	tree, err := rr.TreePath(ref, p)
+	if err != nil {
+		// Send file paths to the blob page.
+		if root, err := rr.Tree(ref); err == nil {
+			if te, err := root.TreeEntry(p); err == nil && te.IsBlob() {
+				http.Redirect(w, r, uiURL(repo.Name(), "-", "blob", refName, p), http.StatusFound)
+				return
+			}
+		}
+
+		renderUIErr(w, r, uiNotFound("directory"))
+		return
+	}
+
+	ents, err := tree.Entries()
+	if err != nil {
+		renderUIErr(w, r, err)
+		return
+	}
+
+	ents.Sort()
+	page := uiTreePage{
+		uiRefPage: uiRefPage{
+			uiRepoPage: newUIRepoPage(r, repo, "files", p),
+			Ref:        refName,
+			Refs:       uiRefLinks(repo, rr, "tree"),
+		},
+		Crumbs:  uiCrumbs(repo, refName, p, "tree"),
+		Entries: make([]uiTreeEntry, len(ents)),
+	}
+	for i, e := range ents {
+		entry := uiTreeEntry{
+			Name:  e.Name(),
+			Mode:  e.Mode().String(),
+			Size:  "-",
+			IsDir: e.IsTree(),
+		}
+
+		switch {
+		case e.IsTree():
+			entry.URL = uiURL(repo.Name(), "-", "tree", refName, p, e.Name())
+		case e.IsBlob():
+			entry.Size = humanize.Bytes(uint64(e.Size())) //nolint:gosec
+			entry.URL = uiURL(repo.Name(), "-", "blob", refName, p, e.Name())
+		}
+
+		page.Entries[i] = entry
+	}
+
+	renderUI(w, r, "tree", http.StatusOK, page)
+}
+
+// uiBlob returns the tree entry of the file named by the request path.
+func uiBlob(r *http.Request, rr *git.Repository) (*git.Reference, string, *git.TreeEntry, error) {
+	ref, p, err := uiResolveRef(rr, mux.Vars(r)["rest"])
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	tree, err := rr.Tree(ref)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	te, err := tree.TreeEntry(p)
+	if err != nil || !te.IsBlob() {
+		return nil, "", nil, uiNotFound("file")
+	}
+
+	return ref, p, te, nil
+}
+
+// uiBlobPage is the data of the file page.
+type uiBlobPage struct {
+	uiRefPage
+	Crumbs  []uiLink
+	Size    string
+	RawURL  string
+	Binary  bool
+	Content template.HTML
+}
+
+// GET /{repo}/-/blob/{ref}/{path}
+func getUIBlob(w http.ResponseWriter, r *http.Request) {
+	repo := proto.RepositoryFromContext(r.Context())
+	rr, err := repo.Open()
+	if err != nil {
+		renderUIErr(w, r, err)
+		return
+	}
+
+	ref, p, te, err := uiBlob(r, rr)
+	if err != nil {
+		renderUIErr(w, r, err)
+		return
+	}
+
+	refName := ref.Name().Short()
+	page := uiBlobPage{
+		uiRefPage: uiRefPage{
+			uiRepoPage: newUIRepoPage(r, repo, "files", p),
+			Ref:        refName,
+			Refs:       uiRefLinks(repo, rr, "blob"),
+		},
+		Crumbs: uiCrumbs(repo, refName, p, "blob"),
+		Size:   humanize.Bytes(uint64(te.Size())), //nolint:gosec
+		RawURL: uiURL(repo.Name(), "-", "raw", refName, p),
+	}
+
+	page.Binary, _ = te.File().IsBinary()
+	if !page.Binary {
+		bts, err := te.Contents()
+		if err != nil {
+			renderUIErr(w, r, err)
+			return
+		}
+
+		page.Content, err = uiHighlight(p, string(bts))
+		if err != nil {
+			renderUIErr(w, r, err)
+			return
+		}
+	}
+
+	renderUI(w, r, "blob", http.StatusOK, page)
+}
+
+// GET /{repo}/-/raw/{ref}/{path}
+func getUIRaw(w http.ResponseWriter, r *http.Request) {
+	repo := proto.RepositoryFromContext(r.Context())
+	rr, err := repo.Open()
+	if err != nil {
+		renderUIErr(w, r, err)
+		return
+	}
+
+	_, _, te, err := uiBlob(r, rr)
+	if err != nil {
+		renderUIErr(w, r, err)
+		return
+	}
+
+	bts, err := te.Contents()
+	if err != nil {
+		renderUIErr(w, r, err)
+		return
+	}
+
+	// Never let browsers render repository contents as HTML.
+	contentType := "text/plain; charset=utf-8"
+	if isBin, _ := te.File().IsBinary(); isBin {
+		contentType = "application/octet-stream"
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Content-Security-Policy", "default-src 'none'; sandbox")
+	w.Header().Set("Content-Length", strconv.Itoa(len(bts)))
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(bts)
+}
+
+// uiCommit is a commit as shown in the web UI.
+type uiCommit struct {
+	ID      string
+	ShortID string
+	Summary string
+	Author  string
+	When    string
+	URL     string
+}
+
+func newUICommit(repo proto.Repository, c *git.Commit) uiCommit {
+	id := c.ID.String()
+	return uiCommit{
+		ID:      id,
+		ShortID: id[:7],
+		Summary: c.Summary(),
+		Author:  c.Author.Name,
+		When:    humanize.Time(c.Committer.When),
+		URL:     uiURL(repo.Name(), "-", "commit", id),
+	}
+}
+
+// uiCommitsPage is the data of the commit log page.
+type uiCommitsPage struct {
+	uiRefPage
+	Commits []uiCommit
+	PrevURL string
+	NextURL string
+}
+
+// GET /{repo}/-/commits/{ref}
+func getUICommits(w http.ResponseWriter, r *http.Request) {
+	repo := proto.RepositoryFromContext(r.Context())
+	rr, err := repo.Open()
+	if err != nil {
+		renderUIErr(w, r, err)
+		return
+	}
+
+	ref, _, err := uiResolveRef(rr, mux.Vars(r)["rest"])
+	if err != nil {
+		renderUIErr(w, r, err)
+		return
+	}
+
+	pageNum := 1
+	if s := r.URL.Query().Get("page"); s != "" {
+		pageNum, err = strconv.Atoi(s)
+		if err != nil || pageNum < 1 {
+			pageNum = 1
+		}
+	}
+
+	total, err := rr.CountCommits(ref)
+	if err != nil {
+		renderUIErr(w, r, err)
+		return
+	}
+
+	commits, err := rr.CommitsByPage(ref, pageNum, uiCommitsPerPage)
+	if err != nil {
+		renderUIErr(w, r, err)
+		return
+	}
+
+	refName := ref.Name().Short()
+	page := uiCommitsPage{
+		uiRefPage: uiRefPage{
+			uiRepoPage: newUIRepoPage(r, repo, "commits", "Commits"),
+			Ref:        refName,
+			Refs:       uiRefLinks(repo, rr, "commits"),
+		},
+		Commits: make([]uiCommit, len(commits)),
+	}
+	for i, c := range commits {
+		page.Commits[i] = newUICommit(repo, c)
+	}
+
+	pageURL := uiURL(repo.Name(), "-", "commits", refName)
+	if pageNum > 1 {
+		page.PrevURL = fmt.Sprintf("%s?page=%d", pageURL, pageNum-1)
+	}
+	if int64(pageNum*uiCommitsPerPage) < total {
+		page.NextURL = fmt.Sprintf("%s?page=%d", pageURL, pageNum+1)
+	}
+
+	renderUI(w, r, "commits", http.StatusOK, page)
+}
+
+// uiDiffFile is a file changed by a commit as shown in the web UI.
+type uiDiffFile struct {
+	Name      string
+	Additions int
+	Deletions int
+}
+
+// uiCommitPage is the data of the commit page.
+type uiCommitPage struct {
+	uiRepoPage
+	Commit  uiCommit
+	Message string
+	Parents []uiCommit
+	Files   []uiDiffFile
+	Patch   template.HTML
+}
+
+// GET /{repo}/-/commit/{sha}
+func getUICommit(w http.ResponseWriter, r *http.Request) {
+	repo := proto.RepositoryFromContext(r.Context())
+	rr, err := repo.Open()
+	if err != nil {
+		renderUIErr(w, r, err)
+		return
+	}
+
+	commit, err := rr.CommitByRevision(mux.Vars(r)["sha"])
+	if err != nil {
+		renderUIErr(w, r, uiNotFound("commit"))
+		return
+	}
+
+	diff, err := rr.Diff(commit)
+	if err != nil {
+		renderUIErr(w, r, err)
+		return
+	}
+
+	c := newUICommit(repo, commit)
+	page := uiCommitPage{
+		uiRepoPage: newUIRepoPage(r, repo, "commits", c.ShortID),
+		Commit:     c,
+		Message:    commit.Message,
+	}
+
+	for i := 0; i < commit.ParentsCount(); i++ {
+		id, err := commit.ParentID(i)
+		if err != nil {
+			break
+		}
+
+		page.Parents = append(page.Parents, uiCommit{
+			ID:  id.String(),
+			URL: uiURL(repo.Name(), "-", "commit", id.String()),
+		})
+	}
+
+	for _, f := range diff.Files {
+		name := f.Name
+		if f.IsRenamed() {
+			name = f.OldName() + " → " + f.Name
+		}
+
+		page.Files = append(page.Files, uiDiffFile{
+			Name:      name,
+			Additions: f.NumAdditions(),
+			Deletions: f.NumDeletions(),
+		})
+	}
+
+	page.Patch, err = uiHighlight(path.Join(c.ShortID+".diff"), diff.Patch())
+	if err != nil {
+		renderUIErr(w, r, err)
+		return
+	}
+
+	renderUI(w, r, "commit", http.StatusOK, page)
+}
@@ -92,4 +92,17 @@ type Commit struct {
 	Committer Author `json:"committer" url:"committer"`
 	// Timestamp is the commit timestamp.
 	Timestamp time.Time `json:"timestamp" url:"timestamp"`
+	// Verification is the verification of the commit signature.
+	Verification Verification `json:"verification" url:"verification"`
+}
+
+// Verification is the verification of a commit signature.
+type Verification struct {
+	// Status is one of "verified", "unverified", "unknown_key" or "unsigned".
+	Status string `json:"status" url:"status"`
+	// Signer is the username of the user who owns the signing key.
+	Signer string `json:"signer,omitempty" url:"signer,omitempty"`
+	// Fingerprint is the SHA256 fingerprint of the SSH key that signed the
+	// commit.
+	Fingerprint string `json:"fingerprint,omitempty" url:"fingerprint,omitempty"`
 }
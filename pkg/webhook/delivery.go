@@ -0,0 +1,156 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/db/models"
+	"github.com/charmbracelet/soft-serve/pkg/store"
+)
+
+// DeliveryStatus is the status of a webhook delivery.
+type DeliveryStatus int
+
+const (
+	// DeliveryStatusPending is a delivery that is waiting to be sent or
+	// retried.
+	DeliveryStatusPending DeliveryStatus = iota
+	// DeliveryStatusFailed is a delivery that exhausted all its attempts.
+	DeliveryStatusFailed
+	// DeliveryStatusSucceeded is a delivery that got a 2xx response.
+	DeliveryStatusSucceeded
+)
+
+var deliveryStatusStrings = map[DeliveryStatus]string{
+	DeliveryStatusPending:   "pending",
+	DeliveryStatusFailed:    "failed",
+	DeliveryStatusSucceeded: "succeeded",
+}
+
+// String returns the string representation of the delivery status.
+func (s DeliveryStatus) String() string {
+	return deliveryStatusStrings[s]
+}
+
+const (
+	// MaxDeliveryAttempts is the number of times a delivery is attempted
+	// before it is marked as failed.
+	MaxDeliveryAttempts = 5
+
+	// initialDeliveryBackoff is the delay before the first retry.
+	initialDeliveryBackoff = 30 * time.Second
+
+	// maxDeliveryBackoff caps the delay between two attempts.
+	maxDeliveryBackoff = time.Hour
+)
+
+// DeliveryBackoff returns how long to wait before retrying a delivery that
+// has failed the given number of attempts. The delay doubles with every
+// attempt, starting at 30 seconds and capped at one hour.
+func DeliveryBackoff(attempts int) time.Duration {
+	if attempts < 1 {
+		return 0
+	}
+
+	backoff := initialDeliveryBackoff
+	for i := 1; i < attempts; i++ {
+		backoff *= 2
+		if backoff >= maxDeliveryBackoff {
+			return maxDeliveryBackoff
+		}
+	}
+
+	return backoff
+}
+
+// Delivery is a webhook delivery.
+type Delivery struct {
+	models.WebhookDelivery
+	Event  Event
+	Status DeliveryStatus
+}
+
+// NewDelivery returns a Delivery from its database model.
+func NewDelivery(d models.WebhookDelivery) Delivery {
+	return Delivery{
+		WebhookDelivery: d,
+		Event:           Event(d.Event),
+		Status:          DeliveryStatus(d.Status),
+	}
+}
+
+// IsDue returns whether a pending delivery should be attempted at the given
+// time.
+func (d Delivery) IsDue(now time.Time) bool {
+	if d.Status != DeliveryStatusPending {
+		return false
+	}
+
+	return !d.NextAttemptAt.Valid || !d.NextAttemptAt.Time.After(now)
+}
+
+// Deliver makes one attempt at sending a pending webhook delivery and records
+// the outcome.
+//
+// A 2xx response marks the delivery as succeeded. Any other outcome
+// schedules a retry with exponential backoff until MaxDeliveryAttempts is
+// reached, at which point the delivery is marked as failed. The returned
+// error is the request error, if any; it has already been recorded.
+func Deliver(ctx context.Context, d Delivery) error {
+	dbx := db.FromContext(ctx)
+	datastore := store.FromContext(ctx)
+
+	headers := http.Header{}
+	for _, line := range strings.Split(d.RequestHeaders, "\n") {
+		k, v, ok := strings.Cut(line, ": ")
+		if !ok {
+			continue
+		}
+		headers.Add(k, v)
+	}
+
+	res, reqErr := do(ctx, d.RequestURL, d.RequestMethod, headers, strings.NewReader(d.RequestBody))
+
+	resStatus := 0
+	resHeaders := ""
+	resBody := ""
+
+	if res != nil {
+		resStatus = res.StatusCode
+		for k, v := range res.Header {
+			resHeaders += k + ": " + v[0] + "\n"
+		}
+
+		if res.Body != nil {
+			defer res.Body.Close() //nolint: errcheck
+			b, err := io.ReadAll(res.Body)
+			if err != nil && reqErr == nil {
+				reqErr = err
+			}
+
+			resBody = string(b)
+		}
+	}
+
+	attempts := d.Attempts + 1
+	status := DeliveryStatusPending
+	var next time.Time
+	switch {
+	case reqErr == nil && resStatus >= 200 && resStatus < 300:
+		status = DeliveryStatusSucceeded
+	case attempts >= MaxDeliveryAttempts:
+		status = DeliveryStatusFailed
+	default:
+		next = time.Now().UTC().Add(DeliveryBackoff(attempts))
+	}
+
+	if err := datastore.UpdateWebhookDeliveryByID(ctx, dbx, d.ID, int(status), attempts, next, reqErr, resStatus, resHeaders, resBody); err != nil {
+		return db.WrapError(err)
+	}
+
+	return reqErr
+}
@@ -0,0 +1,78 @@
+package webhook
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/soft-serve/pkg/db/models"
+)
+
+func TestDeliveryBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{attempts: 0, want: 0},
+		{attempts: 1, want: 30 * time.Second},
+		{attempts: 2, want: time.Minute},
+		{attempts: 3, want: 2 * time.Minute},
+		{attempts: 4, want: 4 * time.Minute},
+		{attempts: 10, want: time.Hour},
+	}
+
+	for _, tt := range tests {
+		if got := DeliveryBackoff(tt.attempts); got != tt.want {
+			t.Errorf("DeliveryBackoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestDeliveryIsDue(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name string
+		d    models.WebhookDelivery
+		want bool
+	}{
+		{
+			name: "new pending",
+			d:    models.WebhookDelivery{Status: int(DeliveryStatusPending)},
+			want: true,
+		},
+		{
+			name: "retry due",
+			d: models.WebhookDelivery{
+				Status:        int(DeliveryStatusPending),
+				NextAttemptAt: sql.NullTime{Time: now.Add(-time.Second), Valid: true},
+			},
+			want: true,
+		},
+		{
+			name: "retry not due",
+			d: models.WebhookDelivery{
+				Status:        int(DeliveryStatusPending),
+				NextAttemptAt: sql.NullTime{Time: now.Add(time.Minute), Valid: true},
+			},
+			want: false,
+		},
+		{
+			name: "succeeded",
+			d:    models.WebhookDelivery{Status: int(DeliveryStatusSucceeded)},
+			want: false,
+		},
+		{
+			name: "failed",
+			d:    models.WebhookDelivery{Status: int(DeliveryStatusFailed)},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewDelivery(tt.d).IsDue(now); got != tt.want {
+				t.Errorf("IsDue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -26,6 +26,12 @@ const (
 
 	// EventRepositoryVisibilityChange is a repository visibility change event.
 	EventRepositoryVisibilityChange Event = 6
+
+	// EventPullRequest is a pull request open, comment, merge, close event.
+	EventPullRequest Event = 7
+
+	// EventIssue is an issue open, comment, label, close, reopen event.
+	EventIssue Event = 8
 )
 
 // Events return all events.
@@ -37,6 +43,8 @@ func Events() []Event {
 		EventPush,
 		EventRepository,
 		EventRepositoryVisibilityChange,
+		EventPullRequest,
+		EventIssue,
 	}
 }
 
@@ -47,6 +55,8 @@ var eventStrings = map[Event]string{
 	EventPush:                       "push",
 	EventRepository:                 "repository",
 	EventRepositoryVisibilityChange: "repository_visibility_change",
+	EventPullRequest:                "pull_request",
+	EventIssue:                      "issue",
 }
 
 // String returns the string representation of the event.
@@ -61,6 +71,8 @@ var stringEvent = map[string]Event{
 	"push":                         EventPush,
 	"repository":                   EventRepository,
 	"repository_visibility_change": EventRepositoryVisibilityChange,
+	"pull_request":                 EventPullRequest,
+	"issue":                        EventIssue,
 }
 
 // ErrInvalidEvent is returned when the event is invalid.
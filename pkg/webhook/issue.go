@@ -0,0 +1,137 @@
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"github.com/charmbracelet/soft-serve/pkg/config"
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/store"
+)
+
+// IssueEvent is an issue event.
+type IssueEvent struct {
+	Common
+
+	// Action is the issue event action.
+	Action IssueEventAction `json:"action" url:"action"`
+	// Issue is the issue.
+	Issue Issue `json:"issue" url:"issue"`
+	// Comment is the comment body of commented events.
+	Comment string `json:"comment,omitempty" url:"comment,omitempty"`
+	// Label is the added or removed label of labeled and unlabeled events.
+	Label string `json:"label,omitempty" url:"label,omitempty"`
+}
+
+// Issue represents an issue in an event.
+type Issue struct {
+	// Number is the issue number.
+	Number int64 `json:"number" url:"number"`
+	// Title is the issue title.
+	Title string `json:"title" url:"title"`
+	// Body is the issue body.
+	Body string `json:"body" url:"body"`
+	// State is the issue state.
+	State string `json:"state" url:"state"`
+	// Labels are the issue labels.
+	Labels []string `json:"labels" url:"labels"`
+	// Author is the issue author.
+	Author User `json:"author" url:"author"`
+	// CreatedAt is the issue creation time.
+	CreatedAt time.Time `json:"created_at" url:"created_at"`
+	// UpdatedAt is the issue last update time.
+	UpdatedAt time.Time `json:"updated_at" url:"updated_at"`
+}
+
+// IssueEventAction is an issue event action.
+type IssueEventAction string
+
+const (
+	// IssueEventActionOpened is an issue opened event.
+	IssueEventActionOpened IssueEventAction = "opened"
+	// IssueEventActionCommented is an issue commented event.
+	IssueEventActionCommented IssueEventAction = "commented"
+	// IssueEventActionLabeled is an issue labeled event.
+	IssueEventActionLabeled IssueEventAction = "labeled"
+	// IssueEventActionUnlabeled is an issue unlabeled event.
+	IssueEventActionUnlabeled IssueEventAction = "unlabeled"
+	// IssueEventActionClosed is an issue closed event.
+	IssueEventActionClosed IssueEventAction = "closed"
+	// IssueEventActionReopened is an issue reopened event.
+	IssueEventActionReopened IssueEventAction = "reopened"
+)
+
+// NewIssueEvent sends an issue event.
+func NewIssueEvent(ctx context.Context, user proto.User, repo proto.Repository, number int64, action IssueEventAction) (IssueEvent, error) {
+	event := EventIssue
+
+	payload := IssueEvent{
+		Action: action,
+		Common: Common{
+			EventType: event,
+			Repository: Repository{
+				ID:          repo.ID(),
+				Name:        repo.Name(),
+				Description: repo.Description(),
+				ProjectName: repo.ProjectName(),
+				Private:     repo.IsPrivate(),
+				CreatedAt:   repo.CreatedAt(),
+				UpdatedAt:   repo.UpdatedAt(),
+			},
+			Sender: User{
+				ID:       user.ID(),
+				Username: user.Username(),
+			},
+		},
+	}
+
+	cfg := config.FromContext(ctx)
+	payload.Repository.HTTPURL = repoURL(cfg.HTTP.PublicURL, repo.Name())
+	payload.Repository.SSHURL = repoURL(cfg.SSH.PublicURL, repo.Name())
+	payload.Repository.GitURL = repoURL(cfg.Git.PublicURL, repo.Name())
+
+	// Find repo owner.
+	dbx := db.FromContext(ctx)
+	datastore := store.FromContext(ctx)
+	owner, err := datastore.GetUserByID(ctx, dbx, repo.UserID())
+	if err != nil {
+		return IssueEvent{}, db.WrapError(err)
+	}
+
+	payload.Repository.Owner.ID = owner.ID
+	payload.Repository.Owner.Username = owner.Username
+	payload.Repository.DefaultBranch, _ = getDefaultBranch(repo)
+
+	issue, err := datastore.GetIssueByRepoAndNumber(ctx, dbx, repo.Name(), number)
+	if err != nil {
+		return IssueEvent{}, db.WrapError(err)
+	}
+
+	labels, err := datastore.ListIssueLabels(ctx, dbx, issue.ID)
+	if err != nil {
+		return IssueEvent{}, db.WrapError(err)
+	}
+
+	payload.Issue = Issue{
+		Number:    issue.Number,
+		Title:     issue.Title,
+		Body:      issue.Body,
+		State:     issue.State,
+		Labels:    labels,
+		CreatedAt: issue.CreatedAt,
+		UpdatedAt: issue.UpdatedAt,
+	}
+
+	if issue.UserID.Valid {
+		author, err := datastore.GetUserByID(ctx, dbx, issue.UserID.Int64)
+		if err != nil {
+			return IssueEvent{}, db.WrapError(err)
+		}
+
+		payload.Issue.Author.ID = author.ID
+		payload.Issue.Author.Username = author.Username
+	}
+
+	return payload, nil
+}
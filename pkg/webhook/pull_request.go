@@ -0,0 +1,132 @@
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"github.com/charmbracelet/soft-serve/pkg/config"
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/store"
+)
+
+// PullRequestEvent is a pull request event.
+type PullRequestEvent struct {
+	Common
+
+	// Action is the pull request event action.
+	Action PullRequestEventAction `json:"action" url:"action"`
+	// PullRequest is the pull request.
+	PullRequest PullRequest `json:"pull_request" url:"pull_request"`
+	// Comment is the comment body of commented events.
+	Comment string `json:"comment,omitempty" url:"comment,omitempty"`
+}
+
+// PullRequest represents a pull request in an event.
+type PullRequest struct {
+	// Number is the pull request number.
+	Number int64 `json:"number" url:"number"`
+	// Title is the pull request title.
+	Title string `json:"title" url:"title"`
+	// Description is the pull request description.
+	Description string `json:"description" url:"description"`
+	// State is the pull request state.
+	State string `json:"state" url:"state"`
+	// SourceRef is the ref to merge.
+	SourceRef string `json:"source_ref" url:"source_ref"`
+	// TargetBranch is the branch to merge into.
+	TargetBranch string `json:"target_branch" url:"target_branch"`
+	// Author is the pull request author.
+	Author User `json:"author" url:"author"`
+	// MergeCommit is the commit the pull request was merged with.
+	MergeCommit string `json:"merge_commit,omitempty" url:"merge_commit,omitempty"`
+	// CreatedAt is the pull request creation time.
+	CreatedAt time.Time `json:"created_at" url:"created_at"`
+	// UpdatedAt is the pull request last update time.
+	UpdatedAt time.Time `json:"updated_at" url:"updated_at"`
+}
+
+// PullRequestEventAction is a pull request event action.
+type PullRequestEventAction string
+
+const (
+	// PullRequestEventActionOpened is a pull request opened event.
+	PullRequestEventActionOpened PullRequestEventAction = "opened"
+	// PullRequestEventActionCommented is a pull request commented event.
+	PullRequestEventActionCommented PullRequestEventAction = "commented"
+	// PullRequestEventActionMerged is a pull request merged event.
+	PullRequestEventActionMerged PullRequestEventAction = "merged"
+	// PullRequestEventActionClosed is a pull request closed event.
+	PullRequestEventActionClosed PullRequestEventAction = "closed"
+)
+
+// NewPullRequestEvent sends a pull request event.
+func NewPullRequestEvent(ctx context.Context, user proto.User, repo proto.Repository, number int64, action PullRequestEventAction) (PullRequestEvent, error) {
+	event := EventPullRequest
+
+	payload := PullRequestEvent{
+		Action: action,
+		Common: Common{
+			EventType: event,
+			Repository: Repository{
+				ID:          repo.ID(),
+				Name:        repo.Name(),
+				Description: repo.Description(),
+				ProjectName: repo.ProjectName(),
+				Private:     repo.IsPrivate(),
+				CreatedAt:   repo.CreatedAt(),
+				UpdatedAt:   repo.UpdatedAt(),
+			},
+			Sender: User{
+				ID:       user.ID(),
+				Username: user.Username(),
+			},
+		},
+	}
+
+	cfg := config.FromContext(ctx)
+	payload.Repository.HTTPURL = repoURL(cfg.HTTP.PublicURL, repo.Name())
+	payload.Repository.SSHURL = repoURL(cfg.SSH.PublicURL, repo.Name())
+	payload.Repository.GitURL = repoURL(cfg.Git.PublicURL, repo.Name())
+
+	// Find repo owner.
+	dbx := db.FromContext(ctx)
+	datastore := store.FromContext(ctx)
+	owner, err := datastore.GetUserByID(ctx, dbx, repo.UserID())
+	if err != nil {
+		return PullRequestEvent{}, db.WrapError(err)
+	}
+
+	payload.Repository.Owner.ID = owner.ID
+	payload.Repository.Owner.Username = owner.Username
+	payload.Repository.DefaultBranch, _ = getDefaultBranch(repo)
+
+	pr, err := datastore.GetPullRequestByRepoAndNumber(ctx, dbx, repo.Name(), number)
+	if err != nil {
+		return PullRequestEvent{}, db.WrapError(err)
+	}
+
+	payload.PullRequest = PullRequest{
+		Number:       pr.Number,
+		Title:        pr.Title,
+		Description:  pr.Description,
+		State:        pr.State,
+		SourceRef:    pr.SourceRef,
+		TargetBranch: pr.TargetBranch,
+		MergeCommit:  pr.MergeCommit.String,
+		CreatedAt:    pr.CreatedAt,
+		UpdatedAt:    pr.UpdatedAt,
+	}
+
+	if pr.UserID.Valid {
+		author, err := datastore.GetUserByID(ctx, dbx, pr.UserID.Int64)
+		if err != nil {
+			return PullRequestEvent{}, db.WrapError(err)
+		}
+
+		payload.PullRequest.Author.ID = author.ID
+		payload.PullRequest.Author.Username = author.Username
+	}
+
+	return payload, nil
+}
@@ -31,12 +31,6 @@ type Hook struct {
 	Events      []Event
 }
 
-// Delivery is a webhook delivery.
-type Delivery struct {
-	models.WebhookDelivery
-	Event Event
-}
-
 // secureHTTPClient is an HTTP client with SSRF protection.
 var secureHTTPClient = ssrf.NewSecureClient()
 
@@ -57,12 +51,13 @@ func do(ctx context.Context, url string, method string, headers http.Header, bod
 	return res, nil
 }
 
-// SendWebhook sends a webhook event.
+// SendWebhook queues a webhook event for delivery.
+//
+// The payload is encoded and signed right away, and the resulting request is
+// stored as a pending delivery. The request is sent later by the delivery
+// queue, see Deliver.
 func SendWebhook(ctx context.Context, w models.Webhook, event Event, payload interface{}) error {
 	var buf bytes.Buffer
-	dbx := db.FromContext(ctx)
-	datastore := store.FromContext(ctx)
-
 	contentType := ContentType(w.ContentType) //nolint:gosec
 	switch contentType {
 	case ContentTypeJSON:
@@ -79,56 +74,47 @@ func SendWebhook(ctx context.Context, w models.Webhook, event Event, payload int
 		return ErrInvalidContentType
 	}
 
-	headers := http.Header{}
-	headers.Add("Content-Type", contentType.String())
-	headers.Add("User-Agent", "SoftServe/"+version.Version)
-	headers.Add("X-SoftServe-Event", event.String())
+	return enqueue(ctx, w, event, buf.String())
+}
+
+// Redeliver queues a new delivery of a previously delivered webhook request.
+// The new delivery gets its own ID and signature but carries the same body.
+func Redeliver(ctx context.Context, w models.Webhook, d models.WebhookDelivery) error {
+	return enqueue(ctx, w, Event(d.Event), d.RequestBody)
+}
+
+// enqueue stores a pending webhook delivery.
+func enqueue(ctx context.Context, w models.Webhook, event Event, reqBody string) error {
+	dbx := db.FromContext(ctx)
+	datastore := store.FromContext(ctx)
 
 	id, err := uuid.NewUUID()
 	if err != nil {
 		return err
 	}
 
+	headers := http.Header{}
+	headers.Add("Content-Type", ContentType(w.ContentType).String()) //nolint:gosec
+	headers.Add("User-Agent", "SoftServe/"+version.Version)
+	headers.Add("X-SoftServe-Event", event.String())
 	headers.Add("X-SoftServe-Delivery", id.String())
 
-	reqBody := buf.String()
 	if w.Secret != "" {
 		sig := hmac.New(sha256.New, []byte(w.Secret))
 		sig.Write([]byte(reqBody)) //nolint: errcheck
 		headers.Add("X-SoftServe-Signature", "sha256="+hex.EncodeToString(sig.Sum(nil)))
 	}
 
-	res, reqErr := do(ctx, w.URL, http.MethodPost, headers, &buf)
 	var reqHeaders string
 	for k, v := range headers {
 		reqHeaders += k + ": " + v[0] + "\n"
 	}
 
-	resStatus := 0
-	resHeaders := ""
-	resBody := ""
-
-	if res != nil {
-		resStatus = res.StatusCode
-		for k, v := range res.Header {
-			resHeaders += k + ": " + v[0] + "\n"
-		}
-
-		if res.Body != nil {
-			defer res.Body.Close() //nolint: errcheck
-			b, err := io.ReadAll(res.Body)
-			if err != nil {
-				return err
-			}
-
-			resBody = string(b)
-		}
-	}
-
-	return db.WrapError(datastore.CreateWebhookDelivery(ctx, dbx, id, w.ID, int(event), w.URL, http.MethodPost, reqErr, reqHeaders, reqBody, resStatus, resHeaders, resBody))
+	return db.WrapError(datastore.CreateWebhookDelivery(ctx, dbx, id, w.ID, int(event), w.URL, http.MethodPost, reqHeaders, reqBody))
 }
 
-// SendEvent sends a webhook event.
+// SendEvent queues a webhook event for delivery to every webhook of the
+// event's repository that subscribes to it.
 func SendEvent(ctx context.Context, payload EventPayload) error {
 	dbx := db.FromContext(ctx)
 	datastore := store.FromContext(ctx)
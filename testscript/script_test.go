@@ -3,6 +3,7 @@ package testscript
 import (
 	"bytes"
 	"context"
+	cryptorand "crypto/rand"
 	"encoding/json"
 	"flag"
 	"fmt"
@@ -14,6 +15,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"runtime"
 	"strconv"
 	"strings"
@@ -83,6 +85,29 @@ func TestScript(t *testing.T) {
 	_, admin2 := mkkey("admin2")
 	user1Key, user1 := mkkey("user1")
 	attackerKey, attacker := mkkey("attacker")
+	deployKey, deploy := mkkey("deploy")
+	_, ca := mkkey("ca")
+	_, certUser := mkkey("cert")
+
+	// mkcert returns a signer with a certificate signed by the trusted CA.
+	mkcert := func(key *keygen.SSHKeyPair, keyID string, principals ...string) ssh.Signer {
+		cert := &ssh.Certificate{
+			Key:             key.PublicKey(),
+			KeyId:           keyID,
+			CertType:        ssh.UserCert,
+			ValidPrincipals: principals,
+			ValidAfter:      uint64(time.Now().Add(-time.Minute).Unix()),
+			ValidBefore:     ssh.CertTimeInfinity,
+		}
+		if err := cert.SignCert(cryptorand.Reader, ca.Signer()); err != nil {
+			t.Fatal(err)
+		}
+		signer, err := ssh.NewCertSigner(cert, key.Signer())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signer
+	}
 	attackerSigner := &maliciousSigner{
 		publicKey: admin1.PublicKey(),
 	}
@@ -94,11 +119,13 @@ func TestScript(t *testing.T) {
 		Cmds: map[string]func(ts *testscript.TestScript, neg bool, args []string){
 			"soft":                   cmdSoft("admin", admin1.Signer()),
 			"usoft":                  cmdSoft("user1", user1.Signer()),
+			"waitsoft":               cmdWaitSoft("admin", admin1.Signer()),
 			"attacksoft":             cmdSoft("attacker", attackerSigner, attacker.Signer()),
 			"ksoft":                  cmdKeylessSoft,
 			"git":                    cmdGit(admin1Key),
 			"ugit":                   cmdGit(user1Key),
 			"agit":                   cmdGit(attackerKey),
+			"dgit":                   cmdGit(deployKey),
 			"curl":                   cmdCurl,
 			"mkfile":                 cmdMkfile,
 			"envfile":                cmdEnvfile,
@@ -110,6 +137,8 @@ func TestScript(t *testing.T) {
 			"stopserver":             cmdStopserver,
 			"ui":                     cmdUI(admin1.Signer()),
 			"uui":                    cmdUI(user1.Signer()),
+			"dsoft":                  cmdSoft("deploy", deploy.Signer()),
+			"csoft":                  cmdSoft("cert", mkcert(certUser, "user1@ci", "nobody", "user1")),
 		},
 		Setup: func(e *testscript.Env) error {
 			// Add binPath to PATH
@@ -134,7 +163,9 @@ func TestScript(t *testing.T) {
 			e.Setenv("ADMIN1_AUTHORIZED_KEY", admin1.AuthorizedKey())
 			e.Setenv("ADMIN2_AUTHORIZED_KEY", admin2.AuthorizedKey())
 			e.Setenv("USER1_AUTHORIZED_KEY", user1.AuthorizedKey())
+			e.Setenv("USER1_SIGNING_KEY", filepath.ToSlash(user1Key))
 			e.Setenv("ATTACKER_AUTHORIZED_KEY", attacker.AuthorizedKey())
+			e.Setenv("DEPLOY_AUTHORIZED_KEY", deploy.AuthorizedKey())
 			e.Setenv("SSH_KNOWN_HOSTS_FILE", filepath.Join(t.TempDir(), "known_hosts"))
 			e.Setenv("SSH_KNOWN_CONFIG_FILE", filepath.Join(t.TempDir(), "config"))
 
@@ -159,6 +190,7 @@ func TestScript(t *testing.T) {
 			cfg.DataPath = data
 			cfg.Name = serverName
 			cfg.InitialAdminKeys = []string{admin1.AuthorizedKey()}
+			cfg.SSH.TrustedUserCAKeys = []string{ca.AuthorizedKey()}
 			cfg.SSH.ListenAddr = sshListen
 			cfg.SSH.PublicURL = "ssh://" + sshListen
 			cfg.Git.ListenAddr = gitListen
@@ -199,29 +231,65 @@ func TestScript(t *testing.T) {
 
 func cmdSoft(user string, keys ...ssh.Signer) func(ts *testscript.TestScript, neg bool, args []string) {
 	return func(ts *testscript.TestScript, neg bool, args []string) {
-		cli, err := ssh.Dial(
-			"tcp",
-			net.JoinHostPort("localhost", ts.Getenv("SSH_PORT")),
-			&ssh.ClientConfig{
-				User:            user,
-				Auth:            []ssh.AuthMethod{ssh.PublicKeys(keys...)},
-				HostKeyCallback: ssh.InsecureIgnoreHostKey(),
-			},
-		)
-		ts.Check(err)
-		defer cli.Close()
+		check(ts, runSoft(ts, user, keys, args, ts.Stdout(), ts.Stderr()), neg)
+	}
+}
 
-		sess, err := cli.NewSession()
+// cmdWaitSoft is like cmdSoft, but runs the command every second until its
+// output matches a regular expression, for state the server reaches in the
+// background. Usage: waitsoft <regexp> <args...>
+func cmdWaitSoft(user string, keys ...ssh.Signer) func(ts *testscript.TestScript, neg bool, args []string) {
+	return func(ts *testscript.TestScript, neg bool, args []string) {
+		if neg || len(args) < 2 {
+			ts.Fatalf("usage: waitsoft <regexp> <args...>")
+		}
+
+		re, err := regexp.Compile(args[0])
 		ts.Check(err)
-		defer sess.Close()
 
-		sess.Stdout = ts.Stdout()
-		sess.Stderr = ts.Stderr()
+		var out bytes.Buffer
+		deadline := time.Now().Add(time.Minute)
+		for {
+			out.Reset()
+			err := runSoft(ts, user, keys, args[1:], &out, ts.Stderr())
+			if err == nil && re.Match(out.Bytes()) {
+				break
+			}
+			if time.Now().After(deadline) {
+				ts.Stdout().Write(out.Bytes()) //nolint:errcheck
+				ts.Fatalf("output did not match %q within a minute", args[0])
+			}
+			time.Sleep(time.Second)
+		}
 
-		check(ts, sess.Run(strings.Join(args, " ")), neg)
+		ts.Stdout().Write(out.Bytes()) //nolint:errcheck
 	}
 }
 
+// runSoft runs a soft command over SSH as user.
+func runSoft(ts *testscript.TestScript, user string, keys []ssh.Signer, args []string, stdout, stderr io.Writer) error {
+	cli, err := ssh.Dial(
+		"tcp",
+		net.JoinHostPort("localhost", ts.Getenv("SSH_PORT")),
+		&ssh.ClientConfig{
+			User:            user,
+			Auth:            []ssh.AuthMethod{ssh.PublicKeys(keys...)},
+			HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+		},
+	)
+	ts.Check(err)
+	defer cli.Close()
+
+	sess, err := cli.NewSession()
+	ts.Check(err)
+	defer sess.Close()
+
+	sess.Stdout = stdout
+	sess.Stderr = stderr
+
+	return sess.Run(strings.Join(args, " "))
+}
+
 // cmdKeylessSoft is like cmdSoft, but authenticates with zero public keys,
 // forcing keyboard-interactive auth -- the actual "no key offered at all"
 // path that allow-keyless gates. This is distinct from cmdSoft/cmdUsoft